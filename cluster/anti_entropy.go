@@ -0,0 +1,639 @@
+package cluster
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/monitor/diagnostics"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/uber-go/zap"
+	"github.com/zhexuany/influxcloud/rpc"
+)
+
+// AntiEntropyLeaseName is the name of the meta lease held by the node
+// comparing the shard replicas. Only one node in the cluster checks at a time.
+const AntiEntropyLeaseName = "anti-entropy"
+
+// ShardDiff describes a series whose points differ between the owners of a
+// shard within a time range.
+type ShardDiff struct {
+	ShardID  uint64
+	Database string
+	Policy   string
+	Series   string
+	Start    time.Time
+	End      time.Time
+
+	// Counts holds the number of points of the series within the time range
+	// on each owner of the shard.
+	Counts map[uint64]uint64
+
+	// Repaired is true if the points of all owners were merged and written
+	// back to every owner.
+	Repaired bool
+}
+
+// AntiEntropy periodically compares the replicas of the cold shards owned by
+// more than one data node and optionally repairs the divergent series.
+//
+// Replicas are compared using digests of each series over fixed time ranges.
+// A divergent range is repaired by reading the points of the series from all
+// owners, merging them and writing the merged points to every owner. Field
+// values conflicting between owners are resolved in favour of the owner with
+// the highest node id.
+type AntiEntropy struct {
+	mu    sync.Mutex
+	diffs []ShardDiff
+
+	// checkMu ensures only one check runs at a time.
+	checkMu sync.Mutex
+
+	wg      sync.WaitGroup
+	closing chan struct{}
+
+	enabled        bool
+	checkInterval  time.Duration
+	digestInterval time.Duration
+	repair         bool
+
+	MetaClient interface {
+		AcquireLease(name string) (*meta.Lease, error)
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		Databases() ([]meta.DatabaseInfo, error)
+	}
+
+	ShardReader interface {
+		ShardDigest(addr string, shardID uint64, interval time.Duration) ([]rpc.SeriesDigest, error)
+		ShardPoints(addr string, shardID uint64, keys []string, start, end int64) ([]models.Point, error)
+	}
+
+	ShardWriter interface {
+		WriteShard(shardID, ownerID uint64, points []models.Point) error
+	}
+
+	Monitor interface {
+		RegisterDiagnosticsClient(name string, client diagnostics.Client)
+		DeregisterDiagnosticsClient(name string)
+	}
+
+	Logger zap.Logger
+}
+
+// NewAntiEntropy returns a new instance of AntiEntropy. The ShardWriter
+// writing the repaired points must be set before opening the service.
+func NewAntiEntropy(c Config) *AntiEntropy {
+	return &AntiEntropy{
+		enabled:        c.AntiEntropyEnabled,
+		checkInterval:  time.Duration(c.AntiEntropyCheckInterval),
+		digestInterval: time.Duration(c.AntiEntropyDigestInterval),
+		repair:         c.AntiEntropyRepair,
+		ShardReader:    &Client{timeout: time.Duration(c.DialTimeout), TLS: NewTLS(c)},
+		Logger:         zap.New(zap.NullEncoder()),
+	}
+}
+
+// WithLogger sets the internal logger to the logger passed in
+func (s *AntiEntropy) WithLogger(log zap.Logger) {
+	s.Logger = log.With(zap.String("service", "anti-entropy"))
+}
+
+// Open starts comparing the shard replicas periodically.
+func (s *AntiEntropy) Open() error {
+	if !s.enabled {
+		return nil
+	} else if s.repair && s.ShardWriter == nil {
+		return errors.New("anti-entropy repair requires a shard writer")
+	}
+
+	s.Logger.Info(fmt.Sprintf("Starting anti-entropy service, check interval: %s, repair: %t", s.checkInterval, s.repair))
+
+	// Register diagnostics if a Monitor service is available.
+	if s.Monitor != nil {
+		s.Monitor.RegisterDiagnosticsClient("shard-diffs", s)
+	}
+
+	s.closing = make(chan struct{})
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Close stops the service and waits for a running check to complete.
+func (s *AntiEntropy) Close() error {
+	if s.closing == nil {
+		return nil
+	}
+	close(s.closing)
+	s.wg.Wait()
+	s.closing = nil
+
+	if s.Monitor != nil {
+		s.Monitor.DeregisterDiagnosticsClient("shard-diffs")
+	}
+	return nil
+}
+
+func (s *AntiEntropy) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			diffs, err := s.Check(s.repair)
+			if err != nil {
+				s.Logger.Warn("anti-entropy check error: " + err.Error())
+				continue
+			}
+			if len(diffs) > 0 {
+				s.Logger.Info(fmt.Sprintf("anti-entropy found %d divergent series ranges", len(diffs)))
+			}
+		}
+	}
+}
+
+// Diffs returns the divergent series found by the last check.
+func (s *AntiEntropy) Diffs() []ShardDiff {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ShardDiff(nil), s.diffs...)
+}
+
+// Diagnostics returns the divergent series found by the last check.
+func (s *AntiEntropy) Diagnostics() (*diagnostics.Diagnostics, error) {
+	diffs := s.Diffs()
+
+	d := &diagnostics.Diagnostics{
+		Columns: []string{"shard_id", "database", "retention_policy", "series", "start", "end", "counts", "repaired"},
+		Rows:    make([][]interface{}, 0, len(diffs)),
+	}
+	for _, diff := range diffs {
+		ids := make([]uint64, 0, len(diff.Counts))
+		for id := range diff.Counts {
+			ids = append(ids, id)
+		}
+		sort.Sort(uint64Slice(ids))
+
+		counts := make([]string, 0, len(ids))
+		for _, id := range ids {
+			counts = append(counts, fmt.Sprintf("%d:%d", id, diff.Counts[id]))
+		}
+
+		d.Rows = append(d.Rows, []interface{}{diff.ShardID, diff.Database, diff.Policy, diff.Series, diff.Start, diff.End, strings.Join(counts, ","), diff.Repaired})
+	}
+	return d, nil
+}
+
+// Check compares the replicas of every cold shard owned by more than one
+// node and repairs the divergent series if repair is set. The divergent
+// series are returned and kept for Diffs.
+func (s *AntiEntropy) Check(repair bool) ([]ShardDiff, error) {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	if _, err := s.MetaClient.AcquireLease(AntiEntropyLeaseName); err != nil {
+		return nil, err
+	}
+
+	dbs, err := s.MetaClient.Databases()
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []ShardDiff
+	now := time.Now()
+	for _, dbi := range dbs {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				// Shards still accepting writes are expected to differ.
+				if sgi.Deleted() || sgi.EndTime.After(now) {
+					continue
+				}
+
+				for _, si := range sgi.Shards {
+					if len(si.Owners) < 2 {
+						continue
+					}
+
+					a, err := s.checkShard(dbi.Name, rpi.Name, si, repair)
+					if err != nil {
+						s.Logger.Warn(fmt.Sprintf("anti-entropy check of shard %d: %s", si.ID, err))
+					}
+					diffs = append(diffs, a...)
+				}
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.diffs = diffs
+	s.mu.Unlock()
+	return append([]ShardDiff(nil), diffs...), nil
+}
+
+// checkShard compares the digests of every owner of the shard.
+func (s *AntiEntropy) checkShard(database, policy string, si meta.ShardInfo, repair bool) ([]ShardDiff, error) {
+	hosts := make(map[uint64]string, len(si.Owners))
+	digests := make(map[uint64]map[digestKey]rpc.SeriesDigest, len(si.Owners))
+	keys := make(map[digestKey]struct{})
+	for _, o := range si.Owners {
+		ni, err := s.MetaClient.DataNode(o.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		hosts[o.NodeID] = ni.TCPHost
+
+		a, err := s.ShardReader.ShardDigest(ni.TCPHost, si.ID, s.digestInterval)
+		if err != nil {
+			return nil, fmt.Errorf("digest from node %d: %s", o.NodeID, err)
+		}
+
+		m := make(map[digestKey]rpc.SeriesDigest, len(a))
+		for _, d := range a {
+			k := digestKey{key: d.Key, start: d.Start}
+			m[k] = d
+			keys[k] = struct{}{}
+		}
+		digests[o.NodeID] = m
+	}
+
+	var diffs []ShardDiff
+	for k := range keys {
+		var diverged bool
+		var first *rpc.SeriesDigest
+		counts := make(map[uint64]uint64, len(si.Owners))
+		for _, o := range si.Owners {
+			d := digests[o.NodeID][k]
+			counts[o.NodeID] = d.Count
+			if first == nil {
+				first = &d
+			} else if d.Count != first.Count || d.Sum != first.Sum {
+				diverged = true
+			}
+		}
+		if !diverged {
+			continue
+		}
+
+		diffs = append(diffs, ShardDiff{
+			ShardID:  si.ID,
+			Database: database,
+			Policy:   policy,
+			Series:   k.key,
+			Start:    time.Unix(0, k.start).UTC(),
+			End:      time.Unix(0, k.start).Add(s.digestInterval).UTC(),
+			Counts:   counts,
+		})
+	}
+	sort.Sort(shardDiffs(diffs))
+
+	if !repair {
+		return diffs, nil
+	}
+	for i := range diffs {
+		if err := s.repairSeries(si, hosts, &diffs[i]); err != nil {
+			return diffs, err
+		}
+	}
+	return diffs, nil
+}
+
+// repairSeries merges the points of the divergent series of every owner and
+// writes the merged points back to all owners.
+func (s *AntiEntropy) repairSeries(si meta.ShardInfo, hosts map[uint64]string, diff *ShardDiff) error {
+	ids := make([]uint64, 0, len(si.Owners))
+	for _, o := range si.Owners {
+		ids = append(ids, o.NodeID)
+	}
+	sort.Sort(uint64Slice(ids))
+
+	var times []int64
+	merged := make(map[int64]models.Point)
+	fields := make(map[int64]models.Fields)
+	for _, id := range ids {
+		points, err := s.ShardReader.ShardPoints(hosts[id], si.ID, []string{diff.Series}, diff.Start.UnixNano(), diff.End.UnixNano())
+		if err != nil {
+			return fmt.Errorf("read points from node %d: %s", id, err)
+		}
+
+		for _, p := range points {
+			f, err := p.Fields()
+			if err != nil {
+				return err
+			}
+
+			t := p.UnixNano()
+			if _, ok := merged[t]; !ok {
+				times = append(times, t)
+				merged[t] = p
+				fields[t] = make(models.Fields, len(f))
+			}
+			for k, v := range f {
+				fields[t][k] = v
+			}
+		}
+	}
+	sort.Sort(int64Slice(times))
+
+	points := make([]models.Point, 0, len(times))
+	for _, t := range times {
+		p := merged[t]
+		pt, err := models.NewPoint(p.Name(), p.Tags(), fields[t], p.Time())
+		if err != nil {
+			return err
+		}
+		points = append(points, pt)
+	}
+
+	for _, id := range ids {
+		if err := s.ShardWriter.WriteShard(si.ID, id, points); err != nil {
+			return fmt.Errorf("write points to node %d: %s", id, err)
+		}
+	}
+
+	diff.Repaired = true
+	s.Logger.Info(fmt.Sprintf("anti-entropy repaired %d points of %s in shard %d", len(points), diff.Series, si.ID))
+	return nil
+}
+
+// digestKey identifies the digest of a series within a time range.
+type digestKey struct {
+	key   string
+	start int64
+}
+
+// shardDigests computes the digest of every series of sg for each time range
+// of the given interval.
+func shardDigests(sg tsdb.ShardGroup, interval time.Duration) ([]rpc.SeriesDigest, error) {
+	m := make(map[digestKey]*rpc.SeriesDigest)
+	if err := scanShard(sg, nil, influxql.MinTime, influxql.MaxTime, func(p models.Point) error {
+		h, err := pointHash(p)
+		if err != nil {
+			return err
+		}
+
+		t := p.UnixNano()
+		start := t - t%int64(interval)
+		if t%int64(interval) < 0 {
+			start -= int64(interval)
+		}
+
+		k := digestKey{key: string(p.Key()), start: start}
+		d := m[k]
+		if d == nil {
+			d = &rpc.SeriesDigest{Key: k.key, Start: k.start}
+			m[k] = d
+		}
+		d.Count++
+		d.Sum += h
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	digests := make([]rpc.SeriesDigest, 0, len(m))
+	for _, d := range m {
+		digests = append(digests, *d)
+	}
+	sort.Sort(seriesDigests(digests))
+	return digests, nil
+}
+
+// pointHash returns a hash of the time and field values of p. Digests sum the
+// hashes of their points so that the order of the points does not matter.
+func pointHash(p models.Point) (uint64, error) {
+	fields, err := p.Fields()
+	if err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(p.UnixNano()))
+	h.Write(buf[:])
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%T:%v,", k, fields[k], fields[k])
+	}
+	return h.Sum64(), nil
+}
+
+// scanShard calls fn for every point of sg between start and end, inclusive.
+// Points are read measurement by measurement with all fields and tags. If
+// scope is set, only its measurements are read and the points of each are
+// filtered by the measurement's condition, if any.
+func scanShard(sg tsdb.ShardGroup, scope map[string]influxql.Expr, start, end int64, fn func(p models.Point) error) error {
+	names := sg.MeasurementsByRegex(regexp.MustCompile(`.*`))
+	sort.Strings(names)
+
+	for _, name := range names {
+		var cond influxql.Expr
+		if scope != nil {
+			expr, ok := scope[name]
+			if !ok {
+				continue
+			}
+			cond = expr
+		}
+
+		fields, dimensions, err := sg.FieldDimensions([]string{name})
+		if err != nil {
+			return err
+		}
+
+		aux := make([]influxql.VarRef, 0, len(fields))
+		for k, typ := range fields {
+			aux = append(aux, influxql.VarRef{Val: k, Type: typ})
+		}
+		sort.Sort(influxql.VarRefs(aux))
+
+		dims := make([]string, 0, len(dimensions))
+		for k := range dimensions {
+			dims = append(dims, k)
+		}
+		sort.Strings(dims)
+
+		itr, err := sg.CreateIterator(name, influxql.IteratorOptions{
+			Aux:        aux,
+			Dimensions: dims,
+			GroupBy:    dimensions,
+			Condition:  cond,
+			StartTime:  start,
+			EndTime:    end,
+			Ascending:  true,
+			Ordered:    true,
+		})
+		if err != nil {
+			return err
+		} else if itr == nil {
+			continue
+		}
+
+		err = readAuxPoints(itr, func(tags influxql.Tags, t int64, values []interface{}) error {
+			f := make(models.Fields, len(values))
+			for i, v := range values {
+				if v != nil && i < len(aux) {
+					f[aux[i].Val] = v
+				}
+			}
+			if len(f) == 0 {
+				return nil
+			}
+
+			m := make(map[string]string)
+			for k, v := range tags.KeyValues() {
+				if v != "" {
+					m[k] = v
+				}
+			}
+
+			p, err := models.NewPoint(name, models.NewTags(m), f, time.Unix(0, t))
+			if err != nil {
+				return err
+			}
+			return fn(p)
+		})
+		itr.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seriesScope returns the scope of scanShard reading the series of keys. The
+// condition of each measurement matches the tags of its series, or is nil if
+// one of its series has no tags.
+func seriesScope(keys []string) (map[string]influxql.Expr, error) {
+	scope := make(map[string]influxql.Expr)
+	all := make(map[string]bool)
+	for _, k := range keys {
+		name, tags, err := models.ParseKey([]byte(k))
+		if err != nil {
+			return nil, err
+		}
+		if all[name] {
+			continue
+		} else if len(tags) == 0 {
+			all[name] = true
+			scope[name] = nil
+			continue
+		}
+
+		var expr influxql.Expr
+		for _, t := range tags {
+			eq := &influxql.BinaryExpr{
+				Op:  influxql.EQ,
+				LHS: &influxql.VarRef{Val: string(t.Key)},
+				RHS: &influxql.StringLiteral{Val: string(t.Value)},
+			}
+			if expr == nil {
+				expr = eq
+			} else {
+				expr = &influxql.BinaryExpr{Op: influxql.AND, LHS: expr, RHS: eq}
+			}
+		}
+
+		if other := scope[name]; other != nil {
+			expr = &influxql.BinaryExpr{Op: influxql.OR, LHS: other, RHS: &influxql.ParenExpr{Expr: expr}}
+		} else {
+			expr = &influxql.ParenExpr{Expr: expr}
+		}
+		scope[name] = expr
+	}
+	return scope, nil
+}
+
+// readAuxPoints calls fn with the tags, time and auxiliary values of every
+// point read from itr.
+func readAuxPoints(itr influxql.Iterator, fn func(tags influxql.Tags, t int64, values []interface{}) error) error {
+	switch itr := itr.(type) {
+	case influxql.FloatIterator:
+		for {
+			p, err := itr.Next()
+			if err != nil || p == nil {
+				return err
+			} else if err := fn(p.Tags, p.Time, p.Aux); err != nil {
+				return err
+			}
+		}
+	case influxql.IntegerIterator:
+		for {
+			p, err := itr.Next()
+			if err != nil || p == nil {
+				return err
+			} else if err := fn(p.Tags, p.Time, p.Aux); err != nil {
+				return err
+			}
+		}
+	case influxql.StringIterator:
+		for {
+			p, err := itr.Next()
+			if err != nil || p == nil {
+				return err
+			} else if err := fn(p.Tags, p.Time, p.Aux); err != nil {
+				return err
+			}
+		}
+	case influxql.BooleanIterator:
+		for {
+			p, err := itr.Next()
+			if err != nil || p == nil {
+				return err
+			} else if err := fn(p.Tags, p.Time, p.Aux); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported iterator type: %T", itr)
+	}
+}
+
+// seriesDigests sorts digests by series key and start time.
+type seriesDigests []rpc.SeriesDigest
+
+func (a seriesDigests) Len() int      { return len(a) }
+func (a seriesDigests) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a seriesDigests) Less(i, j int) bool {
+	if a[i].Key != a[j].Key {
+		return a[i].Key < a[j].Key
+	}
+	return a[i].Start < a[j].Start
+}
+
+// shardDiffs sorts shard diffs by series and start time.
+type shardDiffs []ShardDiff
+
+func (a shardDiffs) Len() int      { return len(a) }
+func (a shardDiffs) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a shardDiffs) Less(i, j int) bool {
+	if a[i].Series != a[j].Series {
+		return a[i].Series < a[j].Series
+	}
+	return a[i].Start.Before(a[j].Start)
+}
+
+// int64Slice sorts int64 values in ascending order.
+type int64Slice []int64
+
+func (a int64Slice) Len() int           { return len(a) }
+func (a int64Slice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a int64Slice) Less(i, j int) bool { return a[i] < a[j] }
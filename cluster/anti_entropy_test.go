@@ -0,0 +1,187 @@
+package cluster_test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud/cluster"
+)
+
+// Ensure divergent series are reported without being repaired.
+func TestAntiEntropy_Check(t *testing.T) {
+	s := MustOpenAntiEntropy()
+	defer s.Close()
+
+	diffs, err := s.Check(false)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(diffs) != 1 {
+		t.Fatalf("unexpected diffs: %+v", diffs)
+	} else if d := diffs[0]; d.ShardID != 1 || d.Database != "db0" || d.Policy != "rp0" || d.Series != "cpu,host=serverA" || d.Repaired {
+		t.Fatalf("unexpected diff: %+v", d)
+	} else if !d.Start.Equal(time.Unix(0, 0)) || !d.End.Equal(time.Unix(3600, 0)) {
+		t.Fatalf("unexpected range: %s - %s", d.Start, d.End)
+	} else if exp := map[uint64]uint64{1: 2, 2: 1}; !reflect.DeepEqual(d.Counts, exp) {
+		t.Fatalf("unexpected counts: %v", d.Counts)
+	} else if len(s.ShardWriter.Writes) != 0 {
+		t.Fatalf("unexpected writes: %v", s.ShardWriter.Writes)
+	}
+
+	if diag, err := s.Diagnostics(); err != nil {
+		t.Fatal(err)
+	} else if len(diag.Rows) != 1 || diag.Rows[0][6] != "1:2,2:1" {
+		t.Fatalf("unexpected diagnostics: %v", diag.Rows)
+	}
+}
+
+// Ensure the merged points of a divergent series are written to every owner.
+func TestAntiEntropy_Check_Repair(t *testing.T) {
+	s := MustOpenAntiEntropy()
+	defer s.Close()
+
+	diffs, err := s.Check(true)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(diffs) != 1 || !diffs[0].Repaired {
+		t.Fatalf("unexpected diffs: %+v", diffs)
+	}
+
+	sort.Strings(s.ShardWriter.Writes)
+	if exp := []string{
+		"1 1 cpu,host=serverA value=1 0",
+		"1 1 cpu,host=serverA value=2 10000000000",
+		"1 2 cpu,host=serverA value=1 0",
+		"1 2 cpu,host=serverA value=2 10000000000",
+	}; !reflect.DeepEqual(s.ShardWriter.Writes, exp) {
+		t.Fatalf("unexpected writes: %v", s.ShardWriter.Writes)
+	}
+}
+
+// Ensure reading the points of a series only reads its measurement and tags.
+func TestClient_ShardPoints(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	sg := &ShardGroup{
+		Points: []influxql.FloatPoint{
+			{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 0, Aux: []interface{}{float64(1)}},
+			{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverB"}), Time: 0, Aux: []interface{}{float64(3)}},
+		},
+		Measurements: []string{"cpu", "mem"},
+		Fields:       map[string]influxql.DataType{"value": influxql.Float},
+		Dimensions:   map[string]struct{}{"host": struct{}{}},
+	}
+	s.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup { return sg }
+
+	c := cluster.NewClient(time.Second)
+	if points, err := c.ShardPoints(s.Addr().String(), 1, []string{"cpu,host=serverA"}, 0, int64(time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if len(points) != 1 || points[0].String() != "cpu,host=serverA value=1 0" {
+		t.Fatalf("unexpected points: %v", points)
+	} else if exp := []string{"cpu (host = 'serverA')"}; !reflect.DeepEqual(sg.Iterators, exp) {
+		t.Fatalf("unexpected iterators: %v", sg.Iterators)
+	}
+}
+
+// AntiEntropy is a test wrapper for cluster.AntiEntropy comparing the
+// replicas of shard 1 on two services.
+type AntiEntropy struct {
+	*cluster.AntiEntropy
+	Services    []*Service
+	ShardWriter *RepairShardWriter
+}
+
+// MustOpenAntiEntropy returns an anti-entropy service for two nodes owning
+// shard 1. Node 2 is missing the second point of cpu,host=serverA.
+func MustOpenAntiEntropy() *AntiEntropy {
+	points := []influxql.FloatPoint{
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 0, Aux: []interface{}{float64(1)}},
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 10 * int64(time.Second), Aux: []interface{}{float64(2)}},
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverB"}), Time: 0, Aux: []interface{}{float64(3)}},
+	}
+
+	var mc AntiEntropyMetaClient
+	s := &AntiEntropy{ShardWriter: &RepairShardWriter{}}
+	for i, a := range [][]influxql.FloatPoint{points, {points[0], points[2]}} {
+		a := a
+		svc := MustOpenService()
+		svc.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+			return &ShardGroup{
+				Points:       a,
+				Measurements: []string{"cpu"},
+				Fields:       map[string]influxql.DataType{"value": influxql.Float},
+				Dimensions:   map[string]struct{}{"host": struct{}{}},
+			}
+		}
+		s.Services = append(s.Services, svc)
+		mc.Nodes = append(mc.Nodes, meta.NodeInfo{ID: uint64(i + 1), TCPHost: svc.Addr().String()})
+	}
+	mc.DatabaseInfos = []meta.DatabaseInfo{{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{
+			Name: "rp0",
+			ShardGroups: []meta.ShardGroupInfo{{
+				ID:        1,
+				StartTime: time.Unix(0, 0),
+				EndTime:   time.Unix(3600, 0),
+				Shards:    []meta.ShardInfo{{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}}},
+			}},
+		}},
+	}}
+
+	c := cluster.NewConfig()
+	s.AntiEntropy = cluster.NewAntiEntropy(c)
+	s.AntiEntropy.MetaClient = &mc
+	s.AntiEntropy.ShardWriter = s.ShardWriter
+	return s
+}
+
+// Close closes the services.
+func (s *AntiEntropy) Close() error {
+	for _, svc := range s.Services {
+		svc.Close()
+	}
+	return nil
+}
+
+// AntiEntropyMetaClient is a test meta client for the anti-entropy service.
+type AntiEntropyMetaClient struct {
+	Nodes         meta.NodeInfos
+	DatabaseInfos []meta.DatabaseInfo
+}
+
+func (c *AntiEntropyMetaClient) AcquireLease(name string) (*meta.Lease, error) {
+	return &meta.Lease{Name: name, Owner: 1}, nil
+}
+
+func (c *AntiEntropyMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	for i := range c.Nodes {
+		if c.Nodes[i].ID == id {
+			return &c.Nodes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("node not found: %d", id)
+}
+
+func (c *AntiEntropyMetaClient) Databases() ([]meta.DatabaseInfo, error) {
+	return c.DatabaseInfos, nil
+}
+
+// RepairShardWriter is a test shard writer recording written points.
+type RepairShardWriter struct {
+	Writes []string
+}
+
+func (w *RepairShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point) error {
+	for _, p := range points {
+		w.Writes = append(w.Writes, fmt.Sprintf("%d %d %s", shardID, ownerID, p.String()))
+	}
+	return nil
+}
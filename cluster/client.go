@@ -0,0 +1,277 @@
+package cluster
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/zhexuany/influxcloud/rpc"
+	"github.com/zhexuany/influxcloud/tlv"
+)
+
+// Client issues shard management requests to the cluster service of a data
+// node.
+type Client struct {
+	timeout time.Duration
+
+	// TLS secures the connections to the nodes if set.
+	TLS *TLS
+}
+
+// NewClient returns a new instance of Client.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		timeout: timeout,
+	}
+}
+
+// CopyShard copies shardID from the source node to the dest node. Both are
+// the TCP addresses of the nodes. It blocks until the copy has completed.
+func (c *Client) CopyShard(source, dest string, shardID uint64) error {
+	var resp rpc.CopyShardResponse
+	if err := c.request(dest, tlv.CopyShardRequestMessage, &rpc.CopyShardRequest{
+		Source:  source,
+		Dest:    dest,
+		ShardID: shardID,
+	}, tlv.CopyShardResponseMessage, &resp); err != nil {
+		return err
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
+// CopyShardStatus returns the shard copies running on the node at addr.
+func (c *Client) CopyShardStatus(addr string) ([]rpc.CopyShardStatus, error) {
+	var resp rpc.CopyShardStatusResponse
+	if err := c.request(addr, tlv.CopyShardStatusRequestMessage, &rpc.CopyShardStatusRequest{},
+		tlv.CopyShardStatusResponseMessage, &resp); err != nil {
+		return nil, err
+	} else if resp.Err != "" {
+		return nil, errors.New(resp.Err)
+	}
+	return resp.Tasks, nil
+}
+
+// KillCopyShard aborts copying shardID from the source node to the dest node.
+func (c *Client) KillCopyShard(source, dest string, shardID uint64) error {
+	var resp rpc.KillCopyShardResponse
+	if err := c.request(dest, tlv.KillCopyShardRequestMessage, &rpc.KillCopyShardRequest{
+		Source:  source,
+		Dest:    dest,
+		ShardID: shardID,
+	}, tlv.KillCopyShardReesponseMessage, &resp); err != nil {
+		return err
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
+// ShardDigest returns the digests of shardID on the node at addr. Each digest
+// covers the points of one series within a time range of the given interval.
+func (c *Client) ShardDigest(addr string, shardID uint64, interval time.Duration) ([]rpc.SeriesDigest, error) {
+	var resp rpc.ShardDigestResponse
+	if err := c.request(addr, tlv.ShardDigestRequestMessage, &rpc.ShardDigestRequest{
+		ShardID:  shardID,
+		Interval: interval,
+	}, tlv.ShardDigestResponseMessage, &resp); err != nil {
+		return nil, err
+	} else if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return resp.Digests, nil
+}
+
+// ShardPoints returns the points of the series keys of shardID between start
+// and end, exclusive, on the node at addr.
+func (c *Client) ShardPoints(addr string, shardID uint64, keys []string, start, end int64) ([]models.Point, error) {
+	var resp rpc.ShardPointsResponse
+	if err := c.request(addr, tlv.ShardPointsRequestMessage, &rpc.ShardPointsRequest{
+		ShardID: shardID,
+		Keys:    keys,
+		Start:   start,
+		End:     end,
+	}, tlv.ShardPointsResponseMessage, &resp); err != nil {
+		return nil, err
+	} else if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return resp.Points, nil
+}
+
+// JoinCluster asks the data node at addr to join the cluster of the meta
+// servers at metaAddrs. It returns the id assigned to the data node.
+func (c *Client) JoinCluster(addr string, metaAddrs []string, importMetaData bool) (uint64, error) {
+	var resp rpc.JoinClusterResponse
+	if err := c.request(addr, tlv.JoinClusterRequestMessage, &rpc.JoinClusterRequest{
+		NodeAddr:       addr,
+		MetaAddrs:      metaAddrs,
+		ImportMetaData: importMetaData,
+	}, tlv.JoinClusterResponseMessage, &resp); err != nil {
+		return 0, err
+	} else if resp.Err != "" {
+		return 0, errors.New(resp.Err)
+	}
+	return resp.NodeID, nil
+}
+
+// LeaveCluster asks the data node at addr to drain its shards and leave its
+// cluster. It blocks until the node has left.
+func (c *Client) LeaveCluster(addr string) error {
+	var resp rpc.LeaveClusterReesponse
+	if err := c.request(addr, tlv.LeaveClusterRequestMessage, &rpc.LeaveClusterRequest{
+		NodeAddr: addr,
+	}, tlv.LeaveClusterResponseMessage, &resp); err != nil {
+		return err
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
+// ShardStatus returns the status of the shards stored on the node at addr.
+// Shards not stored on the node are omitted.
+func (c *Client) ShardStatus(addr string, shardIDs []uint64) ([]rpc.ShardStatus, error) {
+	var resp rpc.ShardStatusResponse
+	if err := c.request(addr, tlv.ShardStatusRequestMessage, &rpc.ShardStatusRequest{
+		ShardIDs: shardIDs,
+	}, tlv.ShardStatusResponseMessage, &resp); err != nil {
+		return nil, err
+	} else if resp.Err != "" {
+		return nil, errors.New(resp.Err)
+	}
+	return resp.Shards, nil
+}
+
+// HintedHandoffStatus returns the hinted handoff queues the node at addr
+// holds for other nodes.
+func (c *Client) HintedHandoffStatus(addr string) ([]rpc.HintedHandoffQueue, error) {
+	var resp rpc.HintedHandoffStatusResponse
+	if err := c.request(addr, tlv.HintedHandoffStatusRequestMessage, &rpc.HintedHandoffStatusRequest{},
+		tlv.HintedHandoffStatusResponseMessage, &resp); err != nil {
+		return nil, err
+	} else if resp.Err != "" {
+		return nil, errors.New(resp.Err)
+	}
+	return resp.Queues, nil
+}
+
+// HintedHandoffAction drains, purges, pauses or resumes the hinted handoff
+// queue the node at addr holds for nodeID. A drain returns once the replay
+// has started.
+func (c *Client) HintedHandoffAction(addr string, nodeID uint64, action string) error {
+	var resp rpc.HintedHandoffActionResponse
+	if err := c.request(addr, tlv.HintedHandoffActionRequestMessage, &rpc.HintedHandoffActionRequest{
+		NodeID: nodeID,
+		Action: action,
+	}, tlv.HintedHandoffActionResponseMessage, &resp); err != nil {
+		return err
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
+// RemoveShard deletes shardID from the node at addr. The shard owners stored
+// in the meta data are not changed.
+func (c *Client) RemoveShard(addr string, shardID uint64) error {
+	var resp rpc.RemoveShardResponse
+	if err := c.request(addr, tlv.RemoveShardRequestMessage, &rpc.RemoveShardRequest{
+		ShardID: shardID,
+	}, tlv.RemoveShardResponseMessage, &resp); err != nil {
+		return err
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
+// BackupShard writes a snapshot of shardID on the node at addr to w. The
+// snapshot is a tar archive of the shard files changed since the given time,
+// or of every file if since is zero. It returns the time of the data node the
+// snapshot started at, to be passed as since to back up the later changes.
+func (c *Client) BackupShard(addr string, shardID uint64, since time.Time, w io.Writer) (time.Time, error) {
+	conn, err := dialTCPHost(addr, c.timeout, c.TLS)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	if err := tlv.EncodeTLV(conn, tlv.DownloadShardSnapshotRequestMessage, &rpc.DownloadShardSnapshotRequest{
+		ShardID: shardID,
+		Since:   since,
+	}); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := io.Copy(w, &snapshotReader{r: conn}); err != nil {
+		return time.Time{}, err
+	}
+
+	var resp rpc.DownloadShardSnapshotResponse
+	if typ, err := tlv.DecodeTLV(conn, &resp); err != nil {
+		return time.Time{}, err
+	} else if typ != tlv.DownloadShardSnapshotResponseMessage {
+		return time.Time{}, fmt.Errorf("unexpected response type: %d", typ)
+	} else if resp.Err != "" {
+		return time.Time{}, errors.New(resp.Err)
+	}
+	return resp.SnapshotTime, nil
+}
+
+// RestoreShard creates shardID on the node at addr and restores the snapshot
+// read from r into it. The shard must be owned by the node in the meta data.
+// The snapshot may be taken from a shard with a different id, database or
+// retention policy.
+func (c *Client) RestoreShard(addr string, shardID uint64, r io.Reader) error {
+	conn, err := dialTCPHost(addr, c.timeout, c.TLS)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := tlv.EncodeTLV(conn, tlv.RestoreShardRequestMessage, &rpc.RestoreShardRequest{
+		ShardID: shardID,
+	}); err != nil {
+		return err
+	}
+	w := &snapshotWriter{w: conn}
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	} else if err := w.Close(); err != nil {
+		return err
+	}
+
+	var resp rpc.RestoreShardResponse
+	if typ, err := tlv.DecodeTLV(conn, &resp); err != nil {
+		return err
+	} else if typ != tlv.RestoreShardResponseMessage {
+		return fmt.Errorf("unexpected response type: %d", typ)
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
+// request sends req to the node at addr and decodes the response into resp.
+func (c *Client) request(addr string, reqType byte, req encoding.BinaryMarshaler, respType byte, resp encoding.BinaryUnmarshaler) error {
+	conn, err := dialTCPHost(addr, c.timeout, c.TLS)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := tlv.EncodeTLV(conn, reqType, req); err != nil {
+		return err
+	}
+
+	if typ, err := tlv.DecodeTLV(conn, resp); err != nil {
+		return err
+	} else if typ != respType {
+		return fmt.Errorf("unexpected response type: %d", typ)
+	}
+	return nil
+}
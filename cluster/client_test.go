@@ -0,0 +1,250 @@
+package cluster_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cluster"
+	"github.com/zhexuany/influxcloud/hh"
+	"github.com/zhexuany/influxcloud/rpc"
+)
+
+// Ensure the sizes of the shards stored on a node are returned.
+func TestClient_ShardStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxcloud-cluster-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "db0", "rp0", "1"), 0777); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(filepath.Join(dir, "db0", "rp0", "1", "000001.tsm"), make([]byte, 100), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	s := MustOpenService()
+	defer s.Close()
+	s.TSDBStore.PathFn = func() string { return dir }
+	s.TSDBStore.ShardRelativePathFn = func(id uint64) (string, error) {
+		if id != 1 {
+			return "", os.ErrNotExist
+		}
+		return filepath.Join("db0", "rp0", "1"), nil
+	}
+
+	c := cluster.NewClient(time.Second)
+	if shards, err := c.ShardStatus(s.Addr().String(), []uint64{1, 2}); err != nil {
+		t.Fatal(err)
+	} else if exp := []rpc.ShardStatus{{ShardID: 1, Size: 100}}; !reflect.DeepEqual(shards, exp) {
+		t.Fatalf("unexpected shards: %+v", shards)
+	}
+}
+
+// Ensure a shard is deleted from a node.
+func TestClient_RemoveShard(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	var deleted []uint64
+	s.TSDBStore.DeleteShardFn = func(id uint64) error {
+		deleted = append(deleted, id)
+		return nil
+	}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.RemoveShard(s.Addr().String(), 3); err != nil {
+		t.Fatal(err)
+	} else if exp := []uint64{3}; !reflect.DeepEqual(deleted, exp) {
+		t.Fatalf("unexpected deleted shards: %v", deleted)
+	}
+}
+
+// Ensure the hinted handoff queues of a node are listed and controlled.
+func TestClient_HintedHandoff(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	c := cluster.NewClient(time.Second)
+	if _, err := c.HintedHandoffStatus(s.Addr().String()); err == nil || err.Error() != hh.ErrHintedHandoffDisabled.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	modified := time.Unix(0, 10).UTC()
+	var actions []string
+	s.HintedHandoff = &HintedHandoff{
+		StatusFn: func() []hh.NodeStatus {
+			return []hh.NodeStatus{{NodeID: 2, Bytes: 1024, Segments: 1, HeadModified: modified, Paused: true}}
+		},
+		ActionFn: func(action string, nodeID uint64) error {
+			if nodeID != 2 {
+				return fmt.Errorf("no hinted handoff queue for node %d", nodeID)
+			}
+			actions = append(actions, action)
+			return nil
+		},
+	}
+
+	if queues, err := c.HintedHandoffStatus(s.Addr().String()); err != nil {
+		t.Fatal(err)
+	} else if exp := []rpc.HintedHandoffQueue{{NodeID: 2, Bytes: 1024, Segments: 1, HeadModified: modified, Paused: true}}; !reflect.DeepEqual(queues, exp) {
+		t.Fatalf("unexpected queues: %+v", queues)
+	}
+
+	for _, action := range []string{rpc.HintedHandoffDrain, rpc.HintedHandoffPurge, rpc.HintedHandoffPause, rpc.HintedHandoffResume} {
+		if err := c.HintedHandoffAction(s.Addr().String(), 2, action); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if exp := []string{"drain", "purge", "pause", "resume"}; !reflect.DeepEqual(actions, exp) {
+		t.Fatalf("unexpected actions: %v", actions)
+	}
+
+	if err := c.HintedHandoffAction(s.Addr().String(), 3, rpc.HintedHandoffDrain); err == nil || err.Error() != "no hinted handoff queue for node 3" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := c.HintedHandoffAction(s.Addr().String(), 2, "flush"); err == nil {
+		t.Fatal("expected error for unknown action")
+	}
+}
+
+// HintedHandoff is a mockable implementation of cluster.Service.HintedHandoff.
+type HintedHandoff struct {
+	StatusFn func() []hh.NodeStatus
+	ActionFn func(action string, nodeID uint64) error
+}
+
+func (h *HintedHandoff) Status() []hh.NodeStatus    { return h.StatusFn() }
+func (h *HintedHandoff) Drain(nodeID uint64) error  { return h.ActionFn("drain", nodeID) }
+func (h *HintedHandoff) Purge(nodeID uint64) error  { return h.ActionFn("purge", nodeID) }
+func (h *HintedHandoff) Pause(nodeID uint64) error  { return h.ActionFn("pause", nodeID) }
+func (h *HintedHandoff) Resume(nodeID uint64) error { return h.ActionFn("resume", nodeID) }
+
+// Ensure a shard snapshot is streamed from a node.
+func TestClient_BackupShard(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	var sinces []time.Time
+	s.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		if id != 1 {
+			return fmt.Errorf("shard %d doesn't exist on this server", id)
+		}
+		sinces = append(sinces, since)
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}
+
+	c := cluster.NewClient(time.Second)
+	var buf bytes.Buffer
+	before := time.Now()
+	snapshotTime, err := c.BackupShard(s.Addr().String(), 1, time.Time{}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "snapshot" {
+		t.Fatalf("unexpected snapshot: %q", buf.String())
+	} else if snapshotTime.Before(before) || snapshotTime.After(time.Now()) {
+		t.Fatalf("unexpected snapshot time: %v", snapshotTime)
+	}
+
+	// Incremental snapshots pass the time of the previous snapshot.
+	if _, err := c.BackupShard(s.Addr().String(), 1, snapshotTime, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	} else if exp := []time.Time{{}, snapshotTime}; !reflect.DeepEqual(sinces, exp) {
+		t.Fatalf("unexpected since: %v", sinces)
+	}
+
+	if _, err := c.BackupShard(s.Addr().String(), 2, time.Time{}, ioutil.Discard); err == nil || err.Error() != "shard 2 doesn't exist on this server" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a shard snapshot taken from another shard is restored into a new
+// shard with its files moved under the path of the shard.
+func TestClient_RestoreShard(t *testing.T) {
+	s, _ := MustOpenCopyShardService()
+	defer s.Close()
+
+	var created []uint64
+	s.TSDBStore.CreateShardFn = func(database, policy string, shardID uint64, enabled bool) error {
+		if database != "db0" || policy != "rp0" {
+			t.Errorf("unexpected shard: %s.%s %d", database, policy, shardID)
+		}
+		created = append(created, shardID)
+		return nil
+	}
+	s.TSDBStore.ShardRelativePathFn = func(id uint64) (string, error) {
+		return filepath.Join("db0", "rp0", fmt.Sprint(id)), nil
+	}
+
+	files := make(map[string]string)
+	s.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			b, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			files[hdr.Name] = string(b)
+		}
+	}
+
+	// Snapshot shard 7 of another database.
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{"000000001-000000001.tsm", "000000002-000000001.tsm"} {
+		if err := tw.WriteHeader(&tar.Header{Name: "db1/rp1/7/" + name, Mode: 0600, Size: 4}); err != nil {
+			t.Fatal(err)
+		} else if _, err := tw.Write([]byte("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.RestoreShard(s.Addr().String(), 3, &buf); err != nil {
+		t.Fatal(err)
+	} else if exp := []uint64{3}; !reflect.DeepEqual(created, exp) {
+		t.Fatalf("unexpected created shards: %v", created)
+	} else if exp := map[string]string{
+		"db0/rp0/3/000000001-000000001.tsm": "data",
+		"db0/rp0/3/000000002-000000001.tsm": "data",
+	}; !reflect.DeepEqual(files, exp) {
+		t.Fatalf("unexpected restored files: %v", files)
+	}
+
+	// Errors restoring the shard are returned after the snapshot is sent.
+	s.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		return errors.New("marker")
+	}
+	if err := c.RestoreShard(s.Addr().String(), 3, bytes.NewReader(make([]byte, 1<<20))); err == nil || err.Error() != "marker" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a restore is refused with an error by a service without meta client.
+func TestClient_RestoreShard_NoMetaClient(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	c := cluster.NewClient(time.Second)
+	if err := c.RestoreShard(s.Addr().String(), 3, bytes.NewReader(make([]byte, 1024))); err == nil || err.Error() != "restore shard: meta client not set" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"errors"
 	"time"
 
 	"github.com/influxdata/influxdb/influxql"
@@ -42,6 +43,33 @@ const (
 	// DefaultMaxSelectSeriesN is the maximum number of series a SELECT can run.
 	// A value of zero will make the maximum series count unlimited.
 	DefaultMaxSelectBucketsN = 0
+
+	// DefaultRebalanceEnabled disables the shard rebalancer by default.
+	DefaultRebalanceEnabled = false
+
+	// DefaultRebalanceCheckInterval is the default time between rebalance checks.
+	DefaultRebalanceCheckInterval = 10 * time.Minute
+
+	// DefaultRebalanceThrottle is the default pause between two shard moves.
+	DefaultRebalanceThrottle = 10 * time.Second
+
+	// DefaultAntiEntropyEnabled disables the anti-entropy service by default.
+	DefaultAntiEntropyEnabled = false
+
+	// DefaultAntiEntropyCheckInterval is the default time between two
+	// comparisons of the shard replicas.
+	DefaultAntiEntropyCheckInterval = 30 * time.Minute
+
+	// DefaultAntiEntropyDigestInterval is the default time range covered by
+	// a single series digest.
+	DefaultAntiEntropyDigestInterval = time.Hour
+
+	// DefaultAntiEntropyRepair only reports divergent replicas by default.
+	DefaultAntiEntropyRepair = false
+
+	// DefaultDiskUsageReportInterval is the default time between two reports
+	// of the disk usage of a data node to the meta service.
+	DefaultDiskUsageReportInterval = time.Minute
 )
 
 // Config represents the configuration for the clustering service.
@@ -50,7 +78,7 @@ type Config struct {
 	ShardWriterTimeout        toml.Duration `toml:"shard-writer-timeout"`
 	ShardReaderTimeout        toml.Duration `toml:"shard-reader-timeout"`
 	MaxRemoteWriteConnections int           `toml:"max-remote-write-connections"`
-	ClusterTracing            bool          `toml:"cluster-tracing`
+	ClusterTracing            bool          `toml:"cluster-tracing"`
 	WriteTimeout              toml.Duration `toml:"write-timeout"`
 	MaxConcurrentQueries      int           `toml:"max-concurrent-queries"`
 	QueryTimeout              toml.Duration `toml:"query-timeout"`
@@ -58,6 +86,34 @@ type Config struct {
 	MaxSelectPointN           int           `toml:"max-select-point"`
 	MaxSelectSeriesN          int           `toml:"max-select-series"`
 	MaxSelectBucketsN         int           `toml:"max-select-buckets"`
+	RebalanceEnabled          bool          `toml:"rebalance-enabled"`
+	RebalanceCheckInterval    toml.Duration `toml:"rebalance-check-interval"`
+	RebalanceDryRun           bool          `toml:"rebalance-dry-run"`
+	RebalanceThrottle         toml.Duration `toml:"rebalance-throttle"`
+	AntiEntropyEnabled        bool          `toml:"anti-entropy-enabled"`
+	AntiEntropyCheckInterval  toml.Duration `toml:"anti-entropy-check-interval"`
+	AntiEntropyDigestInterval toml.Duration `toml:"anti-entropy-digest-interval"`
+	AntiEntropyRepair         bool          `toml:"anti-entropy-repair"`
+	DiskUsageReportInterval   toml.Duration `toml:"disk-usage-report-interval"`
+
+	// Labels describe the failure domain of this node, e.g. its zone and
+	// rack. They are registered with the meta service when the node joins a
+	// cluster so replicas of a shard are placed in distinct domains.
+	Labels map[string]string `toml:"labels"`
+
+	// TLSEnabled secures the traffic between data nodes with mutual TLS.
+	// Every node presents TLSCertificate, which must be signed by a CA in
+	// TLSCABundle, and verifies the certificates of its peers against it.
+	TLSEnabled     bool   `toml:"tls-enabled"`
+	TLSCertificate string `toml:"tls-certificate"`
+	TLSPrivateKey  string `toml:"tls-private-key"`
+	TLSCABundle    string `toml:"tls-ca-bundle"`
+
+	// MetaAuthEnabled signs the requests to the meta nodes with
+	// MetaInternalSharedSecret. It must be set when the meta nodes have
+	// auth enabled.
+	MetaAuthEnabled          bool   `toml:"meta-auth-enabled"`
+	MetaInternalSharedSecret string `toml:"meta-internal-shared-secret"`
 }
 
 // NewConfig returns an instance of Config with defaults.
@@ -74,5 +130,37 @@ func NewConfig() Config {
 		MaxSelectPointN:           DefaultMaxSelectPointN,
 		MaxSelectSeriesN:          DefaultMaxSelectSeriesN,
 		MaxSelectBucketsN:         DefaultMaxSelectBucketsN,
+		RebalanceEnabled:          DefaultRebalanceEnabled,
+		RebalanceCheckInterval:    toml.Duration(DefaultRebalanceCheckInterval),
+		RebalanceThrottle:         toml.Duration(DefaultRebalanceThrottle),
+		AntiEntropyEnabled:        DefaultAntiEntropyEnabled,
+		AntiEntropyCheckInterval:  toml.Duration(DefaultAntiEntropyCheckInterval),
+		AntiEntropyDigestInterval: toml.Duration(DefaultAntiEntropyDigestInterval),
+		AntiEntropyRepair:         DefaultAntiEntropyRepair,
+		DiskUsageReportInterval:   toml.Duration(DefaultDiskUsageReportInterval),
+	}
+}
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	if c.TLSEnabled {
+		if c.TLSCertificate == "" || c.TLSPrivateKey == "" {
+			return errors.New("cluster tls-certificate and tls-private-key must be specified when tls is enabled")
+		} else if c.TLSCABundle == "" {
+			return errors.New("cluster tls-ca-bundle must be specified when tls is enabled")
+		}
+	}
+	if c.RebalanceEnabled && c.RebalanceCheckInterval <= 0 {
+		return errors.New("cluster rebalance-check-interval must be positive when rebalance is enabled")
+	}
+	if c.AntiEntropyEnabled && c.AntiEntropyCheckInterval <= 0 {
+		return errors.New("cluster anti-entropy-check-interval must be positive when anti-entropy is enabled")
+	}
+	if (c.AntiEntropyEnabled || c.RebalanceEnabled) && c.AntiEntropyDigestInterval <= 0 {
+		return errors.New("cluster anti-entropy-digest-interval must be positive when anti-entropy or rebalance is enabled")
+	}
+	if c.MetaAuthEnabled && c.MetaInternalSharedSecret == "" {
+		return errors.New("cluster meta-internal-shared-secret must be specified when meta auth is enabled")
 	}
+	return nil
 }
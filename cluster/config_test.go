@@ -1,6 +1,7 @@
 package cluster_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -14,6 +15,14 @@ func TestConfig_Parse(t *testing.T) {
 	if _, err := toml.Decode(`
 shard-writer-timeout = "10s"
 write-timeout = "20s"
+tls-enabled = true
+tls-certificate = "/etc/influxdb/cert.pem"
+tls-private-key = "/etc/influxdb/key.pem"
+tls-ca-bundle = "/etc/influxdb/ca.pem"
+
+[labels]
+zone = "us-east-1a"
+rack = "r1"
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -23,5 +32,39 @@ write-timeout = "20s"
 		t.Fatalf("unexpected shard-writer timeout: %s", c.ShardWriterTimeout)
 	} else if time.Duration(c.WriteTimeout) != 20*time.Second {
 		t.Fatalf("unexpected write timeout s: %s", c.WriteTimeout)
+	} else if exp := map[string]string{"zone": "us-east-1a", "rack": "r1"}; !reflect.DeepEqual(c.Labels, exp) {
+		t.Fatalf("unexpected labels: %v", c.Labels)
+	} else if !c.TLSEnabled || c.TLSCertificate != "/etc/influxdb/cert.pem" || c.TLSPrivateKey != "/etc/influxdb/key.pem" || c.TLSCABundle != "/etc/influxdb/ca.pem" {
+		t.Fatalf("unexpected tls config: %+v", c)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure a rebalancer without a check interval is rejected.
+func TestConfig_Validate_RebalanceCheckInterval(t *testing.T) {
+	c := cluster.NewConfig()
+	c.RebalanceEnabled = true
+	c.RebalanceCheckInterval = 0
+	if err := c.Validate(); err == nil || err.Error() != "cluster rebalance-check-interval must be positive when rebalance is enabled" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure anti-entropy without a check or digest interval is rejected.
+func TestConfig_Validate_AntiEntropyIntervals(t *testing.T) {
+	c := cluster.NewConfig()
+	c.AntiEntropyEnabled = true
+	c.AntiEntropyCheckInterval = 0
+	if err := c.Validate(); err == nil || err.Error() != "cluster anti-entropy-check-interval must be positive when anti-entropy is enabled" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c = cluster.NewConfig()
+	c.AntiEntropyEnabled = true
+	c.AntiEntropyDigestInterval = 0
+	if err := c.Validate(); err == nil || err.Error() != "cluster anti-entropy-digest-interval must be positive when anti-entropy or rebalance is enabled" {
+		t.Fatalf("unexpected error: %v", err)
 	}
 }
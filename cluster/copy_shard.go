@@ -0,0 +1,309 @@
+package cluster
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/zhexuany/influxcloud/rpc"
+	"github.com/zhexuany/influxcloud/tlv"
+)
+
+var (
+	// ErrCopyShardInProgress is returned when a shard is already being copied
+	// from the same source to the same destination.
+	ErrCopyShardInProgress = errors.New("copy shard already in progress")
+
+	// ErrCopyShardNotFound is returned when killing a copy that is not running.
+	ErrCopyShardNotFound = errors.New("copy shard not found")
+
+	// ErrCopyShardKilled is returned when a running copy is killed.
+	ErrCopyShardKilled = errors.New("copy shard killed")
+)
+
+// copyShardKey identifies a shard copy running on a destination node.
+type copyShardKey struct {
+	shardID uint64
+	source  string
+	dest    string
+}
+
+// copyShardTask tracks a shard copy streaming from a source node.
+type copyShardTask struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	killed bool
+
+	key       copyShardKey
+	database  string
+	policy    string
+	copied    uint64
+	startedAt time.Time
+}
+
+// setConn sets the connection to the source node so that the copy can be
+// aborted. The connection is closed immediately if the task was killed.
+func (t *copyShardTask) setConn(conn net.Conn) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.killed {
+		conn.Close()
+		return ErrCopyShardKilled
+	}
+	t.conn = conn
+	return nil
+}
+
+// kill aborts the copy by closing the connection to the source node.
+func (t *copyShardTask) kill() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.killed = true
+	if t.conn != nil {
+		t.conn.Close()
+	}
+}
+
+// isKilled returns true if the task has been killed.
+func (t *copyShardTask) isKilled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.killed
+}
+
+// status returns the current progress of the task.
+func (t *copyShardTask) status() rpc.CopyShardStatus {
+	return rpc.CopyShardStatus{
+		ShardID:     t.key.shardID,
+		Source:      t.key.source,
+		Dest:        t.key.dest,
+		Database:    t.database,
+		Policy:      t.policy,
+		CurrentSize: atomic.LoadUint64(&t.copied),
+		StartedAt:   t.startedAt,
+	}
+}
+
+// reader wraps r so that the bytes read from the source node are counted.
+func (t *copyShardTask) reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, n: &t.copied}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n *uint64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	atomic.AddUint64(r.n, uint64(n))
+	return n, err
+}
+
+// snapshotWriter streams a shard snapshot as length-value chunks so that the
+// end of the snapshot can be distinguished from a broken connection.
+type snapshotWriter struct {
+	w io.Writer
+}
+
+func (w *snapshotWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := tlv.WriteLV(w.w, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes the empty chunk marking the end of the snapshot.
+func (w *snapshotWriter) Close() error {
+	return tlv.WriteLV(w.w, nil)
+}
+
+// snapshotReader reads a shard snapshot written by snapshotWriter.
+type snapshotReader struct {
+	r   io.Reader
+	buf []byte
+	eof bool
+}
+
+func (r *snapshotReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+
+		buf, err := tlv.ReadLV(r.r)
+		if err != nil {
+			return 0, err
+		} else if len(buf) == 0 {
+			r.eof = true
+		}
+		r.buf = buf
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// relocateSnapshot returns the tar archive read from r with its entries moved
+// under the shard relative path. Entries of a shard snapshot are named
+// <database>/<retention policy>/<shard id>/<file>. Closing the returned reader
+// stops the relocation and waits until r is no longer read.
+func relocateSnapshot(r io.Reader, path string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tr, tw := tar.NewReader(r), tar.NewWriter(pw)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			a := strings.SplitN(hdr.Name, "/", 4)
+			if len(a) != 4 {
+				pw.CloseWithError(fmt.Errorf("unexpected snapshot file: %s", hdr.Name))
+				return
+			}
+			hdr.Name = filepath.ToSlash(filepath.Join(path, filepath.FromSlash(a[3])))
+
+			if err := tw.WriteHeader(hdr); err != nil {
+				pw.CloseWithError(err)
+				return
+			} else if _, err := io.Copy(tw, tr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+	return &relocatedSnapshot{PipeReader: pr, done: done}
+}
+
+// relocatedSnapshot is the reader returned by relocateSnapshot.
+type relocatedSnapshot struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+func (r *relocatedSnapshot) Close() error {
+	err := r.PipeReader.Close()
+	<-r.done
+	return err
+}
+
+// shardReader reads the digests and points of a shard from a data node.
+type shardReader interface {
+	ShardDigest(addr string, shardID uint64, interval time.Duration) ([]rpc.SeriesDigest, error)
+	ShardPoints(addr string, shardID uint64, keys []string, start, end int64) ([]models.Point, error)
+}
+
+// shardWriter writes points to one owner of a shard.
+type shardWriter interface {
+	WriteShard(shardID, ownerID uint64, points []models.Point) error
+}
+
+// storeShardWriter writes points to the shards of the local store.
+type storeShardWriter struct {
+	store interface {
+		WriteToShard(shardID uint64, points []models.Point) error
+	}
+}
+
+// WriteShard writes points to the local shard. The owner is ignored.
+func (w storeShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point) error {
+	return w.store.WriteToShard(shardID, points)
+}
+
+// catchUpShard writes the points of a shard missing on dst after the shard
+// was copied from src. A copy is a snapshot of the source, so points written
+// to src while the copy ran are found by comparing the digests of both nodes
+// over the given interval. It returns the number of points written to dst.
+func catchUpShard(r shardReader, w shardWriter, shardID uint64, src, dst *meta.NodeInfo, interval time.Duration) (int, error) {
+	srcDigests, err := r.ShardDigest(src.TCPHost, shardID, interval)
+	if err != nil {
+		return 0, fmt.Errorf("digest from node %d: %s", src.ID, err)
+	}
+	dstDigests, err := r.ShardDigest(dst.TCPHost, shardID, interval)
+	if err != nil {
+		return 0, fmt.Errorf("digest from node %d: %s", dst.ID, err)
+	}
+
+	m := make(map[digestKey]rpc.SeriesDigest, len(dstDigests))
+	for _, d := range dstDigests {
+		m[digestKey{key: d.Key, start: d.Start}] = d
+	}
+
+	var n int
+	for _, d := range srcDigests {
+		if o, ok := m[digestKey{key: d.Key, start: d.Start}]; ok && o.Count == d.Count && o.Sum == d.Sum {
+			continue
+		}
+
+		points, err := r.ShardPoints(src.TCPHost, shardID, []string{d.Key}, d.Start, d.Start+int64(interval))
+		if err != nil {
+			return n, fmt.Errorf("read points from node %d: %s", src.ID, err)
+		} else if len(points) == 0 {
+			continue
+		}
+
+		if err := w.WriteShard(shardID, dst.ID, points); err != nil {
+			return n, fmt.Errorf("write points to node %d: %s", dst.ID, err)
+		}
+		n += len(points)
+	}
+	return n, nil
+}
+
+// copyShardStatuses sorts copy shard statuses by shard, source and destination.
+type copyShardStatuses []rpc.CopyShardStatus
+
+func (a copyShardStatuses) Len() int      { return len(a) }
+func (a copyShardStatuses) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a copyShardStatuses) Less(i, j int) bool {
+	if a[i].ShardID != a[j].ShardID {
+		return a[i].ShardID < a[j].ShardID
+	} else if a[i].Source != a[j].Source {
+		return a[i].Source < a[j].Source
+	}
+	return a[i].Dest < a[j].Dest
+}
+
+// dialTCPHost connects to the cluster service listening on addr.
+func dialTCPHost(addr string, timeout time.Duration, t *TLS) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the cluster multiplexing header byte.
+	if _, err := conn.Write([]byte{MuxHeader}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write mux header: %s", err)
+	}
+
+	// The mux reads the header in plaintext before TLS starts.
+	tlsConn, err := t.client(conn, addr, timeout)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
@@ -0,0 +1,320 @@
+package cluster_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud"
+	"github.com/zhexuany/influxcloud/cluster"
+)
+
+// Ensure a shard is streamed from the source node and added to the destination.
+func TestService_CopyShard(t *testing.T) {
+	src := MustOpenService()
+	defer src.Close()
+	src.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		if id != 1 {
+			t.Errorf("unexpected shard id: %d", id)
+		}
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}
+
+	dst, mc := MustOpenCopyShardService()
+	defer dst.Close()
+
+	var created, restored []byte
+	dst.TSDBStore.CreateShardFn = func(database, policy string, shardID uint64, enabled bool) error {
+		if database != "db0" || policy != "rp0" || shardID != 1 {
+			t.Errorf("unexpected shard: %s.%s %d", database, policy, shardID)
+		}
+		created = append(created, byte(shardID))
+		return nil
+	}
+	dst.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		buf, err := ioutil.ReadAll(r)
+		restored = buf
+		return err
+	}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.CopyShard(src.Addr().String(), dst.Addr().String(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(created) != 1 {
+		t.Fatalf("expected shard to be created")
+	} else if string(restored) != "snapshot" {
+		t.Fatalf("unexpected snapshot: %q", restored)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(mc.Owners[2], exp) {
+		t.Fatalf("unexpected owned shards: %v", mc.Owners[2])
+	} else if len(mc.Pending[2]) != 0 {
+		t.Fatalf("unexpected pending shards: %v", mc.Pending[2])
+	}
+}
+
+// Ensure the points written to the source during a copy are caught up on the
+// destination before it becomes an owner.
+func TestService_CopyShard_CatchUp(t *testing.T) {
+	points := []influxql.FloatPoint{
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 0, Aux: []interface{}{float64(1)}},
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 10 * int64(time.Second), Aux: []interface{}{float64(2)}},
+	}
+	shardGroupFn := func(a []influxql.FloatPoint) func(ids []uint64) tsdb.ShardGroup {
+		return func(ids []uint64) tsdb.ShardGroup {
+			return &ShardGroup{
+				Points:       a,
+				Measurements: []string{"cpu"},
+				Fields:       map[string]influxql.DataType{"value": influxql.Float},
+				Dimensions:   map[string]struct{}{"host": struct{}{}},
+			}
+		}
+	}
+
+	// The snapshot copied only has the first point.
+	src := MustOpenService()
+	defer src.Close()
+	src.TSDBStore.ShardGroupFn = shardGroupFn(points)
+
+	dst, mc := MustOpenCopyShardService()
+	defer dst.Close()
+	dst.TSDBStore.ShardGroupFn = shardGroupFn(points[:1])
+
+	var writes []string
+	dst.TSDBStore.WriteToShardFn = func(shardID uint64, points []models.Point) error {
+		if len(mc.Pending[2]) != 1 {
+			t.Errorf("unexpected write to a committed owner")
+		}
+		for _, p := range points {
+			writes = append(writes, fmt.Sprintf("%d %s", shardID, p.String()))
+		}
+		return nil
+	}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.CopyShard(src.Addr().String(), dst.Addr().String(), 1); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{
+		"1 cpu,host=serverA value=1 0",
+		"1 cpu,host=serverA value=2 10000000000",
+	}; !reflect.DeepEqual(writes, exp) {
+		t.Fatalf("unexpected writes: %v", writes)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(mc.Owners[2], exp) {
+		t.Fatalf("unexpected owned shards: %v", mc.Owners[2])
+	}
+}
+
+// Ensure a copy is refused by a node not registered at the destination
+// address of the request.
+func TestService_CopyShard_WrongDest(t *testing.T) {
+	src := MustOpenService()
+	defer src.Close()
+
+	dst, mc := MustOpenCopyShardService()
+	defer dst.Close()
+	mc.TCPHost = "host2:8088"
+
+	c := cluster.NewClient(time.Second)
+	exp := fmt.Sprintf("copy shard: data node 2 is registered as host2:8088, not %s", dst.Addr())
+	if err := c.CopyShard(src.Addr().String(), dst.Addr().String(), 1); err == nil || err.Error() != exp {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(mc.Pending[2]) != 0 || len(mc.Owners) != 0 {
+		t.Fatalf("unexpected owners: %v, pending: %v", mc.Owners, mc.Pending)
+	}
+}
+
+// Ensure a copy fails on a destination without a meta client.
+func TestService_CopyShard_NoMetaClient(t *testing.T) {
+	dst := MustOpenService()
+	defer dst.Close()
+	dst.Node = &influxcloud.Node{ID: 2}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.CopyShard("127.0.0.1:0", dst.Addr().String(), 1); err == nil || err.Error() != "copy shard: meta client not set" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a shard is removed from the destination when the snapshot fails.
+func TestService_CopyShard_SnapshotError(t *testing.T) {
+	src := MustOpenService()
+	defer src.Close()
+	src.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		if _, err := w.Write([]byte("snap")); err != nil {
+			return err
+		}
+		return errors.New("marker")
+	}
+
+	dst, mc := MustOpenCopyShardService()
+	defer dst.Close()
+
+	var deleted bool
+	dst.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		_, err := ioutil.ReadAll(r)
+		return err
+	}
+	dst.TSDBStore.DeleteShardFn = func(id uint64) error {
+		deleted = true
+		return nil
+	}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.CopyShard(src.Addr().String(), dst.Addr().String(), 1); err == nil || err.Error() != "marker" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !deleted {
+		t.Fatal("expected shard to be deleted")
+	} else if len(mc.Owners) != 0 || len(mc.Pending[2]) != 0 {
+		t.Fatalf("unexpected owners: %v, pending: %v", mc.Owners, mc.Pending)
+	}
+}
+
+// Ensure a running copy is reported and can be killed.
+func TestService_KillCopyShard(t *testing.T) {
+	src := MustOpenService()
+	defer src.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	src.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		if _, err := w.Write([]byte("snap")); err != nil {
+			return err
+		}
+		<-done
+		return nil
+	}
+
+	dst, mc := MustOpenCopyShardService()
+	defer dst.Close()
+
+	read := make(chan struct{})
+	dst.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		var buf [4]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		close(read)
+		_, err := ioutil.ReadAll(r)
+		return err
+	}
+	deleted := make(chan uint64, 1)
+	dst.TSDBStore.DeleteShardFn = func(id uint64) error {
+		deleted <- id
+		return nil
+	}
+
+	c := cluster.NewClient(time.Second)
+	errC := make(chan error, 1)
+	go func() {
+		errC <- c.CopyShard(src.Addr().String(), dst.Addr().String(), 1)
+	}()
+
+	select {
+	case <-read:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for snapshot")
+	}
+
+	tasks, err := c.CopyShardStatus(dst.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(tasks) != 1 {
+		t.Fatalf("unexpected task count: %d", len(tasks))
+	} else if task := tasks[0]; task.ShardID != 1 || task.Source != src.Addr().String() || task.Database != "db0" || task.Policy != "rp0" || task.CurrentSize != 4 {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+
+	if err := c.KillCopyShard(src.Addr().String(), dst.Addr().String(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errC:
+		if err == nil || err.Error() != cluster.ErrCopyShardKilled.Error() {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for copy to be killed")
+	}
+
+	if id := <-deleted; id != 1 {
+		t.Fatalf("unexpected deleted shard: %d", id)
+	} else if len(mc.Owners) != 0 || len(mc.Pending[2]) != 0 {
+		t.Fatalf("unexpected owners: %v, pending: %v", mc.Owners, mc.Pending)
+	}
+
+	if err := c.KillCopyShard(src.Addr().String(), dst.Addr().String(), 1); err == nil || err.Error() != cluster.ErrCopyShardNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// MustOpenCopyShardService returns an open service for node 2 which does not
+// own shard 1 in db0.rp0.
+func MustOpenCopyShardService() (*Service, *CopyShardMetaClient) {
+	mc := &CopyShardMetaClient{
+		Pending: make(map[uint64][]uint64),
+		Owners:  make(map[uint64][]uint64),
+	}
+	s := NewService()
+	s.Node = &influxcloud.Node{ID: 2}
+	s.MetaClient = mc
+	s.ln = MustListen("tcp", "127.0.0.1:0")
+	s.Listener = &muxListener{s.ln}
+	if err := s.Open(); err != nil {
+		panic(err)
+	}
+	mc.TCPHost = s.Addr().String()
+	return s, mc
+}
+
+// CopyShardMetaClient is a test meta client recording pending and committed
+// shard owners. Every data node is registered at TCPHost.
+type CopyShardMetaClient struct {
+	TCPHost string
+	Pending map[uint64][]uint64
+	Owners  map[uint64][]uint64
+}
+
+func (c *CopyShardMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	return &meta.NodeInfo{ID: id, TCPHost: c.TCPHost}, nil
+}
+
+func (c *CopyShardMetaClient) ShardOwner(shardID uint64) (string, string, meta.ShardInfo) {
+	return "db0", "rp0", meta.ShardInfo{ID: shardID, Owners: []meta.ShardOwner{{NodeID: 1}}}
+}
+
+func (c *CopyShardMetaClient) AddPendingShardOwner(shardID, nodeID uint64) error {
+	c.Pending[nodeID] = append(c.Pending[nodeID], shardID)
+	return nil
+}
+
+func (c *CopyShardMetaClient) CommitPendingShardOwner(shardID, nodeID uint64) error {
+	if err := c.RemovePendingShardOwner(shardID, nodeID); err != nil {
+		return err
+	}
+	c.Owners[nodeID] = append(c.Owners[nodeID], shardID)
+	return nil
+}
+
+func (c *CopyShardMetaClient) RemovePendingShardOwner(shardID, nodeID uint64) error {
+	var other []uint64
+	for _, id := range c.Pending[nodeID] {
+		if id != shardID {
+			other = append(other, id)
+		}
+	}
+	if len(other) == len(c.Pending[nodeID]) {
+		return fmt.Errorf("pending shard owner not found")
+	}
+	c.Pending[nodeID] = other
+	return nil
+}
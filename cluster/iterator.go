@@ -1,96 +1,317 @@
 package cluster
 
 import (
+	"encoding"
+	"fmt"
 	"net"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/zhexuany/influxcloud/rpc"
 	"github.com/zhexuany/influxcloud/tlv"
-	"sort"
 )
 
+// remoteIteratorCreator creates iterators for shards owned by other data nodes.
+// It lives for a single query: nodes which can't be reached are skipped for
+// the rest of the query and the fields and dimensions of a measurement are
+// only requested once.
 type remoteIteratorCreator struct {
-	nodeDialer *NodeDialer
+	dialer *NodeDialer
+
+	// owners maps shard ids to the remote nodes owning them, in the order
+	// they are read from.
+	owners map[uint64][]uint64
 
-	ids []uint64
+	mu sync.Mutex
+
+	// failed holds the nodes which could not be reached.
+	failed map[uint64]bool
+
+	// fieldDimensions caches the fields and dimensions by measurement.
+	fieldDimensions map[string]*fieldDimensions
 }
 
-//
-func (ric *remoteIteratorCreator) shardIDs() uint64Slice {
-	ids := make(uint64Slice, len(ric.ids))
-	for i, id := range ric.ids {
-		ids[i] = id
+// fieldDimensions holds the fields and dimensions of a measurement.
+type fieldDimensions struct {
+	fields     map[string]influxql.DataType
+	dimensions map[string]struct{}
+}
+
+// newRemoteIteratorCreator returns a remoteIteratorCreator reading the shards
+// of owners through dialer.
+func newRemoteIteratorCreator(dialer *NodeDialer, owners map[uint64][]uint64) *remoteIteratorCreator {
+	return &remoteIteratorCreator{
+		dialer:          dialer,
+		owners:          owners,
+		failed:          make(map[uint64]bool),
+		fieldDimensions: make(map[string]*fieldDimensions),
 	}
-	return ids
 }
 
-//
-func (ric *remoteIteratorCreator) nodeIDs() uint64Slice {
-	ids := make(uint64Slice, 0)
-	sort.Sort(ids)
-	return ids
+// nodeError is returned when a remote node can't be reached or the connection
+// to it fails. The shards of the node are then read from another owner.
+type nodeError struct {
+	id  uint64
+	err error
 }
 
-// func (ric *remoteIteratorCreator) nodeIteratorCreators() influxql.IteratorCreators {
-// 	shardIDs := ric.shardIDs()
-// 	nodeIDS := ric.nodeIDs()
-// 	return nil
-// }
+func (e *nodeError) Error() string {
+	return fmt.Sprintf("data node %d: %s", e.id, e.err)
+}
 
-//
-func (ric *remoteIteratorCreator) createIterators(stmt *influxql.SelectStatement, ctx *influxql.ExecutionContext) (influxql.Iterators, *influxql.SelectStatement, error) {
-	// m := make
-	// ric.createNodeIterator()
-	return nil, nil, nil
+// assign groups the shards by the first owner which hasn't failed.
+func (ric *remoteIteratorCreator) assign(shardIDs []uint64) (map[uint64][]uint64, error) {
+	ric.mu.Lock()
+	defer ric.mu.Unlock()
+
+	shards := make(map[uint64][]uint64)
+	for _, shardID := range shardIDs {
+		owner, ok := uint64(0), false
+		for _, id := range ric.owners[shardID] {
+			if !ric.failed[id] {
+				owner, ok = id, true
+				break
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("no available owner for shard %d", shardID)
+		}
+		shards[owner] = append(shards[owner], shardID)
+	}
+	return shards, nil
 }
 
-func (ric *remoteIteratorCreator) createNodeIterator(id uint64, shardIDs uint64Slice, opt influxql.IteratorOptions) (influxql.Iterator, error) {
-	conn, err := ric.nodeDialer.DialNode(id)
+// eachNode calls fn with the shards read from every remote node. When a node
+// can't be reached, its shards are read from their next owner instead.
+func (ric *remoteIteratorCreator) eachNode(fn func(id uint64, shardIDs []uint64) error) error {
+	pending, err := ric.assign(ric.shardIDs())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	//
-	//
-	if err := func() error {
-		req := rpc.CreateIteratorRequest{}
-		req.ShardIDs = []uint64(shardIDs)
-		req.Opt = opt
+	for len(pending) > 0 {
+		ids := make(uint64Slice, 0, len(pending))
+		for id := range pending {
+			ids = append(ids, id)
+		}
+		sort.Sort(ids)
 
-		if err := tlv.EncodeTLV(conn, tlv.CreateIteratorRequestMessage, &req); err != nil {
+		id := ids[0]
+		shardIDs := pending[id]
+		delete(pending, id)
 
+		err := fn(id, shardIDs)
+		if err == nil {
+			continue
+		} else if _, ok := err.(*nodeError); !ok {
+			return err
 		}
 
-		//
-		resp := rpc.CreateIteratorResponse{}
-		if typ, err := tlv.DecodeTLV(conn, &resp); err != nil {
+		ric.mu.Lock()
+		ric.failed[id] = true
+		ric.mu.Unlock()
 
-		} else if typ != tlv.CreateIteratorResponseMessage {
+		next, aerr := ric.assign(shardIDs)
+		if aerr != nil {
+			return fmt.Errorf("%s: %s", aerr, err)
+		}
+		for owner, ids := range next {
+			pending[owner] = append(pending[owner], ids...)
+		}
+	}
+	return nil
+}
+
+// shardIDs returns a sorted list of all shard ids.
+func (ric *remoteIteratorCreator) shardIDs() uint64Slice {
+	ids := make(uint64Slice, 0, len(ric.owners))
+	for id := range ric.owners {
+		ids = append(ids, id)
+	}
+	sort.Sort(ids)
+	return ids
+}
 
+// createIterators creates one iterator per remote node.
+func (ric *remoteIteratorCreator) createIterators(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterators, error) {
+	var itrs influxql.Iterators
+	if err := ric.eachNode(func(id uint64, shardIDs []uint64) error {
+		itr, err := ric.createNodeIterator(id, shardIDs, m, opt)
+		if err != nil {
+			return err
+		} else if itr != nil {
+			itrs = append(itrs, itr)
 		}
+		return nil
+	}); err != nil {
+		itrs.Close()
+		return nil, err
+	}
+	return itrs, nil
+}
 
-		err := resp.Err
-		return err
+// createNodeIterator requests an iterator for shardIDs from node id. The
+// returned iterator reads points directly off the connection and closes it
+// when the iterator is closed.
+func (ric *remoteIteratorCreator) createNodeIterator(id uint64, shardIDs []uint64, m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+	conn, err := ric.dialer.DialNode(id)
+	if err != nil {
+		return nil, &nodeError{id: id, err: err}
+	}
+
+	var resp rpc.CreateIteratorResponse
+	if err := func() error {
+		// Write request.
+		if err := tlv.EncodeTLV(conn, tlv.CreateIteratorRequestMessage, &rpc.CreateIteratorRequest{
+			ShardIDs:    shardIDs,
+			Measurement: *m,
+			Opt:         opt,
+		}); err != nil {
+			return &nodeError{id: id, err: err}
+		}
+
+		// Read the response.
+		if typ, err := tlv.DecodeTLV(conn, &resp); err != nil {
+			return &nodeError{id: id, err: err}
+		} else if typ != tlv.CreateIteratorResponseMessage {
+			return fmt.Errorf("unexpected response type: %d", typ)
+		}
+		return resp.Err
 	}(); err != nil {
+		conn.Close()
+		return nil, err
+	}
 
+	// The remote node had nothing to read for these shards.
+	if resp.Type == influxql.Unknown {
+		conn.Close()
+		return nil, nil
 	}
 
-	// it := influxql.NewReaderIterator(conn, , stats influxql.IteratorStats)
+	return influxql.NewReaderIterator(conn, resp.Type, influxql.IteratorStats{}), nil
+}
 
-	// it = influxql.NewCloseInterruptIterator(it, closing)
-	return nil, nil
+// FieldDimensions returns the union of fields & dimensions from every remote
+// node. The result is cached for the rest of the query.
+func (ric *remoteIteratorCreator) FieldDimensions(m *influxql.Measurement) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+	key := m.String()
+	ric.mu.Lock()
+	fd := ric.fieldDimensions[key]
+	ric.mu.Unlock()
+	if fd != nil {
+		return fd.fields, fd.dimensions, nil
+	}
+
+	fields = make(map[string]influxql.DataType)
+	dimensions = make(map[string]struct{})
+
+	if err := ric.eachNode(func(id uint64, shardIDs []uint64) error {
+		var resp rpc.FieldDimensionsResponse
+		if err := ric.requestNode(id, tlv.FieldDimensionsRequestMessage, &rpc.FieldDimensionsRequest{
+			ShardIDs: shardIDs,
+			Sources:  influxql.Sources{m},
+		}, tlv.FieldDimensionsResponseMessage, &resp); err != nil {
+			return err
+		} else if resp.Err != nil {
+			return resp.Err
+		}
+
+		for k, typ := range resp.Fields {
+			if fields[k].LessThan(typ) {
+				fields[k] = typ
+			}
+		}
+		for k := range resp.Dimensions {
+			dimensions[k] = struct{}{}
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	ric.mu.Lock()
+	ric.fieldDimensions[key] = &fieldDimensions{fields: fields, dimensions: dimensions}
+	ric.mu.Unlock()
+	return fields, dimensions, nil
 }
 
-func (ric *remoteIteratorCreator) FieldDimensions(sources influxql.Sources) (fields, dimensions map[string]struct{}, err error) {
-	// return ric.nodeIteratorCreators().FieldDimensions(sources)
-	return nil, nil, nil
+// MapType returns the data type of field on the remote nodes. Tags are
+// reported as influxql.Tag.
+func (ric *remoteIteratorCreator) MapType(m *influxql.Measurement, field string) influxql.DataType {
+	fields, dimensions, err := ric.FieldDimensions(m)
+	if err != nil {
+		return influxql.Unknown
+	}
+
+	if typ, ok := fields[field]; ok {
+		return typ
+	} else if _, ok := dimensions[field]; ok {
+		return influxql.Tag
+	}
+	return influxql.Unknown
 }
 
+// ExpandSources expands regex sources against every remote node and returns
+// a sorted, de-duplicated list of sources.
 func (ric *remoteIteratorCreator) ExpandSources(sources influxql.Sources) (influxql.Sources, error) {
-	// return ric.nodeIteratorCreators().ExpandSources(sources)
-	return nil, nil
+	set := make(map[string]influxql.Source)
+	if err := ric.eachNode(func(id uint64, shardIDs []uint64) error {
+		var resp rpc.ExpandSourcesResponse
+		if err := ric.requestNode(id, tlv.ExpandSourcesRequestMessage, &rpc.ExpandSourcesRequest{
+			ShardIDs: shardIDs,
+			Sources:  sources,
+		}, tlv.ExpandSourcesResponseMessage, &resp); err != nil {
+			return err
+		} else if resp.Err != nil {
+			return resp.Err
+		}
+
+		for _, src := range resp.Sources {
+			set[src.String()] = src
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return sortedSources(set), nil
+}
+
+// requestNode sends a single request to node id and decodes its response.
+func (ric *remoteIteratorCreator) requestNode(id uint64, reqType byte, req encoding.BinaryMarshaler, respType byte, resp encoding.BinaryUnmarshaler) error {
+	conn, err := ric.dialer.DialNode(id)
+	if err != nil {
+		return &nodeError{id: id, err: err}
+	}
+	defer conn.Close()
+
+	if err := tlv.EncodeTLV(conn, reqType, req); err != nil {
+		return &nodeError{id: id, err: err}
+	}
+
+	if typ, err := tlv.DecodeTLV(conn, resp); err != nil {
+		return &nodeError{id: id, err: err}
+	} else if typ != respType {
+		return fmt.Errorf("unexpected response type: %d", typ)
+	}
+	return nil
+}
+
+// sortedSources converts a set of sources keyed by name into a sorted list.
+func sortedSources(set map[string]influxql.Source) influxql.Sources {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sources := make(influxql.Sources, 0, len(set))
+	for _, name := range names {
+		sources = append(sources, set[name])
+	}
+	return sources
 }
 
 type remoteNodeIteratorCreator struct {
@@ -128,6 +349,7 @@ func (mic *metaIteratorCreator) ExpandSources(sources influxql.Sources) (influxq
 
 type NodeDialer struct {
 	timeout    time.Duration
+	tls        *TLS
 	MetaClient interface {
 		DataNode(id uint64) (*meta.NodeInfo, error)
 	}
@@ -139,12 +361,7 @@ func (nd *NodeDialer) DialNode(id uint64) (net.Conn, error) {
 		return nil, err
 	}
 
-	conn, err := net.DialTimeout("tcp", node.TCPHost, nd.timeout)
-	if err != nil {
-		return nil, err
-	}
-	//
-	return conn, nil
+	return dialTCPHost(node.TCPHost, nd.timeout, nd.tls)
 }
 
 type uint64Slice []uint64
@@ -0,0 +1,338 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/zhexuany/influxcloud/rpc"
+)
+
+var (
+	// ErrNodeAlreadyJoined is returned when a data node already registered
+	// with a cluster is asked to join one.
+	ErrNodeAlreadyJoined = errors.New("data node already belongs to a cluster")
+
+	// ErrNodeNotJoined is returned when a data node not registered with a
+	// cluster is asked to leave it.
+	ErrNodeNotJoined = errors.New("data node does not belong to a cluster")
+
+	// ErrNoDrainTarget is returned when a data node leaving the cluster is the
+	// only owner of a shard and there is no other data node to move it to.
+	ErrNoDrainTarget = errors.New("no other data node to drain shards to")
+)
+
+// processJoinClusterRequest registers this node with the meta servers of the
+// request and persists the assigned node id.
+func (s *Service) processJoinClusterRequest(buf []byte) *rpc.JoinClusterResponse {
+	var req rpc.JoinClusterRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.JoinClusterResponse{Err: err.Error()}
+	}
+
+	n, err := s.joinCluster(&req)
+	if err != nil {
+		s.Logger.Warn("join cluster error: " + err.Error())
+		return &rpc.JoinClusterResponse{Err: err.Error()}
+	}
+	return &rpc.JoinClusterResponse{NodeID: n.ID, TCPHost: n.TCPHost}
+}
+
+func (s *Service) joinCluster(req *rpc.JoinClusterRequest) (*meta.NodeInfo, error) {
+	s.membershipMu.Lock()
+	defer s.membershipMu.Unlock()
+
+	if s.Node == nil || s.MembershipClient == nil {
+		return nil, errors.New("cluster membership is not supported by this node")
+	} else if s.Node.ID != 0 {
+		return nil, ErrNodeAlreadyJoined
+	} else if len(req.MetaAddrs) == 0 {
+		return nil, errors.New("at least one meta server is required")
+	} else if req.NodeAddr == "" {
+		return nil, errors.New("data node tcp address is required")
+	}
+
+	s.MembershipClient.SetMetaServers(req.MetaAddrs)
+	n, err := s.MembershipClient.CreateDataNodeWithLabels(s.HTTPAddr, req.NodeAddr, s.labels)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Node.ID = n.ID
+	if err := s.Node.Save(); err != nil {
+		return nil, err
+	}
+	s.Logger.Info(fmt.Sprintf("joined cluster as data node %d (%s)", n.ID, n.TCPHost))
+
+	if req.ImportMetaData {
+		if err := s.importMetaData(); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// importMetaData creates the databases stored on this node in the cluster
+// meta data. It is used when a standalone node joins a cluster.
+func (s *Service) importMetaData() error {
+	names := s.TSDBStore.Databases()
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := s.MembershipClient.CreateDatabase(name); err != nil {
+			return fmt.Errorf("import database %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// updateDataNodeRetryInterval is the time between two attempts to register
+// the labels of a restarted data node.
+var updateDataNodeRetryInterval = 5 * time.Second
+
+// updateDataNode registers the labels of this node with the meta service when
+// a node which joined a cluster starts, so that changed labels are used
+// without joining the cluster again. It retries until the meta service knows
+// the node.
+func (s *Service) updateDataNode() {
+	defer s.wg.Done()
+
+	for {
+		err := s.setDataNodeLabels()
+		if err == nil {
+			return
+		}
+		s.Logger.Warn("update data node error: " + err.Error())
+
+		select {
+		case <-s.closing:
+			return
+		case <-time.After(updateDataNodeRetryInterval):
+		}
+	}
+}
+
+// setDataNodeLabels updates the labels of this node if it joined a cluster.
+func (s *Service) setDataNodeLabels() error {
+	s.membershipMu.Lock()
+	defer s.membershipMu.Unlock()
+
+	if s.Node == nil || s.Node.ID == 0 || s.MembershipClient == nil {
+		return nil
+	}
+
+	nodes, err := s.MembershipClient.DataNodes()
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.ID == s.Node.ID {
+			return s.MembershipClient.UpdateDataNode(n.ID, n.Host, n.TCPHost, s.labels)
+		}
+	}
+	return fmt.Errorf("data node %d not found", s.Node.ID)
+}
+
+// reportDiskUsage periodically records the size of the shards stored on this
+// node with the meta service. Balanced shard placement prefers the data nodes
+// using less disk.
+func (s *Service) reportDiskUsage() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.diskInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			if err := s.setDiskUsage(); err != nil {
+				s.Logger.Warn("disk usage report error: " + err.Error())
+			}
+		}
+	}
+}
+
+// setDiskUsage records the disk usage of this node if it joined a cluster.
+func (s *Service) setDiskUsage() error {
+	s.membershipMu.Lock()
+	defer s.membershipMu.Unlock()
+
+	if s.Node == nil || s.Node.ID == 0 || s.MembershipClient == nil {
+		return nil
+	}
+
+	size, err := dirSize(s.TSDBStore.Path())
+	if err != nil {
+		return err
+	}
+	return s.MembershipClient.SetDataNodeDiskUsage(s.Node.ID, uint64(size))
+}
+
+// processLeaveClusterRequest drains the shards of this node and removes it
+// from the cluster.
+func (s *Service) processLeaveClusterRequest(buf []byte) *rpc.LeaveClusterReesponse {
+	var req rpc.LeaveClusterRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.LeaveClusterReesponse{Err: err.Error()}
+	}
+
+	if err := s.leaveCluster(&req); err != nil {
+		s.Logger.Warn("leave cluster error: " + err.Error())
+		return &rpc.LeaveClusterReesponse{Err: err.Error()}
+	}
+	return &rpc.LeaveClusterReesponse{}
+}
+
+func (s *Service) leaveCluster(req *rpc.LeaveClusterRequest) error {
+	s.membershipMu.Lock()
+	defer s.membershipMu.Unlock()
+
+	if s.Node == nil || s.MembershipClient == nil {
+		return errors.New("cluster membership is not supported by this node")
+	} else if s.Node.ID == 0 {
+		return ErrNodeNotJoined
+	}
+
+	nodes, err := s.MembershipClient.DataNodes()
+	if err != nil {
+		return err
+	}
+
+	var self *meta.NodeInfo
+	for i := range nodes {
+		if nodes[i].ID == s.Node.ID {
+			self = &nodes[i]
+		}
+	}
+	if self == nil {
+		return fmt.Errorf("data node %d not found", s.Node.ID)
+	} else if req.NodeAddr != "" && req.NodeAddr != self.TCPHost {
+		return fmt.Errorf("data node %d is registered as %s, not %s", self.ID, self.TCPHost, req.NodeAddr)
+	}
+
+	if err := s.drainShards(self, nodes); err != nil {
+		return err
+	}
+	if err := s.MembershipClient.DeleteDataNode(self.ID); err != nil {
+		return err
+	}
+
+	s.Node.ID = 0
+	if err := s.Node.Save(); err != nil {
+		return err
+	}
+	s.Logger.Info(fmt.Sprintf("data node %d (%s) left the cluster", self.ID, self.TCPHost))
+	return nil
+}
+
+// drainShards removes this node as an owner of all of its shards. Every shard
+// is first copied to a data node which doesn't own it yet, so that the shard
+// keeps its number of owners. The node sharing the fewest label values with
+// the other owners is preferred, then the one owning the fewest shards. The
+// points written to a hot shard during its copy are caught up before this
+// node is removed.
+func (s *Service) drainShards(self *meta.NodeInfo, nodes meta.NodeInfos) error {
+	labels, err := s.MembershipClient.DataNodeLabels()
+	if err != nil {
+		return err
+	}
+	dbs, err := s.MembershipClient.Databases()
+	if err != nil {
+		return err
+	}
+
+	// Count the shards owned by every other data node.
+	ids := make([]uint64, 0, len(nodes))
+	load := make(map[uint64]int, len(nodes))
+	others := make(map[uint64]*meta.NodeInfo, len(nodes))
+	for i := range nodes {
+		if n := &nodes[i]; n.ID != self.ID {
+			ids = append(ids, n.ID)
+			load[n.ID] = 0
+			others[n.ID] = n
+		}
+	}
+	sort.Sort(uint64Slice(ids))
+
+	type ownedShard struct {
+		si  meta.ShardInfo
+		hot bool
+	}
+
+	var owned []ownedShard
+	now := time.Now()
+	for _, dbi := range dbs {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+
+				for _, si := range sgi.Shards {
+					for _, o := range si.Owners {
+						if _, ok := load[o.NodeID]; ok {
+							load[o.NodeID]++
+						}
+					}
+					if si.OwnedBy(self.ID) {
+						owned = append(owned, ownedShard{si: si, hot: sgi.EndTime.After(now)})
+					}
+				}
+			}
+		}
+	}
+
+	c := NewClient(s.dialTimeout)
+	c.TLS = s.tls
+	for _, sh := range owned {
+		si := sh.si
+
+		owners := make([]uint64, 0, len(si.Owners))
+		for _, o := range si.Owners {
+			owners = append(owners, o.NodeID)
+		}
+
+		sort.Stable(nodesByLoad{ids: ids, load: load})
+		var dest *meta.NodeInfo
+		var conflicts int
+		for _, id := range ids {
+			if si.OwnedBy(id) {
+				continue
+			} else if c := labelConflicts(labels, id, owners, self.ID); dest == nil || c < conflicts {
+				dest, conflicts = others[id], c
+			}
+		}
+
+		if dest == nil && len(si.Owners) == 1 {
+			return ErrNoDrainTarget
+		} else if dest == nil {
+			// Every other data node already owns the shard.
+			s.Logger.Info(fmt.Sprintf("no data node left to copy shard %d to, removing the replica of data node %d", si.ID, self.ID))
+		} else {
+			if err := c.CopyShard(self.TCPHost, dest.TCPHost, si.ID); err != nil {
+				return fmt.Errorf("drain shard %d to node %d: %s", si.ID, dest.ID, err)
+			}
+			load[dest.ID]++
+
+			if sh.hot {
+				if s.ShardWriter == nil {
+					return fmt.Errorf("drain shard %d: shard writer not set", si.ID)
+				}
+				n, err := catchUpShard(c, s.ShardWriter, si.ID, self, dest, s.digestInterval)
+				if err != nil {
+					return fmt.Errorf("catch up shard %d on node %d: %s", si.ID, dest.ID, err)
+				}
+				s.Logger.Info(fmt.Sprintf("caught up %d points of shard %d on data node %d", n, si.ID, dest.ID))
+			}
+		}
+
+		if err := s.MembershipClient.RemoveShardOwner(si.ID, self.ID); err != nil {
+			return fmt.Errorf("drain shard %d: %s", si.ID, err)
+		}
+		s.Logger.Info(fmt.Sprintf("drained shard %d from data node %d", si.ID, self.ID))
+	}
+	return nil
+}
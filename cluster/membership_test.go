@@ -0,0 +1,420 @@
+package cluster_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/toml"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud"
+	"github.com/zhexuany/influxcloud/cluster"
+)
+
+// Ensure a data node registers with the meta servers and persists its id.
+func TestService_JoinCluster(t *testing.T) {
+	s, mc := MustOpenMembershipService()
+	defer s.Close()
+	defer os.RemoveAll(s.Dir)
+	s.TSDBStore.DatabasesFn = func() []string { return []string{"db1", "db0"} }
+
+	c := cluster.NewClient(time.Second)
+	id, err := c.JoinCluster(s.Addr().String(), []string{"meta0:8091"}, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if id != 3 {
+		t.Fatalf("unexpected node id: %d", id)
+	} else if exp := []string{"meta0:8091"}; !reflect.DeepEqual(mc.MetaServers, exp) {
+		t.Fatalf("unexpected meta servers: %v", mc.MetaServers)
+	} else if exp := []string{"db0", "db1"}; !reflect.DeepEqual(mc.Created, exp) {
+		t.Fatalf("unexpected created databases: %v", mc.Created)
+	}
+
+	if n, err := influxcloud.LoadNode(s.Dir); err != nil {
+		t.Fatal(err)
+	} else if n.ID != 3 {
+		t.Fatalf("unexpected persisted node id: %d", n.ID)
+	}
+
+	if _, err := c.JoinCluster(s.Addr().String(), []string{"meta0:8091"}, false); err == nil || err.Error() != cluster.ErrNodeAlreadyJoined.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a leaving data node moves its shards to other nodes before it is
+// removed from the cluster.
+func TestService_LeaveCluster(t *testing.T) {
+	s, mc := MustOpenMembershipService()
+	defer s.Close()
+	defer os.RemoveAll(s.Dir)
+	s.Node.ID = 1
+	s.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}
+
+	dst, dstmc := MustOpenCopyShardService()
+	defer dst.Close()
+	dst.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		_, err := ioutil.ReadAll(r)
+		return err
+	}
+
+	mc.Nodes = meta.NodeInfos{{ID: 1, TCPHost: s.Addr().String()}, {ID: 2, TCPHost: dst.Addr().String()}}
+	mc.DatabaseInfos = []meta.DatabaseInfo{{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{
+			Name: "rp0",
+			ShardGroups: []meta.ShardGroupInfo{{
+				ID: 1,
+				Shards: []meta.ShardInfo{
+					{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}}},
+					{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+				},
+			}},
+		}},
+	}}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.LeaveCluster(s.Addr().String()); err != nil {
+		t.Fatal(err)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(dstmc.Owners[2], exp) {
+		t.Fatalf("unexpected copied shards: %v", dstmc.Owners[2])
+	} else if exp := []string{"1 1", "2 1"}; !reflect.DeepEqual(mc.Removed, exp) {
+		t.Fatalf("unexpected removed owners: %v", mc.Removed)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(mc.Deleted, exp) {
+		t.Fatalf("unexpected deleted nodes: %v", mc.Deleted)
+	} else if s.Node.ID != 0 {
+		t.Fatalf("unexpected node id: %d", s.Node.ID)
+	}
+}
+
+// Ensure a leaving data node copies its shards to a node which doesn't share a
+// label value with the other owners, even if it owns more shards.
+func TestService_LeaveCluster_Labels(t *testing.T) {
+	s, mc := MustOpenMembershipService()
+	defer s.Close()
+	defer os.RemoveAll(s.Dir)
+	s.Node.ID = 1
+	s.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}
+
+	dst, dstmc := MustOpenCopyShardService()
+	defer dst.Close()
+	dst.Node.ID = 4
+	dst.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		_, err := ioutil.ReadAll(r)
+		return err
+	}
+
+	// Node 3 owns no shard but is in the same zone as node 2.
+	mc.Nodes = meta.NodeInfos{
+		{ID: 1, TCPHost: s.Addr().String()},
+		{ID: 2, TCPHost: "127.0.0.1:0"},
+		{ID: 3, TCPHost: "127.0.0.1:0"},
+		{ID: 4, TCPHost: dst.Addr().String()},
+	}
+	mc.Labels = map[uint64]map[string]string{
+		1: {"zone": "a"},
+		2: {"zone": "b"},
+		3: {"zone": "b"},
+		4: {"zone": "a"},
+	}
+	mc.DatabaseInfos = []meta.DatabaseInfo{{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{
+			Name: "rp0",
+			ShardGroups: []meta.ShardGroupInfo{{
+				ID: 1,
+				Shards: []meta.ShardInfo{
+					{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}},
+					{ID: 2, Owners: []meta.ShardOwner{{NodeID: 4}}},
+				},
+			}},
+		}},
+	}}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.LeaveCluster(s.Addr().String()); err != nil {
+		t.Fatal(err)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(dstmc.Owners[4], exp) {
+		t.Fatalf("unexpected copied shards: %v", dstmc.Owners)
+	} else if exp := []string{"1 1"}; !reflect.DeepEqual(mc.Removed, exp) {
+		t.Fatalf("unexpected removed owners: %v", mc.Removed)
+	}
+}
+
+// Ensure the shards of a leaving data node are copied to a node which doesn't
+// own them yet and hot shards are caught up before the node is removed.
+func TestService_LeaveCluster_HotReplica(t *testing.T) {
+	s, mc := MustOpenMembershipService()
+	defer s.Close()
+	defer os.RemoveAll(s.Dir)
+	s.Node.ID = 1
+	s.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}
+	sw := &RepairShardWriter{}
+	s.ShardWriter = sw
+
+	points := []influxql.FloatPoint{
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 0, Aux: []interface{}{float64(1)}},
+		{Name: "cpu", Tags: influxql.NewTags(map[string]string{"host": "serverA"}), Time: 10 * int64(time.Second), Aux: []interface{}{float64(2)}},
+	}
+	shardGroupFn := func(a []influxql.FloatPoint) func(ids []uint64) tsdb.ShardGroup {
+		return func(ids []uint64) tsdb.ShardGroup {
+			return &ShardGroup{
+				Points:       a,
+				Measurements: []string{"cpu"},
+				Fields:       map[string]influxql.DataType{"value": influxql.Float},
+				Dimensions:   map[string]struct{}{"host": struct{}{}},
+			}
+		}
+	}
+	s.TSDBStore.ShardGroupFn = shardGroupFn(points)
+
+	// Node 2 already owns the shard, node 3 missed the last write.
+	dst, dstmc := MustOpenCopyShardService()
+	defer dst.Close()
+	dst.Node.ID = 3
+	dst.TSDBStore.RestoreShardFn = func(id uint64, r io.Reader) error {
+		_, err := ioutil.ReadAll(r)
+		return err
+	}
+	dst.TSDBStore.ShardGroupFn = shardGroupFn(points[:1])
+
+	mc.Nodes = meta.NodeInfos{{ID: 1, TCPHost: s.Addr().String()}, {ID: 2, TCPHost: "127.0.0.1:0"}, {ID: 3, TCPHost: dst.Addr().String()}}
+	mc.DatabaseInfos = []meta.DatabaseInfo{{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{
+			Name: "rp0",
+			ShardGroups: []meta.ShardGroupInfo{{
+				ID:        1,
+				StartTime: time.Unix(0, 0),
+				EndTime:   time.Now().Add(time.Hour),
+				Shards:    []meta.ShardInfo{{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}}},
+			}},
+		}},
+	}}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.LeaveCluster(s.Addr().String()); err != nil {
+		t.Fatal(err)
+	} else if exp := []uint64{1}; !reflect.DeepEqual(dstmc.Owners[3], exp) {
+		t.Fatalf("unexpected copied shards: %v", dstmc.Owners)
+	} else if exp := []string{
+		"1 3 cpu,host=serverA value=1 0",
+		"1 3 cpu,host=serverA value=2 10000000000",
+	}; !reflect.DeepEqual(sw.Writes, exp) {
+		t.Fatalf("unexpected writes: %v", sw.Writes)
+	} else if exp := []string{"1 1"}; !reflect.DeepEqual(mc.Removed, exp) {
+		t.Fatalf("unexpected removed owners: %v", mc.Removed)
+	}
+}
+
+// Ensure a data node owning the only copy of a shard cannot leave alone.
+func TestService_LeaveCluster_NoDrainTarget(t *testing.T) {
+	s, mc := MustOpenMembershipService()
+	defer s.Close()
+	defer os.RemoveAll(s.Dir)
+	s.Node.ID = 1
+
+	mc.Nodes = meta.NodeInfos{{ID: 1, TCPHost: s.Addr().String()}}
+	mc.DatabaseInfos = []meta.DatabaseInfo{{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{{
+			Name: "rp0",
+			ShardGroups: []meta.ShardGroupInfo{{
+				ID:     1,
+				Shards: []meta.ShardInfo{{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}}}},
+			}},
+		}},
+	}}
+
+	c := cluster.NewClient(time.Second)
+	if err := c.LeaveCluster(s.Addr().String()); err == nil || err.Error() != cluster.ErrNoDrainTarget.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(mc.Deleted) != 0 || s.Node.ID != 1 {
+		t.Fatalf("unexpected deleted nodes: %v", mc.Deleted)
+	}
+}
+
+// Ensure a data node which joined a cluster reports its disk usage.
+func TestService_ReportDiskUsage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxcloud-cluster-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "000001.tsm"), make([]byte, 100), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	mc := &MembershipMetaClient{DiskUsage: make(chan string, 1)}
+	c := cluster.NewConfig()
+	c.DiskUsageReportInterval = toml.Duration(10 * time.Millisecond)
+	s := NewServiceWithConfig(c)
+	s.Node = &influxcloud.Node{ID: 1}
+	s.MembershipClient = mc
+	s.TSDBStore.PathFn = func() string { return dir }
+	s.ln = MustListen("tcp", "127.0.0.1:0")
+	s.Listener = &muxListener{s.ln}
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	select {
+	case got := <-mc.DiskUsage:
+		if got != "1 100" {
+			t.Fatalf("unexpected disk usage: %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for disk usage")
+	}
+}
+
+// Ensure a data node which joined a cluster registers its labels on start.
+func TestService_Open_UpdateDataNode(t *testing.T) {
+	mc := &MembershipMetaClient{
+		Nodes:   meta.NodeInfos{{ID: 1, Host: "localhost:8086", TCPHost: "localhost:8088"}},
+		Updated: make(chan string, 1),
+	}
+	c := cluster.NewConfig()
+	c.Labels = map[string]string{"rack": "r2"}
+	s := NewServiceWithConfig(c)
+	s.Node = &influxcloud.Node{ID: 1}
+	s.MembershipClient = mc
+	s.ln = MustListen("tcp", "127.0.0.1:0")
+	s.Listener = &muxListener{s.ln}
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	select {
+	case got := <-mc.Updated:
+		if exp := "1 localhost:8086 localhost:8088 map[rack:r2]"; got != exp {
+			t.Fatalf("unexpected update: %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for data node update")
+	}
+}
+
+// MembershipService is a test wrapper for a service storing its node in a
+// temporary directory.
+type MembershipService struct {
+	*Service
+	Dir string
+}
+
+// MustOpenMembershipService returns an open service for a node which has not
+// joined a cluster.
+func MustOpenMembershipService() (*MembershipService, *MembershipMetaClient) {
+	dir, err := ioutil.TempDir("", "influxcloud-cluster-")
+	if err != nil {
+		panic(err)
+	}
+
+	mc := &MembershipMetaClient{}
+	c := cluster.NewConfig()
+	c.Labels = map[string]string{"rack": "r1"}
+	s := NewServiceWithConfig(c)
+	s.Node = influxcloud.NewNode(dir)
+	s.HTTPAddr = "localhost:8086"
+	s.MembershipClient = mc
+	s.ln = MustListen("tcp", "127.0.0.1:0")
+	s.Listener = &muxListener{s.ln}
+	if err := s.Open(); err != nil {
+		panic(err)
+	}
+	return &MembershipService{Service: s, Dir: dir}, mc
+}
+
+// MembershipMetaClient is a test meta client recording membership changes.
+type MembershipMetaClient struct {
+	MetaServers   []string
+	Nodes         meta.NodeInfos
+	Labels        map[uint64]map[string]string
+	DatabaseInfos []meta.DatabaseInfo
+	Created       []string
+	Removed       []string
+	Deleted       []uint64
+
+	// DiskUsage receives the reported disk usages if set.
+	DiskUsage chan string
+
+	// Updated receives the updated data nodes if set.
+	Updated chan string
+}
+
+func (c *MembershipMetaClient) SetMetaServers(a []string) {
+	c.MetaServers = a
+}
+
+func (c *MembershipMetaClient) CreateDataNodeWithLabels(httpAddr, tcpAddr string, labels map[string]string) (*meta.NodeInfo, error) {
+	if httpAddr != "localhost:8086" {
+		return nil, fmt.Errorf("unexpected http address: %s", httpAddr)
+	} else if exp := map[string]string{"rack": "r1"}; !reflect.DeepEqual(labels, exp) {
+		return nil, fmt.Errorf("unexpected labels: %v", labels)
+	}
+	return &meta.NodeInfo{ID: 3, Host: httpAddr, TCPHost: tcpAddr}, nil
+}
+
+func (c *MembershipMetaClient) DeleteDataNode(id uint64) error {
+	c.Deleted = append(c.Deleted, id)
+	return nil
+}
+
+func (c *MembershipMetaClient) DataNodes() (meta.NodeInfos, error) {
+	return c.Nodes, nil
+}
+
+func (c *MembershipMetaClient) DataNodeLabels() (map[uint64]map[string]string, error) {
+	return c.Labels, nil
+}
+
+func (c *MembershipMetaClient) Databases() ([]meta.DatabaseInfo, error) {
+	return c.DatabaseInfos, nil
+}
+
+func (c *MembershipMetaClient) CreateDatabase(name string) (*meta.DatabaseInfo, error) {
+	c.Created = append(c.Created, name)
+	return &meta.DatabaseInfo{Name: name}, nil
+}
+
+func (c *MembershipMetaClient) RemoveShardOwner(shardID, nodeID uint64) error {
+	c.Removed = append(c.Removed, fmt.Sprintf("%d %d", shardID, nodeID))
+	return nil
+}
+
+func (c *MembershipMetaClient) SetDataNodeDiskUsage(id, size uint64) error {
+	if c.DiskUsage != nil {
+		select {
+		case c.DiskUsage <- fmt.Sprintf("%d %d", id, size):
+		default:
+		}
+	}
+	return nil
+}
+
+func (c *MembershipMetaClient) UpdateDataNode(id uint64, host, tcpHost string, labels map[string]string) error {
+	if c.Updated != nil {
+		select {
+		case c.Updated <- fmt.Sprintf("%d %s %s %v", id, host, tcpHost, labels):
+		default:
+		}
+	}
+	return nil
+}
@@ -6,15 +6,33 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
 	"github.com/uber-go/zap"
 	"github.com/zhexuany/influxcloud"
 )
 
+// The keys for statistics generated by the "write" module.
+const (
+	statWriteReq            = "req"
+	statPointWriteReq       = "pointReq"
+	statPointWriteReqLocal  = "pointReqLocal"
+	statPointWriteReqRemote = "pointReqRemote"
+	statPointWriteReqHH     = "pointReqHH"
+	statWriteOK             = "writeOk"
+	statWriteDrop           = "writeDrop"
+	statWriteTimeout        = "writeTimeout"
+	statWritePartial        = "writePartial"
+	statWriteErr            = "writeError"
+	statSubWriteOK          = "subWriteOk"
+	statSubWriteDrop        = "subWriteDrop"
+)
+
 var (
 	// ErrTimeout is returned when a write times out.
 	ErrTimeout = errors.New("timeout")
@@ -43,7 +61,7 @@ type PointsWriter struct {
 	}
 
 	TSDBStore interface {
-		CreateShard(database, retentionPolicy string, shardID uint64) error
+		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
 		WriteToShard(shardID uint64, points []models.Point) error
 	}
 
@@ -54,6 +72,13 @@ type PointsWriter struct {
 	HintedHandoff interface {
 		WriteShard(shardID, ownerID uint64, points []models.Point) error
 	}
+
+	Subscriber interface {
+		Points() chan<- *coordinator.WritePointsRequest
+	}
+	subPoints chan<- *coordinator.WritePointsRequest
+
+	stats *WriteStatistics
 }
 
 // WritePointsRequest represents a request to write point data to the cluster.
@@ -80,6 +105,7 @@ func NewPointsWriter() *PointsWriter {
 		closing:      make(chan struct{}),
 		WriteTimeout: DefaultWriteTimeout,
 		Logger:       zap.New(zap.NullEncoder()),
+		stats:        &WriteStatistics{},
 	}
 }
 
@@ -113,6 +139,9 @@ func (w *PointsWriter) Open() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.closing = make(chan struct{})
+	if w.Subscriber != nil {
+		w.subPoints = w.Subscriber.Points()
+	}
 	return nil
 }
 
@@ -123,6 +152,12 @@ func (w *PointsWriter) Close() error {
 	if w.closing != nil {
 		close(w.closing)
 	}
+	if w.subPoints != nil {
+		// 'nil' channels always block so this makes the
+		// select statement in WritePoints hit its default case
+		// dropping any in-flight writes.
+		w.subPoints = nil
+	}
 	return nil
 }
 
@@ -147,6 +182,28 @@ type WriteStatistics struct {
 	SubWriteDrop        int64
 }
 
+// Statistics returns statistics for periodic monitoring.
+func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
+	return []models.Statistic{{
+		Name: "write",
+		Tags: tags,
+		Values: map[string]interface{}{
+			statWriteReq:            atomic.LoadInt64(&w.stats.WriteReq),
+			statPointWriteReq:       atomic.LoadInt64(&w.stats.PointWriteReq),
+			statPointWriteReqLocal:  atomic.LoadInt64(&w.stats.PointWriteReqLocal),
+			statPointWriteReqRemote: atomic.LoadInt64(&w.stats.PointWriteReqRemote),
+			statPointWriteReqHH:     atomic.LoadInt64(&w.stats.PointWriteReqHH),
+			statWriteOK:             atomic.LoadInt64(&w.stats.WriteOK),
+			statWriteDrop:           atomic.LoadInt64(&w.stats.WriteDropped),
+			statWriteTimeout:        atomic.LoadInt64(&w.stats.WriteTimeout),
+			statWritePartial:        atomic.LoadInt64(&w.stats.WritePartial),
+			statWriteErr:            atomic.LoadInt64(&w.stats.WriteErr),
+			statSubWriteOK:          atomic.LoadInt64(&w.stats.SubWriteOK),
+			statSubWriteDrop:        atomic.LoadInt64(&w.stats.SubWriteDrop),
+		},
+	}}
+}
+
 // MapShards maps the points contained in wp to a ShardMapping.  If a point
 // maps to a shard group or shard that does not currently exist, it will be
 // created before returning the mapping.
@@ -215,13 +272,20 @@ func (l sgList) Covers(t time.Time) bool {
 // at the given time.
 //
 // Shard groups are sorted first according to end time, and then according
-// to start time. Therefore, if there are multiple shard groups that match
-// this point's time they will be preferred in this order:
+// to start time. The end time of a truncated shard group is the time it was
+// truncated at, so points after it go to the shard group that replaced it.
+// Therefore, if there are multiple shard groups that match this point's time
+// they will be preferred in this order:
 //
 //  - a shard group with the earliest end time;
 //  - (assuming identical end times) the shard group with the earliest start time.
 func (l sgList) ShardGroupAt(t time.Time) *meta.ShardGroupInfo {
-	idx := sort.Search(len(l), func(i int) bool { return l[i].EndTime.After(t) })
+	idx := sort.Search(len(l), func(i int) bool {
+		if l[i].Truncated() {
+			return l[i].TruncatedAt.After(t)
+		}
+		return l[i].EndTime.After(t)
+	})
 
 	// We couldn't find a shard group the point falls into.
 	if idx == len(l) || t.Before(l[idx].StartTime) {
@@ -245,6 +309,8 @@ func (w *PointsWriter) WritePointsInto(p *coordinator.IntoWriteRequest) error {
 
 // WritePoints writes across multiple local and remote data nodes according the consistency level.
 func (w *PointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+	atomic.AddInt64(&w.stats.WriteReq, 1)
+	atomic.AddInt64(&w.stats.PointWriteReq, int64(len(points)))
 
 	if retentionPolicy == "" {
 		db := w.MetaClient.Database(database)
@@ -268,6 +334,22 @@ func (w *PointsWriter) WritePoints(database, retentionPolicy string, consistency
 		}(shardMappings.Shards[shardID], database, retentionPolicy, points)
 	}
 
+	// Send points to subscriptions if possible.
+	ok := false
+	// We need to lock just in case the channel is about to be nil'ed
+	w.mu.RLock()
+	select {
+	case w.subPoints <- &coordinator.WritePointsRequest{Database: database, RetentionPolicy: retentionPolicy, Points: points}:
+		ok = true
+	default:
+	}
+	w.mu.RUnlock()
+	if ok {
+		atomic.AddInt64(&w.stats.SubWriteOK, 1)
+	} else {
+		atomic.AddInt64(&w.stats.SubWriteDrop, 1)
+	}
+
 	for range shardMappings.Points {
 		select {
 		case <-w.closing:
@@ -281,7 +363,8 @@ func (w *PointsWriter) WritePoints(database, retentionPolicy string, consistency
 	return nil
 }
 
-// writeToShards writes points to a shard.
+// writeToShard writes points to every owner of a shard and waits until the
+// requested consistency level is met. Each owner produces exactly one result.
 func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPolicy string,
 	consistency models.ConsistencyLevel, points []models.Point) error {
 	required := len(shard.Owners)
@@ -304,44 +387,14 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 
 	for _, owner := range shard.Owners {
 		go func(shardID uint64, owner meta.ShardOwner, points []models.Point) {
-			if w.Node.ID != owner.NodeID {
-				w.Logger.Info("Remote Write")
-				return
-			}
-			// not actually created this shard, tell it to create it and retry the write
-			err := w.TSDBStore.WriteToShard(shardID, points)
-			if err != nil {
-				w.Logger.Info("failed to write point to shard locally:", zap.Error(err))
+			var err error
+			if w.Node.ID == owner.NodeID {
+				err = w.writeToLocalShard(shardID, database, retentionPolicy, points)
+			} else {
+				err = w.writeToRemoteShard(shardID, owner.NodeID, consistency, points)
 			}
 			ch <- &AsyncWriteResult{owner, err}
-			return
 		}(shard.ID, owner, points)
-
-		// Start to write Shard into remote nodes
-		go func(shardID uint64, owner meta.ShardOwner, points []models.Point) {
-			if w.Node.ID != owner.NodeID {
-
-				err := w.ShardWriter.WriteShard(shardID, owner.NodeID, points)
-				if err != nil && isRetryable(err) {
-					// The remote write failed so queue it via hinted handoff
-					hherr := w.HintedHandoff.WriteShard(shardID, owner.NodeID, points)
-					if hherr != nil {
-						ch <- &AsyncWriteResult{owner, hherr}
-						return
-					}
-
-					// If the write consistency level is ANY, then a successful hinted handoff can
-					// be considered a successful write so send nil to the response channel
-					// otherwise, let the original error propagate to the response channel
-					if hherr == nil && consistency == models.ConsistencyLevelAny {
-						ch <- &AsyncWriteResult{owner, nil}
-						return
-					}
-				}
-				ch <- &AsyncWriteResult{owner, err}
-			}
-		}(shard.ID, owner, points)
-
 	}
 
 	var wrote int
@@ -352,12 +405,13 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 		case <-w.closing:
 			return ErrWriteFailed
 		case <-timeout:
+			atomic.AddInt64(&w.stats.WriteTimeout, 1)
 			// return timeout error to caller
 			return ErrTimeout
 		case result := <-ch:
 			// If the write returned an error, continue to the next response
 			if result.Err != nil {
-				// w.Logger.Error("write failed for shard %d on node %d: %v", shard.ID, result.Owner.NodeID, result.Err)
+				w.Logger.Warn(fmt.Sprintf("write failed for shard %d on node %d: %v", shard.ID, result.Owner.NodeID, result.Err))
 
 				// Keep track of the first error we see to return back to the client
 				if writeError == nil {
@@ -370,15 +424,18 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 
 			// We wrote the required consistency level
 			if wrote >= required {
+				atomic.AddInt64(&w.stats.WriteOK, 1)
 				return nil
 			}
 		}
 	}
 
 	if wrote > 0 {
+		atomic.AddInt64(&w.stats.WritePartial, 1)
 		return ErrPartialWrite
 	}
 
+	atomic.AddInt64(&w.stats.WriteErr, 1)
 	if writeError != nil {
 		return fmt.Errorf("write failed: %v", writeError)
 	}
@@ -386,6 +443,48 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 	return ErrWriteFailed
 }
 
+// writeToLocalShard writes points to a shard owned by this node.
+func (w *PointsWriter) writeToLocalShard(shardID uint64, database, retentionPolicy string, points []models.Point) error {
+	atomic.AddInt64(&w.stats.PointWriteReqLocal, int64(len(points)))
+
+	err := w.TSDBStore.WriteToShard(shardID, points)
+
+	// If we've written to shard that should exist on the current node, but the store has
+	// not actually created this shard, tell it to create it and retry the write
+	if err == tsdb.ErrShardNotFound {
+		if err := w.TSDBStore.CreateShard(database, retentionPolicy, shardID, true); err != nil {
+			return err
+		}
+		err = w.TSDBStore.WriteToShard(shardID, points)
+	}
+	return err
+}
+
+// writeToRemoteShard writes points to a shard owned by another node. If the
+// remote write fails, the points are queued via hinted handoff when it is
+// configured. A queued write only counts as a successful write for consistency level ANY.
+func (w *PointsWriter) writeToRemoteShard(shardID, ownerID uint64, consistency models.ConsistencyLevel, points []models.Point) error {
+	atomic.AddInt64(&w.stats.PointWriteReqRemote, int64(len(points)))
+
+	err := w.ShardWriter.WriteShard(shardID, ownerID, points)
+	if err == nil || !isRetryable(err) || w.HintedHandoff == nil {
+		return err
+	}
+
+	// The remote write failed so queue it via hinted handoff
+	atomic.AddInt64(&w.stats.PointWriteReqHH, int64(len(points)))
+	if hherr := w.HintedHandoff.WriteShard(shardID, ownerID, points); hherr != nil {
+		return hherr
+	}
+
+	// If the write consistency level is ANY, then a successful hinted handoff can
+	// be considered a successful write. Otherwise, let the original error propagate.
+	if consistency == models.ConsistencyLevelAny {
+		return nil
+	}
+	return err
+}
+
 func isRetryable(err error) bool {
 	if err == nil {
 		return true
@@ -17,7 +17,9 @@ func TestSgList_ShardGroupAt(t *testing.T) {
 		{ID: 3, StartTime: day(2), EndTime: day(3)},
 		// SG day 3 to day 4 missing...
 		{ID: 4, StartTime: day(4), EndTime: day(5)},
-		{ID: 5, StartTime: day(5), EndTime: day(6)},
+		// SG 6 was split from SG 5 at noon.
+		{ID: 5, StartTime: day(5), EndTime: day(6), TruncatedAt: day(5).Add(12 * time.Hour)},
+		{ID: 6, StartTime: day(5).Add(12 * time.Hour), EndTime: day(6)},
 	}
 
 	examples := []struct {
@@ -30,6 +32,8 @@ func TestSgList_ShardGroupAt(t *testing.T) {
 		{T: day(1), ShardGroupID: 2},
 		{T: day(3).Add(time.Minute), ShardGroupID: 0}, // No matching SG
 		{T: day(5).Add(time.Hour), ShardGroupID: 5},
+		{T: day(5).Add(12 * time.Hour), ShardGroupID: 6},
+		{T: day(5).Add(13 * time.Hour), ShardGroupID: 6},
 	}
 
 	for i, example := range examples {
@@ -1,14 +1,18 @@
 package cluster_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
 	"github.com/zhexuany/influxcloud"
 	"github.com/zhexuany/influxcloud/cluster"
 )
@@ -219,18 +223,18 @@ func TestPointsWriter_WritePoints(t *testing.T) {
 		}
 		ms.NodeIDFn = func() uint64 { return 1 }
 
-		// subPoints := make(chan *cluster.WritePointsRequest, 1)
-		// sub := Subscriber{}
-		// sub.PointsFn = func() chan<- *cluster.WritePointsRequest {
-		// 	return subPoints
-		// }
+		subPoints := make(chan *coordinator.WritePointsRequest, 1)
+		sub := Subscriber{}
+		sub.PointsFn = func() chan<- *coordinator.WritePointsRequest {
+			return subPoints
+		}
 
 		c := cluster.NewPointsWriter()
 		c.MetaClient = ms
 		c.ShardWriter = sw
 		c.TSDBStore = store
 		c.HintedHandoff = hh
-		// c.Subscriber = sub
+		c.Subscriber = sub
 		c.Node = &influxcloud.Node{ID: 1}
 
 		c.Open()
@@ -247,16 +251,203 @@ func TestPointsWriter_WritePoints(t *testing.T) {
 		if err != nil && test.expErr != nil && err.Error() != test.expErr.Error() {
 			t.Errorf("PointsWriter.WritePoints(): '%s' error: got %v, exp %v", test.name, err, test.expErr)
 		}
-		// if test.expErr == nil {
-		// 	select {
-		// 	case p := <-subPoints:
-		// 		if !reflect.DeepEqual(p, pr) {
-		// 			t.Errorf("PointsWriter.WritePoints(): '%s' error: unexpected WritePointsRequest got %v, exp %v", test.name, p, pr)
-		// 		}
-		// 	default:
-		// 		t.Errorf("PointsWriter.WritePoints(): '%s' error: Subscriber.Points not called", test.name)
-		// 	}
-		// }
+		if test.expErr == nil {
+			select {
+			case p := <-subPoints:
+				if !reflect.DeepEqual((*cluster.WritePointsRequest)(p), pr) {
+					t.Errorf("PointsWriter.WritePoints(): '%s' error: unexpected WritePointsRequest got %v, exp %v", test.name, p, pr)
+				}
+			default:
+				t.Errorf("PointsWriter.WritePoints(): '%s' error: Subscriber.Points not called", test.name)
+			}
+		}
+	}
+}
+
+// Ensures the points writer honors each consistency level across combinations
+// of local, remote and hinted handoff failures. Node 1 is the local node and
+// every shard is owned by nodes 1, 2 and 3.
+func TestPointsWriter_WritePoints_ConsistencyLevels(t *testing.T) {
+	errFail := errors.New("fail")
+	errConflict := errors.New("field type conflict")
+
+	tests := []struct {
+		name        string
+		consistency models.ConsistencyLevel
+
+		// the responses returned by each shard write call.  node ID 1 = pos 0
+		err   []error
+		hhErr error
+		slow  bool
+
+		expErr error
+	}{
+		{name: "any all ok", consistency: models.ConsistencyLevelAny, err: []error{nil, nil, nil}},
+		{name: "any only local", consistency: models.ConsistencyLevelAny, err: []error{nil, errFail, errFail}, hhErr: errFail},
+		{name: "any only hinted handoff", consistency: models.ConsistencyLevelAny, err: []error{errFail, errFail, errFail}},
+		{name: "any all failed", consistency: models.ConsistencyLevelAny, err: []error{errFail, errFail, errFail}, hhErr: errFail, expErr: fmt.Errorf("write failed: fail")},
+		{name: "any non-retryable", consistency: models.ConsistencyLevelAny, err: []error{errConflict, errConflict, errConflict}, expErr: fmt.Errorf("write failed: field type conflict")},
+
+		{name: "one all ok", consistency: models.ConsistencyLevelOne, err: []error{nil, nil, nil}},
+		{name: "one only remote", consistency: models.ConsistencyLevelOne, err: []error{errFail, errFail, nil}},
+		{name: "one only hinted handoff", consistency: models.ConsistencyLevelOne, err: []error{errFail, errFail, errFail}, expErr: fmt.Errorf("write failed: fail")},
+
+		{name: "quorum all ok", consistency: models.ConsistencyLevelQuorum, err: []error{nil, nil, nil}},
+		{name: "quorum one failed", consistency: models.ConsistencyLevelQuorum, err: []error{nil, errFail, nil}},
+		{name: "quorum two failed", consistency: models.ConsistencyLevelQuorum, err: []error{errFail, nil, errFail}, expErr: cluster.ErrPartialWrite},
+		{name: "quorum all failed", consistency: models.ConsistencyLevelQuorum, err: []error{errFail, errFail, errFail}, expErr: fmt.Errorf("write failed: fail")},
+
+		{name: "all all ok", consistency: models.ConsistencyLevelAll, err: []error{nil, nil, nil}},
+		{name: "all local failed", consistency: models.ConsistencyLevelAll, err: []error{errFail, nil, nil}, expErr: cluster.ErrPartialWrite},
+		{name: "all remote failed", consistency: models.ConsistencyLevelAll, err: []error{nil, nil, errFail}, expErr: cluster.ErrPartialWrite},
+		{name: "all all failed", consistency: models.ConsistencyLevelAll, err: []error{errFail, errFail, errFail}, expErr: fmt.Errorf("write failed: fail")},
+		{name: "all timeout", consistency: models.ConsistencyLevelAll, err: []error{nil, nil, nil}, slow: true, expErr: cluster.ErrTimeout},
+	}
+
+	for _, test := range tests {
+		theTest := test
+
+		sw := &fakeShardWriter{
+			ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+				if theTest.slow && nodeID == 3 {
+					time.Sleep(time.Second)
+				}
+				return theTest.err[int(nodeID)-1]
+			},
+		}
+
+		store := &fakeStore{
+			WriteFn: func(shardID uint64, points []models.Point) error {
+				return theTest.err[0]
+			},
+		}
+
+		hh := &fakeShardWriter{
+			ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+				return theTest.hhErr
+			},
+		}
+
+		c := cluster.NewPointsWriter()
+		c.MetaClient = NewPointsWriterMetaClient()
+		c.ShardWriter = sw
+		c.TSDBStore = store
+		c.HintedHandoff = hh
+		c.Node = &influxcloud.Node{ID: 1}
+		c.WriteTimeout = 100 * time.Millisecond
+
+		c.Open()
+
+		pr := &cluster.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+		pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+		err := c.WritePoints(pr.Database, pr.RetentionPolicy, test.consistency, pr.Points)
+		if (err == nil) != (test.expErr == nil) || (err != nil && err.Error() != test.expErr.Error()) {
+			t.Errorf("PointsWriter.WritePoints(): '%s' error: got %v, exp %v", test.name, err, test.expErr)
+		}
+		c.Close()
+	}
+}
+
+// Ensures a local write creates the shard when it does not exist yet.
+func TestPointsWriter_WritePoints_CreateLocalShard(t *testing.T) {
+	var created bool
+	store := &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error {
+			if !created {
+				return tsdb.ErrShardNotFound
+			}
+			return nil
+		},
+		CreateShardfn: func(database, retentionPolicy string, shardID uint64, enabled bool) error {
+			if database != "mydb" || retentionPolicy != "myrp" {
+				t.Errorf("unexpected shard creation: %s.%s", database, retentionPolicy)
+			}
+			created = true
+			return nil
+		},
+	}
+
+	c := cluster.NewPointsWriter()
+	c.MetaClient = NewPointsWriterMetaClient()
+	c.TSDBStore = store
+	c.Node = &influxcloud.Node{ID: 1}
+
+	pr := &cluster.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+	// Writes to nodes 2 and 3 fail so only the local write can satisfy ONE.
+	c.ShardWriter = &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			return errors.New("fail")
+		},
+	}
+	c.HintedHandoff = c.ShardWriter
+
+	c.Open()
+	defer c.Close()
+
+	if err := c.WritePoints(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !created {
+		t.Fatal("expected shard to be created")
+	}
+}
+
+// Ensures the points writer sends every point of a shard to the remote owners
+// through the shard writer and the cluster service.
+func TestPointsWriter_WritePoints_Remote(t *testing.T) {
+	ts := newTestWriteService(nil)
+	ts.TSDBStore.WriteToShardFn = ts.writeShardSuccess
+	s := cluster.NewService(cluster.Config{})
+	s.Listener = ts.muxln
+	s.TSDBStore = &ts.TSDBStore
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer ts.Close()
+
+	// Nodes 2 and 3 are both served by the test service.
+	w := cluster.NewShardWriter(time.Minute, 1)
+	w.MetaClient = &metaClient{host: ts.ln.Addr().String()}
+	defer w.Close()
+
+	c := cluster.NewPointsWriter()
+	c.MetaClient = NewPointsWriterMetaClient()
+	c.ShardWriter = w
+	c.TSDBStore = &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error { return nil },
+	}
+	c.HintedHandoff = &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			return errors.New("unexpected hinted handoff write")
+		},
+	}
+	c.Node = &influxcloud.Node{ID: 1}
+
+	c.Open()
+	defer c.Close()
+
+	now := time.Now()
+	pr := &cluster.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, now, nil)
+	pr.AddPoint("mem", 2.0, now, nil)
+
+	if err := c.WritePoints(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelAll, pr.Points); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	responses, err := ts.ResponseN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range responses {
+		if len(r.points) != 2 {
+			t.Fatalf("unexpected point count for shard %d: %d", r.shardID, len(r.points))
+		} else if name := r.points[1].Name(); name != "mem" {
+			t.Fatalf("unexpected name: %s", name)
+		}
 	}
 }
 
@@ -272,15 +463,15 @@ func (f *fakeShardWriter) WriteShard(shardID, nodeID uint64, points []models.Poi
 
 type fakeStore struct {
 	WriteFn       func(shardID uint64, points []models.Point) error
-	CreateShardfn func(database, retentionPolicy string, shardID uint64) error
+	CreateShardfn func(database, retentionPolicy string, shardID uint64, enabled bool) error
 }
 
 func (f *fakeStore) WriteToShard(shardID uint64, points []models.Point) error {
 	return f.WriteFn(shardID, points)
 }
 
-func (f *fakeStore) CreateShard(database, retentionPolicy string, shardID uint64) error {
-	return f.CreateShardfn(database, retentionPolicy, shardID)
+func (f *fakeStore) CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error {
+	return f.CreateShardfn(database, retentionPolicy, shardID, enabled)
 }
 
 func NewPointsWriterMetaClient() *PointsWriterMetaClient {
@@ -339,10 +530,10 @@ func (m PointsWriterMetaClient) ShardOwner(shardID uint64) (string, string, *met
 }
 
 type Subscriber struct {
-	PointsFn func() chan<- *cluster.WritePointsRequest
+	PointsFn func() chan<- *coordinator.WritePointsRequest
 }
 
-func (s Subscriber) Points() chan<- *cluster.WritePointsRequest {
+func (s Subscriber) Points() chan<- *coordinator.WritePointsRequest {
 	return s.PointsFn()
 }
 
@@ -0,0 +1,449 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/uber-go/zap"
+	"github.com/zhexuany/influxcloud/rpc"
+)
+
+// RebalanceLeaseName is the name of the meta lease held by the node running
+// the rebalancer. Only one node in the cluster rebalances at a time.
+const RebalanceLeaseName = "rebalancer"
+
+// ShardMove moves one owner of a shard from the Source data node to the Dest
+// data node.
+type ShardMove struct {
+	ShardID  uint64
+	Database string
+	Policy   string
+	Source   uint64
+	Dest     uint64
+
+	// Hot is true if the shard belongs to a shard group still accepting
+	// writes. The points written to the source during the copy of a hot
+	// shard are caught up on the destination before the source is removed.
+	Hot bool
+}
+
+func (m ShardMove) String() string {
+	typ := "cold"
+	if m.Hot {
+		typ = "hot"
+	}
+	return fmt.Sprintf("%s shard %d (%s.%s) from node %d to node %d", typ, m.ShardID, m.Database, m.Policy, m.Source, m.Dest)
+}
+
+// RebalanceReport describes a rebalance run.
+type RebalanceReport struct {
+	StartedAt time.Time
+	DryRun    bool
+
+	// Moves are all of the planned moves.
+	Moves []ShardMove
+
+	// Completed is the number of moves copied and removed from their source.
+	Completed int
+
+	// Err is the error that stopped the run, if any.
+	Err string
+}
+
+// Rebalancer moves shard ownership between data nodes so that every data node
+// owns an even share of the hot and cold shards. A shard is moved by copying
+// it to the new owner and then removing the old owner. Hot shards are caught
+// up with the writes received during the copy before the old owner is
+// removed, so that no write is lost by the move.
+type Rebalancer struct {
+	mu     sync.Mutex
+	report *RebalanceReport
+
+	// rebalanceMu ensures only one rebalance runs at a time.
+	rebalanceMu sync.Mutex
+
+	wg      sync.WaitGroup
+	closing chan struct{}
+
+	enabled        bool
+	checkInterval  time.Duration
+	dryRun         bool
+	throttle       time.Duration
+	digestInterval time.Duration
+
+	MetaClient interface {
+		AcquireLease(name string) (*meta.Lease, error)
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		DataNodes() (meta.NodeInfos, error)
+		DataNodeLabels() (map[uint64]map[string]string, error)
+		Databases() ([]meta.DatabaseInfo, error)
+		RemoveShardOwner(shardID, nodeID uint64) error
+	}
+
+	ShardCopier interface {
+		CopyShard(source, dest string, shardID uint64) error
+		RemoveShard(addr string, shardID uint64) error
+	}
+
+	ShardReader interface {
+		ShardDigest(addr string, shardID uint64, interval time.Duration) ([]rpc.SeriesDigest, error)
+		ShardPoints(addr string, shardID uint64, keys []string, start, end int64) ([]models.Point, error)
+	}
+
+	ShardWriter interface {
+		WriteShard(shardID, ownerID uint64, points []models.Point) error
+	}
+
+	Logger zap.Logger
+}
+
+// NewRebalancer returns a new instance of Rebalancer.
+func NewRebalancer(c Config) *Rebalancer {
+	client := &Client{timeout: time.Duration(c.DialTimeout), TLS: NewTLS(c)}
+	return &Rebalancer{
+		enabled:        c.RebalanceEnabled,
+		checkInterval:  time.Duration(c.RebalanceCheckInterval),
+		dryRun:         c.RebalanceDryRun,
+		throttle:       time.Duration(c.RebalanceThrottle),
+		digestInterval: time.Duration(c.AntiEntropyDigestInterval),
+		ShardCopier:    client,
+		ShardReader:    client,
+		Logger:         zap.New(zap.NullEncoder()),
+	}
+}
+
+// WithLogger sets the internal logger to the logger passed in
+func (r *Rebalancer) WithLogger(log zap.Logger) {
+	r.Logger = log.With(zap.String("service", "rebalancer"))
+}
+
+// Open starts checking the cluster balance periodically.
+func (r *Rebalancer) Open() error {
+	if !r.enabled {
+		return nil
+	}
+
+	r.Logger.Info(fmt.Sprintf("Starting rebalancer, check interval: %s, dry run: %t", r.checkInterval, r.dryRun))
+	r.closing = make(chan struct{})
+	r.wg.Add(1)
+	go r.run()
+	return nil
+}
+
+// Close stops the rebalancer and waits for a running move to complete.
+func (r *Rebalancer) Close() error {
+	if r.closing == nil {
+		return nil
+	}
+	close(r.closing)
+	r.wg.Wait()
+	r.closing = nil
+	return nil
+}
+
+func (r *Rebalancer) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.closing:
+			return
+		case <-ticker.C:
+			// Only the node holding the lease rebalances the cluster.
+			if _, err := r.MetaClient.AcquireLease(RebalanceLeaseName); err != nil {
+				continue
+			}
+
+			report, err := r.Rebalance(r.dryRun)
+			if err != nil {
+				r.Logger.Warn("rebalance error: " + err.Error())
+				continue
+			}
+			if len(report.Moves) > 0 {
+				r.Logger.Info(fmt.Sprintf("rebalance completed %d of %d planned shard moves", report.Completed, len(report.Moves)))
+			}
+		}
+	}
+}
+
+// LastReport returns a copy of the report of the last rebalance run.
+func (r *Rebalancer) LastReport() *RebalanceReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report == nil {
+		return nil
+	}
+	other := *r.report
+	other.Moves = append([]ShardMove(nil), r.report.Moves...)
+	return &other
+}
+
+// Plan returns the shard moves needed to balance the cluster.
+func (r *Rebalancer) Plan() ([]ShardMove, error) {
+	nodes, err := r.MetaClient.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+	labels, err := r.MetaClient.DataNodeLabels()
+	if err != nil {
+		return nil, err
+	}
+	dbs, err := r.MetaClient.Databases()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Sort(uint64Slice(ids))
+
+	return planShardMoves(ids, labels, dbs, time.Now()), nil
+}
+
+// Rebalance plans the shard moves and executes them unless dryRun is set. The meta lease is renewed before every move and the run stops if
+// the lease is lost or a move fails.
+func (r *Rebalancer) Rebalance(dryRun bool) (*RebalanceReport, error) {
+	r.rebalanceMu.Lock()
+	defer r.rebalanceMu.Unlock()
+
+	report := &RebalanceReport{StartedAt: time.Now(), DryRun: dryRun}
+	err := func() error {
+		if _, err := r.MetaClient.AcquireLease(RebalanceLeaseName); err != nil {
+			return err
+		}
+
+		moves, err := r.Plan()
+		if err != nil {
+			return err
+		}
+		r.updateReport(report, func() { report.Moves = moves })
+
+		for _, m := range moves {
+			r.Logger.Info("rebalance planned move: " + m.String())
+		}
+		if dryRun {
+			return nil
+		}
+
+		var n int
+		for _, m := range moves {
+			// Pause between moves so copies don't starve the cluster.
+			if n > 0 && r.throttle > 0 {
+				select {
+				case <-r.closing:
+					return nil
+				case <-time.After(r.throttle):
+				}
+			}
+			n++
+
+			if _, err := r.MetaClient.AcquireLease(RebalanceLeaseName); err != nil {
+				return err
+			}
+			if err := r.moveShard(m); err != nil {
+				return err
+			}
+			r.updateReport(report, func() { report.Completed++ })
+		}
+		return nil
+	}()
+	if err != nil {
+		r.updateReport(report, func() { report.Err = err.Error() })
+		return r.LastReport(), err
+	}
+	return r.LastReport(), nil
+}
+
+// updateReport applies fn to report and sets it as the last report.
+func (r *Rebalancer) updateReport(report *RebalanceReport, fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn()
+	r.report = report
+}
+
+// moveShard copies the shard to the destination node and then removes the
+// source node as an owner. The destination owns the shard once the copy
+// completes, so the points written to the source during the copy of a hot
+// shard are caught up before the source stops receiving writes. The shard is
+// deleted from the source once it no longer owns it, freeing its disk.
+func (r *Rebalancer) moveShard(m ShardMove) error {
+	src, err := r.MetaClient.DataNode(m.Source)
+	if err != nil {
+		return err
+	}
+	dst, err := r.MetaClient.DataNode(m.Dest)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ShardCopier.CopyShard(src.TCPHost, dst.TCPHost, m.ShardID); err != nil {
+		return fmt.Errorf("move %s: %s", m, err)
+	}
+	if m.Hot {
+		n, err := catchUpShard(r.ShardReader, r.ShardWriter, m.ShardID, src, dst, r.digestInterval)
+		if err != nil {
+			return fmt.Errorf("catch up %s: %s", m, err)
+		}
+		r.Logger.Info(fmt.Sprintf("rebalance caught up %d points of %s", n, m))
+	}
+	if err := r.MetaClient.RemoveShardOwner(m.ShardID, m.Source); err != nil {
+		return fmt.Errorf("move %s: %s", m, err)
+	}
+	if err := r.ShardCopier.RemoveShard(src.TCPHost, m.ShardID); err != nil {
+		return fmt.Errorf("remove shard %d from node %d: %s", m.ShardID, m.Source, err)
+	}
+
+	r.Logger.Info("rebalance moved " + m.String())
+	return nil
+}
+
+// shardRef is a shard with the database and retention policy it belongs to.
+type shardRef struct {
+	database string
+	policy   string
+	id       uint64
+	owners   []uint64
+}
+
+func (s *shardRef) ownedBy(id uint64) bool {
+	for _, o := range s.owners {
+		if o == id {
+			return true
+		}
+	}
+	return false
+}
+
+// planShardMoves plans the moves balancing the hot and the cold shards
+// across the data nodes separately. Shard groups ending after now are hot.
+// labels holds the labels of the data nodes by node id.
+func planShardMoves(nodeIDs []uint64, labels map[uint64]map[string]string, dbs []meta.DatabaseInfo, now time.Time) []ShardMove {
+	if len(nodeIDs) < 2 {
+		return nil
+	}
+
+	var hot, cold []*shardRef
+	for _, dbi := range dbs {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+
+				for _, si := range sgi.Shards {
+					ref := &shardRef{database: dbi.Name, policy: rpi.Name, id: si.ID}
+					for _, o := range si.Owners {
+						ref.owners = append(ref.owners, o.NodeID)
+					}
+
+					if sgi.EndTime.After(now) {
+						hot = append(hot, ref)
+					} else {
+						cold = append(cold, ref)
+					}
+				}
+			}
+		}
+	}
+
+	moves := balanceShards(nodeIDs, labels, cold, false)
+	return append(moves, balanceShards(nodeIDs, labels, hot, true)...)
+}
+
+// balanceShards moves owners from the most loaded node to the least loaded
+// nodes until no two nodes differ by more than one shard. An owner is never
+// moved to a node sharing more label values with the other owners than the
+// node it leaves, so moves keep the replicas spread across failure domains.
+func balanceShards(nodeIDs []uint64, labels map[uint64]map[string]string, shards []*shardRef, hot bool) []ShardMove {
+	load := make(map[uint64]int, len(nodeIDs))
+	for _, id := range nodeIDs {
+		load[id] = 0
+	}
+	for _, s := range shards {
+		for _, o := range s.owners {
+			if _, ok := load[o]; ok {
+				load[o]++
+			}
+		}
+	}
+
+	var moves []ShardMove
+	for {
+		// Order nodes by load, breaking ties by node id.
+		ids := append([]uint64(nil), nodeIDs...)
+		sort.Stable(nodesByLoad{ids: ids, load: load})
+		src := ids[len(ids)-1]
+
+		var move *ShardMove
+		for _, dst := range ids {
+			if load[src]-load[dst] <= 1 {
+				break
+			}
+
+			for _, s := range shards {
+				if !s.ownedBy(src) || s.ownedBy(dst) {
+					continue
+				} else if labelConflicts(labels, dst, s.owners, src) > labelConflicts(labels, src, s.owners, src) {
+					continue
+				}
+
+				for i := range s.owners {
+					if s.owners[i] == src {
+						s.owners[i] = dst
+					}
+				}
+				move = &ShardMove{ShardID: s.id, Database: s.database, Policy: s.policy, Source: src, Dest: dst, Hot: hot}
+				break
+			}
+			if move != nil {
+				break
+			}
+		}
+		if move == nil {
+			return moves
+		}
+
+		load[move.Source]--
+		load[move.Dest]++
+		moves = append(moves, *move)
+	}
+}
+
+// labelConflicts returns the number of labels of node id whose value is shared
+// by one of owners other than id and skip. Nodes with fewer conflicts are in a
+// different failure domain.
+func labelConflicts(labels map[uint64]map[string]string, id uint64, owners []uint64, skip uint64) int {
+	var n int
+	for k, v := range labels[id] {
+		for _, o := range owners {
+			if o == id || o == skip {
+				continue
+			} else if ov, ok := labels[o][k]; ok && ov == v {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// nodesByLoad sorts node ids by ascending load.
+type nodesByLoad struct {
+	ids  []uint64
+	load map[uint64]int
+}
+
+func (a nodesByLoad) Len() int      { return len(a.ids) }
+func (a nodesByLoad) Swap(i, j int) { a.ids[i], a.ids[j] = a.ids[j], a.ids[i] }
+func (a nodesByLoad) Less(i, j int) bool {
+	return a.load[a.ids[i]] < a.load[a.ids[j]]
+}
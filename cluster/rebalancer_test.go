@@ -0,0 +1,318 @@
+package cluster_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/zhexuany/influxcloud/cluster"
+	"github.com/zhexuany/influxcloud/rpc"
+)
+
+// Ensure the rebalancer plans moves balancing hot and cold shards separately.
+func TestRebalancer_Plan(t *testing.T) {
+	r := NewTestRebalancer()
+
+	moves, err := r.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []cluster.ShardMove{
+		{ShardID: 4, Database: "db0", Policy: "rp0", Source: 2, Dest: 3},
+		{ShardID: 1, Database: "db0", Policy: "rp0", Source: 1, Dest: 3},
+		{ShardID: 7, Database: "db0", Policy: "rp0", Source: 1, Dest: 2, Hot: true},
+	}
+	if !reflect.DeepEqual(moves, exp) {
+		t.Fatalf("unexpected moves:\n got %v\n exp %v", moves, exp)
+	}
+}
+
+// Ensure the rebalancer doesn't move an owner to a node sharing a label value
+// with the other owners of the shard.
+func TestRebalancer_Plan_Labels(t *testing.T) {
+	r := NewTestRebalancer()
+	r.MetaClient.Nodes = append(r.MetaClient.Nodes, meta.NodeInfo{ID: 4, TCPHost: "host4"})
+	r.MetaClient.Labels = map[uint64]map[string]string{
+		1: {"zone": "a"},
+		2: {"zone": "b"},
+		3: {"zone": "a"},
+		4: {"zone": "b"},
+	}
+
+	sg := meta.ShardGroupInfo{ID: 1, StartTime: time.Now().Add(-2 * time.Hour), EndTime: time.Now().Add(-time.Hour)}
+	for id := uint64(1); id <= 4; id++ {
+		sg.Shards = append(sg.Shards, meta.ShardInfo{ID: id, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}})
+	}
+	r.MetaClient.DatabaseInfos[0].RetentionPolicies[0].ShardGroups = []meta.ShardGroupInfo{sg}
+
+	moves, err := r.Plan()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(moves) != 4 {
+		t.Fatalf("unexpected moves: %v", moves)
+	}
+	for _, m := range moves {
+		if src, dst := r.MetaClient.Labels[m.Source]["zone"], r.MetaClient.Labels[m.Dest]["zone"]; src != dst {
+			t.Fatalf("unexpected move across zones: %+v", m)
+		}
+	}
+}
+
+// Ensure a dry run reports the planned moves without moving any shard.
+func TestRebalancer_Rebalance_DryRun(t *testing.T) {
+	r := NewTestRebalancer()
+
+	report, err := r.Rebalance(true)
+	if err != nil {
+		t.Fatal(err)
+	} else if !report.DryRun || len(report.Moves) != 3 || report.Completed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	} else if len(r.Copier.Copies) != 0 || len(r.MetaClient.Removed) != 0 {
+		t.Fatalf("unexpected moves: %v, %v", r.Copier.Copies, r.MetaClient.Removed)
+	}
+}
+
+// Ensure shards are copied to their new owner before the old owner is
+// removed, and are then deleted from the old owner.
+func TestRebalancer_Rebalance(t *testing.T) {
+	r := NewTestRebalancer()
+
+	report, err := r.Rebalance(false)
+	if err != nil {
+		t.Fatal(err)
+	} else if report.Completed != 3 {
+		t.Fatalf("unexpected completed moves: %d", report.Completed)
+	} else if exp := []string{"host2 host3 4", "host1 host3 1", "host1 host2 7"}; !reflect.DeepEqual(r.Copier.Copies, exp) {
+		t.Fatalf("unexpected copies: %v", r.Copier.Copies)
+	} else if exp := []string{"4 2", "1 1", "7 1"}; !reflect.DeepEqual(r.MetaClient.Removed, exp) {
+		t.Fatalf("unexpected removed owners: %v", r.MetaClient.Removed)
+	} else if exp := []string{"host2 4", "host1 1", "host1 7"}; !reflect.DeepEqual(r.Copier.Removed, exp) {
+		t.Fatalf("unexpected removed shards: %v", r.Copier.Removed)
+	} else if last := r.LastReport(); !reflect.DeepEqual(last, report) {
+		t.Fatalf("unexpected last report: %+v", last)
+	}
+}
+
+// Ensure the points written to the source of a hot move during the copy are
+// written to the destination before the source is removed.
+func TestRebalancer_Rebalance_HotCatchUp(t *testing.T) {
+	r := NewTestRebalancer()
+	r.Reader.Digests = map[string][]rpc.SeriesDigest{
+		"host1": {{Key: "cpu,host=serverA", Start: 0, Count: 2, Sum: 3}, {Key: "cpu,host=serverB", Start: 0, Count: 1, Sum: 5}},
+		"host2": {{Key: "cpu,host=serverA", Start: 0, Count: 1, Sum: 1}, {Key: "cpu,host=serverB", Start: 0, Count: 1, Sum: 5}},
+	}
+	r.Reader.Points = map[string][]models.Point{
+		"host1": {
+			models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "serverA"}), models.Fields{"value": float64(1)}, time.Unix(0, 0)),
+			models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "serverA"}), models.Fields{"value": float64(2)}, time.Unix(10, 0)),
+		},
+	}
+
+	if _, err := r.Rebalance(false); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"host1 7 [cpu,host=serverA] 0"}; !reflect.DeepEqual(r.Reader.Reads, exp) {
+		t.Fatalf("unexpected reads: %v", r.Reader.Reads)
+	} else if exp := []string{
+		"7 2 cpu,host=serverA value=1 0",
+		"7 2 cpu,host=serverA value=2 10000000000",
+	}; !reflect.DeepEqual(r.Writer.Writes, exp) {
+		t.Fatalf("unexpected writes: %v", r.Writer.Writes)
+	} else if exp := []string{"4 2", "1 1", "7 1"}; !reflect.DeepEqual(r.MetaClient.Removed, exp) {
+		t.Fatalf("unexpected removed owners: %v", r.MetaClient.Removed)
+	}
+}
+
+// Ensure the source of a hot move is kept when the catch-up fails.
+func TestRebalancer_Rebalance_HotCatchUpError(t *testing.T) {
+	r := NewTestRebalancer()
+	r.Reader.Err = errors.New("marker")
+
+	report, err := r.Rebalance(false)
+	if err == nil {
+		t.Fatal("expected error")
+	} else if report.Completed != 2 {
+		t.Fatalf("unexpected completed moves: %d", report.Completed)
+	} else if exp := []string{"4 2", "1 1"}; !reflect.DeepEqual(r.MetaClient.Removed, exp) {
+		t.Fatalf("unexpected removed owners: %v", r.MetaClient.Removed)
+	} else if exp := []string{"host2 4", "host1 1"}; !reflect.DeepEqual(r.Copier.Removed, exp) {
+		t.Fatalf("unexpected removed shards: %v", r.Copier.Removed)
+	}
+}
+
+// Ensure the old owner is kept when the copy fails.
+func TestRebalancer_Rebalance_CopyError(t *testing.T) {
+	r := NewTestRebalancer()
+	r.Copier.Err = errors.New("marker")
+
+	report, err := r.Rebalance(false)
+	if err == nil {
+		t.Fatal("expected error")
+	} else if report.Completed != 0 || report.Err != err.Error() {
+		t.Fatalf("unexpected report: %+v", report)
+	} else if len(r.MetaClient.Removed) != 0 {
+		t.Fatalf("unexpected removed owners: %v", r.MetaClient.Removed)
+	} else if len(r.Copier.Removed) != 0 {
+		t.Fatalf("unexpected removed shards: %v", r.Copier.Removed)
+	}
+}
+
+// Ensure nothing is moved without the rebalance lease.
+func TestRebalancer_Rebalance_Lease(t *testing.T) {
+	r := NewTestRebalancer()
+	r.MetaClient.LeaseErr = errors.New("another node has the lease")
+
+	if _, err := r.Rebalance(false); err == nil || err.Error() != "another node has the lease" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(r.Copier.Copies) != 0 {
+		t.Fatalf("unexpected copies: %v", r.Copier.Copies)
+	}
+}
+
+// Rebalancer is a test wrapper for cluster.Rebalancer.
+type Rebalancer struct {
+	*cluster.Rebalancer
+	MetaClient *RebalancerMetaClient
+	Copier     *ShardCopier
+	Reader     *CatchUpShardReader
+	Writer     *RepairShardWriter
+}
+
+// NewTestRebalancer returns a rebalancer for a cluster where node 3 was just
+// added. Nodes 1 and 2 each own three cold shards and node 1 owns both hot
+// shards.
+func NewTestRebalancer() *Rebalancer {
+	now := time.Now()
+	cold := meta.ShardGroupInfo{ID: 1, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}
+	for id := uint64(1); id <= 6; id++ {
+		owner := uint64(1)
+		if id > 3 {
+			owner = 2
+		}
+		cold.Shards = append(cold.Shards, meta.ShardInfo{ID: id, Owners: []meta.ShardOwner{{NodeID: owner}}})
+	}
+	hot := meta.ShardGroupInfo{ID: 2, StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}
+	for id := uint64(7); id <= 8; id++ {
+		hot.Shards = append(hot.Shards, meta.ShardInfo{ID: id, Owners: []meta.ShardOwner{{NodeID: 1}}})
+	}
+
+	c := cluster.NewConfig()
+	c.RebalanceThrottle = 0
+	r := &Rebalancer{
+		Rebalancer: cluster.NewRebalancer(c),
+		MetaClient: &RebalancerMetaClient{
+			Nodes: meta.NodeInfos{{ID: 1, TCPHost: "host1"}, {ID: 2, TCPHost: "host2"}, {ID: 3, TCPHost: "host3"}},
+			DatabaseInfos: []meta.DatabaseInfo{{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{{
+					Name:        "rp0",
+					ShardGroups: []meta.ShardGroupInfo{cold, hot},
+				}},
+			}},
+		},
+		Copier: &ShardCopier{},
+		Reader: &CatchUpShardReader{},
+		Writer: &RepairShardWriter{},
+	}
+	r.Rebalancer.MetaClient = r.MetaClient
+	r.Rebalancer.ShardCopier = r.Copier
+	r.Rebalancer.ShardReader = r.Reader
+	r.Rebalancer.ShardWriter = r.Writer
+	return r
+}
+
+// RebalancerMetaClient is a test meta client recording removed shard owners.
+type RebalancerMetaClient struct {
+	Nodes         meta.NodeInfos
+	Labels        map[uint64]map[string]string
+	DatabaseInfos []meta.DatabaseInfo
+	LeaseErr      error
+	Removed       []string
+}
+
+func (c *RebalancerMetaClient) AcquireLease(name string) (*meta.Lease, error) {
+	if c.LeaseErr != nil {
+		return nil, c.LeaseErr
+	}
+	return &meta.Lease{Name: name, Owner: 1}, nil
+}
+
+func (c *RebalancerMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	for i := range c.Nodes {
+		if c.Nodes[i].ID == id {
+			return &c.Nodes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("node not found: %d", id)
+}
+
+func (c *RebalancerMetaClient) DataNodes() (meta.NodeInfos, error) {
+	return c.Nodes, nil
+}
+
+func (c *RebalancerMetaClient) DataNodeLabels() (map[uint64]map[string]string, error) {
+	return c.Labels, nil
+}
+
+func (c *RebalancerMetaClient) Databases() ([]meta.DatabaseInfo, error) {
+	return c.DatabaseInfos, nil
+}
+
+func (c *RebalancerMetaClient) RemoveShardOwner(shardID, nodeID uint64) error {
+	c.Removed = append(c.Removed, fmt.Sprintf("%d %d", shardID, nodeID))
+	return nil
+}
+
+// ShardCopier is a test shard copier recording copies and removed shards.
+type ShardCopier struct {
+	Copies  []string
+	Removed []string
+	Err     error
+}
+
+func (c *ShardCopier) CopyShard(source, dest string, shardID uint64) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	c.Copies = append(c.Copies, fmt.Sprintf("%s %s %d", source, dest, shardID))
+	return nil
+}
+
+func (c *ShardCopier) RemoveShard(addr string, shardID uint64) error {
+	c.Removed = append(c.Removed, fmt.Sprintf("%s %d", addr, shardID))
+	return nil
+}
+
+// CatchUpShardReader is a test shard reader returning the digests and points
+// of each host and recording the points read.
+type CatchUpShardReader struct {
+	Digests map[string][]rpc.SeriesDigest
+	Points  map[string][]models.Point
+	Reads   []string
+	Err     error
+}
+
+func (r *CatchUpShardReader) ShardDigest(addr string, shardID uint64, interval time.Duration) ([]rpc.SeriesDigest, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return r.Digests[addr], nil
+}
+
+func (r *CatchUpShardReader) ShardPoints(addr string, shardID uint64, keys []string, start, end int64) ([]models.Point, error) {
+	r.Reads = append(r.Reads, fmt.Sprintf("%s %d %v %d", addr, shardID, keys, start))
+
+	var points []models.Point
+	for _, p := range r.Points[addr] {
+		for _, k := range keys {
+			if string(p.Key()) == k && p.UnixNano() >= start && p.UnixNano() < end {
+				points = append(points, p)
+			}
+		}
+	}
+	return points, nil
+}
@@ -1,16 +1,26 @@
 package cluster
 
 import (
+	"errors"
 	"expvar"
+	"io"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud"
+	"github.com/zhexuany/influxcloud/hh"
 	"github.com/zhexuany/influxcloud/rpc"
 	"github.com/zhexuany/influxcloud/tlv"
 	"github.com/uber-go/zap"
@@ -31,34 +41,119 @@ type Service struct {
 
 	Listener net.Listener
 
+	Node *influxcloud.Node
+
+	// HTTPAddr is the address of the HTTP API of this node registered with
+	// the meta service when joining a cluster.
+	HTTPAddr string
+
 	MetaClient interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
 		ShardOwner(shardID uint64) (string, string, meta.ShardInfo)
+		AddPendingShardOwner(shardID, nodeID uint64) error
+		CommitPendingShardOwner(shardID, nodeID uint64) error
+		RemovePendingShardOwner(shardID, nodeID uint64) error
 	}
 
-	TSDBStore coordinator.TSDBStore
+	// MembershipClient registers and removes this node with the meta
+	// service when the node joins or leaves a cluster.
+	MembershipClient interface {
+		SetMetaServers(a []string)
+		CreateDataNodeWithLabels(httpAddr, tcpAddr string, labels map[string]string) (*meta.NodeInfo, error)
+		DeleteDataNode(id uint64) error
+		DataNodes() (meta.NodeInfos, error)
+		DataNodeLabels() (map[uint64]map[string]string, error)
+		Databases() ([]meta.DatabaseInfo, error)
+		CreateDatabase(name string) (*meta.DatabaseInfo, error)
+		RemoveShardOwner(shardID, nodeID uint64) error
+		SetDataNodeDiskUsage(id, size uint64) error
+		UpdateDataNode(id uint64, host, tcpHost string, labels map[string]string) error
+	}
 
-	ShardIteratorCreator coordinator.ShardIteratorCreator
+	TSDBStore interface {
+		coordinator.TSDBStore
+		ShardGroup(ids []uint64) tsdb.ShardGroup
+		Databases() []string
+		Path() string
+		ShardRelativePath(id uint64) (string, error)
+	}
 
-	Logger      zap.Logger
-	ShardWriter ShardWriter
+	TaskManager interface {
+		Queries() []influxql.QueryInfo
+		KillQuery(qid uint64) error
+	}
+
+	// HintedHandoff holds the writes queued for unreachable nodes. Requests
+	// for the queues fail when it is not set.
+	HintedHandoff interface {
+		Status() []hh.NodeStatus
+		Drain(nodeID uint64) error
+		Purge(nodeID uint64) error
+		Pause(nodeID uint64) error
+		Resume(nodeID uint64) error
+	}
+
+	Logger zap.Logger
+
+	// ShardWriter writes the points missed by the new owners of the hot
+	// shards drained from this node.
+	ShardWriter interface {
+		WriteShard(shardID, ownerID uint64, points []models.Point) error
+	}
 
 	statMap *expvar.Map
+
+	// copyShards holds the shard copies running on this node.
+	copyShards     map[copyShardKey]*copyShardTask
+	dialTimeout    time.Duration
+	digestInterval time.Duration
+	diskInterval   time.Duration
+	labels         map[string]string
+	tls            *TLS
+
+	// membershipMu ensures only one join or leave runs at a time.
+	membershipMu sync.Mutex
 }
 
 // NewService returns a new instance of Service.
 func NewService(c Config) *Service {
+	dialTimeout := time.Duration(c.DialTimeout)
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+	digestInterval := time.Duration(c.AntiEntropyDigestInterval)
+	if digestInterval == 0 {
+		digestInterval = DefaultAntiEntropyDigestInterval
+	}
+
 	return &Service{
-		closing: make(chan struct{}),
-		Logger:  zap.New(zap.NullEncoder()),
+		closing:        make(chan struct{}),
+		Logger:         zap.New(zap.NullEncoder()),
+		copyShards:     make(map[copyShardKey]*copyShardTask),
+		dialTimeout:    dialTimeout,
+		digestInterval: digestInterval,
+		diskInterval:   time.Duration(c.DiskUsageReportInterval),
+		labels:         c.Labels,
+		tls:            NewTLS(c),
 	}
 }
 
 // Open opens the network listener and begins serving requests
 func (s *Service) Open() error {
 	s.Logger.Info("Starting cluster service")
+	s.Listener = s.tls.Listen(s.Listener)
+
 	s.wg.Add(1)
 	go s.serve()
 
+	if s.diskInterval > 0 {
+		s.wg.Add(1)
+		go s.reportDiskUsage()
+	}
+
+	s.wg.Add(1)
+	go s.updateDataNode()
+
 	return nil
 }
 
@@ -164,12 +259,161 @@ func (s *Service) handleConn(conn net.Conn) {
 				s.Logger.Warn("process execute statement error:" + err.Error())
 			}
 			s.writeShardResponse(conn, err)
+		case tlv.ShowQuriesStatementRequestMessage:
+			if _, err := tlv.ReadLV(conn); err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.ShowQuriesStatementResponseMessage, s.processShowQueriesRequest()); err != nil {
+				s.Logger.Warn("error writing ShowQueries response: " + err.Error())
+				return
+			}
+		case tlv.KillQueryRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.KillQueryResponseMessage, s.processKillQueryRequest(buf)); err != nil {
+				s.Logger.Warn("error writing KillQuery response: " + err.Error())
+				return
+			}
+		case tlv.CopyShardRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.CopyShardResponseMessage, s.processCopyShardRequest(buf)); err != nil {
+				s.Logger.Warn("error writing CopyShard response: " + err.Error())
+				return
+			}
+		case tlv.CopyShardStatusRequestMessage:
+			if _, err := tlv.ReadLV(conn); err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.CopyShardStatusResponseMessage, s.processCopyShardStatusRequest()); err != nil {
+				s.Logger.Warn("error writing CopyShardStatus response: " + err.Error())
+				return
+			}
+		case tlv.KillCopyShardRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.KillCopyShardReesponseMessage, s.processKillCopyShardRequest(buf)); err != nil {
+				s.Logger.Warn("error writing KillCopyShard response: " + err.Error())
+				return
+			}
+		case tlv.ShardDigestRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.ShardDigestResponseMessage, s.processShardDigestRequest(buf)); err != nil {
+				s.Logger.Warn("error writing ShardDigest response: " + err.Error())
+				return
+			}
+		case tlv.ShardPointsRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.ShardPointsResponseMessage, s.processShardPointsRequest(buf)); err != nil {
+				s.Logger.Warn("error writing ShardPoints response: " + err.Error())
+				return
+			}
+		case tlv.JoinClusterRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.JoinClusterResponseMessage, s.processJoinClusterRequest(buf)); err != nil {
+				s.Logger.Warn("error writing JoinCluster response: " + err.Error())
+				return
+			}
+		case tlv.LeaveClusterRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.LeaveClusterResponseMessage, s.processLeaveClusterRequest(buf)); err != nil {
+				s.Logger.Warn("error writing LeaveCluster response: " + err.Error())
+				return
+			}
+		case tlv.ShardStatusRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.ShardStatusResponseMessage, s.processShardStatusRequest(buf)); err != nil {
+				s.Logger.Warn("error writing ShardStatus response: " + err.Error())
+				return
+			}
+		case tlv.HintedHandoffStatusRequestMessage:
+			if _, err := tlv.ReadLV(conn); err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.HintedHandoffStatusResponseMessage, s.processHintedHandoffStatusRequest()); err != nil {
+				s.Logger.Warn("error writing HintedHandoffStatus response: " + err.Error())
+				return
+			}
+		case tlv.HintedHandoffActionRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.HintedHandoffActionResponseMessage, s.processHintedHandoffActionRequest(buf)); err != nil {
+				s.Logger.Warn("error writing HintedHandoffAction response: " + err.Error())
+				return
+			}
+		case tlv.RemoveShardRequestMessage:
+			buf, err := tlv.ReadLV(conn)
+			if err != nil {
+				s.Logger.Warn("unable to read length-value: " + err.Error())
+				return
+			}
+
+			if err := tlv.EncodeTLV(conn, tlv.RemoveShardResponseMessage, s.processRemoveShardRequest(buf)); err != nil {
+				s.Logger.Warn("error writing RemoveShard response: " + err.Error())
+				return
+			}
+		case tlv.DownloadShardSnapshotRequestMessage:
+			s.processDownloadShardSnapshotRequest(conn)
+			return
+		case tlv.RestoreShardRequestMessage:
+			s.processRestoreShardRequest(conn)
+			return
 		case tlv.CreateIteratorRequestMessage:
 			s.processCreateIteratorRequest(conn)
 			return
 		case tlv.FieldDimensionsRequestMessage:
 			s.processFieldDimensionsRequest(conn)
 			return
+		case tlv.ExpandSourcesRequestMessage:
+			s.processExpandSourcesRequest(conn)
+			return
 		// case seriesKeysRequestMessage:
 		// s.processSeriesKeysRequest(conn)
 		// return
@@ -274,36 +518,42 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 			return err
 		}
 
-		// Collect iterator creators for each shard.
-		// ics := make([]influxql.IteratorCreator, 0, len(req.ShardIDs))
-		// for _, shardID := range req.ShardIDs {
-		// 	ic := s.ShardIteratorCreator.ShardIteratorCreator(shardID)
-		// 	if ic == nil {
-		// 		return nil
-		// 	}
-		// 	ics = append(ics, ic)
-		// }
+		// Map the requested shards under the measurement's source so that
+		// regex measurements are expanded against the local shards only.
+		source := coordinator.Source{
+			Database:        req.Measurement.Database,
+			RetentionPolicy: req.Measurement.RetentionPolicy,
+		}
+		ic := &coordinator.LocalShardMapping{
+			ShardMap: map[coordinator.Source]tsdb.ShardGroup{
+				source: s.TSDBStore.ShardGroup(req.ShardIDs),
+			},
+		}
 
-		// // Generate a single iterator from all shards.
-		// i, err := influxql.IteratorCreators(ics).CreateIterator(req.Opt)
-		// if err != nil {
-		// 	return err
-		// }
-		// itr = i
+		// Generate a single iterator from all shards.
+		i, err := ic.CreateIterator(&req.Measurement, req.Opt)
+		if err != nil {
+			return err
+		}
+		itr = i
 
 		return nil
 	}(); err != nil {
-		itr.Close()
+		if itr != nil {
+			itr.Close()
+		}
 		s.Logger.Warn("error reading CreateIterator request:" + err.Error())
-		// tlv.EncodeTLV(conn, tlv.CreateIteratorResponseMessage, &CreateIteratorResponse{Err: err})
-
-		tlv.EncodeTLV(conn, tlv.CreateIteratorResponseMessage, nil)
+		tlv.EncodeTLV(conn, tlv.CreateIteratorResponseMessage, &rpc.CreateIteratorResponse{Err: err})
 		return
 	}
 
-	// Encode success response.
-	if err := tlv.EncodeTLV(conn, tlv.CreateIteratorResponseMessage, nil); err != nil {
+	// Encode success response along with the type of the streamed points.
+	resp := rpc.CreateIteratorResponse{Type: iteratorType(itr)}
+	if err := tlv.EncodeTLV(conn, tlv.CreateIteratorResponseMessage, &resp); err != nil {
 		s.Logger.Warn("error writing CreateIterator response: " + err.Error())
+		if itr != nil {
+			itr.Close()
+		}
 		return
 	}
 
@@ -311,6 +561,7 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 	if itr == nil {
 		return
 	}
+	defer itr.Close()
 
 	// Stream iterator to connection.
 	if err := influxql.NewIteratorEncoder(conn).EncodeIterator(itr); err != nil {
@@ -319,8 +570,27 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 	}
 }
 
+// iteratorType returns the data type of the points produced by itr.
+func iteratorType(itr influxql.Iterator) influxql.DataType {
+	switch itr.(type) {
+	case influxql.FloatIterator:
+		return influxql.Float
+	case influxql.IntegerIterator:
+		return influxql.Integer
+	case influxql.StringIterator:
+		return influxql.String
+	case influxql.BooleanIterator:
+		return influxql.Boolean
+	default:
+		return influxql.Unknown
+	}
+}
+
 func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
-	var fields, dimensions map[string]struct{}
+	defer conn.Close()
+
+	var fields map[string]influxql.DataType
+	var dimensions map[string]struct{}
 	if err := func() error {
 		// Parse request.
 		var req rpc.FieldDimensionsRequest
@@ -328,29 +598,35 @@ func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 			return err
 		}
 
-		// Collect iterator creators for each shard.
-		// ics := make(influxql.Iterators, 0, len(req.ShardIDs))
-		// for _, shardID := range req.ShardIDs {
-		// 	ic := s.ShardIteratorCreator.ShardIteratorCreator(shardID)
-		// 	if ic == nil {
-		// 		return nil
-		// 	}
-		// 	// ics = append(ics, ic.CreateIterator(nil))
-		// }
-
-		// // Generate a single iterator from all shards.
-		// i, _ := ics.Merge(nil)
-		// f, d, err := influxql.FieldMapper.FieldDimensions(nil)
-		// // f, d, err := influxql.IteratorCreators(ics).FieldDimensions(req.Sources)
-		// if err != nil {
-		// 	return err
-		// }
-		// fields, dimensions = f, d
+		// Union the fields & dimensions of every requested measurement.
+		sg := s.TSDBStore.ShardGroup(req.ShardIDs)
+		fields = make(map[string]influxql.DataType)
+		dimensions = make(map[string]struct{})
+		for _, m := range req.Sources.Measurements() {
+			ic := &coordinator.LocalShardMapping{
+				ShardMap: map[coordinator.Source]tsdb.ShardGroup{
+					{Database: m.Database, RetentionPolicy: m.RetentionPolicy}: sg,
+				},
+			}
+
+			f, d, err := ic.FieldDimensions(m)
+			if err != nil {
+				return err
+			}
+			for k, typ := range f {
+				if fields[k].LessThan(typ) {
+					fields[k] = typ
+				}
+			}
+			for k := range d {
+				dimensions[k] = struct{}{}
+			}
+		}
 
 		return nil
 	}(); err != nil {
 		s.Logger.Warn("error reading FieldDimensions request: " + err.Error())
-		tlv.EncodeTLV(conn, tlv.FieldDimensionsResponseMessage, nil)
+		tlv.EncodeTLV(conn, tlv.FieldDimensionsResponseMessage, &rpc.FieldDimensionsResponse{Err: err})
 		return
 	}
 
@@ -364,33 +640,339 @@ func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 	}
 }
 
-func (s *Service) processJoinClusterRequest() {
+func (s *Service) processCreateShardSnapshotRequest() {
+
+}
+func (s *Service) processDeleteShardSnapshotRequest() {
+
+}
+
+func (s *Service) processExpandSourcesRequest(conn net.Conn) {
+	defer conn.Close()
+
+	var sources influxql.Sources
+	if err := func() error {
+		// Parse request.
+		var req rpc.ExpandSourcesRequest
+		if err := tlv.DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		// Expand the sources against the requested shards.
+		a, err := s.TSDBStore.ShardGroup(req.ShardIDs).ExpandSources(req.Sources)
+		if err != nil {
+			return err
+		}
+		sources = a
+
+		return nil
+	}(); err != nil {
+		s.Logger.Warn("error reading ExpandSources request: " + err.Error())
+		tlv.EncodeTLV(conn, tlv.ExpandSourcesResponseMessage, &rpc.ExpandSourcesResponse{Err: err})
+		return
+	}
+
+	// Encode success response.
+	if err := tlv.EncodeTLV(conn, tlv.ExpandSourcesResponseMessage, &rpc.ExpandSourcesResponse{
+		Sources: sources,
+	}); err != nil {
+		s.Logger.Warn("error writing ExpandSources response: " + err.Error())
+		return
+	}
+}
+
+// processDownloadShardSnapshotRequest streams a snapshot of a local shard to
+// the node copying it. The snapshot is followed by a response reporting
+// whether the snapshot completed.
+func (s *Service) processDownloadShardSnapshotRequest(conn net.Conn) {
+	defer conn.Close()
+
+	w := &snapshotWriter{w: conn}
+	var snapshotTime time.Time
+	err := func() error {
+		var req rpc.DownloadShardSnapshotRequest
+		if err := tlv.DecodeLV(conn, &req); err != nil {
+			return err
+		}
 
+		// Files changed while the snapshot is taken are included again by
+		// a snapshot since this time.
+		snapshotTime = time.Now().UTC()
+		return s.TSDBStore.BackupShard(req.ShardID, req.Since, w)
+	}()
+	if err != nil {
+		s.Logger.Warn("error processing DownloadShardSnapshot request: " + err.Error())
+	}
+
+	if err := w.Close(); err != nil {
+		s.Logger.Warn("error writing DownloadShardSnapshot snapshot: " + err.Error())
+		return
+	}
+	if err := writeDownloadShardSnapshotResponse(conn, snapshotTime, err); err != nil {
+		s.Logger.Warn("error writing DownloadShardSnapshot response: " + err.Error())
+	}
 }
-func (s *Service) writeJoinClusterResponse() {
 
+// writeDownloadShardSnapshotResponse writes the result of a snapshot started
+// at snapshotTime to w.
+func writeDownloadShardSnapshotResponse(w io.Writer, snapshotTime time.Time, err error) error {
+	var resp rpc.DownloadShardSnapshotResponse
+	if err != nil {
+		resp.Err = err.Error()
+	} else {
+		resp.SnapshotTime = snapshotTime
+	}
+	return tlv.EncodeTLV(w, tlv.DownloadShardSnapshotResponseMessage, &resp)
 }
-func (s *Service) importMetaData() {
 
+// processCopyShardRequest copies a shard from the source node to this node.
+// The response is written once the copy has completed or failed.
+func (s *Service) processCopyShardRequest(buf []byte) *rpc.CopyShardResponse {
+	var req rpc.CopyShardRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.CopyShardResponse{Err: err.Error()}
+	}
+
+	if err := s.copyShard(&req); err != nil {
+		s.Logger.Warn(fmt.Sprintf("copy shard %d from %s error: %s", req.ShardID, req.Source, err))
+		return &rpc.CopyShardResponse{Err: err.Error()}
+	}
+	return &rpc.CopyShardResponse{}
 }
-func (s *Service) processLeaveClusterRequest() {
 
+func (s *Service) copyShard(req *rpc.CopyShardRequest) error {
+	if s.Node == nil {
+		return errors.New("copy shard: node id unknown")
+	} else if s.MetaClient == nil {
+		return errors.New("copy shard: meta client not set")
+	}
+
+	db, rp, si := s.MetaClient.ShardOwner(req.ShardID)
+	if req.Database != "" {
+		db = req.Database
+	}
+	if req.Policy != "" {
+		rp = req.Policy
+	}
+	if db == "" || rp == "" {
+		return fmt.Errorf("shard %d not found", req.ShardID)
+	} else if si.OwnedBy(s.Node.ID) {
+		return fmt.Errorf("shard %d already owned by node %d", req.ShardID, s.Node.ID)
+	}
+
+	// Copies are keyed by the destination address, so a request sent to
+	// another node must not run here.
+	self, err := s.MetaClient.DataNode(s.Node.ID)
+	if err != nil {
+		return fmt.Errorf("copy shard: %s", err)
+	} else if req.Dest != self.TCPHost {
+		return fmt.Errorf("copy shard: data node %d is registered as %s, not %s", self.ID, self.TCPHost, req.Dest)
+	}
+
+	task, err := s.startCopyShard(copyShardKey{shardID: req.ShardID, source: req.Source, dest: req.Dest}, db, rp)
+	if err != nil {
+		return err
+	}
+	defer s.finishCopyShard(task)
+
+	conn, err := dialTCPHost(req.Source, s.dialTimeout, s.tls)
+	if err != nil {
+		return err
+	}
+	if err := task.setConn(conn); err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := tlv.EncodeTLV(conn, tlv.DownloadShardSnapshotRequestMessage, &rpc.DownloadShardSnapshotRequest{
+		ShardID: req.ShardID,
+	}); err != nil {
+		return err
+	}
+
+	// Mark this node as a pending owner so queries keep using the existing
+	// owners until the copy is committed.
+	if err := s.MetaClient.AddPendingShardOwner(req.ShardID, s.Node.ID); err != nil {
+		return err
+	}
+
+	if err := func() error {
+		if err := s.TSDBStore.CreateShard(db, rp, req.ShardID, true); err != nil {
+			return fmt.Errorf("create shard %d: %s", req.ShardID, err)
+		}
+		return s.restoreShard(task, conn)
+	}(); err != nil {
+		if task.isKilled() {
+			err = ErrCopyShardKilled
+		}
+		if err := s.TSDBStore.DeleteShard(req.ShardID); err != nil {
+			s.Logger.Warn(fmt.Sprintf("delete shard %d error: %s", req.ShardID, err))
+		}
+		if err := s.MetaClient.RemovePendingShardOwner(req.ShardID, s.Node.ID); err != nil {
+			s.Logger.Warn(fmt.Sprintf("remove pending shard owner %d error: %s", req.ShardID, err))
+		}
+		return err
+	}
+
+	// Pending owners receive no writes, so the points written to the source
+	// during the copy are caught up before this node becomes an owner.
+	n, err := catchUpShard(s.copyShardClient(), storeShardWriter{s.TSDBStore}, req.ShardID, s.copySource(req.Source, si), self, s.digestInterval)
+	if err != nil {
+		if err := s.MetaClient.RemovePendingShardOwner(req.ShardID, s.Node.ID); err != nil {
+			s.Logger.Warn(fmt.Sprintf("remove pending shard owner %d error: %s", req.ShardID, err))
+		}
+		return fmt.Errorf("catch up shard %d: %s", req.ShardID, err)
+	} else if n > 0 {
+		s.Logger.Info(fmt.Sprintf("caught up %d points of shard %d copied from %s", n, req.ShardID, req.Source))
+	}
+
+	return s.MetaClient.CommitPendingShardOwner(req.ShardID, s.Node.ID)
 }
-func (s *Service) writeLeaveClusterResponse() {
 
+// copyShardClient returns a client reading the shards of other nodes.
+func (s *Service) copyShardClient() *Client {
+	c := NewClient(s.dialTimeout)
+	c.TLS = s.tls
+	return c
 }
-func (s *Service) processCreateShardSnapshotRequest() {
 
+// copySource returns the owner of si at addr, or a node with just that
+// address if no owner has it.
+func (s *Service) copySource(addr string, si meta.ShardInfo) *meta.NodeInfo {
+	for _, o := range si.Owners {
+		if n, err := s.MetaClient.DataNode(o.NodeID); err == nil && n != nil && n.TCPHost == addr {
+			return n
+		}
+	}
+	return &meta.NodeInfo{TCPHost: addr}
 }
-func (s *Service) processDeleteShardSnapshotRequest() {
 
+// restoreShard restores the snapshot streamed by the source node and returns
+// any error the source node reported while taking the snapshot.
+func (s *Service) restoreShard(task *copyShardTask, conn net.Conn) error {
+	r := &snapshotReader{r: conn}
+	if err := s.TSDBStore.RestoreShard(task.key.shardID, task.reader(r)); err != nil {
+		return err
+	}
+
+	// Discard anything the restore did not read, such as archive padding.
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return err
+	}
+
+	var resp rpc.DownloadShardSnapshotResponse
+	if _, err := tlv.DecodeTLV(conn, &resp); err != nil {
+		return err
+	} else if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
 }
 
-func (s *Service) processExpandSourcesRequest() {
+// startCopyShard registers a new shard copy.
+func (s *Service) startCopyShard(key copyShardKey, database, policy string) (*copyShardTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.copyShards[key]; ok {
+		return nil, ErrCopyShardInProgress
+	}
 
+	task := &copyShardTask{
+		key:       key,
+		database:  database,
+		policy:    policy,
+		startedAt: time.Now(),
+	}
+	s.copyShards[key] = task
+	return task, nil
 }
-func (s *Service) processDownloadShardSnapshotRequest() {
 
+// finishCopyShard removes a completed shard copy.
+func (s *Service) finishCopyShard(task *copyShardTask) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.copyShards, task.key)
+}
+
+// processCopyShardStatusRequest returns the shard copies running on this node.
+func (s *Service) processCopyShardStatusRequest() *rpc.CopyShardStatusResponse {
+	s.mu.RLock()
+	tasks := make([]rpc.CopyShardStatus, 0, len(s.copyShards))
+	for _, task := range s.copyShards {
+		tasks = append(tasks, task.status())
+	}
+	s.mu.RUnlock()
+
+	sort.Sort(copyShardStatuses(tasks))
+	return &rpc.CopyShardStatusResponse{Tasks: tasks}
+}
+
+// processKillCopyShardRequest aborts a shard copy running on this node.
+func (s *Service) processKillCopyShardRequest(buf []byte) *rpc.KillCopyShardResponse {
+	var req rpc.KillCopyShardRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.KillCopyShardResponse{Err: err.Error()}
+	}
+
+	s.mu.RLock()
+	task := s.copyShards[copyShardKey{shardID: req.ShardID, source: req.Source, dest: req.Dest}]
+	s.mu.RUnlock()
+
+	if task == nil {
+		return &rpc.KillCopyShardResponse{Err: ErrCopyShardNotFound.Error()}
+	}
+	task.kill()
+	return &rpc.KillCopyShardResponse{}
+}
+
+// processShardDigestRequest returns the series digests of a local shard.
+func (s *Service) processShardDigestRequest(buf []byte) *rpc.ShardDigestResponse {
+	var req rpc.ShardDigestRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.ShardDigestResponse{Err: err}
+	} else if req.Interval <= 0 {
+		return &rpc.ShardDigestResponse{Err: fmt.Errorf("invalid digest interval: %s", req.Interval)}
+	}
+
+	digests, err := shardDigests(s.TSDBStore.ShardGroup([]uint64{req.ShardID}), req.Interval)
+	if err != nil {
+		s.Logger.Warn("shard digest error: " + err.Error())
+		return &rpc.ShardDigestResponse{Err: err}
+	}
+	return &rpc.ShardDigestResponse{Digests: digests}
+}
+
+// processShardPointsRequest returns the points of a set of series of a local
+// shard.
+func (s *Service) processShardPointsRequest(buf []byte) *rpc.ShardPointsResponse {
+	var req rpc.ShardPointsRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.ShardPointsResponse{Err: err}
+	}
+
+	keys := make(map[string]struct{}, len(req.Keys))
+	for _, k := range req.Keys {
+		keys[k] = struct{}{}
+	}
+
+	// Only read the measurements and tags of the requested series.
+	scope, err := seriesScope(req.Keys)
+	if err != nil {
+		return &rpc.ShardPointsResponse{Err: err}
+	}
+
+	var points []models.Point
+	if err := scanShard(s.TSDBStore.ShardGroup([]uint64{req.ShardID}), scope, req.Start, req.End-1, func(p models.Point) error {
+		if _, ok := keys[string(p.Key())]; ok {
+			points = append(points, p)
+		}
+		return nil
+	}); err != nil {
+		s.Logger.Warn("shard points error: " + err.Error())
+		return &rpc.ShardPointsResponse{Err: err}
+	}
+	return &rpc.ShardPointsResponse{Points: points}
 }
 
 func (s *Service) shardSnapshot() {
@@ -402,18 +984,184 @@ func (s *Service) deleteSnapshot() {
 func (s *Service) downloadShardSnapshot() {
 
 }
-func (s *Service) processShardStatusRequest() {
 
+// processShardStatusRequest returns the disk size of the requested shards
+// stored on this node. Shards not stored on this node are omitted.
+func (s *Service) processShardStatusRequest(buf []byte) *rpc.ShardStatusResponse {
+	var req rpc.ShardStatusRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.ShardStatusResponse{Err: err.Error()}
+	}
+
+	shards := make([]rpc.ShardStatus, 0, len(req.ShardIDs))
+	for _, id := range req.ShardIDs {
+		path, err := s.TSDBStore.ShardRelativePath(id)
+		if err != nil {
+			continue
+		}
+
+		size, err := dirSize(filepath.Join(s.TSDBStore.Path(), path))
+		if err != nil {
+			return &rpc.ShardStatusResponse{Err: err.Error()}
+		}
+		shards = append(shards, rpc.ShardStatus{ShardID: id, Size: uint64(size)})
+	}
+	return &rpc.ShardStatusResponse{Shards: shards}
+}
+
+// processHintedHandoffStatusRequest returns the hinted handoff queues this
+// node holds for other nodes.
+func (s *Service) processHintedHandoffStatusRequest() *rpc.HintedHandoffStatusResponse {
+	if s.HintedHandoff == nil {
+		return &rpc.HintedHandoffStatusResponse{Err: hh.ErrHintedHandoffDisabled.Error()}
+	}
+
+	statuses := s.HintedHandoff.Status()
+	queues := make([]rpc.HintedHandoffQueue, 0, len(statuses))
+	for _, st := range statuses {
+		queues = append(queues, rpc.HintedHandoffQueue{
+			NodeID:       st.NodeID,
+			Bytes:        st.Bytes,
+			Segments:     st.Segments,
+			HeadModified: st.HeadModified,
+			LastErr:      st.LastErr,
+			LastErrAt:    st.LastErrAt,
+			Paused:       st.Paused,
+		})
+	}
+	return &rpc.HintedHandoffStatusResponse{Queues: queues}
+}
+
+// processHintedHandoffActionRequest drains, purges, pauses or resumes the
+// hinted handoff queue this node holds for another node.
+func (s *Service) processHintedHandoffActionRequest(buf []byte) *rpc.HintedHandoffActionResponse {
+	var req rpc.HintedHandoffActionRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.HintedHandoffActionResponse{Err: err.Error()}
+	} else if s.HintedHandoff == nil {
+		return &rpc.HintedHandoffActionResponse{Err: hh.ErrHintedHandoffDisabled.Error()}
+	}
+
+	var err error
+	switch req.Action {
+	case rpc.HintedHandoffDrain:
+		err = s.HintedHandoff.Drain(req.NodeID)
+	case rpc.HintedHandoffPurge:
+		err = s.HintedHandoff.Purge(req.NodeID)
+	case rpc.HintedHandoffPause:
+		err = s.HintedHandoff.Pause(req.NodeID)
+	case rpc.HintedHandoffResume:
+		err = s.HintedHandoff.Resume(req.NodeID)
+	default:
+		err = fmt.Errorf("unknown hinted handoff action: %s", req.Action)
+	}
+	if err != nil {
+		return &rpc.HintedHandoffActionResponse{Err: err.Error()}
+	}
+	return &rpc.HintedHandoffActionResponse{}
+}
+
+// processRemoveShardRequest deletes a shard stored on this node.
+func (s *Service) processRemoveShardRequest(buf []byte) *rpc.RemoveShardResponse {
+	var req rpc.RemoveShardRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.RemoveShardResponse{Err: err.Error()}
+	}
+
+	if err := s.TSDBStore.DeleteShard(req.ShardID); err != nil {
+		s.Logger.Warn("remove shard error: " + err.Error())
+		return &rpc.RemoveShardResponse{Err: err.Error()}
+	}
+	return &rpc.RemoveShardResponse{}
 }
-func (s *Service) processShowQueriesRequest() {
 
+// dirSize returns the total size of the files under path.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
 }
-func (s *Service) processKillQueryRequest() {
 
+// processShowQueriesRequest returns the queries running on this node.
+func (s *Service) processShowQueriesRequest() *rpc.ShowQueriesResponse {
+	if s.TaskManager == nil {
+		return &rpc.ShowQueriesResponse{}
+	}
+	return &rpc.ShowQueriesResponse{Queries: s.TaskManager.Queries()}
 }
-func (s *Service) processRestoreShard() {
 
+// processKillQueryRequest kills a query running on this node.
+func (s *Service) processKillQueryRequest(buf []byte) *rpc.KillQueryResponse {
+	var req rpc.KillQueryRequest
+	if err := req.UnmarshalBinary(buf); err != nil {
+		return &rpc.KillQueryResponse{Err: err}
+	}
+
+	if s.TaskManager == nil {
+		return &rpc.KillQueryResponse{Err: fmt.Errorf("no such query id: %d", req.QueryID)}
+	}
+	return &rpc.KillQueryResponse{Err: s.TaskManager.KillQuery(req.QueryID)}
 }
+
+// processRestoreShardRequest creates a shard owned by this node and restores
+// the snapshot streamed by the client into it. The snapshot is followed by a
+// response reporting whether the restore completed.
+func (s *Service) processRestoreShardRequest(conn net.Conn) {
+	defer conn.Close()
+
+	r := &snapshotReader{r: conn}
+	err := func() error {
+		var req rpc.RestoreShardRequest
+		if err := tlv.DecodeLV(conn, &req); err != nil {
+			return err
+		} else if s.MetaClient == nil {
+			return errors.New("restore shard: meta client not set")
+		}
+
+		db, rp, _ := s.MetaClient.ShardOwner(req.ShardID)
+		if db == "" || rp == "" {
+			return fmt.Errorf("shard %d not found", req.ShardID)
+		}
+		if err := s.TSDBStore.CreateShard(db, rp, req.ShardID, true); err != nil {
+			return fmt.Errorf("create shard %d: %s", req.ShardID, err)
+		}
+		path, err := s.TSDBStore.ShardRelativePath(req.ShardID)
+		if err != nil {
+			return err
+		}
+
+		// The snapshot may come from a shard with another id, database or
+		// retention policy so its files are moved under the local shard.
+		sr := relocateSnapshot(r, path)
+		defer sr.Close()
+		return s.TSDBStore.RestoreShard(req.ShardID, sr)
+	}()
+	if err != nil {
+		s.Logger.Warn("error processing RestoreShard request: " + err.Error())
+	}
+
+	// Read the rest of the snapshot so the response follows the end of it.
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		s.Logger.Warn("error reading RestoreShard snapshot: " + err.Error())
+		return
+	}
+
+	var resp rpc.RestoreShardResponse
+	if err != nil {
+		resp.Err = err.Error()
+	}
+	if err := tlv.EncodeTLV(conn, tlv.RestoreShardResponseMessage, &resp); err != nil {
+		s.Logger.Warn("error writing RestoreShard response: " + err.Error())
+	}
+}
+
 func (s *Service) processShowMeasurements() {
 
 }
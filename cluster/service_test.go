@@ -27,6 +27,18 @@ func (m *metaClient) ShardOwner(shardID uint64) (db, rp string, si meta.ShardInf
 	return "db", "rp", meta.ShardInfo{}
 }
 
+func (m *metaClient) AddPendingShardOwner(shardID, nodeID uint64) error {
+	return nil
+}
+
+func (m *metaClient) CommitPendingShardOwner(shardID, nodeID uint64) error {
+	return nil
+}
+
+func (m *metaClient) RemovePendingShardOwner(shardID, nodeID uint64) error {
+	return nil
+}
+
 type testService struct {
 	nodeID    uint64
 	ln        net.Listener
@@ -110,8 +122,13 @@ type Service struct {
 
 // NewService returns a new instance of Service.
 func NewService() *Service {
+	return NewServiceWithConfig(cluster.Config{})
+}
+
+// NewServiceWithConfig returns a new instance of Service configured by c.
+func NewServiceWithConfig(c cluster.Config) *Service {
 	s := &Service{
-		Service: cluster.NewService(cluster.Config{}),
+		Service: cluster.NewService(c),
 	}
 	s.Service.TSDBStore = &s.TSDBStore
 	return s
@@ -0,0 +1,245 @@
+package cluster
+
+import (
+	"time"
+
+	"github.com/influxdata/influxdb/coordinator"
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud"
+)
+
+// ShardMapper maps data sources to shards stored on this node and on the
+// remote data nodes that own them.
+type ShardMapper struct {
+	// Timeout is the dial timeout used when connecting to remote nodes.
+	Timeout time.Duration
+
+	// TLS secures the connections to remote nodes if set.
+	TLS *TLS
+
+	Node *influxcloud.Node
+
+	MetaClient interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		ShardsByTimeRange(sources influxql.Sources, tmin, tmax time.Time) (a []meta.ShardInfo, err error)
+	}
+
+	TSDBStore interface {
+		ShardGroup(ids []uint64) tsdb.ShardGroup
+	}
+}
+
+// NewShardMapper returns a new instance of ShardMapper.
+func NewShardMapper(timeout time.Duration) *ShardMapper {
+	return &ShardMapper{
+		Timeout: timeout,
+	}
+}
+
+// MapShards maps the sources to the appropriate local and remote shards.
+func (m *ShardMapper) MapShards(sources influxql.Sources, opt *influxql.SelectOptions) (coordinator.IteratorCreator, error) {
+	a := &shardMapping{
+		local: &coordinator.LocalShardMapping{
+			ShardMap: make(map[coordinator.Source]tsdb.ShardGroup),
+		},
+		remotes: make(map[coordinator.Source]*remoteIteratorCreator),
+	}
+
+	if err := m.mapShards(a, sources, opt); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (m *ShardMapper) mapShards(a *shardMapping, sources influxql.Sources, opt *influxql.SelectOptions) error {
+	for _, s := range sources {
+		switch s := s.(type) {
+		case *influxql.Measurement:
+			source := sourceOf(s)
+
+			// The shards for a database and retention policy are the same
+			// regardless of which measurement is being read.
+			if _, ok := a.local.ShardMap[source]; ok {
+				continue
+			}
+
+			shards, err := m.MetaClient.ShardsByTimeRange(influxql.Sources{s}, opt.MinTime, opt.MaxTime)
+			if err != nil {
+				return err
+			}
+
+			// Read each shard locally if this node owns it. Otherwise read
+			// it from its first owner, falling back to the other owners.
+			var localIDs []uint64
+			owners := make(map[uint64][]uint64)
+			for _, si := range shards {
+				if len(si.Owners) == 0 {
+					continue
+				}
+
+				if m.Node != nil && si.OwnedBy(m.Node.ID) {
+					localIDs = append(localIDs, si.ID)
+					continue
+				}
+
+				for _, o := range si.Owners {
+					owners[si.ID] = append(owners[si.ID], o.NodeID)
+				}
+			}
+
+			if len(localIDs) > 0 {
+				a.local.ShardMap[source] = m.TSDBStore.ShardGroup(localIDs)
+			} else {
+				a.local.ShardMap[source] = nil
+			}
+
+			if len(owners) > 0 {
+				a.remotes[source] = newRemoteIteratorCreator(&NodeDialer{
+					timeout:    m.Timeout,
+					tls:        m.TLS,
+					MetaClient: m.MetaClient,
+				}, owners)
+			}
+		case *influxql.SubQuery:
+			if err := m.mapShards(a, s.Statement.Sources, opt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// shardMapping combines the local shard mapping with iterator creators for
+// the remote nodes owning the remaining shards.
+type shardMapping struct {
+	local   *coordinator.LocalShardMapping
+	remotes map[coordinator.Source]*remoteIteratorCreator
+}
+
+// CreateIterator returns a single iterator merged from the local shards and
+// every remote node.
+func (a *shardMapping) CreateIterator(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+	source := sourceOf(m)
+
+	var itrs influxql.Iterators
+	if itr, err := a.local.CreateIterator(m, opt); err != nil {
+		return nil, err
+	} else if itr != nil {
+		itrs = append(itrs, itr)
+	}
+
+	if ric := a.remotes[source]; ric != nil {
+		remotes, err := ric.createIterators(m, opt)
+		if err != nil {
+			itrs.Close()
+			return nil, err
+		}
+		itrs = append(itrs, remotes...)
+	}
+
+	switch len(itrs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return itrs[0], nil
+	default:
+		return itrs.Merge(opt)
+	}
+}
+
+// FieldDimensions returns the union of fields and dimensions from the local
+// shards and every remote node.
+func (a *shardMapping) FieldDimensions(m *influxql.Measurement) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+	fields, dimensions, err = a.local.FieldDimensions(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ric := a.remotes[sourceOf(m)]
+	if ric == nil {
+		return fields, dimensions, nil
+	}
+
+	f, d, err := ric.FieldDimensions(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if fields == nil {
+		fields = make(map[string]influxql.DataType)
+	}
+	if dimensions == nil {
+		dimensions = make(map[string]struct{})
+	}
+	for k, typ := range f {
+		if fields[k].LessThan(typ) {
+			fields[k] = typ
+		}
+	}
+	for k := range d {
+		dimensions[k] = struct{}{}
+	}
+	return fields, dimensions, nil
+}
+
+// MapType returns the data type of field across the local shards and every
+// remote node.
+func (a *shardMapping) MapType(m *influxql.Measurement, field string) influxql.DataType {
+	typ := a.local.MapType(m, field)
+	if ric := a.remotes[sourceOf(m)]; ric != nil {
+		if t := ric.MapType(m, field); typ.LessThan(t) {
+			typ = t
+		}
+	}
+	return typ
+}
+
+// ExpandSources expands regex sources against the local shards and every
+// remote node.
+func (a *shardMapping) ExpandSources(sources influxql.Sources) (influxql.Sources, error) {
+	set := make(map[string]influxql.Source)
+	for _, src := range sources {
+		m, ok := src.(*influxql.Measurement)
+		if !ok {
+			set[src.String()] = src
+			continue
+		}
+
+		source := sourceOf(m)
+		if sg := a.local.ShardMap[source]; sg != nil {
+			expanded, err := sg.ExpandSources(influxql.Sources{m})
+			if err != nil {
+				return nil, err
+			}
+			for _, src := range expanded {
+				set[src.String()] = src
+			}
+		}
+
+		if ric := a.remotes[source]; ric != nil {
+			expanded, err := ric.ExpandSources(influxql.Sources{m})
+			if err != nil {
+				return nil, err
+			}
+			for _, src := range expanded {
+				set[src.String()] = src
+			}
+		}
+	}
+	return sortedSources(set), nil
+}
+
+// Close closes the shard mapping.
+func (a *shardMapping) Close() error {
+	return a.local.Close()
+}
+
+// sourceOf returns the database and retention policy source of m.
+func sourceOf(m *influxql.Measurement) coordinator.Source {
+	return coordinator.Source{
+		Database:        m.Database,
+		RetentionPolicy: m.RetentionPolicy,
+	}
+}
@@ -0,0 +1,358 @@
+package cluster_test
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud"
+	"github.com/zhexuany/influxcloud/cluster"
+)
+
+// Ensure the shard mapper merges points from local shards and remote owners.
+func TestShardMapper_CreateIterator(t *testing.T) {
+	// Remote node serves shard 2.
+	s := MustOpenService()
+	defer s.Close()
+	s.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		if !reflect.DeepEqual(ids, []uint64{2}) {
+			t.Errorf("unexpected remote shard ids: %v", ids)
+		}
+		return &ShardGroup{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 20, Value: 2},
+			{Name: "cpu", Time: 40, Value: 4},
+		}}
+	}
+
+	m := NewTestShardMapper(s.Addr().String())
+	m.TSDBStore = &TSDBStore{ShardGroupFn: func(ids []uint64) tsdb.ShardGroup {
+		if !reflect.DeepEqual(ids, []uint64{1}) {
+			t.Errorf("unexpected local shard ids: %v", ids)
+		}
+		return &ShardGroup{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 10, Value: 1},
+			{Name: "cpu", Time: 30, Value: 3},
+		}}
+	}}
+
+	mm := &influxql.Measurement{Database: "db0", RetentionPolicy: "rp0", Name: "cpu"}
+	ic, err := m.MapShards(influxql.Sources{mm}, &influxql.SelectOptions{
+		MinTime: time.Unix(0, influxql.MinTime),
+		MaxTime: time.Unix(0, influxql.MaxTime),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ic.Close()
+
+	itr, err := ic.CreateIterator(mm, influxql.IteratorOptions{
+		Expr:      &influxql.VarRef{Val: "value"},
+		StartTime: influxql.MinTime,
+		EndTime:   influxql.MaxTime,
+		Ascending: true,
+		Ordered:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if itr == nil {
+		t.Fatal("expected iterator")
+	}
+	defer itr.Close()
+
+	fitr, ok := itr.(influxql.FloatIterator)
+	if !ok {
+		t.Fatalf("unexpected iterator type: %T", itr)
+	}
+
+	var values []float64
+	for {
+		p, err := fitr.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if p == nil {
+			break
+		}
+		values = append(values, p.Value)
+	}
+
+	if exp := []float64{1, 2, 3, 4}; !reflect.DeepEqual(values, exp) {
+		t.Fatalf("unexpected values: got %v, exp %v", values, exp)
+	}
+}
+
+// Ensure the shard mapper returns the union of fields and dimensions.
+func TestShardMapper_FieldDimensions(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+	s.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		return &ShardGroup{
+			Measurements: []string{"cpu", "cpu_load"},
+			Fields:       map[string]influxql.DataType{"value": influxql.Float, "idle": influxql.Integer},
+			Dimensions:   map[string]struct{}{"region": {}},
+		}
+	}
+
+	m := NewTestShardMapper(s.Addr().String())
+	m.TSDBStore = &TSDBStore{ShardGroupFn: func(ids []uint64) tsdb.ShardGroup {
+		return &ShardGroup{
+			Measurements: []string{"cpu", "mem"},
+			Fields:       map[string]influxql.DataType{"value": influxql.Integer},
+			Dimensions:   map[string]struct{}{"host": {}},
+		}
+	}}
+
+	mm := &influxql.Measurement{Database: "db0", RetentionPolicy: "rp0", Regex: &influxql.RegexLiteral{Val: regexp.MustCompile(`cpu.*`)}}
+	ic, err := m.MapShards(influxql.Sources{mm}, &influxql.SelectOptions{
+		MinTime: time.Unix(0, influxql.MinTime),
+		MaxTime: time.Unix(0, influxql.MaxTime),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ic.Close()
+
+	fields, dimensions, err := ic.FieldDimensions(mm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]influxql.DataType{"value": influxql.Float, "idle": influxql.Integer}; !reflect.DeepEqual(fields, exp) {
+		t.Errorf("unexpected fields: got %v, exp %v", fields, exp)
+	}
+	if exp := map[string]struct{}{"host": {}, "region": {}}; !reflect.DeepEqual(dimensions, exp) {
+		t.Errorf("unexpected dimensions: got %v, exp %v", dimensions, exp)
+	}
+
+	if typ := ic.MapType(mm, "idle"); typ != influxql.Integer {
+		t.Errorf("unexpected type for idle: %v", typ)
+	}
+	if typ := ic.MapType(mm, "region"); typ != influxql.Tag {
+		t.Errorf("unexpected type for region: %v", typ)
+	}
+
+	sources, err := ic.(interface {
+		ExpandSources(influxql.Sources) (influxql.Sources, error)
+	}).ExpandSources(influxql.Sources{mm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := sources.String(), "db0.rp0.cpu, db0.rp0.cpu_load"; got != exp {
+		t.Errorf("unexpected sources: got %s, exp %s", got, exp)
+	}
+}
+
+// Ensure the shard mapper reads a shard from its next owner when the first
+// owner can't be reached.
+func TestShardMapper_CreateIterator_Failover(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+	s.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		return &ShardGroup{Points: []influxql.FloatPoint{{Name: "cpu", Time: 20, Value: 2}}}
+	}
+
+	// Node 3 is listed first but nothing listens on its address.
+	ln := MustListen("tcp", "127.0.0.1:0")
+	down := ln.Addr().String()
+	ln.Close()
+
+	m := NewTestShardMapper(s.Addr().String())
+	m.MetaClient = &ShardMapperMetaClient{
+		Host:  s.Addr().String(),
+		Hosts: map[uint64]string{3: down},
+		Shards: []meta.ShardInfo{
+			{ID: 2, Owners: []meta.ShardOwner{{NodeID: 3}, {NodeID: 2}}},
+		},
+	}
+	m.TSDBStore = &TSDBStore{}
+
+	mm := &influxql.Measurement{Database: "db0", RetentionPolicy: "rp0", Name: "cpu"}
+	ic, err := m.MapShards(influxql.Sources{mm}, &influxql.SelectOptions{
+		MinTime: time.Unix(0, influxql.MinTime),
+		MaxTime: time.Unix(0, influxql.MaxTime),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ic.Close()
+
+	itr, err := ic.CreateIterator(mm, influxql.IteratorOptions{
+		Expr:      &influxql.VarRef{Val: "value"},
+		StartTime: influxql.MinTime,
+		EndTime:   influxql.MaxTime,
+		Ascending: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if itr == nil {
+		t.Fatal("expected iterator")
+	}
+	defer itr.Close()
+
+	p, err := itr.(influxql.FloatIterator).Next()
+	if err != nil {
+		t.Fatal(err)
+	} else if p == nil || p.Value != 2 {
+		t.Fatalf("unexpected point: %v", p)
+	}
+}
+
+// Ensure the fields and dimensions of the remote shards are requested once
+// per query.
+func TestShardMapper_FieldDimensions_Cached(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	var n int
+	s.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		n++
+		return &ShardGroup{
+			Fields:     map[string]influxql.DataType{"value": influxql.Float},
+			Dimensions: map[string]struct{}{"host": {}},
+		}
+	}
+
+	m := NewTestShardMapper(s.Addr().String())
+	m.TSDBStore = &TSDBStore{ShardGroupFn: func(ids []uint64) tsdb.ShardGroup {
+		return &ShardGroup{}
+	}}
+
+	mm := &influxql.Measurement{Database: "db0", RetentionPolicy: "rp0", Name: "cpu"}
+	ic, err := m.MapShards(influxql.Sources{mm}, &influxql.SelectOptions{
+		MinTime: time.Unix(0, influxql.MinTime),
+		MaxTime: time.Unix(0, influxql.MaxTime),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ic.Close()
+
+	if typ := ic.MapType(mm, "value"); typ != influxql.Float {
+		t.Errorf("unexpected type for value: %v", typ)
+	}
+	if typ := ic.MapType(mm, "host"); typ != influxql.Tag {
+		t.Errorf("unexpected type for host: %v", typ)
+	}
+	if _, _, err := ic.FieldDimensions(mm); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Fatalf("unexpected remote requests: %d", n)
+	}
+}
+
+// NewTestShardMapper returns a shard mapper for node 1 which owns shard 1.
+// Shard 2 is owned by node 2 listening on host.
+func NewTestShardMapper(host string) *cluster.ShardMapper {
+	m := cluster.NewShardMapper(time.Second)
+	m.Node = &influxcloud.Node{ID: 1}
+	m.MetaClient = &ShardMapperMetaClient{
+		Host: host,
+		Shards: []meta.ShardInfo{
+			{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}}},
+			{ID: 2, Owners: []meta.ShardOwner{{NodeID: 2}}},
+		},
+	}
+	return m
+}
+
+// ShardMapperMetaClient is a test meta client for the shard mapper.
+// Nodes listen on Host unless they are listed in Hosts.
+type ShardMapperMetaClient struct {
+	Host   string
+	Hosts  map[uint64]string
+	Shards []meta.ShardInfo
+}
+
+func (c *ShardMapperMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	if host, ok := c.Hosts[id]; ok {
+		return &meta.NodeInfo{ID: id, TCPHost: host}, nil
+	}
+	return &meta.NodeInfo{ID: id, TCPHost: c.Host}, nil
+}
+
+func (c *ShardMapperMetaClient) ShardsByTimeRange(sources influxql.Sources, tmin, tmax time.Time) ([]meta.ShardInfo, error) {
+	return c.Shards, nil
+}
+
+// ShardGroup is a test tsdb.ShardGroup returning fixed points, fields and
+// dimensions.
+type ShardGroup struct {
+	Points       []influxql.FloatPoint
+	Measurements []string
+	Fields       map[string]influxql.DataType
+	Dimensions   map[string]struct{}
+
+	// Iterators records the measurement and condition of every iterator
+	// created.
+	Iterators []string
+}
+
+func (sg *ShardGroup) MeasurementsByRegex(re *regexp.Regexp) []string {
+	var names []string
+	for _, name := range sg.Measurements {
+		if re.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (sg *ShardGroup) FieldDimensions(measurements []string) (map[string]influxql.DataType, map[string]struct{}, error) {
+	return sg.Fields, sg.Dimensions, nil
+}
+
+func (sg *ShardGroup) MapType(measurement, field string) influxql.DataType {
+	if typ, ok := sg.Fields[field]; ok {
+		return typ
+	} else if _, ok := sg.Dimensions[field]; ok {
+		return influxql.Tag
+	}
+	return influxql.Unknown
+}
+
+func (sg *ShardGroup) CreateIterator(measurement string, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+	sg.Iterators = append(sg.Iterators, fmt.Sprintf("%s %v", measurement, opt.Condition))
+	return &FloatIterator{Points: sg.Points}, nil
+}
+
+func (sg *ShardGroup) ExpandSources(sources influxql.Sources) (influxql.Sources, error) {
+	var expanded influxql.Sources
+	for _, src := range sources {
+		m := src.(*influxql.Measurement)
+		if m.Regex == nil {
+			expanded = append(expanded, m)
+			continue
+		}
+		for _, name := range sg.MeasurementsByRegex(m.Regex.Val) {
+			expanded = append(expanded, &influxql.Measurement{
+				Database:        m.Database,
+				RetentionPolicy: m.RetentionPolicy,
+				Name:            name,
+			})
+		}
+	}
+	return expanded, nil
+}
+
+// FloatIterator is a test float iterator.
+type FloatIterator struct {
+	Points []influxql.FloatPoint
+}
+
+func (itr *FloatIterator) Stats() influxql.IteratorStats { return influxql.IteratorStats{} }
+
+func (itr *FloatIterator) Close() error { return nil }
+
+func (itr *FloatIterator) Next() (*influxql.FloatPoint, error) {
+	if len(itr.Points) == 0 {
+		return nil, nil
+	}
+	p := itr.Points[0]
+	itr.Points = itr.Points[1:]
+	return &p, nil
+}
@@ -18,6 +18,9 @@ type ShardWriter struct {
 	timeout        time.Duration
 	maxConnections int
 
+	// TLS secures the connections to the owners of the shards if set.
+	TLS *TLS
+
 	MetaClient interface {
 		ShardOwner(shardID uint64) (database, policy string, owners meta.ShardInfo)
 		DataNode(id uint64) (ni *meta.NodeInfo, err error)
@@ -35,20 +38,20 @@ func NewShardWriter(timeout time.Duration, maxConnections int) *ShardWriter {
 
 // WriteShard writes time series points to a shard
 func (w *ShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point) error {
-	buf := make([]byte, 0)
+	a := make([][]byte, 0, len(points))
 	for _, p := range points {
 		b, err := p.MarshalBinary()
 		if err != nil {
 			return fmt.Errorf("failed to marshal point: %v", err)
 		}
-		buf = append(buf, b...)
+		a = append(a, b)
 	}
-	return w.WriteShardBinary(shardID, ownerID, buf)
-
+	return w.WriteShardBinary(shardID, ownerID, a)
 }
 
-// WriteShardBinary writes binary time series points to a shard
-func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, buf []byte) error {
+// WriteShardBinary writes binary time series points to a shard, one
+// encoded point per element of points.
+func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, points [][]byte) error {
 	c, err := w.dial(ownerID)
 	if err != nil {
 		return err
@@ -74,7 +77,9 @@ func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, buf []byte) erro
 	request.SetShardID(shardID)
 	request.SetDatabase(db)
 	request.SetRetentionPolicy(rp)
-	request.SetBinaryPoints(buf)
+	for _, b := range points {
+		request.SetBinaryPoints(b)
+	}
 
 	// Marshal into protocol buffers.
 	reqB, err := request.MarshalBinary()
@@ -96,7 +101,7 @@ func (w *ShardWriter) WriteShardBinary(shardID, ownerID uint64, buf []byte) erro
 
 	// Read the response.
 	conn.SetReadDeadline(time.Now().Add(w.timeout))
-	_, buf, err = tlv.ReadTLV(conn)
+	_, buf, err := tlv.ReadTLV(conn)
 	if err != nil {
 		conn.MarkUnusable()
 		return err
@@ -119,7 +124,7 @@ func (w *ShardWriter) dial(nodeID uint64) (net.Conn, error) {
 	// If we don't have a connection pool for that addr yet, create one
 	_, ok := w.pool.getPool(nodeID)
 	if !ok {
-		factory := &connFactory{nodeID: nodeID, clientPool: w.pool, timeout: w.timeout}
+		factory := &connFactory{nodeID: nodeID, clientPool: w.pool, timeout: w.timeout, tls: w.TLS}
 		factory.metaClient = w.MetaClient
 
 		p, err := NewBoundedPool(1, w.maxConnections, w.timeout, factory.dial)
@@ -151,6 +156,7 @@ var errMaxConnectionsExceeded = fmt.Errorf("can not exceed max connections of %d
 type connFactory struct {
 	nodeID  uint64
 	timeout time.Duration
+	tls     *TLS
 
 	clientPool interface {
 		size() int
@@ -175,17 +181,5 @@ func (c *connFactory) dial() (net.Conn, error) {
 		return nil, fmt.Errorf("node %d does not exist", c.nodeID)
 	}
 
-	conn, err := net.DialTimeout("tcp", ni.TCPHost, c.timeout)
-	if err != nil {
-		return nil, err
-	}
-
-	// Write a marker byte for cluster messages.
-	_, err = conn.Write([]byte{MuxHeader})
-	if err != nil {
-		conn.Close()
-		return nil, err
-	}
-
-	return conn, nil
+	return dialTCPHost(ni.TCPHost, c.timeout, c.tls)
 }
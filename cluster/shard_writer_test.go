@@ -124,6 +124,47 @@ func TestShardWriter_WriteShard_Multiple(t *testing.T) {
 	validatePoint(responses, t, now)
 }
 
+// Ensure the shard writer sends every point of a request.
+func TestShardWriter_WriteShard_Points(t *testing.T) {
+	ts := newTestWriteService(nil)
+	ts.TSDBStore.WriteToShardFn = ts.writeShardSuccess
+	s := cluster.NewService(cluster.Config{})
+	s.Listener = ts.muxln
+	s.TSDBStore = &ts.TSDBStore
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer ts.Close()
+
+	w := cluster.NewShardWriter(time.Minute, 1)
+	w.MetaClient = &metaClient{host: ts.ln.Addr().String()}
+
+	// Build two points.
+	now := time.Now()
+	points := []models.Point{
+		models.MustNewPoint("cpu", newTags(), newFields(), now),
+		models.MustNewPoint("cpu", newTags(), newFields(), now.Add(time.Second)),
+	}
+
+	// Write to shard and close.
+	if err := w.WriteShard(1, 2, points); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate response.
+	responses, err := ts.ResponseN(1)
+	if err != nil {
+		t.Fatal(err)
+	} else if n := len(responses[0].points); n != 2 {
+		t.Fatalf("unexpected point count: %d", n)
+	} else if tm := responses[0].points[1].Time(); !tm.Equal(now.Add(time.Second)) {
+		t.Fatalf("unexpected time: %s", tm)
+	}
+}
+
 // Ensure the shard writer returns an error when the server fails to accept the write.
 func TestShardWriter_WriteShard_Error(t *testing.T) {
 	ts := newTestWriteService(writeShardFail)
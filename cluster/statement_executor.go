@@ -1,7 +1,11 @@
 package cluster
 
 import (
+	"encoding"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/influxdata/influxdb/coordinator"
@@ -18,7 +22,11 @@ type StatementExecutor struct {
 	timeout        time.Duration
 	maxConnections int
 
+	// TLS secures the connections to the other data nodes if set.
+	TLS *TLS
+
 	MetaClient interface {
+		DataNode(id uint64) (ni *meta.NodeInfo, err error)
 		DataNodes() (ni meta.NodeInfos, err error)
 	}
 
@@ -26,6 +34,15 @@ type StatementExecutor struct {
 	StatementExecutor coordinator.StatementExecutor
 }
 
+// NewStatementExecutor returns a new instance of StatementExecutor.
+func NewStatementExecutor(timeout time.Duration, maxConnections int) *StatementExecutor {
+	return &StatementExecutor{
+		pool:           newClientPool(),
+		timeout:        timeout,
+		maxConnections: maxConnections,
+	}
+}
+
 // ExecuteStatement executes the given statement with the given execution context.
 func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
 	switch t := stmt.(type) {
@@ -37,70 +54,183 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 	return e.StatementExecutor.ExecuteStatement(stmt, ctx)
 }
 
+// NormalizeStatement adds a default database and policy to the measurements
+// in the statement.
+func (e *StatementExecutor) NormalizeStatement(stmt influxql.Statement, defaultDatabase string) error {
+	return e.StatementExecutor.NormalizeStatement(stmt, defaultDatabase)
+}
+
+// executeShowQueriesStatement collects the running queries from every data
+// node and returns them as a single result with the host of each query.
+// Nodes which can't be reached are reported as warnings of the result.
 func (e *StatementExecutor) executeShowQueriesStatement(stmt *influxql.ShowQueriesStatement, ctx influxql.ExecutionContext) error {
 	dataNodes, err := e.MetaClient.DataNodes()
 	if err != nil {
 		return err
 	}
 
-	for _, data := range dataNodes {
-		go func(data meta.NodeInfo) error {
-			conn, err := e.pool.conn(data.ID)
-			if err != nil {
-				return fmt.Errorf("failed to connect:%v", err)
-			}
-			req := rpc.ExecuteStatementRequest{}
-			req.SetDatabase(ctx.Database)
-			req.SetStatement(stmt.String())
-			err = tlv.EncodeTLV(conn, tlv.ExecuteStatementRequestMessage, &req)
-			if err != nil {
-				return fmt.Errorf("failed to encode tlv: %v", err)
-			}
+	type nodeQueries struct {
+		i       int
+		queries []influxql.QueryInfo
+		err     error
+	}
 
-			res := rpc.ExecuteStatementResponse{}
-			if _, err := tlv.DecodeTLV(conn, &res); err != nil {
-				return fmt.Errorf("failed to decode tlv: %v", err)
-			} else if res.Code() != 200 {
-				return fmt.Errorf("response status is not correct")
+	ch := make(chan nodeQueries, len(dataNodes))
+	for i, node := range dataNodes {
+		go func(i int, node meta.NodeInfo) {
+			var resp rpc.ShowQueriesResponse
+			err := e.requestNode(node.ID, tlv.ShowQuriesStatementRequestMessage, &rpc.ShowQueriesRequest{}, tlv.ShowQuriesStatementResponseMessage, &resp)
+			if err == nil {
+				err = resp.Err
 			}
-			return nil
-		}(data)
+			ch <- nodeQueries{i: i, queries: resp.Queries, err: err}
+		}(i, node)
 	}
-	return nil
+
+	// Keep the queries in the same order as the data nodes.
+	queries := make([][]influxql.QueryInfo, len(dataNodes))
+	errs := make([]error, len(dataNodes))
+	for range dataNodes {
+		r := <-ch
+		queries[r.i], errs[r.i] = r.queries, r.err
+	}
+
+	var messages []*influxql.Message
+	for i, err := range errs {
+		if err != nil {
+			messages = append(messages, &influxql.Message{
+				Level: influxql.WarningLevel,
+				Text:  remoteNodeError{id: dataNodes[i].ID, err: err}.Error(),
+			})
+		}
+	}
+
+	values := make([][]interface{}, 0)
+	for i, node := range dataNodes {
+		sort.Sort(queryInfos(queries[i]))
+		for _, q := range queries[i] {
+			values = append(values, []interface{}{q.ID, node.Host, q.Query, q.Database, formatQueryDuration(q.Duration)})
+		}
+	}
+
+	return ctx.Send(&influxql.Result{
+		StatementID: ctx.StatementID,
+		Series: []*models.Row{{
+			Columns: []string{"qid", "host", "query", "database", "duration"},
+			Values:  values,
+		}},
+		Messages: messages,
+	})
 }
 
+// executeKillQueryStatement kills a query on the node named by the ON clause.
+// Without an ON clause the query is killed on the local node.
 func (e *StatementExecutor) executeKillQueryStatement(stmt *influxql.KillQueryStatement, ctx influxql.ExecutionContext) error {
+	if stmt.Host == "" {
+		return e.StatementExecutor.ExecuteStatement(stmt, ctx)
+	}
+
 	dataNodes, err := e.MetaClient.DataNodes()
 	if err != nil {
 		return err
 	}
 
-	for _, data := range dataNodes {
-		go func(data meta.NodeInfo) error {
-			conn, err := e.pool.conn(data.ID)
-			if err != nil {
-				return fmt.Errorf("failed to connect:%v", err)
-			}
-			req := rpc.ExecuteStatementRequest{}
-			req.SetDatabase(ctx.Database)
-			req.SetStatement(stmt.String())
-			err = tlv.EncodeTLV(conn, tlv.ExecuteStatementRequestMessage, &req)
-			if err != nil {
-				return fmt.Errorf("failed to encode tlv: %v", err)
-			}
+	node := findDataNode(dataNodes, stmt.Host)
+	if node == nil {
+		return fmt.Errorf("data node not found: %s", stmt.Host)
+	}
 
-			res := rpc.ExecuteStatementResponse{}
-			if _, err := tlv.DecodeTLV(conn, &res); err != nil {
-				return fmt.Errorf("failed to decode tlv: %v", err)
-			} else if res.Code() != 200 {
-				return fmt.Errorf("response status is not correct")
-			}
-			return nil
-		}(data)
+	var resp rpc.KillQueryResponse
+	if err := e.requestNode(node.ID, tlv.KillQueryRequestMessage, &rpc.KillQueryRequest{QueryID: stmt.QueryID}, tlv.KillQueryResponseMessage, &resp); err != nil {
+		return remoteNodeError{id: node.ID, err: err}
+	} else if resp.Err != nil {
+		return resp.Err
+	}
+
+	return ctx.Send(&influxql.Result{StatementID: ctx.StatementID})
+}
+
+// requestNode sends a single request to a node over a pooled connection and
+// decodes its response.
+func (e *StatementExecutor) requestNode(nodeID uint64, reqType byte, req encoding.BinaryMarshaler, respType byte, resp encoding.BinaryUnmarshaler) error {
+	c, err := e.dial(nodeID)
+	if err != nil {
+		return err
+	}
+
+	conn, ok := c.(*pooledConn)
+	if !ok {
+		panic("wrong connection type in StatementExecutor")
+	}
+	// Return connection to pool by "closing" it.
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(e.timeout))
+	if err := tlv.EncodeTLV(conn, reqType, req); err != nil {
+		conn.MarkUnusable()
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(e.timeout))
+	if typ, err := tlv.DecodeTLV(conn, resp); err != nil {
+		conn.MarkUnusable()
+		return err
+	} else if typ != respType {
+		conn.MarkUnusable()
+		return fmt.Errorf("unexpected response type: %d", typ)
 	}
 	return nil
 }
 
+// dial returns a connection to a single node in the cluster.
+func (e *StatementExecutor) dial(nodeID uint64) (net.Conn, error) {
+	// If we don't have a connection pool for that addr yet, create one
+	_, ok := e.pool.getPool(nodeID)
+	if !ok {
+		factory := &connFactory{nodeID: nodeID, clientPool: e.pool, timeout: e.timeout, tls: e.TLS}
+		factory.metaClient = e.MetaClient
+
+		p, err := NewBoundedPool(1, e.maxConnections, e.timeout, factory.dial)
+		if err != nil {
+			return nil, err
+		}
+		e.pool.setPool(nodeID, p)
+	}
+	return e.pool.conn(nodeID)
+}
+
+// findDataNode returns the node matching host by its HTTP host, TCP host or ID.
+func findDataNode(nodes meta.NodeInfos, host string) *meta.NodeInfo {
+	for i := range nodes {
+		n := &nodes[i]
+		if n.Host == host || n.TCPHost == host || strconv.FormatUint(n.ID, 10) == host {
+			return n
+		}
+	}
+	return nil
+}
+
+// queryInfos sorts queries by id.
+type queryInfos []influxql.QueryInfo
+
+func (a queryInfos) Len() int           { return len(a) }
+func (a queryInfos) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a queryInfos) Less(i, j int) bool { return a[i].ID < a[j].ID }
+
+// formatQueryDuration truncates d to the same precision used by the local
+// SHOW QUERIES output.
+func formatQueryDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		d = d - (d % time.Second)
+	case d >= time.Millisecond:
+		d = d - (d % time.Millisecond)
+	case d >= time.Microsecond:
+		d = d - (d % time.Microsecond)
+	}
+	return d.String()
+}
+
 // IntoWriteRequest is a partial copy of cluster.WriteRequest
 type IntoWriteRequest struct {
 	Database        string
@@ -1,15 +1,20 @@
 package cluster_test
 
 import (
+	"fmt"
 	"io"
+	"reflect"
+	"strings"
+	"testing"
 	"time"
 
 	// "github.com/davecgh/go-spew/spew"
+	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
-	// "github.com/influxdata/influxdb/services/meta"
-	"github.com/influxdata/influxdb/coordinator"
+	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/zhexuany/influxcloud/cluster"
 )
 
 const (
@@ -20,6 +25,161 @@ const (
 	DefaultRetentionPolicy = "rp0"
 )
 
+// Ensure SHOW QUERIES merges the running queries of every data node.
+func TestStatementExecutor_ExecuteStatement_ShowQueries(t *testing.T) {
+	s1, s2 := MustOpenService(), MustOpenService()
+	defer s1.Close()
+	defer s2.Close()
+	s1.Service.TaskManager = &TaskManager{QueriesFn: func() []influxql.QueryInfo {
+		return []influxql.QueryInfo{{ID: 2, Query: "SELECT * FROM mem", Database: "db0", Duration: 1500 * time.Millisecond}}
+	}}
+	s2.Service.TaskManager = &TaskManager{QueriesFn: func() []influxql.QueryInfo {
+		return []influxql.QueryInfo{{ID: 1, Query: "SELECT * FROM cpu", Database: "db1", Duration: 2 * time.Second}}
+	}}
+
+	e := NewStatementExecutor(s1, s2)
+	result, err := e.ExecuteStatement(&influxql.ShowQueriesStatement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := [][]interface{}{
+		{uint64(2), "host1", "SELECT * FROM mem", "db0", "1s"},
+		{uint64(1), "host2", "SELECT * FROM cpu", "db1", "2s"},
+	}; !reflect.DeepEqual(result.Series[0].Values, exp) {
+		t.Fatalf("unexpected values: got %v, exp %v", result.Series[0].Values, exp)
+	}
+}
+
+// Ensure SHOW QUERIES returns the queries of the reachable nodes and a
+// warning for every node which can't be reached.
+func TestStatementExecutor_ExecuteStatement_ShowQueries_NodeDown(t *testing.T) {
+	s1, s2 := MustOpenService(), MustOpenService()
+	defer s1.Close()
+	s1.Service.TaskManager = &TaskManager{QueriesFn: func() []influxql.QueryInfo {
+		return []influxql.QueryInfo{{ID: 2, Query: "SELECT * FROM mem", Database: "db0", Duration: time.Second}}
+	}}
+
+	e := NewStatementExecutor(s1, s2)
+	s2.Close()
+
+	result, err := e.ExecuteStatement(&influxql.ShowQueriesStatement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := [][]interface{}{
+		{uint64(2), "host1", "SELECT * FROM mem", "db0", "1s"},
+	}; !reflect.DeepEqual(result.Series[0].Values, exp) {
+		t.Fatalf("unexpected values: got %v, exp %v", result.Series[0].Values, exp)
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("unexpected messages: %v", result.Messages)
+	} else if m := result.Messages[0]; m.Level != influxql.WarningLevel || !strings.Contains(m.Text, "node 2") {
+		t.Fatalf("unexpected message: %v", m)
+	}
+}
+
+// Ensure KILL QUERY ... ON kills the query only on the named node.
+func TestStatementExecutor_ExecuteStatement_KillQueryOn(t *testing.T) {
+	s1, s2 := MustOpenService(), MustOpenService()
+	defer s1.Close()
+	defer s2.Close()
+	s1.Service.TaskManager = &TaskManager{KillQueryFn: func(qid uint64) error {
+		t.Error("unexpected kill on node 1")
+		return nil
+	}}
+
+	var killed uint64
+	s2.Service.TaskManager = &TaskManager{KillQueryFn: func(qid uint64) error {
+		killed = qid
+		return nil
+	}}
+
+	e := NewStatementExecutor(s1, s2)
+	if _, err := e.ExecuteStatement(&influxql.KillQueryStatement{QueryID: 5, Host: "host2"}); err != nil {
+		t.Fatal(err)
+	} else if killed != 5 {
+		t.Fatalf("unexpected killed query id: %d", killed)
+	}
+
+	// Errors from the remote node are returned.
+	s2.Service.TaskManager = &TaskManager{KillQueryFn: func(qid uint64) error {
+		return fmt.Errorf("no such query id: %d", qid)
+	}}
+	if _, err := e.ExecuteStatement(&influxql.KillQueryStatement{QueryID: 6, Host: "2"}); err == nil || err.Error() != "no such query id: 6" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Unknown nodes are rejected.
+	if _, err := e.ExecuteStatement(&influxql.KillQueryStatement{QueryID: 5, Host: "host3"}); err == nil || err.Error() != "data node not found: host3" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// StatementExecutor is a test wrapper for cluster.StatementExecutor.
+type StatementExecutor struct {
+	*cluster.StatementExecutor
+}
+
+// NewStatementExecutor returns a statement executor for a cluster of services.
+// Each service is registered as data node "host<n>" with ID n.
+func NewStatementExecutor(services ...*Service) *StatementExecutor {
+	var nodes meta.NodeInfos
+	for i, s := range services {
+		nodes = append(nodes, meta.NodeInfo{
+			ID:      uint64(i + 1),
+			Host:    fmt.Sprintf("host%d", i+1),
+			TCPHost: s.Addr().String(),
+		})
+	}
+
+	e := &StatementExecutor{StatementExecutor: cluster.NewStatementExecutor(time.Second, 3)}
+	e.MetaClient = &StatementExecutorMetaClient{nodes: nodes}
+	return e
+}
+
+// ExecuteStatement executes stmt and returns its single result.
+func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement) (*influxql.Result, error) {
+	results := make(chan *influxql.Result, 1)
+	if err := e.StatementExecutor.ExecuteStatement(stmt, influxql.ExecutionContext{Results: results}); err != nil {
+		return nil, err
+	}
+	return <-results, nil
+}
+
+// StatementExecutorMetaClient is a test meta client returning fixed data nodes.
+type StatementExecutorMetaClient struct {
+	nodes meta.NodeInfos
+}
+
+func (c *StatementExecutorMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	for i := range c.nodes {
+		if c.nodes[i].ID == id {
+			return &c.nodes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *StatementExecutorMetaClient) DataNodes() (meta.NodeInfos, error) {
+	return c.nodes, nil
+}
+
+// TaskManager is a mockable implementation of the cluster service's task manager.
+type TaskManager struct {
+	QueriesFn   func() []influxql.QueryInfo
+	KillQueryFn func(qid uint64) error
+}
+
+func (tm *TaskManager) Queries() []influxql.QueryInfo {
+	return tm.QueriesFn()
+}
+
+func (tm *TaskManager) KillQuery(qid uint64) error {
+	return tm.KillQueryFn(qid)
+}
+
 // // Ensure query executor can execute a simple SELECT statement.
 // func TestQueryExecutor_ExecuteQuery_SelectStatement(t *testing.T) {
 // 	e := DefaultQueryExecutor()
@@ -210,6 +370,10 @@ type TSDBStore struct {
 	DeleteShardFn           func(id uint64) error
 	DeleteSeriesFn          func(database string, sources []influxql.Source, condition influxql.Expr) error
 	ShardIteratorCreatorFn  func(id uint64) influxql.IteratorCreator
+	ShardGroupFn            func(ids []uint64) tsdb.ShardGroup
+	DatabasesFn             func() []string
+	PathFn                  func() string
+	ShardRelativePathFn     func(id uint64) (string, error)
 	BackupShardFn           func(id uint64, since time.Time, w io.Writer) error
 	MeasurementsFn          func(databse string, cond influxql.Expr) ([]string, error)
 	RestoreShardFn          func(id uint64, r io.Reader) error
@@ -224,6 +388,9 @@ func (s *TSDBStore) CreateShard(database, policy string, shardID uint64, enabled
 }
 
 func (s *TSDBStore) WriteToShard(shardID uint64, points []models.Point) error {
+	if s.WriteToShardFn == nil {
+		return nil
+	}
 	return s.WriteToShardFn(shardID, points)
 }
 
@@ -231,8 +398,7 @@ func (s *TSDBStore) RestoreShard(id uint64, r io.Reader) error {
 	if s.RestoreShardFn == nil {
 		return nil
 	}
-
-	return s.RestoreShard(id, r)
+	return s.RestoreShardFn(id, r)
 }
 
 func (s *TSDBStore) BackupShard(id uint64, since time.Time, w io.Writer) error {
@@ -269,6 +435,34 @@ func (s *TSDBStore) Measurements(database string, cond influxql.Expr) ([]string,
 	return s.MeasurementsFn(database, cond)
 }
 
+func (s *TSDBStore) ShardGroup(ids []uint64) tsdb.ShardGroup {
+	if s.ShardGroupFn == nil {
+		return &ShardGroup{}
+	}
+	return s.ShardGroupFn(ids)
+}
+
+func (s *TSDBStore) Databases() []string {
+	if s.DatabasesFn == nil {
+		return nil
+	}
+	return s.DatabasesFn()
+}
+
+func (s *TSDBStore) Path() string {
+	if s.PathFn == nil {
+		return ""
+	}
+	return s.PathFn()
+}
+
+func (s *TSDBStore) ShardRelativePath(id uint64) (string, error) {
+	if s.ShardRelativePathFn == nil {
+		return "", fmt.Errorf("shard %d doesn't exist on this server", id)
+	}
+	return s.ShardRelativePathFn(id)
+}
+
 func (s *TSDBStore) TagValues(database string, cond influxql.Expr) ([]tsdb.TagValues, error) {
 	if s.TagValuesFn == nil {
 		return nil, nil
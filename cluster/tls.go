@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLS secures the TCP traffic between data nodes with mutual TLS. Both ends
+// present a certificate signed by the CA bundle of the cluster. The
+// certificate, key and CA bundle are reloaded when their files change so
+// certificates can be rotated without a restart.
+//
+// A nil TLS leaves connections in plaintext.
+type TLS struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu      sync.Mutex
+	modTime time.Time
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+}
+
+// NewTLS returns the TLS configured by c, nil if TLS is disabled.
+func NewTLS(c Config) *TLS {
+	if !c.TLSEnabled {
+		return nil
+	}
+	return &TLS{
+		certFile: c.TLSCertificate,
+		keyFile:  c.TLSPrivateKey,
+		caFile:   c.TLSCABundle,
+	}
+}
+
+// NewTLSFromFiles returns a TLS using the given certificate, key and CA
+// bundle.
+func NewTLSFromFiles(certFile, keyFile, caFile string) *TLS {
+	return &TLS{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+	}
+}
+
+// Listen returns a listener accepting TLS connections from nodes with a
+// valid client certificate.
+func (t *TLS) Listen(ln net.Listener) net.Listener {
+	if t == nil {
+		return ln
+	}
+
+	return tls.NewListener(ln, &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool, err := t.load()
+			if err != nil {
+				return nil, err
+			}
+			return &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    pool,
+				MinVersion:   tls.VersionTLS12,
+			}, nil
+		},
+	})
+}
+
+// client starts a TLS session on conn to the node at addr. The handshake
+// must complete within timeout.
+func (t *TLS) client(conn net.Conn, addr string, timeout time.Duration) (net.Conn, error) {
+	if t == nil {
+		return conn, nil
+	}
+
+	cert, pool, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		Certificates: []tls.Certificate{*cert},
+		RootCAs:      pool,
+		ServerName:   host,
+		MinVersion:   tls.VersionTLS12,
+	})
+	if timeout > 0 {
+		tlsConn.SetDeadline(time.Now().Add(timeout))
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, fmt.Errorf("tls handshake with %s: %s", addr, err)
+	}
+	tlsConn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// load returns the certificate and CA pool, reloading them if any of their
+// files changed since they were last loaded.
+func (t *TLS) load() (*tls.Certificate, *x509.CertPool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var modTime time.Time
+	for _, path := range []string{t.certFile, t.keyFile, t.caFile} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, nil, err
+		} else if fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
+		}
+	}
+	if t.cert != nil && modTime.Equal(t.modTime) {
+		return t.cert, t.pool, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(t.certFile, t.keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load certificate: %s", err)
+	}
+	ca, err := ioutil.ReadFile(t.caFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, nil, fmt.Errorf("no certificates in CA bundle %s", t.caFile)
+	}
+
+	t.cert, t.pool, t.modTime = &cert, pool, modTime
+	return t.cert, t.pool, nil
+}
@@ -0,0 +1,154 @@
+package cluster_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cluster"
+)
+
+// Ensure requests between nodes with TLS enabled require a certificate
+// signed by the CA bundle of the cluster.
+func TestClient_TLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxcloud-cluster-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Nodes share one certificate, a second set is signed by another CA.
+	nodeDir, otherDir := filepath.Join(dir, "node"), filepath.Join(dir, "other")
+	MustWriteCertificates(nodeDir)
+	MustWriteCertificates(otherDir)
+
+	s := MustOpenTLSService(cluster.Config{
+		TLSEnabled:     true,
+		TLSCertificate: filepath.Join(nodeDir, "cert.pem"),
+		TLSPrivateKey:  filepath.Join(nodeDir, "key.pem"),
+		TLSCABundle:    filepath.Join(nodeDir, "ca.pem"),
+	})
+	defer s.Close()
+	s.TSDBStore.DeleteShardFn = func(id uint64) error { return nil }
+
+	c := cluster.NewClient(time.Second)
+	if err := c.RemoveShard(s.Addr().String(), 1); err == nil {
+		t.Fatal("expected plaintext request to fail")
+	}
+
+	c.TLS = cluster.NewTLSFromFiles(filepath.Join(otherDir, "cert.pem"), filepath.Join(otherDir, "key.pem"), filepath.Join(otherDir, "ca.pem"))
+	if err := c.RemoveShard(s.Addr().String(), 1); err == nil {
+		t.Fatal("expected request with untrusted certificate to fail")
+	}
+
+	c.TLS = cluster.NewTLSFromFiles(filepath.Join(nodeDir, "cert.pem"), filepath.Join(nodeDir, "key.pem"), filepath.Join(nodeDir, "ca.pem"))
+	if err := c.RemoveShard(s.Addr().String(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotating the files of the node to the other CA is picked up without
+	// a restart.
+	for _, name := range []string{"cert.pem", "key.pem", "ca.pem"} {
+		b, err := ioutil.ReadFile(filepath.Join(otherDir, name))
+		if err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(filepath.Join(nodeDir, name), b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		future := time.Now().Add(time.Minute)
+		if err := os.Chtimes(filepath.Join(nodeDir, name), future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.TLS = cluster.NewTLSFromFiles(filepath.Join(otherDir, "cert.pem"), filepath.Join(otherDir, "key.pem"), filepath.Join(otherDir, "ca.pem"))
+	if err := c.RemoveShard(s.Addr().String(), 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// MustOpenTLSService returns a new, open service with TLS configured by c.
+func MustOpenTLSService(c cluster.Config) *Service {
+	s := NewServiceWithConfig(c)
+	s.ln = MustListen("tcp", "127.0.0.1:0")
+	s.Listener = &muxListener{s.ln}
+	if err := s.Open(); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// MustWriteCertificates writes a new CA to ca.pem and a certificate for
+// 127.0.0.1 signed by it to cert.pem and key.pem in dir. Panic on error.
+func MustWriteCertificates(dir string) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		panic(err)
+	}
+
+	caKey := MustGenerateKey()
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "influxcloud test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+	ca, err = x509.ParseCertificate(caDER)
+	if err != nil {
+		panic(err)
+	}
+
+	key := MustGenerateKey()
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, cert, ca, &key.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	MustWritePEM(filepath.Join(dir, "ca.pem"), "CERTIFICATE", caDER)
+	MustWritePEM(filepath.Join(dir, "cert.pem"), "CERTIFICATE", certDER)
+	MustWritePEM(filepath.Join(dir, "key.pem"), "EC PRIVATE KEY", keyDER)
+}
+
+// MustGenerateKey returns a new private key. Panic on error.
+func MustGenerateKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// MustWritePEM writes a PEM block to path. Panic on error.
+func MustWritePEM(path, typ string, b []byte) {
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0600); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zhexuany/influxcloud/rpc"
+)
+
+// hhStatus prints the hinted handoff queues a data node holds for other nodes
+// with the size and age of the writes pending.
+func (m *Main) hhStatus(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("hh-status", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	tcpAddr, err := m.dataNodeTCPHost(args[0])
+	if err != nil {
+		return err
+	}
+	queues, err := m.client.HintedHandoffStatus(tcpAddr)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(m.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "Node\tBytes\tSegments\tHead Age\tPaused\tLast Error")
+	for _, q := range queues {
+		headAge := "-"
+		if !q.HeadModified.IsZero() {
+			headAge = time.Since(q.HeadModified).Truncate(time.Second).String()
+		}
+		lastErr := "-"
+		if q.LastErr != "" {
+			lastErr = fmt.Sprintf("%s: %s", q.LastErrAt.Format(time.RFC3339), q.LastErr)
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%t\t%s\n", q.NodeID, q.Bytes, q.Segments, headAge, q.Paused, lastErr)
+	}
+	return w.Flush()
+}
+
+// hhDrain replays the hinted handoff queue a data node holds for a node now
+// rather than at the next retry interval.
+func (m *Main) hhDrain(args []string) error {
+	return m.hhAction("hh-drain", rpc.HintedHandoffDrain, "Draining", args, true)
+}
+
+// hhPurge deletes the writes a data node queued for a node. The writes are
+// lost unless the node already holds them.
+func (m *Main) hhPurge(args []string) error {
+	return m.hhAction("hh-purge", rpc.HintedHandoffPurge, "Purged", args, true)
+}
+
+// hhPause stops a data node replaying its hinted handoff queues. Writes keep
+// being queued while replay is paused.
+func (m *Main) hhPause(args []string) error {
+	return m.hhAction("hh-pause", rpc.HintedHandoffPause, "Paused", args, false)
+}
+
+// hhResume restarts replay of paused hinted handoff queues.
+func (m *Main) hhResume(args []string) error {
+	return m.hhAction("hh-resume", rpc.HintedHandoffResume, "Resumed", args, false)
+}
+
+// hhAction applies action to the hinted handoff queue a data node holds for
+// the node given by args. Unless the node is required the action is applied
+// to every queue of the data node when it is omitted.
+func (m *Main) hhAction(name, action, verb string, args []string, nodeRequired bool) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if n := fs.NArg(); nodeRequired && n != 2 {
+		return fmt.Errorf("%s: expected 2 arguments, got %d\n\n%s", name, n, usage)
+	} else if n != 1 && n != 2 {
+		return fmt.Errorf("%s: expected 1 or 2 arguments, got %d\n\n%s", name, n, usage)
+	}
+	args = fs.Args()
+	addr := args[0]
+	tcpAddr, err := m.dataNodeTCPHost(addr)
+	if err != nil {
+		return err
+	}
+
+	var nodeIDs []uint64
+	if len(args) == 2 {
+		id, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid node id: %s", args[1])
+		}
+		nodeIDs = append(nodeIDs, id)
+	} else {
+		queues, err := m.client.HintedHandoffStatus(tcpAddr)
+		if err != nil {
+			return err
+		}
+		for _, q := range queues {
+			nodeIDs = append(nodeIDs, q.NodeID)
+		}
+	}
+
+	for _, id := range nodeIDs {
+		if err := m.client.HintedHandoffAction(tcpAddr, id, action); err != nil {
+			return fmt.Errorf("node %d: %s", id, err)
+		}
+		fmt.Fprintf(m.Stdout, "%s hinted handoff queue for node %d on %s\n", verb, id, addr)
+	}
+	return nil
+}
+
+// dataNodeTCPHost returns the TCP address of the data node serving HTTP at
+// httpAddr, which the hinted handoff queues are requested from.
+func (m *Main) dataNodeTCPHost(httpAddr string) (string, error) {
+	c, err := m.openMetaClient()
+	if err != nil {
+		return "", err
+	}
+	n, err := c.DataNodeByHTTPHost(httpAddr)
+	if err != nil {
+		return "", fmt.Errorf("data node %s: %s", httpAddr, err)
+	}
+	return n.TCPHost, nil
+}
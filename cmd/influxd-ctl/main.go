@@ -0,0 +1,323 @@
+// Command influxd-ctl manages the meta and data nodes of a cluster.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cluster"
+	"github.com/zhexuany/influxcloud/meta"
+)
+
+// These variables are populated via the Go linker.
+var (
+	version string
+	commit  string
+	branch  string
+)
+
+func init() {
+	// If commit, branch, or build time are not set, make that clear.
+	if version == "" {
+		version = "unknown"
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	if branch == "" {
+		branch = "unknown"
+	}
+}
+
+func main() {
+	m := NewMain()
+	if err := m.Run(os.Args[1:]...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Main represents the program execution.
+type Main struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// bind is the HTTP address of the meta node commands are sent to.
+	bind    string
+	timeout time.Duration
+
+	// secret signs requests to meta nodes with auth enabled.
+	secret string
+
+	// https is set when the meta nodes serve HTTPS.
+	https bool
+
+	// tlsCert, tlsKey and tlsCA secure requests to data nodes with TLS
+	// enabled.
+	tlsCert string
+	tlsKey  string
+	tlsCA   string
+
+	metaClient *meta.Client
+	client     *cluster.Client
+}
+
+// NewMain return a new instance of Main.
+func NewMain() *Main {
+	return &Main{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run determines and runs the command specified by the CLI args.
+func (m *Main) Run(args ...string) error {
+	fs := flag.NewFlagSet("influxd-ctl", flag.ContinueOnError)
+	fs.SetOutput(m.Stderr)
+	fs.StringVar(&m.bind, "bind", "localhost:8091", "")
+	fs.DurationVar(&m.timeout, "timeout", 10*time.Second, "")
+	fs.StringVar(&m.secret, "secret", "", "")
+	fs.BoolVar(&m.https, "https", false, "")
+	fs.StringVar(&m.tlsCert, "tls-certificate", "", "")
+	fs.StringVar(&m.tlsKey, "tls-private-key", "", "")
+	fs.StringVar(&m.tlsCA, "tls-ca-bundle", "", "")
+	fs.Usage = func() { fmt.Fprintln(m.Stderr, usage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fmt.Fprintln(m.Stderr, usage)
+		return errors.New("command required")
+	}
+	name, args := args[0], args[1:]
+
+	m.client = cluster.NewClient(m.timeout)
+	if m.tlsCert != "" || m.tlsKey != "" || m.tlsCA != "" {
+		if m.tlsCert == "" || m.tlsKey == "" || m.tlsCA == "" {
+			return errors.New("-tls-certificate, -tls-private-key and -tls-ca-bundle must be set together")
+		}
+		m.client.TLS = cluster.NewTLSFromFiles(m.tlsCert, m.tlsKey, m.tlsCA)
+	}
+
+	switch name {
+	case "show":
+		return m.show(args)
+	case "show-raft":
+		return m.showRaft(args)
+	case "add-meta":
+		return m.addMeta(args)
+	case "remove-meta":
+		return m.removeMeta(args)
+	case "add-data":
+		return m.addData(args)
+	case "remove-data":
+		return m.removeData(args)
+	case "show-shards":
+		return m.showShards(args)
+	case "copy-shard":
+		return m.copyShard(args)
+	case "copy-shard-status":
+		return m.copyShardStatus(args)
+	case "kill-copy-shard":
+		return m.killCopyShard(args)
+	case "remove-shard":
+		return m.removeShard(args)
+	case "truncate-shards":
+		return m.truncateShards(args)
+	case "set-shard-placement":
+		return m.setShardPlacement(args)
+	case "split-shard-group":
+		return m.splitShardGroup(args)
+	case "hh-status":
+		return m.hhStatus(args)
+	case "hh-drain":
+		return m.hhDrain(args)
+	case "hh-purge":
+		return m.hhPurge(args)
+	case "hh-pause":
+		return m.hhPause(args)
+	case "hh-resume":
+		return m.hhResume(args)
+	case "version":
+		fmt.Fprintf(m.Stdout, "influxd-ctl v%s (git: %s %s)\n", version, branch, commit)
+		return nil
+	case "help":
+		fmt.Fprintln(m.Stdout, usage)
+		return nil
+	default:
+		return fmt.Errorf(`unknown command "%s"`+"\n"+`Run 'influxd-ctl help' for usage`, name)
+	}
+}
+
+// openMetaClient returns a meta client connected to the meta node at bind.
+func (m *Main) openMetaClient() (*meta.Client, error) {
+	if m.metaClient != nil {
+		return m.metaClient, nil
+	}
+
+	config := meta.NewConfig()
+	config.AuthEnabled = m.secret != ""
+	config.InternalSharedSecret = m.secret
+
+	c := meta.NewClient(config)
+	c.SetMetaServers([]string{m.bind})
+	c.SetTLS(m.https)
+
+	// Fail fast instead of retrying when the meta node is down.
+	if err := c.Ping(false); err != nil {
+		return nil, fmt.Errorf("ping meta node %s: %s", m.bind, err)
+	}
+	if err := c.Open(); err != nil {
+		return nil, err
+	}
+	m.metaClient = c
+	return c, nil
+}
+
+// metaURL returns the URL of path on the meta node serving HTTP at host.
+func (m *Main) metaURL(host, path string) string {
+	if m.https {
+		return "https://" + host + path
+	}
+	return "http://" + host + path
+}
+
+// getJSON decodes the JSON response of a GET request to url into v.
+func (m *Main) getJSON(url string, v interface{}) error {
+	b, err := m.get(url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// get returns the body of a successful GET request to url.
+func (m *Main) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if m.secret != "" {
+		if err := meta.SignRequest(req, m.secret); err != nil {
+			return nil, err
+		}
+	}
+
+	c := http.Client{Timeout: m.timeout}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s: %s", url, resp.Status, b)
+	}
+	return b, nil
+}
+
+// parseArgs parses the flags of a command and ensures n positional arguments
+// are given.
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	} else if fs.NArg() != n {
+		return nil, fmt.Errorf("%s: expected %d arguments, got %d\n\n%s", fs.Name(), n, fs.NArg(), usage)
+	}
+	return fs.Args(), nil
+}
+
+// parseShardID parses a shard id argument.
+func parseShardID(s string) (uint64, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid shard id: %s", s)
+	}
+	return id, nil
+}
+
+var usage = `Manages the meta and data nodes of a cluster.
+
+Usage: influxd-ctl [options] <command> [arguments]
+
+Options:
+    -bind <addr>
+            HTTP address of a meta node. Defaults to localhost:8091.
+    -timeout <duration>
+            Timeout for requests to meta and data nodes. Defaults to 10s.
+    -secret <secret>
+            Shared secret signing requests to meta nodes with auth enabled.
+    -https
+            Connect to meta nodes with HTTPS enabled.
+    -tls-certificate <path> -tls-private-key <path> -tls-ca-bundle <path>
+            Client certificate, key and CA bundle for data nodes with
+            cluster TLS enabled.
+
+Commands:
+    show
+            Show the meta and data nodes of the cluster.
+    show-raft
+            Show the raft leader and peers seen by every meta node.
+    add-meta <http-addr> <tcp-addr>
+            Add a meta node to the raft cluster.
+    remove-meta <http-addr>
+            Remove a meta node from the cluster. The removed node leaves the
+            raft cluster, clear its meta directory before adding it again.
+    add-data [-import] <tcp-addr>
+            Join a data node to the cluster. With -import the databases
+            stored on the data node are created in the cluster.
+    remove-data [-force] <tcp-addr>
+            Move the shards of a data node to other nodes and remove it.
+            With -force the node is removed without moving its shards.
+    show-shards
+            Show the shards with their owners and sizes.
+    copy-shard <source-tcp-addr> <dest-tcp-addr> <shard-id>
+            Copy a shard between data nodes.
+    copy-shard-status
+            Show the running shard copies.
+    kill-copy-shard <source-tcp-addr> <dest-tcp-addr> <shard-id>
+            Abort a running shard copy.
+    remove-shard <tcp-addr> <shard-id>
+            Remove a shard from a data node.
+    truncate-shards [-delay <duration>]
+            End the shard groups accepting writes after delay so new writes
+            go to new shard groups spread across all data nodes. Defaults
+            to a delay of 1m.
+    set-shard-placement <database> <retention-policy> <round-robin|balanced>
+            Select how owners of new shard groups of a retention policy are
+            placed. Balanced placement fills the data nodes owning the
+            fewest shards first.
+    split-shard-group [-shards <n>] [-delay <duration>] <database> <retention-policy> <shard-group-id>
+            Spread writes to a shard group across more shards after delay.
+            The shard group keeps the data written before, queries cover the
+            shards of both. Defaults to one shard per data node divided by
+            the replication factor and a delay of 1m.
+    hh-status <http-addr>
+            Show the hinted handoff queues a data node holds for other
+            nodes with the bytes pending, segment count, age of the oldest
+            write and last replay error.
+    hh-drain <http-addr> <node-id>
+            Replay the hinted handoff queue for a node now.
+    hh-purge <http-addr> <node-id>
+            Delete the writes queued for a node. The writes are lost.
+    hh-pause <http-addr> [node-id]
+            Pause replay of the hinted handoff queue for a node, or of
+            every queue of the data node. Writes are still queued.
+    hh-resume <http-addr> [node-id]
+            Resume replay of paused hinted handoff queues.
+    version
+            Display the version.
+`
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// show prints the meta and data nodes of the cluster.
+func (m *Main) show(args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("show", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	dataNodes, err := c.DataNodes()
+	if err != nil {
+		return err
+	}
+	metaNodes, err := c.MetaNodes()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(m.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "Data Nodes\n==========")
+	fmt.Fprintln(w, "ID\tTCP Address\tHTTP Address\tLabels")
+	for _, n := range dataNodes {
+		labels := make([]string, 0, len(n.Labels))
+		for k, v := range n.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", n.ID, n.TCPHost, n.Host, strings.Join(labels, ","))
+	}
+	fmt.Fprintln(w, "\nMeta Nodes\n==========")
+	fmt.Fprintln(w, "ID\tHTTP Address\tTCP Address")
+	for _, n := range metaNodes {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", n.ID, n.Host, n.TCPHost)
+	}
+	return w.Flush()
+}
+
+// showRaft prints the raft leader and peers seen by every meta node.
+func (m *Main) showRaft(args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("show-raft", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	metaNodes, err := c.MetaNodes()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(m.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "ID\tHTTP Address\tLeader\tPeers")
+	for _, n := range metaNodes {
+		leader, err := m.get(m.metaURL(n.Host, "/ping"))
+		if err != nil {
+			fmt.Fprintf(w, "%d\t%s\tunreachable: %s\t\n", n.ID, n.Host, err)
+			continue
+		}
+
+		var peers []string
+		if err := m.getJSON(m.metaURL(n.Host, "/peers"), &peers); err != nil {
+			fmt.Fprintf(w, "%d\t%s\t%s\tunreachable: %s\n", n.ID, n.Host, leader, err)
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", n.ID, n.Host, leader, strings.Join(peers, ","))
+	}
+	return w.Flush()
+}
+
+// addMeta adds a meta node to the raft cluster.
+func (m *Main) addMeta(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("add-meta", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	n, err := c.JoinMetaServer(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Added meta node %d at %s\n", n.ID, n.Host)
+	return nil
+}
+
+// removeMeta removes a meta node from the cluster.
+func (m *Main) removeMeta(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("remove-meta", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	n := c.MetaNodeByAddr(args[0])
+	if n == nil {
+		return fmt.Errorf("meta node not found: %s", args[0])
+	}
+	if err := c.DeleteMetaNode(n.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Removed meta node %d at %s\n", n.ID, n.Host)
+	return nil
+}
+
+// addData joins a data node to the cluster.
+func (m *Main) addData(args []string) error {
+	fs := flag.NewFlagSet("add-data", flag.ContinueOnError)
+	importMetaData := fs.Bool("import", false, "")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	metaNodes, err := c.MetaNodes()
+	if err != nil {
+		return err
+	}
+
+	// Give the data node every meta node so it survives the loss of one.
+	metaAddrs := []string{m.bind}
+	for _, n := range metaNodes {
+		if n.Host != m.bind {
+			metaAddrs = append(metaAddrs, n.Host)
+		}
+	}
+
+	id, err := m.client.JoinCluster(args[0], metaAddrs, *importMetaData)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Added data node %d at %s\n", id, args[0])
+	return nil
+}
+
+// removeData drains and removes a data node from the cluster.
+func (m *Main) removeData(args []string) error {
+	fs := flag.NewFlagSet("remove-data", flag.ContinueOnError)
+	force := fs.Bool("force", false, "")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if !*force {
+		if err := m.client.LeaveCluster(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(m.Stdout, "Removed data node at %s\n", args[0])
+		return nil
+	}
+
+	// The data node is unreachable so remove it from the meta data only.
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	n, err := c.DataNodeByTCPHost(args[0])
+	if err != nil {
+		return fmt.Errorf("data node %s: %s", args[0], err)
+	}
+	if err := c.DeleteDataNode(n.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Removed data node %d at %s without moving its shards\n", n.ID, n.TCPHost)
+	return nil
+}
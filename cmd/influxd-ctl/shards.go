@@ -0,0 +1,267 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// showShards prints every shard with its owners and their on-disk sizes.
+func (m *Main) showShards(args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("show-shards", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	nodes, err := c.DataNodes()
+	if err != nil {
+		return err
+	}
+	dbs, err := c.Databases()
+	if err != nil {
+		return err
+	}
+
+	// Collect the shard ids owned by each data node.
+	owned := make(map[uint64][]uint64)
+	for _, dbi := range dbs {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				for _, si := range sgi.Shards {
+					for _, o := range si.Owners {
+						owned[o.NodeID] = append(owned[o.NodeID], si.ID)
+					}
+				}
+			}
+		}
+	}
+
+	// Ask each data node for the sizes of its shards. Unreachable nodes are
+	// reported without sizes.
+	hosts := make(map[uint64]string, len(nodes))
+	sizes := make(map[uint64]map[uint64]uint64, len(nodes))
+	for _, n := range nodes {
+		hosts[n.ID] = n.TCPHost
+		if len(owned[n.ID]) == 0 {
+			continue
+		}
+
+		shards, err := m.client.ShardStatus(n.TCPHost, owned[n.ID])
+		if err != nil {
+			fmt.Fprintf(m.Stderr, "shard status of data node %d (%s): %s\n", n.ID, n.TCPHost, err)
+			continue
+		}
+		sizes[n.ID] = make(map[uint64]uint64, len(shards))
+		for _, s := range shards {
+			sizes[n.ID][s.ShardID] = s.Size
+		}
+	}
+
+	w := tabwriter.NewWriter(m.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "ID\tDatabase\tRetention Policy\tStart\tEnd\tOwners")
+	for _, dbi := range dbs {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+
+				for _, si := range sgi.Shards {
+					owners := make([]string, 0, len(si.Owners))
+					for _, o := range si.Owners {
+						owner := fmt.Sprintf("%d(%s)", o.NodeID, hosts[o.NodeID])
+						if size, ok := sizes[o.NodeID][si.ID]; ok {
+							owner += fmt.Sprintf(":%d", size)
+						}
+						owners = append(owners, owner)
+					}
+
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", si.ID, dbi.Name, rpi.Name,
+						sgi.StartTime.UTC().Format(time.RFC3339), sgi.EndTime.UTC().Format(time.RFC3339),
+						strings.Join(owners, ","))
+				}
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// copyShard copies a shard from one data node to another.
+func (m *Main) copyShard(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("copy-shard", flag.ContinueOnError), args, 3)
+	if err != nil {
+		return err
+	}
+	id, err := parseShardID(args[2])
+	if err != nil {
+		return err
+	}
+
+	if err := m.client.CopyShard(args[0], args[1], id); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Copied shard %d from %s to %s\n", id, args[0], args[1])
+	return nil
+}
+
+// copyShardStatus prints the running shard copies of every data node.
+func (m *Main) copyShardStatus(args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("copy-shard-status", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	nodes, err := c.DataNodes()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(m.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "Source\tDest\tDatabase\tPolicy\tShardID\tCopied\tStarted")
+	for _, n := range nodes {
+		tasks, err := m.client.CopyShardStatus(n.TCPHost)
+		if err != nil {
+			fmt.Fprintf(m.Stderr, "copy shard status of data node %d (%s): %s\n", n.ID, n.TCPHost, err)
+			continue
+		}
+
+		for _, t := range tasks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n", t.Source, t.Dest, t.Database, t.Policy,
+				t.ShardID, t.CurrentSize, t.StartedAt.UTC().Format(time.RFC3339))
+		}
+	}
+	return w.Flush()
+}
+
+// killCopyShard aborts a running shard copy.
+func (m *Main) killCopyShard(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("kill-copy-shard", flag.ContinueOnError), args, 3)
+	if err != nil {
+		return err
+	}
+	id, err := parseShardID(args[2])
+	if err != nil {
+		return err
+	}
+
+	if err := m.client.KillCopyShard(args[0], args[1], id); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Killed copy of shard %d from %s to %s\n", id, args[0], args[1])
+	return nil
+}
+
+// removeShard removes a data node as an owner of a shard and deletes the
+// shard from it.
+func (m *Main) removeShard(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("remove-shard", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseShardID(args[1])
+	if err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	n, err := c.DataNodeByTCPHost(args[0])
+	if err != nil {
+		return fmt.Errorf("data node %s: %s", args[0], err)
+	}
+
+	// Stop routing writes and queries to the node before deleting the data.
+	if err := c.RemoveShardOwner(id, n.ID); err != nil {
+		return err
+	}
+	if err := m.client.RemoveShard(args[0], id); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Removed shard %d from %s\n", id, args[0])
+	return nil
+}
+
+// truncateShards ends the shard groups accepting writes so new writes are
+// spread across the current data nodes.
+func (m *Main) truncateShards(args []string) error {
+	fs := flag.NewFlagSet("truncate-shards", flag.ContinueOnError)
+	delay := fs.Duration("delay", time.Minute, "")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+
+	// The delay gives data nodes time to see the new shard groups before
+	// writes are routed to them.
+	t := time.Now().Add(*delay).UTC()
+	if err := c.TruncateShardGroups(t); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Truncated shard groups at %s\n", t.Format(time.RFC3339))
+	return nil
+}
+
+// setShardPlacement selects the shard placement strategy of a retention
+// policy.
+func (m *Main) setShardPlacement(args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("set-shard-placement", flag.ContinueOnError), args, 3)
+	if err != nil {
+		return err
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+	if err := c.SetShardPlacement(args[0], args[1], args[2]); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Set shard placement of %s.%s to %s\n", args[0], args[1], args[2])
+	return nil
+}
+
+// splitShardGroup spreads the writes to a shard group across more shards.
+func (m *Main) splitShardGroup(args []string) error {
+	fs := flag.NewFlagSet("split-shard-group", flag.ContinueOnError)
+	shardN := fs.Int("shards", 0, "")
+	delay := fs.Duration("delay", time.Minute, "")
+	args, err := parseArgs(fs, args, 3)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid shard group id: %s", args[2])
+	}
+
+	c, err := m.openMetaClient()
+	if err != nil {
+		return err
+	}
+
+	// The delay gives data nodes time to see the new shard group before
+	// writes are routed to it.
+	t := time.Now().Add(*delay).UTC()
+	sg, err := c.SplitShardGroup(args[0], args[1], id, t, *shardN)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Stdout, "Split shard group %d at %s into shard group %d with %d shards\n",
+		id, t.Format(time.RFC3339), sg.ID, len(sg.Shards))
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 )
 
 const logo = `
@@ -95,6 +96,11 @@ func (cmd *Command) Run(args ...string) error {
 		return fmt.Errorf("%s. To generate a valid configuration file run `influxd-meta config > influxdb-meta.generated.conf`", err)
 	}
 
+	// Force the raft peers when recovering a cluster that lost quorum.
+	if options.RecoverPeers != "" {
+		config.RecoverPeers = strings.Split(options.RecoverPeers, ",")
+	}
+
 	// Create server from config and start it.
 	buildInfo := &BuildInfo{
 		Version: cmd.Version,
@@ -149,6 +155,7 @@ func (cmd *Command) ParseFlags(args ...string) (Options, error) {
 	_ = fs.String("hostname", "", "")
 	fs.StringVar(&options.CPUProfile, "cpuprofile", "", "")
 	fs.StringVar(&options.MemProfile, "memprofile", "", "")
+	fs.StringVar(&options.RecoverPeers, "recover-peers", "", "")
 	fs.Usage = func() { fmt.Fprintln(cmd.Stderr, usage) }
 	if err := fs.Parse(args); err != nil {
 		return Options{}, err
@@ -195,6 +202,11 @@ Usage: influxd run [flags]
             Write CPU profiling information to a file.
     -memprofile <path>
             Write memory usage information to a file.
+    -recover-peers <tcp-addr,...>
+            Recover a meta cluster that lost quorum. Forces the raft peers
+            of this node to the given addresses, which always include this
+            node. Start every surviving node once with the same addresses,
+            then remove the lost meta nodes with influxd-ctl remove-meta.
 `
 
 // Options represents the command line options that can be parsed.
@@ -203,6 +215,9 @@ type Options struct {
 	PIDFile    string
 	CPUProfile string
 	MemProfile string
+
+	// RecoverPeers is a comma separated list of raft addresses.
+	RecoverPeers string
 }
 
 // GetConfigPath returns the config path from the options.
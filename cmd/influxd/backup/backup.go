@@ -44,6 +44,22 @@ type Command struct {
 	host     string
 	path     string
 	database string
+
+	// metaAddr is the HTTP address of a meta node when backing up a cluster.
+	// The secret signs requests to meta nodes with auth enabled, the TLS
+	// files secure requests to data nodes with cluster TLS enabled.
+	metaAddr string
+	secret   string
+	tlsCert  string
+	tlsKey   string
+	tlsCA    string
+
+	// parent is the location of the backup an incremental cluster backup
+	// builds on.
+	parent string
+
+	// storage opens the storage of cluster backups in object storage.
+	storage StorageConfig
 }
 
 // NewCommand returns a new instance of Command with default settings.
@@ -66,7 +82,12 @@ func (cmd *Command) Run(args ...string) error {
 	}
 
 	// based on the arguments passed in we only backup the minimum
-	if shardID != "" {
+	if cmd.metaAddr != "" {
+		if !since.IsZero() {
+			return errors.New("-since can't be used with -meta, use -parent for incremental backups")
+		}
+		err = cmd.backupCluster(retentionPolicy)
+	} else if shardID != "" {
 		// always backup the metastore
 		if err := cmd.backupMetastore(); err != nil {
 			return err
@@ -100,6 +121,14 @@ func (cmd *Command) parseFlags(args []string) (retentionPolicy, shardID string,
 	fs.StringVar(&shardID, "shard", "", "")
 	var sinceArg string
 	fs.StringVar(&sinceArg, "since", "", "")
+	fs.StringVar(&cmd.metaAddr, "meta", "", "")
+	fs.StringVar(&cmd.secret, "secret", "", "")
+	fs.StringVar(&cmd.tlsCert, "tls-certificate", "", "")
+	fs.StringVar(&cmd.tlsKey, "tls-private-key", "", "")
+	fs.StringVar(&cmd.tlsCA, "tls-ca-bundle", "", "")
+	fs.StringVar(&cmd.parent, "parent", "", "")
+	cmd.storage = NewStorageConfig()
+	cmd.storage.RegisterFlags(fs)
 
 	fs.SetOutput(cmd.Stderr)
 	fs.Usage = cmd.printUsage
@@ -115,6 +144,15 @@ func (cmd *Command) parseFlags(args []string) (retentionPolicy, shardID string,
 		}
 	}
 
+	if cmd.parent != "" && cmd.metaAddr == "" {
+		return "", "", time.Unix(0, 0), errors.New("-parent requires -meta")
+	}
+	if cmd.tlsCert != "" || cmd.tlsKey != "" || cmd.tlsCA != "" {
+		if cmd.tlsCert == "" || cmd.tlsKey == "" || cmd.tlsCA == "" {
+			return "", "", time.Unix(0, 0), errors.New("-tls-certificate, -tls-private-key and -tls-ca-bundle must be set together")
+		}
+	}
+
 	// Ensure that only one arg is specified.
 	if fs.NArg() == 0 {
 		return "", "", time.Unix(0, 0), errors.New("backup destination path required")
@@ -123,6 +161,14 @@ func (cmd *Command) parseFlags(args []string) (retentionPolicy, shardID string,
 	}
 	cmd.path = fs.Arg(0)
 
+	// Only cluster backups are written to object storage, which has no
+	// directories to create.
+	if IsURL(cmd.path) {
+		if cmd.metaAddr == "" {
+			return "", "", time.Unix(0, 0), errors.New("backup to object storage requires -meta")
+		}
+		return
+	}
 	err = os.MkdirAll(cmd.path, 0700)
 
 	return
@@ -360,6 +406,9 @@ func (cmd *Command) printUsage() {
 
 Usage: influxd backup [flags] PATH
 
+PATH is a local directory or, with -meta, an s3://bucket/prefix URL. Backups
+of a single server without -meta are only written to a local directory.
+
     -host <host:port>
             The host to connect to snapshot. Defaults to 127.0.0.1:8088.
     -database <name>
@@ -371,6 +420,29 @@ Usage: influxd backup [flags] PATH
     -since <2015-12-24T08:12:23>
             Optional. Do an incremental backup since the passed in RFC3339
             formatted time.
+    -meta <host:port>
+            Optional. The HTTP address of a meta node. Backs up the meta data
+            and every shard of the cluster, or of the database and retention
+            policy given, from one live owner each and writes a manifest.
+    -parent <path>
+            Optional. With -meta, do an incremental backup of the shard files
+            changed since the backup at path copied them. Restoring the
+            backup restores its parents first.
+    -s3-endpoint <url>
+            Optional. The endpoint of the S3 compatible service storing s3://
+            backups. Defaults to https://s3.<region>.amazonaws.com. The
+            credentials are read from AWS_ACCESS_KEY_ID and
+            AWS_SECRET_ACCESS_KEY.
+    -s3-region <region>
+            Optional. The region requests are signed for. Defaults to us-east-1.
+    -s3-part-size <bytes>
+            Optional. The size of the first parts shard snapshots are uploaded
+            in, doubled every 1000 parts. Defaults to 16MB, the minimum is 5MB.
+    -secret <secret>
+            Optional. The shared secret of meta nodes with auth enabled.
+    -tls-certificate <path> -tls-private-key <path> -tls-ca-bundle <path>
+            Optional. Client certificate, key and CA bundle for data nodes
+            with cluster TLS enabled.
 
 `)
 }
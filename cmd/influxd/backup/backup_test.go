@@ -0,0 +1,23 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/zhexuany/influxcloud/cmd/influxd/backup"
+)
+
+// Ensure a backup of a single server isn't written to object storage.
+func TestCommand_ObjectStorageRequiresMeta(t *testing.T) {
+	cmd := backup.NewCommand()
+	cmd.Stdout, cmd.Stderr = ioutil.Discard, ioutil.Discard
+
+	for _, args := range [][]string{
+		{"s3://bucket/backups/full"},
+		{"-database", "db0", "s3://bucket/backups/full"},
+	} {
+		if err := cmd.Run(args...); err == nil || err.Error() != "backup to object storage requires -meta" {
+			t.Fatalf("unexpected error for %v: %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cluster"
+	"github.com/zhexuany/influxcloud/meta"
+)
+
+// Manifestfile is the base name of the manifest of a cluster backup.
+const Manifestfile = "manifest.json"
+
+// Manifest describes the files of a cluster backup.
+type Manifest struct {
+	// Meta is the file holding the meta data of the cluster.
+	Meta      string          `json:"meta"`
+	Shards    []ShardManifest `json:"shards"`
+	CreatedAt time.Time       `json:"createdAt"`
+
+	// Parent is the location of the backup an incremental backup builds on,
+	// relative to the location of the backup unless absolute. It is empty
+	// for a full backup.
+	Parent string `json:"parent,omitempty"`
+}
+
+// ShardManifest describes the snapshot of a shard in a cluster backup.
+type ShardManifest struct {
+	ID           uint64    `json:"id"`
+	Database     string    `json:"database"`
+	Policy       string    `json:"policy"`
+	ShardGroupID uint64    `json:"shardGroupID"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+
+	// Owners are the data nodes owning the shard, NodeID is the one the
+	// snapshot was taken from.
+	Owners []uint64 `json:"owners"`
+	NodeID uint64   `json:"nodeID"`
+
+	// SnapshotTime is the time of the data node when the snapshot started.
+	// The snapshot holds the files changed after Since, the snapshot time of
+	// the shard in the parent backup, or every file if Since is zero. Both
+	// are taken from the clock of the data node.
+	SnapshotTime time.Time `json:"snapshotTime"`
+	Since        time.Time `json:"since"`
+
+	// Checksum is the hex encoded SHA-256 of the file.
+	File     string `json:"file"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// ReadManifest reads the manifest of the cluster backup in s.
+func ReadManifest(s Storage) (*Manifest, error) {
+	r, err := s.Open(Manifestfile)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read manifest: %s", err)
+	}
+	return &m, nil
+}
+
+// Backup is a cluster backup stored in a storage.
+type Backup struct {
+	Storage  Storage
+	Manifest *Manifest
+}
+
+// ReadChain returns the backups restoring the backup in s as of target: the
+// full backup it builds on followed by the incremental backups up to the last
+// one created at or before target. A zero target includes s.
+func ReadChain(s Storage, target time.Time) ([]Backup, error) {
+	var chain []Backup
+	visited := make(map[string]bool)
+	for {
+		location := s.Location()
+		if !IsURL(location) {
+			abs, err := filepath.Abs(location)
+			if err != nil {
+				return nil, err
+			}
+			location = abs
+		}
+		if visited[location] {
+			return nil, fmt.Errorf("backup %s is its own parent", s.Location())
+		}
+		visited[location] = true
+
+		m, err := ReadManifest(s)
+		if err != nil {
+			return nil, fmt.Errorf("backup %s: %s", s.Location(), err)
+		}
+		chain = append([]Backup{{Storage: s, Manifest: m}}, chain...)
+
+		if m.Parent == "" {
+			break
+		} else if s, err = s.Resolve(m.Parent); err != nil {
+			return nil, err
+		}
+	}
+
+	// Drop the backups created after the target.
+	if !target.IsZero() {
+		for len(chain) > 0 && chain[len(chain)-1].Manifest.CreatedAt.After(target) {
+			chain = chain[:len(chain)-1]
+		}
+		if len(chain) == 0 {
+			return nil, fmt.Errorf("no backup created at or before %s", target.Format(time.RFC3339))
+		}
+	}
+	return chain, nil
+}
+
+// Verify returns an error if a shard file of the backup is missing or does
+// not match its checksum.
+func (b Backup) Verify() error {
+	for _, sm := range b.Manifest.Shards {
+		r, err := b.Storage.Open(sm.File)
+		if err != nil {
+			return err
+		}
+
+		h := sha256.New()
+		_, err = io.Copy(h, r)
+		r.Close()
+		if err != nil {
+			return err
+		} else if sum := hex.EncodeToString(h.Sum(nil)); sum != sm.Checksum {
+			return fmt.Errorf("checksum mismatch of %s in %s", sm.File, b.Storage.Location())
+		}
+	}
+	return nil
+}
+
+// backupCluster backs up the meta data of the cluster and every shard of it,
+// optionally limited to a database and retention policy. Each shard is copied
+// from one live owner, the shards of each data node are copied in parallel
+// with the other nodes. With a parent backup only the shard files changed
+// since the snapshot of the parent are copied from the owner the parent was
+// copied from.
+func (cmd *Command) backupCluster(retentionPolicy string) error {
+	store, err := cmd.storage.Open(cmd.path)
+	if err != nil {
+		return err
+	}
+	if ok, err := store.Exists(Manifestfile); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("backup already exists in %s", cmd.path)
+	}
+
+	manifest := &Manifest{CreatedAt: time.Now().UTC()}
+	parentShards := make(map[uint64]ShardManifest)
+	preferred := make(map[uint64]uint64)
+	if cmd.parent != "" {
+		parentStore, err := cmd.storage.Open(cmd.parent)
+		if err != nil {
+			return err
+		}
+		parent, err := ReadManifest(parentStore)
+		if err != nil {
+			return fmt.Errorf("parent backup %s: %s", cmd.parent, err)
+		}
+		if manifest.Parent, err = relativeParent(cmd.path, cmd.parent); err != nil {
+			return err
+		}
+
+		// Files of a shard differ between owners, copy the changes from the
+		// owner the parent was copied from.
+		for _, sm := range parent.Shards {
+			parentShards[sm.ID] = sm
+			preferred[sm.ID] = sm.NodeID
+		}
+	}
+
+	c, err := cmd.openMetaClient()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	client := cluster.NewClient(10 * time.Second)
+	if cmd.tlsCert != "" {
+		client.TLS = cluster.NewTLSFromFiles(cmd.tlsCert, cmd.tlsKey, cmd.tlsCA)
+	}
+
+	// Snapshot the meta data before the shards so every shard backed up is
+	// known to the meta data restored.
+	data := c.Data()
+	manifest.Meta = Metafile + ".00"
+	cmd.Logger.Printf("backing up meta data to %s", manifest.Meta)
+	b, err := data.MarshalBinary()
+	if err != nil {
+		return err
+	} else if err := store.Put(manifest.Meta, bytes.NewReader(b)); err != nil {
+		return err
+	}
+
+	// Collect the shards to back up along with their owners.
+	owners := make(map[uint64][]uint64)
+	for _, dbi := range data.Databases {
+		if cmd.database != "" && dbi.Name != cmd.database {
+			continue
+		}
+		for _, rpi := range dbi.RetentionPolicies {
+			if retentionPolicy != "" && rpi.Name != retentionPolicy {
+				continue
+			}
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					for _, o := range si.Owners {
+						owners[si.ID] = append(owners[si.ID], o.NodeID)
+					}
+					manifest.Shards = append(manifest.Shards, ShardManifest{
+						ID:           si.ID,
+						Database:     dbi.Name,
+						Policy:       rpi.Name,
+						ShardGroupID: sgi.ID,
+						StartTime:    sgi.StartTime,
+						EndTime:      sgi.EndTime,
+						Owners:       owners[si.ID],
+					})
+				}
+			}
+		}
+	}
+
+	assigned, err := cmd.assignShards(client, data.DataNodes, manifest.Shards, preferred)
+	if err != nil {
+		return err
+	}
+
+	// The changes of a shard are only known on the owner and since the time
+	// it was copied by the parent. Shards new to the parent or copied from
+	// another owner are copied entirely.
+	for i := range manifest.Shards {
+		sm := &manifest.Shards[i]
+		if p, ok := parentShards[sm.ID]; ok && p.NodeID == sm.NodeID {
+			sm.Since = p.SnapshotTime
+		}
+	}
+
+	// Copy the shards of each node in parallel with the other nodes.
+	var wg sync.WaitGroup
+	errs := make(chan error, len(assigned))
+	for _, n := range data.DataNodes {
+		if len(assigned[n.ID]) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(n meta.NodeInfo, shards []*ShardManifest) {
+			defer wg.Done()
+			for _, sm := range shards {
+				if err := cmd.backupClusterShard(client, store, n, sm); err != nil {
+					errs <- fmt.Errorf("backup shard %d from data node %d (%s): %s", sm.ID, n.ID, n.TCPHost, err)
+					return
+				}
+			}
+		}(n, assigned[n.ID])
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	// The backup is complete once its manifest is stored.
+	b, err = json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+	return store.Put(Manifestfile, bytes.NewReader(b))
+}
+
+// assignShards assigns every shard to a live owner storing it. The preferred
+// owner of a shard is used if possible, otherwise the owner with the fewest
+// assigned shards. Data nodes not responding are skipped.
+func (cmd *Command) assignShards(client *cluster.Client, nodes meta.NodeInfos, shards []ShardManifest, preferred map[uint64]uint64) (map[uint64][]*ShardManifest, error) {
+	owned := make(map[uint64][]uint64)
+	for _, sm := range shards {
+		for _, nodeID := range sm.Owners {
+			owned[nodeID] = append(owned[nodeID], sm.ID)
+		}
+	}
+
+	// Ask each node which of its shards it stores.
+	stored := make(map[uint64]map[uint64]bool, len(nodes))
+	for _, n := range nodes {
+		if len(owned[n.ID]) == 0 {
+			continue
+		}
+
+		statuses, err := client.ShardStatus(n.TCPHost, owned[n.ID])
+		if err != nil {
+			cmd.Logger.Printf("skipping data node %d (%s): %s", n.ID, n.TCPHost, err)
+			continue
+		}
+		stored[n.ID] = make(map[uint64]bool, len(statuses))
+		for _, s := range statuses {
+			stored[n.ID][s.ShardID] = true
+		}
+	}
+
+	assigned := make(map[uint64][]*ShardManifest)
+	for i := range shards {
+		sm := &shards[i]
+		for _, nodeID := range sm.Owners {
+			if !stored[nodeID][sm.ID] {
+				continue
+			} else if nodeID == preferred[sm.ID] {
+				sm.NodeID = nodeID
+				break
+			} else if sm.NodeID == 0 || len(assigned[nodeID]) < len(assigned[sm.NodeID]) {
+				sm.NodeID = nodeID
+			}
+		}
+		if sm.NodeID == 0 {
+			return nil, fmt.Errorf("shard %d has no live owner", sm.ID)
+		}
+		assigned[sm.NodeID] = append(assigned[sm.NodeID], sm)
+	}
+	return assigned, nil
+}
+
+// backupClusterShard streams a snapshot of the files of a shard changed since
+// sm.Since from a data node to the storage and records its file, size,
+// checksum and snapshot time in sm.
+func (cmd *Command) backupClusterShard(client *cluster.Client, store Storage, n meta.NodeInfo, sm *ShardManifest) error {
+	name := fmt.Sprintf(BackupFilePattern+".00", sm.Database, sm.Policy, sm.ID)
+	cmd.Logger.Printf("backing up db=%v rp=%v shard=%v from data node %d to %s",
+		sm.Database, sm.Policy, sm.ID, n.ID, name)
+
+	pr, pw := io.Pipe()
+	h := sha256.New()
+	w := &countingWriter{w: io.MultiWriter(pw, h)}
+	done := make(chan struct{})
+	var snapshotTime time.Time
+	go func() {
+		defer close(done)
+		var err error
+		snapshotTime, err = client.BackupShard(n.TCPHost, sm.ID, sm.Since, w)
+		pw.CloseWithError(err)
+	}()
+
+	// Unblock the snapshot if storing it fails.
+	err := store.Put(name, pr)
+	pr.CloseWithError(err)
+	<-done
+	if err != nil {
+		return err
+	}
+	sm.File, sm.Size, sm.Checksum = name, w.n, hex.EncodeToString(h.Sum(nil))
+	sm.SnapshotTime = snapshotTime
+	return nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// relativeParent returns the location of the parent backup relative to the
+// directory of the backup, or absolute if it can't be made relative. Parents
+// of backups in object storage are referenced by their URL.
+func relativeParent(dir, parent string) (string, error) {
+	if IsURL(dir) != IsURL(parent) {
+		return "", fmt.Errorf("backup %s can't be the parent of %s", parent, dir)
+	} else if IsURL(parent) {
+		return parent, nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absParent, err := filepath.Abs(parent)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(absDir, absParent); err == nil {
+		return rel, nil
+	}
+	return absParent, nil
+}
+
+// openMetaClient returns a meta client connected to the meta node at
+// cmd.metaAddr.
+func (cmd *Command) openMetaClient() (*meta.Client, error) {
+	config := meta.NewConfig()
+	config.AuthEnabled = cmd.secret != ""
+	config.InternalSharedSecret = cmd.secret
+
+	c := meta.NewClient(config)
+	c.SetMetaServers([]string{cmd.metaAddr})
+	if err := c.Ping(false); err != nil {
+		return nil, fmt.Errorf("ping meta node %s: %s", cmd.metaAddr, err)
+	}
+	if err := c.Open(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
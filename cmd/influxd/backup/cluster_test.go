@@ -0,0 +1,99 @@
+package backup_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cmd/influxd/backup"
+)
+
+// Ensure the chain of an incremental backup is read up to the target time.
+func TestReadChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxcloud-backup-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	t0 := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	MustWriteBackup(filepath.Join(dir, "full"), &backup.Manifest{CreatedAt: t0})
+	MustWriteBackup(filepath.Join(dir, "incr1"), &backup.Manifest{CreatedAt: t0.Add(time.Hour), Parent: "../full"})
+	MustWriteBackup(filepath.Join(dir, "incr2"), &backup.Manifest{CreatedAt: t0.Add(2 * time.Hour), Parent: filepath.Join(dir, "incr1")})
+
+	for _, tt := range []struct {
+		target time.Time
+		exp    []string
+	}{
+		{target: time.Time{}, exp: []string{"full", "incr1", "incr2"}},
+		{target: t0.Add(90 * time.Minute), exp: []string{"full", "incr1"}},
+		{target: t0, exp: []string{"full"}},
+	} {
+		chain, err := backup.ReadChain(backup.NewLocalStorage(filepath.Join(dir, "incr2")), tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, b := range chain {
+			names = append(names, filepath.Base(b.Storage.Location()))
+		}
+		if len(names) != len(tt.exp) {
+			t.Fatalf("unexpected chain at %s: %v", tt.target, names)
+		}
+		for i := range names {
+			if names[i] != tt.exp[i] {
+				t.Fatalf("unexpected chain at %s: %v", tt.target, names)
+			}
+		}
+	}
+
+	if _, err := backup.ReadChain(backup.NewLocalStorage(filepath.Join(dir, "incr2")), t0.Add(-time.Hour)); err == nil {
+		t.Fatal("expected error restoring before the full backup")
+	}
+}
+
+// Ensure shard files not matching their checksum fail verification.
+func TestBackup_Verify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxcloud-backup-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sum := sha256.Sum256([]byte("snapshot"))
+	m := &backup.Manifest{Shards: []backup.ShardManifest{{ID: 1, File: "db0.rp0.00001.00", Checksum: hex.EncodeToString(sum[:])}}}
+	MustWriteBackup(dir, m)
+	if err := ioutil.WriteFile(filepath.Join(dir, "db0.rp0.00001.00"), []byte("snapshot"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := backup.Backup{Storage: backup.NewLocalStorage(dir), Manifest: m}
+	if err := b.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "db0.rp0.00001.00"), []byte("corrupt"), 0600); err != nil {
+		t.Fatal(err)
+	} else if err := b.Verify(); err == nil {
+		t.Fatal("expected checksum mismatch")
+	}
+}
+
+// MustWriteBackup writes the manifest of a backup to dir. Panic on error.
+func MustWriteBackup(dir string, m *backup.Manifest) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		panic(err)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, backup.Manifestfile), b, 0600); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,558 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultS3Region is the default region requests are signed for.
+	DefaultS3Region = "us-east-1"
+
+	// DefaultS3PartSize is the default size of the first parts of an upload.
+	DefaultS3PartSize = 16 << 20
+
+	// MinS3PartSize is the smallest part size accepted by S3.
+	MinS3PartSize = 5 << 20
+
+	// MaxS3PartSize is the largest part size accepted by S3.
+	MaxS3PartSize = 5 << 30
+
+	// MaxS3Parts is the largest number of parts of an upload accepted by S3.
+	MaxS3Parts = 10000
+
+	// DefaultS3MaxRetries is the default number of times a failed request
+	// or an interrupted download is retried.
+	DefaultS3MaxRetries = 5
+)
+
+// emptySHA256 is the hex encoded SHA-256 of an empty payload.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Storage stores the files of a backup under a prefix of a bucket of an S3
+// compatible service. Files are uploaded in parts read from the stream stored,
+// and downloads resume where they were interrupted.
+//
+// The size of a stream isn't known before it is read, so the part size starts
+// at PartSize and doubles after every tenth of MaxParts parts. Even with the
+// smallest part size the parts then cover the largest objects S3 accepts.
+type S3Storage struct {
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	Bucket string
+	Prefix string
+
+	PartSize   int64
+	MaxParts   int
+	MaxRetries int
+
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// S3RegionEndpoint returns the AWS endpoint of S3 in region. Buckets outside
+// of us-east-1 can't be reached through the global endpoint with requests
+// signed for their region.
+func S3RegionEndpoint(region string) string {
+	return "https://s3." + region + ".amazonaws.com"
+}
+
+// S3Error is an error returned by an S3 compatible service.
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+// Error returns the string representation of the error.
+func (e *S3Error) Error() string {
+	return fmt.Sprintf("s3: %s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// Location returns the s3:// URL of the backup.
+func (s *S3Storage) Location() string {
+	return "s3://" + s.Bucket + "/" + s.Prefix
+}
+
+// Exists returns true if the named object exists.
+func (s *S3Storage) Exists(name string) (bool, error) {
+	resp, err := s.do("HEAD", s.key(name), nil, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, readS3Error(resp)
+	}
+}
+
+// Open returns a reader of the named object. A download interrupted by a
+// failed connection is resumed from the last byte read, as long as the object
+// is not replaced in the meantime.
+func (s *S3Storage) Open(name string) (io.ReadCloser, error) {
+	r := &s3Reader{s: s, key: s.key(name)}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Put stores the named object. Objects larger than a part are streamed in a
+// multipart upload holding a single part in memory at a time. The upload is
+// aborted if r returns an error.
+func (s *S3Storage) Put(name string, r io.Reader) error {
+	key := s.key(name)
+	buf := make([]byte, s.PartSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return s.putObject(key, buf[:n])
+	} else if err != nil {
+		return err
+	}
+
+	uploadID, err := s.createMultipartUpload(key)
+	if err != nil {
+		return err
+	}
+
+	var parts []s3Part
+	for last := false; ; {
+		etag, err := s.uploadPart(key, uploadID, len(parts)+1, buf[:n])
+		if err != nil {
+			s.abortMultipartUpload(key, uploadID)
+			return err
+		}
+		parts = append(parts, s3Part{Number: len(parts) + 1, ETag: etag})
+		if last {
+			break
+		}
+
+		if size := s.partSize(len(parts) + 1); size != int64(len(buf)) {
+			buf = make([]byte, size)
+		}
+		n, err = io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			last = true
+		} else if err != nil {
+			s.abortMultipartUpload(key, uploadID)
+			return err
+		}
+	}
+
+	if err := s.completeMultipartUpload(key, uploadID, parts); err != nil {
+		s.abortMultipartUpload(key, uploadID)
+		return err
+	}
+	return nil
+}
+
+// Resolve returns the storage of the backup at location, an s3:// URL or a
+// path relative to the prefix of this storage.
+func (s *S3Storage) Resolve(location string) (Storage, error) {
+	other := *s
+	if IsURL(location) {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		} else if u.Scheme != "s3" || u.Host == "" {
+			return nil, fmt.Errorf("backup %s can't be reached from %s", location, s.Location())
+		}
+		other.Bucket, other.Prefix = u.Host, strings.Trim(u.Path, "/")
+		return &other, nil
+	} else if path.IsAbs(location) {
+		return nil, fmt.Errorf("backup %s can't be reached from %s", location, s.Location())
+	}
+
+	other.Prefix = path.Join(s.Prefix, location)
+	if other.Prefix == ".." || strings.HasPrefix(other.Prefix, "../") {
+		return nil, fmt.Errorf("backup %s is outside of bucket %s", location, s.Bucket)
+	}
+	return &other, nil
+}
+
+// partSize returns the size of the part number of an upload.
+func (s *S3Storage) partSize(number int) int64 {
+	maxParts := s.MaxParts
+	if maxParts <= 0 {
+		maxParts = MaxS3Parts
+	}
+	step := maxParts / 10
+	if step == 0 {
+		step = 1
+	}
+
+	size := s.PartSize
+	for i := step; i < number && size < MaxS3PartSize; i += step {
+		size *= 2
+	}
+	if size > MaxS3PartSize {
+		size = MaxS3PartSize
+	}
+	return size
+}
+
+// key returns the object key of the named file.
+func (s *S3Storage) key(name string) string {
+	return path.Join(s.Prefix, name)
+}
+
+// putObject stores an object in a single request.
+func (s *S3Storage) putObject(key string, data []byte) error {
+	resp, err := s.do("PUT", key, nil, data, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return readS3Error(resp)
+	}
+	return nil
+}
+
+// s3Part is a part of a multipart upload.
+type s3Part struct {
+	Number int    `xml:"PartNumber"`
+	ETag   string `xml:"ETag"`
+}
+
+// createMultipartUpload starts a multipart upload of key and returns its ID.
+func (s *S3Storage) createMultipartUpload(key string) (string, error) {
+	resp, err := s.do("POST", key, url.Values{"uploads": {""}}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", readS3Error(resp)
+	}
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("create multipart upload: %s", err)
+	} else if result.UploadID == "" {
+		return "", fmt.Errorf("create multipart upload: no upload id")
+	}
+	return result.UploadID, nil
+}
+
+// uploadPart uploads a part of a multipart upload and returns its ETag.
+func (s *S3Storage) uploadPart(key, uploadID string, number int, data []byte) (string, error) {
+	resp, err := s.do("PUT", key, url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}, data, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", readS3Error(resp)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// completeMultipartUpload assembles the object from the uploaded parts.
+func (s *S3Storage) completeMultipartUpload(key, uploadID string, parts []s3Part) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []s3Part `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do("POST", key, url.Values{"uploadId": {uploadID}}, body, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return readS3Error(resp)
+	}
+
+	// Completing an upload may fail after the response status was sent.
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		XMLName xml.Name
+		S3Error
+	}
+	if err := xml.Unmarshal(b, &result); err == nil && result.XMLName.Local == "Error" {
+		result.S3Error.StatusCode = resp.StatusCode
+		return &result.S3Error
+	}
+	return nil
+}
+
+// abortMultipartUpload discards the parts of a failed multipart upload.
+func (s *S3Storage) abortMultipartUpload(key, uploadID string) {
+	resp, err := s.do("DELETE", key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+// do sends a signed request for key, retrying on connection errors and
+// server errors.
+func (s *S3Storage) do(method, key string, query url.Values, body []byte, header http.Header) (*http.Response, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	for i := 0; ; i++ {
+		req, err := s.newRequest(method, key, query, body, header)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		} else if i >= s.MaxRetries {
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			return nil, readS3Error(resp)
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		time.Sleep(time.Duration(1<<uint(i)) * 100 * time.Millisecond)
+	}
+}
+
+// newRequest returns a request for key of the bucket signed with AWS
+// Signature Version 4.
+func (s *S3Storage) newRequest(method, key string, query url.Values, body []byte, header http.Header) (*http.Request, error) {
+	canonicalURI := "/" + s3Escape(s.Bucket, false) + "/" + s3Escape(key, false)
+	canonicalQuery := s3Query(query)
+	rawurl := s.Endpoint + canonicalURI
+	if canonicalQuery != "" {
+		rawurl += "?" + canonicalQuery
+	}
+
+	req, err := http.NewRequest(method, rawurl, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	payloadHash := emptySHA256
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	s.sign(req, canonicalURI, canonicalQuery, payloadHash, time.Now())
+	return req, nil
+}
+
+// sign adds the date, payload hash and authorization headers of AWS Signature
+// Version 4 to req. Requests are sent unsigned without an access key.
+func (s *S3Storage) sign(req *http.Request, canonicalURI, canonicalQuery, payloadHash string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.AccessKeyID == "" {
+		return
+	}
+
+	// Sign the host, the range and the amz headers.
+	names := []string{"host"}
+	for k := range req.Header {
+		if k := strings.ToLower(k); k == "range" || strings.HasPrefix(k, "x-amz-") {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	var headers bytes.Buffer
+	for _, name := range names {
+		v := req.URL.Host
+		if name != "host" {
+			v = strings.TrimSpace(req.Header.Get(name))
+		}
+		fmt.Fprintf(&headers, "%s:%s\n", name, v)
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{req.Method, canonicalURI, canonicalQuery, headers.String(), signedHeaders, payloadHash}, "\n")
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	scope := amzDate[:8] + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := []byte("AWS4" + s.SecretAccessKey)
+	for _, v := range []string{amzDate[:8], s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// s3Reader reads an object, resuming the download where a failed connection
+// interrupted it.
+type s3Reader struct {
+	s       *S3Storage
+	key     string
+	etag    string
+	offset  int64
+	retries int
+	body    io.ReadCloser
+}
+
+// open requests the object from the current offset. Resumed requests only
+// match the object first read.
+func (r *s3Reader) open() error {
+	header := make(http.Header)
+	if r.offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+	}
+	if r.etag != "" {
+		header.Set("If-Match", r.etag)
+	}
+
+	resp, err := r.s.do("GET", r.key, nil, nil, header)
+	if err != nil {
+		return err
+	}
+	if r.offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return fmt.Errorf("resume download of %s: range not supported", r.key)
+		}
+		return readS3Error(resp)
+	} else if r.offset == 0 && resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return readS3Error(resp)
+	}
+
+	if r.etag == "" {
+		r.etag = resp.Header.Get("ETag")
+	}
+	r.body = resp.Body
+	return nil
+}
+
+// Read reads from the object, reopening it from the current offset when the
+// connection fails.
+func (r *s3Reader) Read(p []byte) (int, error) {
+	for {
+		if r.body == nil {
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			// Only give up on a download failing without progress.
+			r.retries = 0
+		}
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+
+		r.body.Close()
+		r.body = nil
+		if r.retries >= r.s.MaxRetries {
+			return n, err
+		}
+		r.retries++
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Close closes the current response body.
+func (r *s3Reader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
+
+// readS3Error returns the error of a failed response.
+func readS3Error(resp *http.Response) error {
+	e := &S3Error{StatusCode: resp.StatusCode, Code: http.StatusText(resp.StatusCode)}
+	if b, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20)); err == nil && len(b) > 0 {
+		xml.Unmarshal(b, e)
+	}
+	return e
+}
+
+// s3Query returns the canonical form of a query, sorted by key.
+func s3Query(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes every byte of s but unreserved characters, and
+// slashes unless escapeSlash is set.
+func s3Escape(s string, escapeSlash bool) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !escapeSlash) {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
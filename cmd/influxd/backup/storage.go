@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage stores the files of a cluster backup. A storage is rooted at the
+// location of one backup and names the files of the backup relative to it.
+type Storage interface {
+	// Location returns the local directory or URL the storage is rooted at.
+	Location() string
+
+	// Exists returns true if the named file is stored.
+	Exists(name string) (bool, error)
+
+	// Open returns a reader of the named file.
+	Open(name string) (io.ReadCloser, error)
+
+	// Put stores the named file with the contents read from r until EOF.
+	// The file is only stored if r is read without error.
+	Put(name string, r io.Reader) error
+
+	// Resolve returns the storage of the backup at location, which may be
+	// relative to the location of this storage.
+	Resolve(location string) (Storage, error)
+}
+
+// StorageConfig configures the storages opened for backup locations.
+type StorageConfig struct {
+	// S3Endpoint and S3Region locate the S3 compatible service storing the
+	// backups at s3://bucket/prefix locations. The endpoint defaults to the
+	// AWS endpoint of the region. The credentials default to the
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+	S3Endpoint        string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	// S3PartSize is the size of the parts files are uploaded in.
+	S3PartSize int64
+}
+
+// NewStorageConfig returns a StorageConfig with default settings.
+func NewStorageConfig() StorageConfig {
+	return StorageConfig{
+		S3Region:          DefaultS3Region,
+		S3AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		S3PartSize:        DefaultS3PartSize,
+	}
+}
+
+// RegisterFlags registers the flags configuring the storage on fs.
+func (c *StorageConfig) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.S3Endpoint, "s3-endpoint", c.S3Endpoint, "")
+	fs.StringVar(&c.S3Region, "s3-region", c.S3Region, "")
+	fs.Int64Var(&c.S3PartSize, "s3-part-size", c.S3PartSize, "")
+}
+
+// Open returns the storage of the backup at location, a local directory or
+// an s3://bucket/prefix URL.
+func (c StorageConfig) Open(location string) (Storage, error) {
+	if !IsURL(location) {
+		return NewLocalStorage(location), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("bucket required in %s", location)
+		} else if c.S3PartSize < MinS3PartSize {
+			return nil, fmt.Errorf("s3 part size must be at least %d bytes", MinS3PartSize)
+		}
+		endpoint := c.S3Endpoint
+		if endpoint == "" {
+			endpoint = S3RegionEndpoint(c.S3Region)
+		}
+		return &S3Storage{
+			Endpoint:        strings.TrimSuffix(endpoint, "/"),
+			Region:          c.S3Region,
+			AccessKeyID:     c.S3AccessKeyID,
+			SecretAccessKey: c.S3SecretAccessKey,
+			Bucket:          u.Host,
+			Prefix:          strings.Trim(u.Path, "/"),
+			PartSize:        c.S3PartSize,
+			MaxRetries:      DefaultS3MaxRetries,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported backup location %s", location)
+	}
+}
+
+// IsURL returns true if location is a URL rather than a local path.
+func IsURL(location string) bool {
+	return strings.Contains(location, "://")
+}
+
+// LocalStorage stores the files of a backup in a local directory.
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage returns a storage of the backup in dir.
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{dir: dir}
+}
+
+// Location returns the directory of the backup.
+func (s *LocalStorage) Location() string { return s.dir }
+
+// Exists returns true if the named file exists in the directory.
+func (s *LocalStorage) Exists(name string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(s.dir, name)); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Open opens the named file of the directory.
+func (s *LocalStorage) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, name))
+}
+
+// Put writes the named file to a pending file first and renames it once r is
+// read to the end.
+func (s *LocalStorage) Put(name string, r io.Reader) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, name)
+	tmppath := path + Suffix
+	f, err := os.Create(tmppath)
+	if err != nil {
+		return fmt.Errorf("open temp file: %s", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		os.Remove(tmppath)
+		return err
+	} else if err := f.Close(); err != nil {
+		os.Remove(tmppath)
+		return err
+	}
+	if err := os.Rename(tmppath, path); err != nil {
+		return fmt.Errorf("rename: %s", err)
+	}
+	return nil
+}
+
+// Resolve returns the storage of the directory at location, relative to this
+// directory unless absolute.
+func (s *LocalStorage) Resolve(location string) (Storage, error) {
+	if IsURL(location) {
+		return nil, fmt.Errorf("backup %s can't be reached from %s", location, s.dir)
+	} else if filepath.IsAbs(location) {
+		return NewLocalStorage(location), nil
+	}
+	return NewLocalStorage(filepath.Join(s.dir, location)), nil
+}
@@ -0,0 +1,431 @@
+package backup_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cmd/influxd/backup"
+)
+
+// Ensure files are streamed to S3 in parts and read back.
+func TestS3Storage_Put(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+	s := srv.Storage("backups/full")
+
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	for _, tt := range []struct {
+		name  string
+		data  []byte
+		parts int
+	}{
+		{name: "meta.00", data: []byte("meta"), parts: 0},
+		{name: "db0.rp0.00001.00", data: data, parts: 4},
+		{name: "db0.rp0.00002.00", data: data[:60], parts: 3},
+	} {
+		if err := s.Put(tt.name, bytes.NewReader(tt.data)); err != nil {
+			t.Fatal(err)
+		} else if parts := srv.Parts("/bucket/backups/full/" + tt.name); parts != tt.parts {
+			t.Fatalf("unexpected parts of %s: %d", tt.name, parts)
+		}
+
+		if ok, err := s.Exists(tt.name); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			t.Fatalf("%s not stored", tt.name)
+		}
+		if b := MustReadAll(s, tt.name); !bytes.Equal(b, tt.data) {
+			t.Fatalf("unexpected data of %s: %q", tt.name, b)
+		}
+	}
+
+	if ok, err := s.Exists("manifest.json"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("unexpected manifest")
+	}
+}
+
+// Ensure the part size grows so large files fit in the parts of an upload.
+func TestS3Storage_Put_PartSize(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+	s := srv.Storage("backups/full")
+	s.PartSize, s.MaxParts = 10, 20
+
+	// Parts of 10, 10, 20, 20 and 40 bytes.
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	if err := s.Put("db0.rp0.00001.00", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	} else if parts := srv.Parts("/bucket/backups/full/db0.rp0.00001.00"); parts != 5 {
+		t.Fatalf("unexpected parts: %d", parts)
+	} else if b := MustReadAll(s, "db0.rp0.00001.00"); !bytes.Equal(b, data) {
+		t.Fatalf("unexpected data: %q", b)
+	}
+}
+
+// Ensure a multipart upload is aborted when reading the file fails.
+func TestS3Storage_Put_Abort(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+	s := srv.Storage("backups/full")
+
+	r := io.MultiReader(bytes.NewReader(make([]byte, 75)), &errReader{errors.New("snapshot failed")})
+	if err := s.Put("db0.rp0.00001.00", r); err == nil || err.Error() != "snapshot failed" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if ok, err := s.Exists("db0.rp0.00001.00"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("unexpected object")
+	} else if n := srv.Uploads(); n != 0 {
+		t.Fatalf("unexpected pending uploads: %d", n)
+	}
+}
+
+// Ensure an interrupted download resumes where it stopped.
+func TestS3Storage_Open_Resume(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+	s := srv.Storage("backups/full")
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	if err := s.Put("db0.rp0.00001.00", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Interrupt(2, 300)
+	if b := MustReadAll(s, "db0.rp0.00001.00"); !bytes.Equal(b, data) {
+		t.Fatalf("unexpected data: %d bytes", len(b))
+	} else if ranges := srv.Ranges(); len(ranges) != 2 || ranges[0] != "bytes=300-" || ranges[1] != "bytes=600-" {
+		t.Fatalf("unexpected ranges: %v", ranges)
+	}
+
+	if _, err := s.Open("db0.rp0.00002.00"); err == nil {
+		t.Fatal("expected error opening missing object")
+	}
+}
+
+// Ensure a download keeps resuming as long as it makes progress.
+func TestS3Storage_Open_ResumeProgress(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+	s := srv.Storage("backups/full")
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	if err := s.Put("db0.rp0.00001.00", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	// More interruptions than retries, each after 100 bytes.
+	srv.Interrupt(s.MaxRetries+2, 100)
+	if b := MustReadAll(s, "db0.rp0.00001.00"); !bytes.Equal(b, data) {
+		t.Fatalf("unexpected data: %d bytes", len(b))
+	} else if ranges := srv.Ranges(); len(ranges) != s.MaxRetries+2 {
+		t.Fatalf("unexpected ranges: %v", ranges)
+	}
+}
+
+// Ensure S3 is reached through the endpoint of the region by default.
+func TestStorageConfig_Open_S3Endpoint(t *testing.T) {
+	c := backup.NewStorageConfig()
+	c.S3Region = "eu-west-1"
+	if s, err := c.Open("s3://bucket/backups/full"); err != nil {
+		t.Fatal(err)
+	} else if endpoint := s.(*backup.S3Storage).Endpoint; endpoint != "https://s3.eu-west-1.amazonaws.com" {
+		t.Fatalf("unexpected endpoint: %s", endpoint)
+	}
+
+	c.S3Endpoint = "http://localhost:9000/"
+	if s, err := c.Open("s3://bucket/backups/full"); err != nil {
+		t.Fatal(err)
+	} else if endpoint := s.(*backup.S3Storage).Endpoint; endpoint != "http://localhost:9000" {
+		t.Fatalf("unexpected endpoint: %s", endpoint)
+	}
+}
+
+// Ensure the parent of a backup in S3 is resolved within the bucket.
+func TestS3Storage_Resolve(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+	s := srv.Storage("backups/incr1")
+
+	for _, tt := range []struct {
+		location string
+		exp      string
+		err      bool
+	}{
+		{location: "../full", exp: "s3://bucket/backups/full"},
+		{location: "s3://other/full", exp: "s3://other/full"},
+		{location: "../../../full", err: true},
+		{location: "/var/backups/full", err: true},
+		{location: "http://example.com/full", err: true},
+	} {
+		other, err := s.Resolve(tt.location)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("expected error resolving %s", tt.location)
+			}
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		} else if other.Location() != tt.exp {
+			t.Fatalf("unexpected location of %s: %s", tt.location, other.Location())
+		}
+	}
+}
+
+// Ensure the chain of a backup in S3 is read through its parents.
+func TestReadChain_S3(t *testing.T) {
+	srv := NewS3Server()
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		prefix string
+		m      string
+	}{
+		{prefix: "backups/full", m: `{"createdAt":"2017-01-01T00:00:00Z"}`},
+		{prefix: "backups/incr1", m: `{"createdAt":"2017-01-01T01:00:00Z","parent":"../full"}`},
+	} {
+		if err := srv.Storage(tt.prefix).Put(backup.Manifestfile, strings.NewReader(tt.m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain, err := backup.ReadChain(srv.Storage("backups/incr1"), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(chain) != 2 || chain[0].Storage.Location() != "s3://bucket/backups/full" || chain[1].Storage.Location() != "s3://bucket/backups/incr1" {
+		t.Fatalf("unexpected chain: %v", chain)
+	}
+}
+
+// Ensure a file is only stored locally once read without error.
+func TestLocalStorage_Put(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxcloud-backup-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := backup.NewLocalStorage(filepath.Join(dir, "full"))
+
+	if err := s.Put("meta.00", strings.NewReader("meta")); err != nil {
+		t.Fatal(err)
+	} else if b := MustReadAll(s, "meta.00"); string(b) != "meta" {
+		t.Fatalf("unexpected data: %q", b)
+	}
+
+	r := io.MultiReader(strings.NewReader("partial"), &errReader{errors.New("snapshot failed")})
+	if err := s.Put("db0.rp0.00001.00", r); err == nil {
+		t.Fatal("expected error")
+	} else if ok, err := s.Exists("db0.rp0.00001.00"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("unexpected file")
+	} else if _, err := os.Stat(filepath.Join(dir, "full", "db0.rp0.00001.00"+backup.Suffix)); !os.IsNotExist(err) {
+		t.Fatalf("unexpected pending file: %v", err)
+	}
+}
+
+// S3Server is an in-process fake of an S3 compatible service.
+type S3Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	objects   map[string][]byte
+	parts     map[string]int
+	uploads   map[string]map[int][]byte
+	nextID    int
+	ranges    []string
+	interrupt int
+	after     int
+}
+
+// NewS3Server returns a running fake S3 service.
+func NewS3Server() *S3Server {
+	s := &S3Server{
+		objects: make(map[string][]byte),
+		parts:   make(map[string]int),
+		uploads: make(map[string]map[int][]byte),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Storage returns a storage of the backup at prefix of the bucket with parts
+// of 25 bytes.
+func (s *S3Server) Storage(prefix string) *backup.S3Storage {
+	return &backup.S3Storage{
+		Endpoint:        s.URL,
+		Region:          backup.DefaultS3Region,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "SECRET",
+		Bucket:          "bucket",
+		Prefix:          prefix,
+		PartSize:        25,
+		MaxRetries:      3,
+	}
+}
+
+// Parts returns the number of parts an object was uploaded in.
+func (s *S3Server) Parts(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parts[path]
+}
+
+// Uploads returns the number of multipart uploads in progress.
+func (s *S3Server) Uploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.uploads)
+}
+
+// Ranges returns the ranges requested.
+func (s *S3Server) Ranges() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ranges
+}
+
+// Interrupt drops the connection of the next n downloads after sending the
+// given number of bytes.
+func (s *S3Server) Interrupt(n, after int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interrupt, s.after = n, after
+}
+
+func (s *S3Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		s3Error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == "POST" && q.Get("uploadId") != "":
+		parts, ok := s.uploads[q.Get("uploadId")]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		var req struct {
+			Parts []struct {
+				Number int    `xml:"PartNumber"`
+				ETag   string `xml:"ETag"`
+			} `xml:"Part"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			s3Error(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		var buf bytes.Buffer
+		for i, p := range req.Parts {
+			if p.Number != i+1 || p.ETag != fmt.Sprintf(`"%d"`, p.Number) {
+				s3Error(w, http.StatusBadRequest, "InvalidPart")
+				return
+			}
+			buf.Write(parts[p.Number])
+		}
+		delete(s.uploads, q.Get("uploadId"))
+		s.objects[r.URL.Path], s.parts[r.URL.Path] = buf.Bytes(), len(req.Parts)
+		fmt.Fprint(w, `<CompleteMultipartUploadResult></CompleteMultipartUploadResult>`)
+
+	case r.Method == "POST" && q["uploads"] != nil:
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+		s.uploads[id] = make(map[int][]byte)
+		fmt.Fprintf(w, `<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>`, id)
+
+	case r.Method == "PUT" && q.Get("uploadId") != "":
+		parts, ok := s.uploads[q.Get("uploadId")]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		parts[n], _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, n))
+
+	case r.Method == "PUT":
+		s.objects[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+		s.parts[r.URL.Path] = 0
+
+	case r.Method == "DELETE" && q.Get("uploadId") != "":
+		delete(s.uploads, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "HEAD" || r.Method == "GET":
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		} else if r.Method == "HEAD" {
+			return
+		}
+
+		status, offset := http.StatusOK, 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			s.ranges = append(s.ranges, rng)
+			offset, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			status = http.StatusPartialContent
+		}
+		data = data[offset:]
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(status)
+		if s.interrupt > 0 && len(data) > s.after {
+			s.interrupt--
+			w.Write(data[:s.after])
+			return
+		}
+		w.Write(data)
+
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// s3Error writes an S3 error response.
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// errReader returns err on every read.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read(p []byte) (int, error) { return 0, r.err }
+
+// MustReadAll reads the named file of a storage. Panic on error.
+func MustReadAll(s backup.Storage, name string) []byte {
+	r, err := s.Open(name)
+	if err != nil {
+		panic(err)
+	}
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
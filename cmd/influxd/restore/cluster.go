@@ -0,0 +1,214 @@
+package restore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/zhexuany/influxcloud/cluster"
+	"github.com/zhexuany/influxcloud/cmd/influxd/backup"
+	cloudMeta "github.com/zhexuany/influxcloud/meta"
+)
+
+// policyKey identifies a backed up retention policy.
+type policyKey struct {
+	database string
+	policy   string
+}
+
+// shardFile is a snapshot of a shard stored in a backup.
+type shardFile struct {
+	storage backup.Storage
+	name    string
+}
+
+// restoreShardTask restores a backed up shard to one owner of the shard
+// created for it. Files holds the snapshots of the shard in the order they
+// are restored, the full backup first.
+type restoreShardTask struct {
+	shard   backup.ShardManifest
+	files   []shardFile
+	shardID uint64
+}
+
+// restoreCluster restores a cluster backup onto a live cluster. The backup is
+// restored as of cmd.time by walking the chain of the full backup and its
+// incremental backups. Each backed up retention policy is imported into the
+// cluster, renamed by -newdb and -newrp, which recreates its shard groups with
+// new shard IDs and the same number of shards placed on the current data
+// nodes. Each shard is then restored onto the owners of the shard created for
+// it.
+func (cmd *Command) restoreCluster() error {
+	store, err := cmd.storage.Open(cmd.backupFilesPath)
+	if err != nil {
+		return err
+	}
+	chain, err := backup.ReadChain(store, cmd.time)
+	if err != nil {
+		return err
+	}
+	for _, b := range chain {
+		if err := b.Verify(); err != nil {
+			return err
+		}
+	}
+
+	// The last backup of the chain defines the meta data and shards restored,
+	// the snapshots of each shard are collected along the chain.
+	last := chain[len(chain)-1]
+	manifest := last.Manifest
+	fmt.Fprintf(cmd.Stdout, "Restoring backup %s created at %s\n", last.Storage.Location(), manifest.CreatedAt.Format(time.RFC3339))
+	files := make(map[uint64][]shardFile)
+	for _, b := range chain {
+		for _, sm := range b.Manifest.Shards {
+			files[sm.ID] = append(files[sm.ID], shardFile{storage: b.Storage, name: sm.File})
+		}
+	}
+
+	r, err := last.Storage.Open(manifest.Meta)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	var data cloudMeta.Data
+	if err := data.UnmarshalBinary(b); err != nil {
+		return fmt.Errorf("unmarshal: %s", err)
+	}
+
+	c, err := cmd.openMetaClient()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	client := cluster.NewClient(10 * time.Second)
+	if cmd.tlsCert != "" {
+		client.TLS = cluster.NewTLSFromFiles(cmd.tlsCert, cmd.tlsKey, cmd.tlsCA)
+	}
+
+	// Group the shards by retention policy, keeping the order of the backup.
+	var keys []policyKey
+	policies := make(map[policyKey][]backup.ShardManifest)
+	for _, sm := range manifest.Shards {
+		if cmd.database != "" && sm.Database != cmd.database {
+			continue
+		} else if cmd.retention != "" && sm.Policy != cmd.retention {
+			continue
+		}
+
+		key := policyKey{database: sm.Database, policy: sm.Policy}
+		if _, ok := policies[key]; !ok {
+			keys = append(keys, key)
+		}
+		policies[key] = append(policies[key], sm)
+	}
+
+	tasks := make(map[uint64][]restoreShardTask)
+	for _, key := range keys {
+		database, policy := key.database, key.policy
+		if cmd.newDatabase != "" {
+			database = cmd.newDatabase
+		}
+		if cmd.newRetention != "" {
+			policy = cmd.newRetention
+		}
+
+		fmt.Fprintf(cmd.Stdout, "Importing retention policy %s.%s as %s.%s\n", key.database, key.policy, database, policy)
+		shardIDs, err := c.ImportData(&data, key.database, key.policy, database, policy, cmd.newReplicaN, cmd.force)
+		if err != nil {
+			return fmt.Errorf("import retention policy %s.%s: %s", key.database, key.policy, err)
+		}
+
+		for _, sm := range policies[key] {
+			id, ok := shardIDs[sm.ID]
+			if !ok && cmd.force {
+				fmt.Fprintf(cmd.Stdout, "Skipping shard %d of %s.%s overlapping an existing shard group\n", sm.ID, key.database, key.policy)
+				continue
+			} else if !ok {
+				return fmt.Errorf("shard %d of %s.%s not found in backed up meta data", sm.ID, key.database, key.policy)
+			}
+			_, _, si := c.ShardOwner(id)
+			if si == nil {
+				return fmt.Errorf("shard %d not created", id)
+			}
+			for _, o := range si.Owners {
+				tasks[o.NodeID] = append(tasks[o.NodeID], restoreShardTask{shard: sm, files: files[sm.ID], shardID: id})
+			}
+		}
+	}
+
+	// Restore the shards of each node in parallel with the other nodes.
+	var wg sync.WaitGroup
+	errs := make(chan error, len(tasks))
+	for nodeID, nodeTasks := range tasks {
+		n, err := c.DataNode(nodeID)
+		if err != nil {
+			return fmt.Errorf("data node %d: %s", nodeID, err)
+		}
+
+		wg.Add(1)
+		go func(n *cloudMeta.NodeInfo, nodeTasks []restoreShardTask) {
+			defer wg.Done()
+			for _, t := range nodeTasks {
+				if err := cmd.restoreClusterShard(client, n, t); err != nil {
+					errs <- fmt.Errorf("restore shard %d to data node %d (%s): %s", t.shard.ID, n.ID, n.TCPHost, err)
+					return
+				}
+			}
+		}(n, nodeTasks)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// restoreClusterShard restores the snapshots of a backed up shard onto a data
+// node in order. The data node may not know the shard yet when its meta data
+// is behind, so each restore is retried.
+func (cmd *Command) restoreClusterShard(client *cluster.Client, n *cloudMeta.NodeInfo, t restoreShardTask) error {
+	fmt.Fprintf(cmd.Stdout, "Restoring shard %d of %s.%s as shard %d on data node %d\n",
+		t.shard.ID, t.shard.Database, t.shard.Policy, t.shardID, n.ID)
+
+	for _, file := range t.files {
+		var err error
+		for i := 0; i < 10; i++ {
+			if err = func() error {
+				r, err := file.storage.Open(file.name)
+				if err != nil {
+					return err
+				}
+				defer r.Close()
+				return client.RestoreShard(n.TCPHost, t.shardID, r)
+			}(); err == nil {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openMetaClient returns a meta client connected to the meta node at
+// cmd.metaAddr.
+func (cmd *Command) openMetaClient() (*cloudMeta.Client, error) {
+	config := cloudMeta.NewConfig()
+	config.AuthEnabled = cmd.secret != ""
+	config.InternalSharedSecret = cmd.secret
+
+	c := cloudMeta.NewClient(config)
+	c.SetMetaServers([]string{cmd.metaAddr})
+	if err := c.Ping(false); err != nil {
+		return nil, fmt.Errorf("ping meta node %s: %s", cmd.metaAddr, err)
+	}
+	if err := c.Open(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
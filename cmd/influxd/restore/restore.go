@@ -16,10 +16,11 @@ import (
 	"path/filepath"
 	"strconv"
 	"sync"
+	"time"
 
-	"github.com/influxdata/influxdb/cmd/influxd/backup"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/services/snapshotter"
+	"github.com/zhexuany/influxcloud/cmd/influxd/backup"
 )
 
 // Command represents the program execution for "influxd restore".
@@ -34,6 +35,29 @@ type Command struct {
 	retention       string
 	shard           string
 
+	// metaAddr is the HTTP address of a meta node when restoring a cluster
+	// backup. The secret signs requests to meta nodes with auth enabled, the
+	// TLS files secure requests to data nodes with cluster TLS enabled.
+	metaAddr string
+	secret   string
+	tlsCert  string
+	tlsKey   string
+	tlsCA    string
+
+	// time is the point in time a chain of cluster backups is restored to.
+	time time.Time
+
+	// newDatabase, newRetention and newReplicaN rename a restored database
+	// and retention policy and change its replication factor. Force allows
+	// restoring into existing retention policies.
+	newDatabase  string
+	newRetention string
+	newReplicaN  int
+	force        bool
+
+	// storage opens the storage of cluster backups in object storage.
+	storage backup.StorageConfig
+
 	// TODO: when the new meta stuff is done this should not be exported or be gone
 	MetaConfig *meta.Config
 }
@@ -53,6 +77,10 @@ func (cmd *Command) Run(args ...string) error {
 		return err
 	}
 
+	if cmd.metaAddr != "" {
+		return cmd.restoreCluster()
+	}
+
 	if cmd.metadir != "" {
 		if err := cmd.unpackMeta(); err != nil {
 			return err
@@ -77,6 +105,19 @@ func (cmd *Command) parseFlags(args []string) error {
 	fs.StringVar(&cmd.database, "database", "", "")
 	fs.StringVar(&cmd.retention, "retention", "", "")
 	fs.StringVar(&cmd.shard, "shard", "", "")
+	fs.StringVar(&cmd.metaAddr, "meta", "", "")
+	fs.StringVar(&cmd.secret, "secret", "", "")
+	fs.StringVar(&cmd.tlsCert, "tls-certificate", "", "")
+	fs.StringVar(&cmd.tlsKey, "tls-private-key", "", "")
+	fs.StringVar(&cmd.tlsCA, "tls-ca-bundle", "", "")
+	fs.StringVar(&cmd.newDatabase, "newdb", "", "")
+	fs.StringVar(&cmd.newRetention, "newrp", "", "")
+	fs.IntVar(&cmd.newReplicaN, "newrf", 0, "")
+	fs.BoolVar(&cmd.force, "force", false, "")
+	cmd.storage = backup.NewStorageConfig()
+	cmd.storage.RegisterFlags(fs)
+	var timeArg string
+	fs.StringVar(&timeArg, "time", "", "")
 	fs.SetOutput(cmd.Stdout)
 	fs.Usage = cmd.printUsage
 	if err := fs.Parse(args); err != nil {
@@ -92,6 +133,40 @@ func (cmd *Command) parseFlags(args []string) error {
 		return fmt.Errorf("path with backup files required")
 	}
 
+	if timeArg != "" {
+		t, err := time.Parse(time.RFC3339, timeArg)
+		if err != nil {
+			return err
+		} else if cmd.metaAddr == "" {
+			return fmt.Errorf("-time requires -meta")
+		}
+		cmd.time = t
+	}
+
+	if cmd.metaAddr == "" && backup.IsURL(cmd.backupFilesPath) {
+		return fmt.Errorf("restore from object storage requires -meta")
+	} else if cmd.metaAddr == "" && (cmd.newDatabase != "" || cmd.newRetention != "" || cmd.newReplicaN != 0 || cmd.force) {
+		return fmt.Errorf("-newdb, -newrp, -newrf and -force require -meta")
+	}
+
+	// A cluster backup is restored through the meta and data nodes.
+	if cmd.metaAddr != "" {
+		if cmd.metadir != "" || cmd.datadir != "" || cmd.shard != "" {
+			return fmt.Errorf("-metadir, -datadir and -shard can't be used with -meta")
+		} else if cmd.retention != "" && cmd.database == "" {
+			return fmt.Errorf("-database is required to restore retention policy")
+		} else if cmd.newDatabase != "" && cmd.database == "" {
+			return fmt.Errorf("-database is required to restore as -newdb")
+		} else if cmd.newRetention != "" && cmd.retention == "" {
+			return fmt.Errorf("-retention is required to restore as -newrp")
+		} else if cmd.newReplicaN < 0 {
+			return fmt.Errorf("-newrf must be positive")
+		} else if (cmd.tlsCert != "" || cmd.tlsKey != "" || cmd.tlsCA != "") && (cmd.tlsCert == "" || cmd.tlsKey == "" || cmd.tlsCA == "") {
+			return fmt.Errorf("-tls-certificate, -tls-private-key and -tls-ca-bundle must be set together")
+		}
+		return nil
+	}
+
 	// validate the arguments
 	if cmd.metadir == "" && cmd.database == "" {
 		return fmt.Errorf("-metadir or -database are required to restore")
@@ -339,6 +414,9 @@ running during a restore.
 
 Usage: influxd restore [flags] PATH
 
+PATH is a local directory or, with -meta, an s3://bucket/prefix URL. Backups
+of a single server without -meta are only restored from a local directory.
+
     -metadir <path>
             Optional. If set the metastore will be recovered to the given path.
     -datadir <path>
@@ -353,6 +431,41 @@ Usage: influxd restore [flags] PATH
     -shard <id>
             Optional. If given, database and retention are required. Will restore the shard's
             TSM files.
+    -meta <host:port>
+            Optional. The HTTP address of a meta node. Restores a cluster backup
+            onto the running cluster: the backed up retention policies, or the
+            ones of the database and retention policy given, are created and
+            their shards are placed on the current data nodes under new shard
+            IDs. An incremental backup is restored after the backups it builds
+            on.
+    -newdb <name>
+            Optional. With -meta and -database, restore the database under
+            this name.
+    -newrp <name>
+            Optional. With -meta and -retention, restore the retention policy
+            under this name.
+    -newrf <n>
+            Optional. With -meta, the replication factor of the restored
+            retention policies. Defaults to the backed up replication factor.
+    -force
+            Optional. With -meta, restore into retention policies that already
+            exist. Shard groups overlapping existing ones are skipped along
+            with their shards.
+    -time <2015-12-24T08:12:23Z>
+            Optional. With -meta, restore the last backup of the chain ending
+            at PATH created at or before the passed in RFC3339 formatted time.
+    -s3-endpoint <url>
+            Optional. The endpoint of the S3 compatible service storing s3://
+            backups. Defaults to https://s3.<region>.amazonaws.com. The
+            credentials are read from AWS_ACCESS_KEY_ID and
+            AWS_SECRET_ACCESS_KEY.
+    -s3-region <region>
+            Optional. The region requests are signed for. Defaults to us-east-1.
+    -secret <secret>
+            Optional. The shared secret of meta nodes with auth enabled.
+    -tls-certificate <path> -tls-private-key <path> -tls-ca-bundle <path>
+            Optional. Client certificate, key and CA bundle for data nodes
+            with cluster TLS enabled.
 
 `)
 }
@@ -0,0 +1,23 @@
+package restore_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/zhexuany/influxcloud/cmd/influxd/restore"
+)
+
+// Ensure a backup of a single server isn't restored from object storage.
+func TestCommand_ObjectStorageRequiresMeta(t *testing.T) {
+	cmd := restore.NewCommand()
+	cmd.Stdout, cmd.Stderr = ioutil.Discard, ioutil.Discard
+
+	for _, args := range [][]string{
+		{"-metadir", "/var/lib/influxdb/meta", "s3://bucket/backups/full"},
+		{"-database", "db0", "-datadir", "/var/lib/influxdb/data", "s3://bucket/backups/full"},
+	} {
+		if err := cmd.Run(args...); err == nil || err.Error() != "restore from object storage requires -meta" {
+			t.Fatalf("unexpected error for %v: %v", args, err)
+		}
+	}
+}
@@ -4,12 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"os/user"
 	"path/filepath"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -58,7 +56,7 @@ type Config struct {
 
 	ContinuousQuery continuous_querier.Config `toml:"continuous_queries"`
 
-	Hintedhandoff hh.Config `toml:"hinted-handoff`
+	Hintedhandoff hh.Config `toml:"hinted-handoff"`
 
 	// Server reporting
 	ReportingDisabled bool `toml:"reporting-disabled"`
@@ -72,8 +70,10 @@ func NewConfig() *Config {
 	c := &Config{}
 	c.Meta = meta.NewConfig()
 	c.Data = tsdb.NewConfig()
+	c.Cluster = cluster.NewConfig()
 	c.Coordinator = coordinator.NewConfig()
 	c.Precreator = precreator.NewConfig()
+	c.Hintedhandoff = hh.NewConfig()
 
 	c.Admin = admin.NewConfig()
 	c.Monitor = monitor.NewConfig()
@@ -110,6 +110,7 @@ func NewDemoConfig() (*Config, error) {
 	c.Meta.Dir = filepath.Join(homeDir, ".influxdb/meta")
 	c.Data.Dir = filepath.Join(homeDir, ".influxdb/data")
 	c.Data.WALDir = filepath.Join(homeDir, ".influxdb/wal")
+	c.Hintedhandoff.Dir = filepath.Join(homeDir, ".influxdb/hh")
 
 	return c, nil
 }
@@ -133,15 +134,6 @@ func (c *Config) FromTomlFile(fpath string) error {
 
 // FromToml loads the config from TOML.
 func (c *Config) FromToml(input string) error {
-	// Replace deprecated [cluster] with [coordinator]
-	re := regexp.MustCompile(`(?m)^\s*\[cluster\]`)
-	input = re.ReplaceAllStringFunc(input, func(in string) string {
-		in = strings.TrimSpace(in)
-		out := "[coordinator]"
-		log.Printf("deprecated config option %s replaced with %s; %s will not be supported in a future release\n", in, out, in)
-		return out
-	})
-
 	_, err := toml.Decode(input, c)
 	return err
 }
@@ -156,6 +148,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Cluster.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.Monitor.Validate(); err != nil {
 		return err
 	}
@@ -176,6 +172,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Hintedhandoff.Validate(); err != nil {
+		return err
+	}
+
 	for _, graphite := range c.GraphiteInputs {
 		if err := graphite.Validate(); err != nil {
 			return fmt.Errorf("invalid graphite config: %v", err)
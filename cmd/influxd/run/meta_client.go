@@ -0,0 +1,152 @@
+package run
+
+import (
+	"errors"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+	cloudMeta "github.com/zhexuany/influxcloud/meta"
+)
+
+// metaClient adapts the client of the meta nodes to the interfaces of the
+// influxdb services running on a data node.
+type metaClient struct {
+	*cloudMeta.Client
+}
+
+// Database returns the database by name or nil if it doesn't exist.
+func (c *metaClient) Database(name string) *meta.DatabaseInfo {
+	dbi, _ := c.Client.Database(name)
+	return dbi
+}
+
+// Databases returns all databases.
+func (c *metaClient) Databases() []meta.DatabaseInfo {
+	dbs, _ := c.Client.Databases()
+	return dbs
+}
+
+// CreateRetentionPolicy creates a retention policy and makes it the default
+// of the database if makeDefault is set.
+func (c *metaClient) CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error) {
+	rpi, err := c.Client.CreateRetentionPolicy(database, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if makeDefault {
+		if err := c.Client.SetDefaultRetentionPolicy(database, rpi.Name); err != nil {
+			return nil, err
+		}
+	}
+	return rpi, nil
+}
+
+// UpdateRetentionPolicy updates a retention policy and makes it the default
+// of the database if makeDefault is set.
+func (c *metaClient) UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error {
+	if err := c.Client.UpdateRetentionPolicy(database, name, rpu); err != nil {
+		return err
+	}
+
+	if makeDefault {
+		if rpu.Name != nil {
+			name = *rpu.Name
+		}
+		return c.Client.SetDefaultRetentionPolicy(database, name)
+	}
+	return nil
+}
+
+// ShardGroupsByTimeRange returns the shard groups of a retention policy
+// overlapping the time range.
+func (c *metaClient) ShardGroupsByTimeRange(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+	return c.Client.ShardGroupsByTimeRange(database, policy, min, max)
+}
+
+// DropShard isn't supported by the meta nodes. The owners of a shard are
+// managed through the cluster service instead.
+func (c *metaClient) DropShard(id uint64) error {
+	return errors.New("drop shard is not supported by a cluster, remove the shard from its owners instead")
+}
+
+// PruneShardGroups is a no-op. Deleted shard groups are kept by the meta nodes.
+func (c *metaClient) PruneShardGroups() error { return nil }
+
+// UserPrivilege returns the privilege of a user on a database.
+func (c *metaClient) UserPrivilege(username, database string) (*influxql.Privilege, error) {
+	return c.Client.Data().Data.UserPrivilege(username, database)
+}
+
+// UserPrivileges returns the privileges of a user on every database.
+func (c *metaClient) UserPrivileges(username string) (map[string]influxql.Privilege, error) {
+	return c.Client.Data().Data.UserPrivileges(username)
+}
+
+// clusterMetaClient adapts the client of the meta nodes to the interfaces of
+// the cluster services, which describe data nodes with meta.NodeInfo.
+type clusterMetaClient struct {
+	*cloudMeta.Client
+}
+
+// DataNode returns a data node by id.
+func (c *clusterMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	n, err := c.Client.DataNode(id)
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfo(n), nil
+}
+
+// DataNodes returns all data nodes.
+func (c *clusterMetaClient) DataNodes() (meta.NodeInfos, error) {
+	nodes, err := c.Client.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	a := make(meta.NodeInfos, 0, len(nodes))
+	for i := range nodes {
+		a = append(a, *nodeInfo(&nodes[i]))
+	}
+	return a, nil
+}
+
+// DataNodeLabels returns the labels of every data node by node id.
+func (c *clusterMetaClient) DataNodeLabels() (map[uint64]map[string]string, error) {
+	nodes, err := c.Client.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[uint64]map[string]string, len(nodes))
+	for _, n := range nodes {
+		m[n.ID] = n.Labels
+	}
+	return m, nil
+}
+
+// CreateDataNodeWithLabels registers a data node with the meta nodes.
+func (c *clusterMetaClient) CreateDataNodeWithLabels(httpAddr, tcpAddr string, labels map[string]string) (*meta.NodeInfo, error) {
+	n, err := c.Client.CreateDataNodeWithLabels(httpAddr, tcpAddr, labels)
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfo(n), nil
+}
+
+// ShardOwner returns the database, retention policy and info of a shard. The
+// shard info is empty if the shard doesn't exist.
+func (c *clusterMetaClient) ShardOwner(shardID uint64) (database, policy string, si meta.ShardInfo) {
+	database, policy, sh := c.Client.ShardOwner(shardID)
+	if sh != nil {
+		si = *sh
+	}
+	return database, policy, si
+}
+
+// nodeInfo converts the node info of the meta nodes to meta.NodeInfo.
+func nodeInfo(n *cloudMeta.NodeInfo) *meta.NodeInfo {
+	return &meta.NodeInfo{ID: n.ID, Host: n.Host, TCPHost: n.TCPHost}
+}
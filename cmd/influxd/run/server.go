@@ -3,6 +3,7 @@ package run
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net"
 	"os"
@@ -11,7 +12,6 @@ import (
 	"runtime/pprof"
 	"time"
 
-	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
@@ -21,7 +21,6 @@ import (
 	"github.com/influxdata/influxdb/services/continuous_querier"
 	"github.com/influxdata/influxdb/services/graphite"
 	"github.com/influxdata/influxdb/services/httpd"
-	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/services/opentsdb"
 	"github.com/influxdata/influxdb/services/precreator"
 	"github.com/influxdata/influxdb/services/retention"
@@ -34,7 +33,10 @@ import (
 	"github.com/uber-go/zap"
 	// Initialize the engine packages
 	_ "github.com/influxdata/influxdb/tsdb/engine"
+	"github.com/zhexuany/influxcloud"
 	"github.com/zhexuany/influxcloud/cluster"
+	"github.com/zhexuany/influxcloud/hh"
+	cloudMeta "github.com/zhexuany/influxcloud/meta"
 )
 
 var startTime time.Time
@@ -65,13 +67,23 @@ type Server struct {
 
 	Logger zap.Logger
 
-	MetaClient *meta.Client
+	// Node holds the id of this data node in the cluster.
+	Node *influxcloud.Node
+
+	MetaClient *cloudMeta.Client
 
 	TSDBStore     *tsdb.Store
 	QueryExecutor *influxql.QueryExecutor
-	PointsWriter  *coordinator.PointsWriter
+	PointsWriter  *cluster.PointsWriter
 	Subscriber    *subscriber.Service
 
+	// ShardWriter writes points to the shards owned by other data nodes.
+	ShardWriter *cluster.ShardWriter
+
+	// HintedHandoff queues the writes to other data nodes that failed and
+	// replays them once the nodes are reachable.
+	HintedHandoff *hh.Service
+
 	Services []Service
 
 	// These references are required for the tcp muxer.
@@ -120,11 +132,11 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 		}
 	}
 
-	_, err := influxdb.LoadNode(c.Meta.Dir)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
+	node, err := influxcloud.LoadNode(c.Meta.Dir)
+	if os.IsNotExist(err) {
+		node = influxcloud.NewNode(c.Meta.Dir)
+	} else if err != nil {
+		return nil, err
 	}
 
 	if err := raftDBExists(c.Meta.Dir); err != nil {
@@ -147,7 +159,8 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 			zap.Output(os.Stderr),
 		),
 
-		MetaClient: meta.NewClient(c.Meta),
+		Node:       node,
+		MetaClient: newMetaClient(c, node),
 
 		reportingDisabled: c.ReportingDisabled,
 
@@ -172,28 +185,49 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	// Create the Subscriber service
 	s.Subscriber = subscriber.NewService(c.Subscriber)
 
+	// Initialize the shard writer used by the cluster services.
+	s.ShardWriter = cluster.NewShardWriter(time.Duration(c.Cluster.ShardWriterTimeout), c.Cluster.MaxRemoteWriteConnections)
+	s.ShardWriter.TLS = cluster.NewTLS(c.Cluster)
+	s.ShardWriter.MetaClient = &clusterMetaClient{s.MetaClient}
+
+	// Initialize the hinted handoff service.
+	s.HintedHandoff = hh.NewService(c.Hintedhandoff, s.ShardWriter, &clusterMetaClient{s.MetaClient})
+	s.HintedHandoff.Monitor = s.Monitor
+
 	// Initialize points writer.
-	s.PointsWriter = coordinator.NewPointsWriter()
-	s.PointsWriter.WriteTimeout = time.Duration(c.Coordinator.WriteTimeout)
+	s.PointsWriter = cluster.NewPointsWriter()
+	s.PointsWriter.WriteTimeout = time.Duration(c.Cluster.WriteTimeout)
+	s.PointsWriter.Node = s.Node
 	s.PointsWriter.TSDBStore = s.TSDBStore
+	s.PointsWriter.ShardWriter = s.ShardWriter
 	s.PointsWriter.Subscriber = s.Subscriber
+	if c.Hintedhandoff.Enabled {
+		s.PointsWriter.HintedHandoff = s.HintedHandoff
+	}
 
 	// Initialize query executor.
 	s.QueryExecutor = influxql.NewQueryExecutor()
-	s.QueryExecutor.StatementExecutor = &coordinator.StatementExecutor{
-		MetaClient:  s.MetaClient,
-		TaskManager: s.QueryExecutor.TaskManager,
-		TSDBStore:   coordinator.LocalTSDBStore{Store: s.TSDBStore},
-		ShardMapper: &coordinator.LocalShardMapper{
-			MetaClient: s.MetaClient,
-			TSDBStore:  coordinator.LocalTSDBStore{Store: s.TSDBStore},
-		},
+	shardMapper := cluster.NewShardMapper(time.Duration(c.Cluster.ShardReaderTimeout))
+	shardMapper.TLS = cluster.NewTLS(c.Cluster)
+	shardMapper.Node = s.Node
+	shardMapper.MetaClient = &clusterMetaClient{s.MetaClient}
+	shardMapper.TSDBStore = s.TSDBStore
+
+	statementExecutor := cluster.NewStatementExecutor(time.Duration(c.Cluster.ShardReaderTimeout), c.Cluster.MaxRemoteWriteConnections)
+	statementExecutor.TLS = shardMapper.TLS
+	statementExecutor.MetaClient = &clusterMetaClient{s.MetaClient}
+	statementExecutor.StatementExecutor = coordinator.StatementExecutor{
+		MetaClient:        &metaClient{s.MetaClient},
+		TaskManager:       s.QueryExecutor.TaskManager,
+		TSDBStore:         coordinator.LocalTSDBStore{Store: s.TSDBStore},
+		ShardMapper:       shardMapper,
 		Monitor:           s.Monitor,
 		PointsWriter:      s.PointsWriter,
 		MaxSelectPointN:   c.Coordinator.MaxSelectPointN,
 		MaxSelectSeriesN:  c.Coordinator.MaxSelectSeriesN,
 		MaxSelectBucketsN: c.Coordinator.MaxSelectBucketsN,
 	}
+	s.QueryExecutor.StatementExecutor = statementExecutor
 	s.QueryExecutor.TaskManager.QueryTimeout = time.Duration(c.Coordinator.QueryTimeout)
 	s.QueryExecutor.TaskManager.LogQueriesAfter = time.Duration(c.Coordinator.LogQueriesAfter)
 	s.QueryExecutor.TaskManager.MaxConcurrentQueries = c.Coordinator.MaxConcurrentQueries
@@ -207,12 +241,28 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	return s, nil
 }
 
+// newMetaClient returns a client of the meta nodes for the data node. The meta
+// servers are known once the node joined a cluster.
+func newMetaClient(c *Config, node *influxcloud.Node) *cloudMeta.Client {
+	config := cloudMeta.NewConfig()
+	config.Dir = c.Meta.Dir
+	config.RetentionAutoCreate = c.Meta.RetentionAutoCreate
+	config.LoggingEnabled = c.Meta.LoggingEnabled
+	config.AuthEnabled = c.Cluster.MetaAuthEnabled
+	config.InternalSharedSecret = c.Cluster.MetaInternalSharedSecret
+
+	client := cloudMeta.NewClient(config)
+	client.SetNodeID(node.ID)
+	return client
+}
+
 // Statistics returns statistics for the services running in the Server.
 func (s *Server) Statistics(tags map[string]string) []models.Statistic {
 	var statistics []models.Statistic
 	statistics = append(statistics, s.QueryExecutor.Statistics(tags)...)
 	statistics = append(statistics, s.TSDBStore.Statistics(tags)...)
 	statistics = append(statistics, s.PointsWriter.Statistics(tags)...)
+	statistics = append(statistics, s.HintedHandoff.Statistics(tags)...)
 	statistics = append(statistics, s.Subscriber.Statistics(tags)...)
 	for _, srv := range s.Services {
 		if m, ok := srv.(monitor.Reporter); ok {
@@ -225,7 +275,7 @@ func (s *Server) Statistics(tags map[string]string) []models.Statistic {
 func (s *Server) appendSnapshotterService() {
 	srv := snapshotter.NewService()
 	srv.TSDBStore = s.TSDBStore
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	s.Services = append(s.Services, srv)
 	s.SnapshotterService = srv
 }
@@ -245,7 +295,7 @@ func (s *Server) appendRetentionPolicyService(c retention.Config) {
 		return
 	}
 	srv := retention.NewService(c)
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	srv.TSDBStore = s.TSDBStore
 	s.Services = append(s.Services, srv)
 }
@@ -264,9 +314,9 @@ func (s *Server) appendHTTPDService(c httpd.Config) {
 		return
 	}
 	srv := httpd.NewService(c)
-	srv.Handler.MetaClient = s.MetaClient
-	srv.Handler.QueryAuthorizer = meta.NewQueryAuthorizer(s.MetaClient)
-	srv.Handler.WriteAuthorizer = meta.NewWriteAuthorizer(s.MetaClient)
+	srv.Handler.MetaClient = &metaClient{s.MetaClient}
+	srv.Handler.QueryAuthorizer = cloudMeta.NewQueryAuthorizer(s.MetaClient)
+	srv.Handler.WriteAuthorizer = cloudMeta.NewWriteAuthorizer(s.MetaClient)
 	srv.Handler.QueryExecutor = s.QueryExecutor
 	srv.Handler.Monitor = s.Monitor
 	srv.Handler.PointsWriter = s.PointsWriter
@@ -280,7 +330,7 @@ func (s *Server) appendCollectdService(c collectd.Config) {
 		return
 	}
 	srv := collectd.NewService(c)
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	srv.PointsWriter = s.PointsWriter
 	s.Services = append(s.Services, srv)
 }
@@ -294,7 +344,7 @@ func (s *Server) appendOpenTSDBService(c opentsdb.Config) error {
 		return err
 	}
 	srv.PointsWriter = s.PointsWriter
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	s.Services = append(s.Services, srv)
 	return nil
 }
@@ -309,7 +359,7 @@ func (s *Server) appendGraphiteService(c graphite.Config) error {
 	}
 
 	srv.PointsWriter = s.PointsWriter
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	srv.Monitor = s.Monitor
 	s.Services = append(s.Services, srv)
 	return nil
@@ -324,7 +374,7 @@ func (s *Server) appendPrecreatorService(c precreator.Config) error {
 		return err
 	}
 
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	s.Services = append(s.Services, srv)
 	return nil
 }
@@ -335,7 +385,7 @@ func (s *Server) appendUDPService(c udp.Config) {
 	}
 	srv := udp.NewService(c)
 	srv.PointsWriter = s.PointsWriter
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	s.Services = append(s.Services, srv)
 }
 
@@ -344,16 +394,41 @@ func (s *Server) appendContinuousQueryService(c continuous_querier.Config) {
 		return
 	}
 	srv := continuous_querier.NewService(c)
-	srv.MetaClient = s.MetaClient
+	srv.MetaClient = &metaClient{s.MetaClient}
 	srv.QueryExecutor = s.QueryExecutor
 	s.Services = append(s.Services, srv)
 }
 
-func (s *Server) appendClusterService(c cluster.Config) {
+func (s *Server) appendClusterService(c cluster.Config) error {
 	srv := cluster.NewService(c)
+	srv.Node = s.Node
+	srv.HTTPAddr = s.httpAPIAddr
+	srv.MetaClient = &clusterMetaClient{s.MetaClient}
+	srv.MembershipClient = &clusterMetaClient{s.MetaClient}
+	srv.ShardWriter = s.ShardWriter
 	srv.TSDBStore = s.TSDBStore
+	srv.TaskManager = s.QueryExecutor.TaskManager
+	if s.config.Hintedhandoff.Enabled {
+		srv.HintedHandoff = s.HintedHandoff
+	}
 	s.Services = append(s.Services, srv)
 	s.ClusterServerice = srv
+	return nil
+}
+
+func (s *Server) appendRebalancerService(c cluster.Config) {
+	srv := cluster.NewRebalancer(c)
+	srv.MetaClient = &clusterMetaClient{s.MetaClient}
+	srv.ShardWriter = s.ShardWriter
+	s.Services = append(s.Services, srv)
+}
+
+func (s *Server) appendAntiEntropyService(c cluster.Config) {
+	srv := cluster.NewAntiEntropy(c)
+	srv.MetaClient = &clusterMetaClient{s.MetaClient}
+	srv.ShardWriter = s.ShardWriter
+	srv.Monitor = s.Monitor
+	s.Services = append(s.Services, srv)
 }
 
 // Err returns an error channel that multiplexes all out of band errors received from all services.
@@ -376,7 +451,11 @@ func (s *Server) Open() error {
 	go mux.Serve(ln)
 
 	// Append services.
-	s.appendClusterService(s.config.Cluster)
+	if err := s.appendClusterService(s.config.Cluster); err != nil {
+		return err
+	}
+	s.appendRebalancerService(s.config.Cluster)
+	s.appendAntiEntropyService(s.config.Cluster)
 	s.appendMonitorService()
 	s.appendPrecreatorService(s.config.Precreator)
 	s.appendSnapshotterService()
@@ -401,23 +480,23 @@ func (s *Server) Open() error {
 		s.appendUDPService(i)
 	}
 
-	s.Subscriber.MetaClient = s.MetaClient
-	s.Subscriber.MetaClient = s.MetaClient
-	s.PointsWriter.MetaClient = s.MetaClient
-	s.Monitor.MetaClient = s.MetaClient
+	s.Subscriber.MetaClient = &metaClient{s.MetaClient}
+	s.PointsWriter.MetaClient = &metaClient{s.MetaClient}
+	s.Monitor.MetaClient = &metaClient{s.MetaClient}
 
 	s.SnapshotterService.Listener = mux.Listen(snapshotter.MuxHeader)
 	s.ClusterServerice.Listener = mux.Listen(cluster.MuxHeader)
 
 	// Configure logging for all services and clients.
-	if s.config.Meta.LoggingEnabled {
-		s.MetaClient.WithLogger(s.Logger)
+	if !s.config.Meta.LoggingEnabled {
+		s.MetaClient.SetLogger(log.New(ioutil.Discard, "", 0))
 	}
 	s.TSDBStore.WithLogger(s.Logger)
 	if s.config.Data.QueryLogEnabled {
 		s.QueryExecutor.WithLogger(s.Logger)
 	}
 	s.PointsWriter.WithLogger(s.Logger)
+	s.HintedHandoff.WithLogger(s.Logger)
 	s.Subscriber.WithLogger(s.Logger)
 	for _, svc := range s.Services {
 		svc.WithLogger(s.Logger)
@@ -441,6 +520,11 @@ func (s *Server) Open() error {
 		return fmt.Errorf("open points writer: %s", err)
 	}
 
+	// Open the hinted handoff service
+	if err := s.HintedHandoff.Open(); err != nil {
+		return fmt.Errorf("open hinted handoff: %s", err)
+	}
+
 	for _, service := range s.Services {
 		if err := service.Open(); err != nil {
 			return fmt.Errorf("open service: %s", err)
@@ -474,6 +558,14 @@ func (s *Server) Close() error {
 		s.PointsWriter.Close()
 	}
 
+	if s.HintedHandoff != nil {
+		s.HintedHandoff.Close()
+	}
+
+	if s.ShardWriter != nil {
+		s.ShardWriter.Close()
+	}
+
 	if s.QueryExecutor != nil {
 		s.QueryExecutor.Close()
 	}
@@ -513,7 +605,7 @@ func (s *Server) startServerReporting() {
 
 // reportServer reports usage statistics about the system.
 func (s *Server) reportServer() {
-	dis := s.MetaClient.Databases()
+	dis, _ := s.MetaClient.Databases()
 	numDatabases := len(dis)
 
 	numMeasurements := 0
@@ -629,12 +721,12 @@ type tcpaddr struct{ host string }
 func (a *tcpaddr) Network() string { return "tcp" }
 func (a *tcpaddr) String() string  { return a.host }
 
-// monitorPointsWriter is a wrapper around `coordinator.PointsWriter` that helps
+// monitorPointsWriter is a wrapper around `cluster.PointsWriter` that helps
 // to prevent a circular dependency between the `cluster` and `monitor` packages.
-type monitorPointsWriter coordinator.PointsWriter
+type monitorPointsWriter cluster.PointsWriter
 
 func (pw *monitorPointsWriter) WritePoints(database, retentionPolicy string, points models.Points) error {
-	return (*coordinator.PointsWriter)(pw).WritePoints(database, retentionPolicy, models.ConsistencyLevelAny, points)
+	return (*cluster.PointsWriter)(pw).WritePoints(database, retentionPolicy, models.ConsistencyLevelAny, points)
 }
 
 func raftDBExists(dir string) error {
@@ -42,6 +42,16 @@ type NodeProcessor struct {
 	wg   sync.WaitGroup
 	done chan struct{}
 
+	// drain triggers an immediate replay of the queue. Replay is skipped
+	// while paused is set.
+	drain  chan struct{}
+	paused int32
+
+	// lastErr is the last error replaying the queue, at lastErrAt.
+	errMu     sync.Mutex
+	lastErr   error
+	lastErrAt time.Time
+
 	queue  *queue
 	meta   metaClient
 	writer shardWriter
@@ -59,6 +69,7 @@ func NewNodeProcessor(nodeID uint64, dir string, w shardWriter, m metaClient) *N
 		dir:    dir,
 		writer: w,
 		meta:   m,
+		drain:  make(chan struct{}, 1),
 
 		stats: &Statistics{},
 		defaultTags: models.StatisticTags{
@@ -264,33 +275,142 @@ func (n *NodeProcessor) run() {
 			}
 
 		case <-time.After(currInterval):
-			limiter := NewRateLimiter(n.RetryRateLimit)
-			for {
-				c, err := n.SendWrite()
-				if err != nil {
-					if err == io.EOF {
-						// No more data, return to configured interval
-						currInterval = time.Duration(n.RetryInterval)
-					} else {
-						currInterval = currInterval * 2
-						if currInterval > time.Duration(n.RetryMaxInterval) {
-							currInterval = time.Duration(n.RetryMaxInterval)
-						}
-					}
-					break
-				}
-
-				// Success! Ensure backoff is cancelled.
-				currInterval = time.Duration(n.RetryInterval)
-
-				// Update how many bytes we've sent
-				limiter.Update(c)
-
-				// Block to maintain the throughput rate
-				time.Sleep(limiter.Delay())
+			currInterval = n.replay(currInterval)
+
+		case <-n.drain:
+			// A forced drain starts without backoff.
+			currInterval = n.replay(time.Duration(n.RetryInterval))
+		}
+	}
+}
+
+// replay sends the queued writes to the node until the queue is empty or a
+// write fails, and returns the interval until the next replay.
+func (n *NodeProcessor) replay(currInterval time.Duration) time.Duration {
+	if n.Paused() {
+		return currInterval
+	}
+
+	limiter := NewRateLimiter(n.RetryRateLimit)
+	for {
+		select {
+		case <-n.done:
+			return currInterval
+		default:
+		}
+
+		c, err := n.SendWrite()
+		if err != nil {
+			if err == io.EOF {
+				// No more data, return to configured interval
+				return time.Duration(n.RetryInterval)
 			}
+
+			n.setLastErr(err)
+			currInterval = currInterval * 2
+			if currInterval > time.Duration(n.RetryMaxInterval) {
+				currInterval = time.Duration(n.RetryMaxInterval)
+			}
+			return currInterval
+		}
+
+		// Success! Ensure backoff is cancelled.
+		currInterval = time.Duration(n.RetryInterval)
+
+		// Update how many bytes we've sent
+		limiter.Update(c)
+
+		// Block to maintain the throughput rate
+		time.Sleep(limiter.Delay())
+
+		if n.Paused() {
+			return currInterval
+		}
+	}
+}
+
+// Drain replays the queue immediately instead of waiting for the next retry
+// interval. It returns without waiting for the replay to finish.
+func (n *NodeProcessor) Drain() error {
+	if n.Closed() {
+		return fmt.Errorf("node processor is closed")
+	} else if n.Paused() {
+		return ErrReplayPaused
+	}
+
+	select {
+	case n.drain <- struct{}{}:
+	default:
+		// A drain is already pending.
+	}
+	return nil
+}
+
+// Pause stops replaying the queue to the node. Writes are still queued.
+func (n *NodeProcessor) Pause() {
+	atomic.StoreInt32(&n.paused, 1)
+}
+
+// Resume resumes replaying the queue to the node.
+func (n *NodeProcessor) Resume() {
+	atomic.StoreInt32(&n.paused, 0)
+}
+
+// Paused returns true if replaying the queue is paused.
+func (n *NodeProcessor) Paused() bool {
+	return atomic.LoadInt32(&n.paused) == 1
+}
+
+func (n *NodeProcessor) setLastErr(err error) {
+	n.errMu.Lock()
+	defer n.errMu.Unlock()
+	n.lastErr, n.lastErrAt = err, time.Now().UTC()
+}
+
+// NodeStatus describes the hinted handoff queue of a node.
+type NodeStatus struct {
+	NodeID uint64
+
+	// Bytes is the size of the writes not yet replayed, Segments is the
+	// number of segment files holding them.
+	Bytes    int64
+	Segments int64
+
+	// HeadModified is the last time the head segment was written. The oldest
+	// write queued is at least as old. It is zero for an empty queue.
+	HeadModified time.Time
+
+	// LastErr is the last error replaying the queue, at LastErrAt.
+	LastErr   string
+	LastErrAt time.Time
+
+	Paused bool
+}
+
+// Status returns the status of the queue.
+func (n *NodeProcessor) Status() NodeStatus {
+	st := NodeStatus{NodeID: n.nodeID, Paused: n.Paused()}
+
+	n.errMu.Lock()
+	if n.lastErr != nil {
+		st.LastErr, st.LastErrAt = n.lastErr.Error(), n.lastErrAt
+	}
+	n.errMu.Unlock()
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.done == nil {
+		return st
+	}
+
+	st.Bytes = n.queue.PendingBytes()
+	if st.Bytes > 0 {
+		st.Segments = n.queue.totalSegments()
+		if t, err := n.queue.HeadModified(); err == nil {
+			st.HeadModified = t
 		}
 	}
+	return st
 }
 
 // SendWrite attempts to sent the current block of hinted data to the target node. If successful,
@@ -1,6 +1,7 @@
 package hh
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -164,3 +165,96 @@ func TestNodeProcessorSendBlock(t *testing.T) {
 		t.Fatalf("Node processor directory still present after purge")
 	}
 }
+
+func TestNodeProcessorStatusDrainPause(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node_processor_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pt := models.MustNewPoint("cpu", models.Tags{}, models.Fields{"value": 1.0}, time.Unix(0, 0))
+	written := make(chan struct{}, 10)
+	var writeErr error
+	sh := &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			if writeErr != nil {
+				return writeErr
+			}
+			written <- struct{}{}
+			return nil
+		},
+	}
+	metastore := &fakeMetaStore{
+		NodeFn: func(nodeID uint64) (*meta.NodeInfo, error) {
+			return &meta.NodeInfo{}, nil
+		},
+	}
+
+	// Only a drain replays the queue during the test.
+	n := NewNodeProcessor(200, dir, sh, metastore)
+	n.MaxSize = 1024 * 1024
+	n.PurgeInterval = time.Hour
+	n.RetryInterval = time.Hour
+	n.RetryMaxInterval = time.Hour
+	n.MaxAge = time.Hour
+	if err := n.Open(); err != nil {
+		t.Fatalf("Failed to open node processor: %v", err)
+	}
+	defer n.Close()
+
+	if st := n.Status(); st.NodeID != 200 || st.Bytes != 0 || st.Segments != 0 || !st.HeadModified.IsZero() {
+		t.Fatalf("unexpected status of empty queue: %+v", st)
+	}
+
+	if err := n.WriteShard(100, []models.Point{pt}); err != nil {
+		t.Fatalf("WriteShard() failed: %v", err)
+	}
+	st := n.Status()
+	if exp := int64(len(marshalWrite(100, []models.Point{pt})) + 8); st.Bytes != exp {
+		t.Fatalf("unexpected pending bytes: got %v, exp %v", st.Bytes, exp)
+	} else if st.Segments != 1 || st.HeadModified.IsZero() {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	// A paused queue is not drained.
+	n.Pause()
+	if err := n.Drain(); err != ErrReplayPaused {
+		t.Fatalf("unexpected error draining paused queue: %v", err)
+	} else if st := n.Status(); !st.Paused {
+		t.Fatalf("queue not paused: %+v", st)
+	}
+
+	// A failed drain is reported as the last error.
+	n.Resume()
+	writeErr = fmt.Errorf("node unreachable")
+	if err := n.Drain(); err != nil {
+		t.Fatalf("Drain() failed: %v", err)
+	}
+	for i := 0; n.Status().LastErr == ""; i++ {
+		if i > 100 {
+			t.Fatal("timed out waiting for replay error")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if st := n.Status(); st.LastErr != "node unreachable" || st.LastErrAt.IsZero() || st.Bytes == 0 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	// The next drain replays the queue.
+	writeErr = nil
+	if err := n.Drain(); err != nil {
+		t.Fatalf("Drain() failed: %v", err)
+	}
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for replay")
+	}
+	for i := 0; n.Status().Bytes != 0; i++ {
+		if i > 100 {
+			t.Fatalf("queue not drained: %+v", n.Status())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
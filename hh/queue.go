@@ -257,6 +257,38 @@ func (l *queue) Empty() bool {
 	return empty
 }
 
+// PendingBytes returns the size of the entries not yet advanced past.
+func (l *queue) PendingBytes() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.head == nil {
+		return 0
+	}
+
+	var n int64
+	for _, s := range l.segments {
+		n += s.totalBytes()
+	}
+	l.head.mu.RLock()
+	n -= l.head.pos
+	l.head.mu.RUnlock()
+	return n
+}
+
+// HeadModified returns the last time the head segment was modified, which
+// bounds the age of the oldest entry of the queue. PurgeOlderThan ages
+// entries the same way.
+func (l *queue) HeadModified() (time.Time, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.head == nil {
+		return time.Time{}, ErrNotOpen
+	}
+	return l.head.lastModified()
+}
+
 func (l *queue) TotalBytes() int64 {
 	var totalB int64
 	for _, seg := range l.segments {
@@ -374,7 +406,7 @@ func (l *queue) totalSegments() int64 {
 	num := len(l.segments)
 
 	// check head is empty or not if num is less than or equal 1
-	if num <= 1 && l.head.empty() {
+	if l.head == nil || num <= 1 && l.head.empty() {
 		return 0
 	}
 	return int64(num)
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -20,6 +21,9 @@ import (
 // disabled hinted handoff service.
 var ErrHintedHandoffDisabled = fmt.Errorf("hinted handoff disabled")
 
+// ErrReplayPaused is returned when draining a queue whose replay is paused.
+var ErrReplayPaused = fmt.Errorf("hinted handoff replay paused")
+
 // Statistics for the Subscriber service.
 const (
 	statNodeProcessorCreated = "nodeProcessorCreated"
@@ -115,7 +119,7 @@ func (s *Service) Open() error {
 			continue
 		}
 
-		n := NewNodeProcessor(nodeID, s.pathforNode(nodeID), s.shardWriter, s.MetaClient)
+		n := s.newProcessor(nodeID)
 		//Open newly created NodeProcessor
 		if err := n.Open(); err != nil {
 			return err
@@ -124,7 +128,7 @@ func (s *Service) Open() error {
 	}
 
 	s.wg.Add(1)
-	go s.purgeInactiveProcessors()
+	go s.purgeInactiveProcessors(s.closing)
 
 	return nil
 }
@@ -132,6 +136,17 @@ func (s *Service) Open() error {
 // Close closes the hinted handoff service.
 func (s *Service) Close() error {
 	s.Logger.Info("shutting down hh service")
+
+	// Stop purging before taking the lock the purge holds.
+	s.mu.Lock()
+	closing := s.closing
+	s.closing = nil
+	s.mu.Unlock()
+	if closing != nil {
+		close(closing)
+	}
+	s.wg.Wait()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -145,12 +160,6 @@ func (s *Service) Close() error {
 		s.Monitor.DeregisterDiagnosticsClient("hh")
 	}
 
-	if s.closing != nil {
-		close(s.closing)
-	}
-	s.wg.Wait()
-	s.closing = nil
-
 	return nil
 }
 
@@ -211,7 +220,7 @@ func (s *Service) WriteShard(shardID, ownerID uint64, points []models.Point) err
 
 			processor, ok = s.processors[ownerID]
 			if !ok {
-				processor = NewNodeProcessor(ownerID, s.pathforNode(ownerID), s.shardWriter, s.MetaClient)
+				processor = s.newProcessor(ownerID)
 				if err := processor.Open(); err != nil {
 					return err
 				}
@@ -232,6 +241,91 @@ func (s *Service) WriteShard(shardID, ownerID uint64, points []models.Point) err
 	return nil
 }
 
+// Status returns the status of the queue of every node, ordered by node id.
+func (s *Service) Status() []NodeStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]NodeStatus, 0, len(s.processors))
+	for _, p := range s.processors {
+		statuses = append(statuses, p.Status())
+	}
+	sort.Sort(nodeStatuses(statuses))
+	return statuses
+}
+
+// Drain replays the queue of a node immediately.
+func (s *Service) Drain(nodeID uint64) error {
+	p, err := s.processor(nodeID)
+	if err != nil {
+		return err
+	}
+	return p.Drain()
+}
+
+// Purge deletes the queue of a node, dropping the writes not yet replayed.
+// The queue is recreated by the next write for the node.
+func (s *Service) Purge(nodeID uint64) error {
+	if !s.cfg.Enabled {
+		return ErrHintedHandoffDisabled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.processors[nodeID]
+	if !ok {
+		return fmt.Errorf("no hinted handoff queue for node %d", nodeID)
+	}
+	if err := s.remove(p); err != nil {
+		return err
+	}
+	delete(s.processors, nodeID)
+	return nil
+}
+
+// Pause stops replaying the queue of a node.
+func (s *Service) Pause(nodeID uint64) error {
+	p, err := s.processor(nodeID)
+	if err != nil {
+		return err
+	}
+	p.Pause()
+	return nil
+}
+
+// Resume resumes replaying the queue of a node.
+func (s *Service) Resume(nodeID uint64) error {
+	p, err := s.processor(nodeID)
+	if err != nil {
+		return err
+	}
+	p.Resume()
+	return nil
+}
+
+// processor returns the node processor of a node.
+func (s *Service) processor(nodeID uint64) (*NodeProcessor, error) {
+	if !s.cfg.Enabled {
+		return nil, ErrHintedHandoffDisabled
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.processors[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("no hinted handoff queue for node %d", nodeID)
+	}
+	return p, nil
+}
+
+// nodeStatuses sorts node statuses by node id.
+type nodeStatuses []NodeStatus
+
+func (a nodeStatuses) Len() int           { return len(a) }
+func (a nodeStatuses) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a nodeStatuses) Less(i, j int) bool { return a[i].NodeID < a[j].NodeID }
+
 // Diagnostics returns diagnostic information.
 func (s *Service) Diagnostics() (*diagnostics.Diagnostics, error) {
 	s.mu.RLock()
@@ -259,14 +353,14 @@ func (s *Service) Diagnostics() (*diagnostics.Diagnostics, error) {
 }
 
 // purgeInactiveProcessors will cause the service to remove processors for inactive nodes.
-func (s *Service) purgeInactiveProcessors() {
+func (s *Service) purgeInactiveProcessors(closing <-chan struct{}) {
 	defer s.wg.Done()
 	ticker := time.NewTicker(time.Duration(s.cfg.PurgeInterval))
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-s.closing:
+		case <-closing:
 			return
 		case <-ticker.C:
 			func() {
@@ -335,6 +429,20 @@ func (s *Service) remove(np *NodeProcessor) error {
 	return nil
 }
 
+// newProcessor returns a node processor of the queue for nodeID with the
+// limits and intervals of the service config.
+func (s *Service) newProcessor(nodeID uint64) *NodeProcessor {
+	n := NewNodeProcessor(nodeID, s.pathforNode(nodeID), s.shardWriter, s.MetaClient)
+	n.PurgeInterval = time.Duration(s.cfg.PurgeInterval)
+	n.RetryInterval = time.Duration(s.cfg.RetryInterval)
+	n.RetryMaxInterval = time.Duration(s.cfg.RetryMaxInterval)
+	n.MaxSize = s.cfg.MaxSize
+	n.MaxAge = time.Duration(s.cfg.MaxAge)
+	n.RetryRateLimit = s.cfg.RetryRateLimit
+	n.Logger = s.Logger
+	return n
+}
+
 // pathforNode returns the directory for HH data, for the given node.
 func (s *Service) pathforNode(nodeID uint64) string {
 	return filepath.Join(s.cfg.Dir, fmt.Sprintf("%d", nodeID))
@@ -0,0 +1,52 @@
+package hh
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+)
+
+// Ensure the writes queued by the service are kept until they are replayed.
+func TestService_WriteShard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hh_service_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewConfig()
+	c.Enabled = true
+	c.Dir = dir
+
+	sh := &fakeShardWriter{
+		ShardWriteFn: func(shardID, nodeID uint64, points []models.Point) error {
+			return errors.New("node unreachable")
+		},
+	}
+	metastore := &fakeMetaStore{
+		NodeFn: func(nodeID uint64) (*meta.NodeInfo, error) {
+			return &meta.NodeInfo{ID: nodeID}, nil
+		},
+	}
+
+	s := NewService(c, sh, metastore)
+	if err := s.Open(); err != nil {
+		t.Fatalf("failed to open service: %v", err)
+	}
+	defer s.Close()
+
+	pt := models.MustNewPoint("cpu", models.Tags{}, models.Fields{"value": 1.0}, time.Unix(0, 0))
+	if err := s.WriteShard(100, 200, []models.Point{pt}); err != nil {
+		t.Fatalf("failed to queue write: %v", err)
+	}
+
+	statuses := s.Status()
+	if len(statuses) != 1 || statuses[0].NodeID != 200 || statuses[0].Bytes == 0 {
+		t.Fatalf("unexpected status: %+v", statuses)
+	}
+}
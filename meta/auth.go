@@ -0,0 +1,57 @@
+package meta
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// authTokenTTL is how long a token signing a request between nodes is valid.
+const authTokenTTL = time.Minute
+
+// SignRequest authenticates a request to a meta node with a token signed by
+// the shared secret of the cluster.
+func SignRequest(r *http.Request, secret string) error {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(authTokenTTL).Unix(),
+	})
+	s, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", "Bearer "+s)
+	return nil
+}
+
+// authenticateRequest returns an error unless r carries a valid token signed
+// by secret.
+func authenticateRequest(r *http.Request, secret string) error {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return ErrAuthenticate
+	}
+
+	token, err := jwt.Parse(strings.TrimPrefix(auth, "Bearer "), func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %s", ErrAuthenticate, err)
+	} else if !token.Valid {
+		return ErrAuthenticate
+	}
+
+	// Tokens must expire, Parse only checks the expiry if there is one.
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ErrAuthenticate
+	} else if _, ok := claims["exp"]; !ok {
+		return fmt.Errorf("%s: token expiry required", ErrAuthenticate)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package meta
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+)
+
+// QueryAuthorizer determines whether a user is authorized to execute a query.
+// Besides the privileges of the user, the permissions granted to the user and
+// its roles are checked.
+type QueryAuthorizer struct {
+	Client interface {
+		UserCount() int
+		Permissions(username string) Permissions
+	}
+}
+
+// NewQueryAuthorizer returns a new instance of QueryAuthorizer.
+func NewQueryAuthorizer(c *Client) *QueryAuthorizer {
+	return &QueryAuthorizer{Client: c}
+}
+
+// AuthorizeQuery authorizes u to execute q on database. Database can be "" for
+// queries that do not require a database. Without users only the creation of
+// an admin user is allowed.
+func (a *QueryAuthorizer) AuthorizeQuery(u *meta.UserInfo, query *influxql.Query, database string) error {
+	if a.Client.UserCount() == 0 {
+		if len(query.Statements) > 0 {
+			if cu, ok := query.Statements[0].(*influxql.CreateUserStatement); ok && cu.Admin {
+				return nil
+			}
+		}
+		return &meta.ErrAuthorize{
+			Query:    query,
+			Database: database,
+			Message:  "create admin user first or disable authentication",
+		}
+	}
+
+	if u == nil {
+		return &meta.ErrAuthorize{
+			Query:    query,
+			Database: database,
+			Message:  "no user provided",
+		}
+	} else if u.Admin {
+		return nil
+	}
+
+	perms := a.Client.Permissions(u.Name)
+	for _, stmt := range query.Statements {
+		privs, err := stmt.RequiredPrivileges()
+		if err != nil {
+			return err
+		}
+
+		for _, p := range privs {
+			// Use the db name specified by the statement or the db name
+			// passed by the caller if one wasn't specified by the statement.
+			db := p.Name
+			if db == "" {
+				db = database
+			}
+
+			if p.Admin {
+				required, db := statementPermission(stmt, db)
+				if !perms.Has(required, db) {
+					return &meta.ErrAuthorize{
+						Query:    query,
+						User:     u.Name,
+						Database: database,
+						Message:  fmt.Sprintf("statement '%s', requires %s permission", stmt, required),
+					}
+				}
+				continue
+			}
+
+			if !u.Authorize(p.Privilege, db) && !hasPrivilegePermissions(perms, p.Privilege, db) {
+				return &meta.ErrAuthorize{
+					Query:    query,
+					User:     u.Name,
+					Database: database,
+					Message:  fmt.Sprintf("statement '%s', requires %s on %s", stmt, p.Privilege.String(), db),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteAuthorizer determines whether a user is authorized to write to a
+// database, either by its privileges or by the permissions granted to the
+// user and its roles.
+type WriteAuthorizer struct {
+	Client interface {
+		User(name string) (*meta.UserInfo, error)
+		Permissions(username string) Permissions
+	}
+}
+
+// NewWriteAuthorizer returns a new instance of WriteAuthorizer.
+func NewWriteAuthorizer(c *Client) *WriteAuthorizer {
+	return &WriteAuthorizer{Client: c}
+}
+
+// AuthorizeWrite returns nil if the user may write to the database.
+func (a *WriteAuthorizer) AuthorizeWrite(username, database string) error {
+	u, err := a.Client.User(username)
+	if err == nil && u != nil {
+		if u.Authorize(influxql.WritePrivilege, database) || a.Client.Permissions(username).Has(WriteDataPermission, database) {
+			return nil
+		}
+	}
+	return &meta.ErrAuthorize{
+		Database: database,
+		Message:  fmt.Sprintf("%s not authorized to write to %s", username, database),
+	}
+}
+
+// hasPrivilegePermissions returns true if perms grant the data permissions
+// matching privilege on database.
+func hasPrivilegePermissions(perms Permissions, privilege influxql.Privilege, database string) bool {
+	switch privilege {
+	case influxql.ReadPrivilege:
+		return perms.Has(ReadDataPermission, database)
+	case influxql.WritePrivilege:
+		return perms.Has(WriteDataPermission, database)
+	case influxql.AllPrivileges:
+		return perms.Has(ReadDataPermission, database) && perms.Has(WriteDataPermission, database)
+	}
+	return false
+}
+
+// statementPermission returns the permission required to execute a statement
+// requiring admin privilege, and the database it must be granted on. Statements
+// not scoped to a database require the permission on every database.
+func statementPermission(stmt influxql.Statement, database string) (Permission, string) {
+	switch stmt := stmt.(type) {
+	case *influxql.KillQueryStatement, *influxql.ShowQueriesStatement:
+		return ManageQueryPermission, ""
+	case *influxql.CreateUserStatement, *influxql.DropUserStatement, *influxql.SetPasswordUserStatement,
+		*influxql.GrantStatement, *influxql.GrantAdminStatement, *influxql.RevokeStatement,
+		*influxql.RevokeAdminStatement, *influxql.ShowUsersStatement, *influxql.ShowGrantsForUserStatement:
+		return ManageUserPermission, ""
+	case *influxql.DropShardStatement, *influxql.ShowShardsStatement, *influxql.ShowShardGroupsStatement:
+		return ManageShardPermission, ""
+	case *influxql.ShowStatsStatement, *influxql.ShowDiagnosticsStatement:
+		return MonitorPermission, ""
+	case *influxql.CreateDatabaseStatement:
+		return ManageDatabasePermission, stmt.Name
+	case *influxql.DropDatabaseStatement:
+		return ManageDatabasePermission, stmt.Name
+	case *influxql.CreateRetentionPolicyStatement:
+		return ManageDatabasePermission, stmt.Database
+	case *influxql.AlterRetentionPolicyStatement:
+		return ManageDatabasePermission, stmt.Database
+	case *influxql.ShowDatabasesStatement:
+		return ManageDatabasePermission, ""
+	}
+	return ManageDatabasePermission, database
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/zhexuany/influxcloud/meta/internal"
 
 	"github.com/gogo/protobuf/proto"
+	"golang.org/x/crypto/bcrypt"
 	"path/filepath"
 )
 
@@ -88,34 +89,23 @@ func (c *Client) Open() error {
 		return ErrServiceUnavailable
 	}
 
-	// first load the meta data from disk
-	// path := c.Path()
-	// if path != "" {
-	// 	c.Logger().Printf("using client state dir:%s", path)
-	// 	if err := c.loadMetaServers(path); err != nil {
-	// 		c.Logger().Fatalf("failed to load meta sever data from %s", path)
-	// 	}
-	// }
-
-	// if metas := c.MetaServers(); len(metas) == 0 {
-	// 	return fmt.Errorf("MetaServers is empty. It shold at least contain itslef")
-	// }
-
-	// c.changed = make(chan struct{})
-	// c.closing = make(chan struct{})
-
-	// c.cacheData = c.retryUntilSnapshot(0)
-	// if c.cacheData == nil {
-	// 	return fmt.Errorf("failed to snapshot %v", c.cacheData)
-	// }
-
-	// if err := c.updateMetaServers(); err != nil {
-	// 	c.Logger().Println("failed to updated meta servers")
-	// }
+	// Load the meta servers saved by a previous run.
+	if path := c.Path(); path != "" && len(c.MetaServers()) == 0 {
+		if err := c.loadMetaServers(path); err != nil {
+			return fmt.Errorf("load meta servers from %s: %s", path, err)
+		}
+	}
 
 	c.changed = make(chan struct{})
 	c.closing = make(chan struct{})
-	c.cacheData = c.retryUntilSnapshot(0)
+
+	// A data node which hasn't joined a cluster yet has no meta servers.
+	// It starts with empty meta data and polls once the servers are set.
+	if len(c.MetaServers()) == 0 {
+		c.cacheData = &Data{Data: &meta.Data{}}
+	} else {
+		c.cacheData = c.retryUntilSnapshot(&Data{})
+	}
 
 	go c.pollForUpdates()
 
@@ -124,10 +114,6 @@ func (c *Client) Open() error {
 
 // Close the meta service cluster connection.
 func (c *Client) Close() error {
-	if !c.closed() {
-		return ErrService
-	}
-
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -154,17 +140,55 @@ func (c *Client) closed() bool {
 	}
 }
 
-//TODO zhexuany revisit this later after the realse of our prototype
-func (c *Client) doHTTP(path string, method string, reader io.Reader) (resp *http.Response, err error) {
-	return nil, nil
+// doHTTP sends a request to url, signed with the shared secret of the
+// cluster when auth is enabled. Redirects to the leader are signed again as
+// the authorization isn't forwarded to other hosts.
+func (c *Client) doHTTP(url string, method string, contentType string, body io.Reader) (*http.Response, error) {
+	return c.doHTTPTimeout(url, method, contentType, body, 0)
+}
+
+// doHTTPTimeout is like doHTTP but gives up once timeout elapses, unless it
+// is zero.
+func (c *Client) doHTTPTimeout(url string, method string, contentType string, body io.Reader, timeout time.Duration) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	// Abort the request, including snapshot long-polls, once the client is closed.
+	req.Cancel = c.closing
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if err := c.sign(req); err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return c.sign(req)
+		},
+	}
+	return client.Do(req)
+}
+
+// sign signs a request to a meta node if auth is enabled.
+func (c *Client) sign(req *http.Request) error {
+	if !c.config.AuthEnabled {
+		return nil
+	}
+	return SignRequest(req, c.config.InternalSharedSecret)
 }
 
-func (c *Client) get(path string) (*http.Response, error) {
-	return c.doHTTP(path, "GET", nil)
+func (c *Client) get(url string) (*http.Response, error) {
+	return c.doHTTP(url, "GET", "", nil)
 }
 
-func (c *Client) post(path string, cnt string, bufer *bytes.Buffer) (*http.Response, error) {
-	return c.doHTTP(path, "POST", nil)
+func (c *Client) post(url string, contentType string, body *bytes.Buffer) (*http.Response, error) {
+	return c.doHTTP(url, "POST", contentType, body)
 }
 
 // Leave send leave command into cluster
@@ -175,11 +199,24 @@ func (c *Client) Leave() error {
 // NodeID returns the client's node ID.
 func (c *Client) NodeID() uint64 { return c.nodeID }
 
-// SetMetaServers updates the meta servers on the client.
+// SetNodeID sets the id of the data node using the client. It is sent when
+// acquiring leases so only one node holds a lease at a time. It must be set
+// before the client is opened.
+func (c *Client) SetNodeID(id uint64) { c.nodeID = id }
+
+// SetMetaServers updates the meta servers on the client. They are saved in
+// the path of the client, if set, and loaded again when it is opened.
 func (c *Client) SetMetaServers(a []string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.metaServers = a
+	c.mu.Unlock()
+
+	if c.Path() == "" {
+		return
+	}
+	if err := c.saveMetaServers(); err != nil {
+		c.Logger().Printf("failed to save meta servers: %s", err)
+	}
 }
 
 // CheckMetaServers checks meta nodes status.
@@ -251,7 +288,7 @@ func (c *Client) Ping(checkAllMetaServers bool) error {
 		url = url + "?all=true"
 	}
 
-	resp, err := http.Get(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return err
 	}
@@ -294,7 +331,7 @@ func (c *Client) acquireLease(name string) (*meta.Lease, error) {
 	for _, server := range servers {
 		url := fmt.Sprintf("%s/lease?name=%s&nodeid=%d", c.url(server), name, c.nodeID)
 
-		resp, err := http.Get(url)
+		resp, err := c.get(url)
 		if err != nil {
 			return nil, err
 		}
@@ -361,9 +398,16 @@ func (c *Client) DataNodes() (NodeInfos, error) {
 
 // CreateDataNode will create a new data node in the metastore
 func (c *Client) CreateDataNode(httpAddr, tcpAddr string) (*NodeInfo, error) {
+	return c.CreateDataNodeWithLabels(httpAddr, tcpAddr, nil)
+}
+
+// CreateDataNodeWithLabels will create a new data node in the metastore with
+// labels describing its failure domain.
+func (c *Client) CreateDataNodeWithLabels(httpAddr, tcpAddr string, labels map[string]string) (*NodeInfo, error) {
 	cmd := &internal.CreateDataNodeCommand{
 		HTTPAddr: proto.String(httpAddr),
 		TCPAddr:  proto.String(tcpAddr),
+		Labels:   marshalLabels(labels),
 	}
 
 	if err := c.retryUntilExec(internal.Command_CreateDataNodeCommand, internal.E_CreateDataNodeCommand_Command, cmd); err != nil {
@@ -380,18 +424,27 @@ func (c *Client) CreateDataNode(httpAddr, tcpAddr string) (*NodeInfo, error) {
 	return n, nil
 }
 
-// ShardPendingOwners returns an array of all pending ShardOwners.
+// ShardPendingOwners returns the ids of the shards pending on this data node.
 func (c *Client) ShardPendingOwners() uint64arr {
-	mns := c.data().MetaNodes
-	for _, meta := range mns {
-		if meta.ID == c.nodeID {
-			return meta.PendingShardOwners
+	for _, n := range c.data().DataNodes {
+		if n.ID == c.nodeID {
+			return n.PendingShardOwners
 		}
 	}
 
 	return uint64arr{}
 }
 
+// AddPendingShardOwner adds nodeid as a pending owner of shard id.
+func (c *Client) AddPendingShardOwner(id, nodeid uint64) error {
+	cmd := &internal.AddPendingShardOwnerCommand{
+		ID:     proto.Uint64(id),
+		NodeID: proto.Uint64(nodeid),
+	}
+
+	return c.retryUntilExec(internal.Command_AddPendingShardOwnerCommand, internal.E_AddPendingShardOwnerCommand_Command, cmd)
+}
+
 // RemovePendingShardOwner removes a pending shardOwner according to shardID and nodeID.
 func (c *Client) RemovePendingShardOwner(id, nodeid uint64) error {
 	cmd := &internal.RemovePendingShardOwnerCommand{
@@ -429,15 +482,17 @@ func (c *Client) RemoveShardOwner(id, nodeid uint64) error {
 		NodeID: proto.Uint64(nodeid),
 	}
 
-	return c.retryUntilExec(internal.Command_RemoveShardOwnerCommand, internal.E_AddShardOwnerCommand_Command, cmd)
+	return c.retryUntilExec(internal.Command_RemoveShardOwnerCommand, internal.E_RemoveShardOwnerCommand_Command, cmd)
 }
 
-// UpdateDataNode updates data node info according nodeID.
-func (c *Client) UpdateDataNode(id uint64, host, tcpHost string) error {
+// UpdateDataNode updates data node info according nodeID. The labels of the
+// node are replaced by labels.
+func (c *Client) UpdateDataNode(id uint64, host, tcpHost string, labels map[string]string) error {
 	cmd := &internal.UpdateDataNodeCommand{
 		ID:      proto.Uint64(id),
 		Host:    proto.String(host),
 		TCPHost: proto.String(tcpHost),
+		Labels:  marshalLabels(labels),
 	}
 
 	if err := c.retryUntilExec(internal.Command_UpdateDataNodeCommand, internal.E_UpdateDataNodeCommand_Command, cmd); err != nil {
@@ -789,6 +844,176 @@ func (c *Client) CreateShardGroup(database, policy string, timestamp time.Time)
 	return rpi.ShardGroupByTimestamp(timestamp), nil
 }
 
+// CreateBalancedShardGroup creates a shard group on a database and policy for
+// a given timestamp, placing its owners on the least loaded data nodes.
+func (c *Client) CreateBalancedShardGroup(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+	if sg, _ := c.data().Data.ShardGroupByTimestamp(database, policy, timestamp); sg != nil {
+		return sg, nil
+	}
+
+	cmd := &internal.CreateBalancedShardGroupCommand{
+		Database:  proto.String(database),
+		Policy:    proto.String(policy),
+		Timestamp: proto.Int64(timestamp.UnixNano()),
+	}
+
+	if err := c.retryUntilExec(internal.Command_CreateBalancedShardGroupCommand, internal.E_CreateBalancedShardGroupCommand_Command, cmd); err != nil {
+		return nil, err
+	}
+
+	rpi, err := c.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, errors.New("retention policy deleted after shard group created")
+	}
+
+	return rpi.ShardGroupByTimestamp(timestamp), nil
+}
+
+// SplitShardGroup ends the shard group id at timestamp and returns the shard
+// group with shardN shards created for the rest of its time range. A shardN of
+// zero sizes the new group for the current data nodes.
+func (c *Client) SplitShardGroup(database, policy string, id uint64, timestamp time.Time, shardN int) (*meta.ShardGroupInfo, error) {
+	cmd := &internal.SplitShardGroupCommand{
+		Database:     proto.String(database),
+		Policy:       proto.String(policy),
+		ShardGroupID: proto.Uint64(id),
+		Timestamp:    proto.Int64(timestamp.UnixNano()),
+		ShardN:       proto.Uint64(uint64(shardN)),
+	}
+
+	if err := c.retryUntilExec(internal.Command_SplitShardGroupCommand, internal.E_SplitShardGroupCommand_Command, cmd); err != nil {
+		return nil, err
+	}
+
+	rpi, err := c.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, errors.New("retention policy deleted after shard group split")
+	}
+
+	return rpi.ShardGroupByTimestamp(timestamp), nil
+}
+
+// RestoreShardGroup creates a shard group covering start to end with shardN
+// shards placed on the current data nodes and returns it. A shardN of zero
+// sizes the group for the current data nodes.
+func (c *Client) RestoreShardGroup(database, policy string, start, end time.Time, shardN int) (*meta.ShardGroupInfo, error) {
+	cmd := &internal.RestoreShardGroupCommand{
+		Database:  proto.String(database),
+		Policy:    proto.String(policy),
+		StartTime: proto.Int64(start.UnixNano()),
+		EndTime:   proto.Int64(end.UnixNano()),
+		ShardN:    proto.Uint64(uint64(shardN)),
+	}
+
+	if err := c.retryUntilExec(internal.Command_RestoreShardGroupCommand, internal.E_RestoreShardGroupCommand_Command, cmd); err != nil {
+		return nil, err
+	}
+
+	rpi, err := c.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, errors.New("retention policy deleted after shard group restored")
+	}
+
+	return rpi.ShardGroupByTimestamp(start), nil
+}
+
+// ImportData imports the retention policy database.policy of data as
+// newDatabase.newPolicy with replicaN copies of each shard. The shard groups
+// of the retention policy are recreated with new IDs on the current data
+// nodes, and the IDs of the new shards are returned keyed by the IDs of the
+// imported shards. Importing into an existing retention policy requires force,
+// which skips the shard groups overlapping existing shard groups and leaves
+// their shards out of the returned IDs.
+func (c *Client) ImportData(data *Data, database, policy, newDatabase, newPolicy string, replicaN int, force bool) (map[uint64]uint64, error) {
+	dbi := data.Data.Database(database)
+	if dbi == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+	src := dbi.RetentionPolicy(policy)
+	if src == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	// Only send the retention policy imported.
+	other := &Data{Data: &meta.Data{
+		Databases: []meta.DatabaseInfo{{
+			Name:                   database,
+			DefaultRetentionPolicy: dbi.DefaultRetentionPolicy,
+			RetentionPolicies:      []meta.RetentionPolicyInfo{*src},
+		}},
+	}}
+	if strategy := data.ShardPlacement(database, policy); strategy != ShardPlacementRoundRobin {
+		other.ShardPlacements = []ShardPlacement{{Database: database, Policy: policy, Strategy: strategy}}
+	}
+	buf, err := other.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	if newDatabase == "" {
+		newDatabase = database
+	}
+	if newPolicy == "" {
+		newPolicy = policy
+	}
+	cmd := &internal.ImportDataCommand{
+		Data:                 buf,
+		Force:                proto.Bool(force),
+		Database:             proto.String(database),
+		RetentionPolicy:      proto.String(policy),
+		NewDatabase:          proto.String(newDatabase),
+		NewRetentionPolicy:   proto.String(newPolicy),
+		NewReplicationFactor: proto.Uint64(uint64(replicaN)),
+	}
+
+	resp, err := c.retryUntilExecResponse(internal.Command_ImportDataCommand, internal.E_ImportDataCommand_Command, cmd)
+	if err != nil {
+		return nil, err
+	} else if resp == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	shardIDs := make(map[uint64]uint64)
+	for _, m := range resp.GetShardIDs() {
+		shardIDs[m.GetOldID()] = m.GetNewID()
+	}
+	return shardIDs, nil
+}
+
+// ShardPlacement returns the shard placement strategy of a retention policy.
+func (c *Client) ShardPlacement(database, policy string) string {
+	return c.data().ShardPlacement(database, policy)
+}
+
+// SetShardPlacement sets the shard placement strategy used by new shard groups
+// of a retention policy.
+func (c *Client) SetShardPlacement(database, policy, strategy string) error {
+	cmd := &internal.SetShardPlacementCommand{
+		Database: proto.String(database),
+		Policy:   proto.String(policy),
+		Strategy: proto.String(strategy),
+	}
+
+	return c.retryUntilExec(internal.Command_SetShardPlacementCommand, internal.E_SetShardPlacementCommand_Command, cmd)
+}
+
+// SetDataNodeDiskUsage records the disk usage reported by a data node. It is
+// used to place shards when the balanced shard placement is selected.
+func (c *Client) SetDataNodeDiskUsage(id, size uint64) error {
+	cmd := &internal.SetDataNodeDiskUsageCommand{
+		ID:        proto.Uint64(id),
+		DiskUsage: proto.Uint64(size),
+	}
+
+	return c.retryUntilExec(internal.Command_SetDataNodeDiskUsageCommand, internal.E_SetDataNodeDiskUsageCommand_Command, cmd)
+}
+
 // DeleteShardGroup removes a shard group from a database and retention policy by id.
 func (c *Client) DeleteShardGroup(database, policy string, id uint64) error {
 	cmd := &internal.DeleteShardGroupCommand{
@@ -800,6 +1025,16 @@ func (c *Client) DeleteShardGroup(database, policy string, id uint64) error {
 	return c.retryUntilExec(internal.Command_DeleteShardGroupCommand, internal.E_DeleteShardGroupCommand_Command, cmd)
 }
 
+// TruncateShardGroups ends all shard groups accepting writes at t so that
+// later writes create new shard groups on the current data nodes.
+func (c *Client) TruncateShardGroups(t time.Time) error {
+	cmd := &internal.TruncateShardGroupCommand{
+		TruncateAt: proto.Uint64(uint64(t.UnixNano())),
+	}
+
+	return c.retryUntilExec(internal.Command_TruncateShardGroupsCommand, internal.E_TruncateShardGroupCommand_Command, cmd)
+}
+
 // PrecreateShardGroups creates shard groups whose endtime is before the 'to' time passed in, but
 // is yet to expire before 'from'. This is to avoid the need for these shards to be created when data
 // for the corresponding time range arrives. Shard creation involves Raft consensus, and precreation
@@ -891,7 +1126,7 @@ func (c *Client) JoinMetaServer(httpAddr, tcpAddr string) (*NodeInfo, error) {
 			url = c.url(server) + "/join"
 		}
 
-		resp, err := http.Post(url, "application/json", bytes.NewBuffer(b))
+		resp, err := c.post(url, "application/json", bytes.NewBuffer(b))
 		if err != nil {
 			currentServer++
 			continue
@@ -997,6 +1232,234 @@ func (c *Client) DropSubscription(database, rp, name string) error {
 	)
 }
 
+// bcryptCost is the cost used to hash user passwords.
+var bcryptCost = bcrypt.DefaultCost
+
+// Users returns the users of the cluster.
+func (c *Client) Users() []meta.UserInfo {
+	users := c.data().Data.Users
+	if users == nil {
+		return []meta.UserInfo{}
+	}
+	return users
+}
+
+// User returns a user by name, or ErrUserNotFound.
+func (c *Client) User(name string) (*meta.UserInfo, error) {
+	if u := c.data().Data.User(name); u != nil {
+		return u, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+// UserCount returns the number of users.
+func (c *Client) UserCount() int {
+	return len(c.data().Data.Users)
+}
+
+// AdminUserExists returns true if any user has admin privilege.
+func (c *Client) AdminUserExists() bool {
+	return c.data().Data.AdminUserExists()
+}
+
+// Authenticate returns the user if the password matches.
+func (c *Client) Authenticate(username, password string) (*meta.UserInfo, error) {
+	u, err := c.User(username)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Hash), []byte(password)); err != nil {
+		return nil, ErrAuthenticate
+	}
+	return u, nil
+}
+
+// CreateUser creates a user or returns it if it already exists with the same
+// password and admin privilege.
+func (c *Client) CreateUser(name, password string, admin bool) (*meta.UserInfo, error) {
+	if u := c.data().Data.User(name); u != nil {
+		if err := bcrypt.CompareHashAndPassword([]byte(u.Hash), []byte(password)); err != nil || u.Admin != admin {
+			return nil, ErrUserExists
+		}
+		return u, nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.retryUntilExec(internal.Command_CreateUserCommand, internal.E_CreateUserCommand_Command,
+		&internal.CreateUserCommand{
+			Name:  proto.String(name),
+			Hash:  proto.String(string(hash)),
+			Admin: proto.Bool(admin),
+		},
+	); err != nil {
+		return nil, err
+	}
+	return c.User(name)
+}
+
+// UpdateUser changes the password of a user.
+func (c *Client) UpdateUser(name, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return err
+	}
+
+	return c.retryUntilExec(internal.Command_UpdateUserCommand, internal.E_UpdateUserCommand_Command,
+		&internal.UpdateUserCommand{
+			Name: proto.String(name),
+			Hash: proto.String(string(hash)),
+		},
+	)
+}
+
+// DropUser removes a user along with its permissions and role memberships.
+func (c *Client) DropUser(name string) error {
+	return c.retryUntilExec(internal.Command_DropUserCommand, internal.E_DropUserCommand_Command,
+		&internal.DropUserCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// SetPrivilege sets the privilege of a user on a database.
+func (c *Client) SetPrivilege(username, database string, p influxql.Privilege) error {
+	return c.retryUntilExec(internal.Command_SetPrivilegeCommand, internal.E_SetPrivilegeCommand_Command,
+		&internal.SetPrivilegeCommand{
+			Username:  proto.String(username),
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(p)),
+		},
+	)
+}
+
+// SetAdminPrivilege grants or revokes the admin privilege of a user.
+func (c *Client) SetAdminPrivilege(username string, admin bool) error {
+	return c.retryUntilExec(internal.Command_SetAdminPrivilegeCommand, internal.E_SetAdminPrivilegeCommand_Command,
+		&internal.SetAdminPrivilegeCommand{
+			Username: proto.String(username),
+			Admin:    proto.Bool(admin),
+		},
+	)
+}
+
+// Roles returns the roles of the cluster.
+func (c *Client) Roles() []RoleInfo {
+	roles := c.data().Roles
+	if roles == nil {
+		return []RoleInfo{}
+	}
+	return roles
+}
+
+// Role returns a role by name, or ErrRoleNotFound.
+func (c *Client) Role(name string) (*RoleInfo, error) {
+	if ri := c.data().Role(name); ri != nil {
+		return ri, nil
+	}
+	return nil, ErrRoleNotFound
+}
+
+// CreateRole creates a role without users or permissions.
+func (c *Client) CreateRole(name string) (*RoleInfo, error) {
+	if err := c.retryUntilExec(internal.Command_CreateRoleCommand, internal.E_CreateRoleCommand_Command,
+		&internal.CreateRoleCommand{
+			Name: proto.String(name),
+		},
+	); err != nil {
+		return nil, err
+	}
+	return c.Role(name)
+}
+
+// DropRole removes a role.
+func (c *Client) DropRole(name string) error {
+	return c.retryUntilExec(internal.Command_DropRoleCommand, internal.E_DropRoleCommand_Command,
+		&internal.DropRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// ChangeRoleName renames a role.
+func (c *Client) ChangeRoleName(oldName, newName string) error {
+	return c.retryUntilExec(internal.Command_ChangeRoleNameCommand, internal.E_ChangeRoleNameCommand_Command,
+		&internal.ChangeRoleNameCommand{
+			OldName: proto.String(oldName),
+			NewName: proto.String(newName),
+		},
+	)
+}
+
+// AddRoleUsers grants a role to users.
+func (c *Client) AddRoleUsers(name string, users []string) error {
+	return c.retryUntilExec(internal.Command_AddRoleUsersCommand, internal.E_AddRoleUsersCommand_Command,
+		&internal.AddRoleUsersCommand{
+			Name:  proto.String(name),
+			Users: users,
+		},
+	)
+}
+
+// RemoveRoleUsers revokes a role from users.
+func (c *Client) RemoveRoleUsers(name string, users []string) error {
+	return c.retryUntilExec(internal.Command_RemoveRoleUsersCommand, internal.E_RemoveRoleUsersCommand_Command,
+		&internal.RemoveRoleUsersCommand{
+			Name:  proto.String(name),
+			Users: users,
+		},
+	)
+}
+
+// AddRolePermissions grants permissions to a role.
+func (c *Client) AddRolePermissions(name string, perms Permissions) error {
+	return c.retryUntilExec(internal.Command_AddRolePermissionsCommand, internal.E_AddRolePermissionsCommand_Command,
+		&internal.AddRolePermissionsCommand{
+			Name:        proto.String(name),
+			Permissions: marshalPermissions(perms),
+		},
+	)
+}
+
+// RemoveRolePermissions revokes permissions from a role.
+func (c *Client) RemoveRolePermissions(name string, perms Permissions) error {
+	return c.retryUntilExec(internal.Command_RemoveRolePermissionsCommand, internal.E_RemoveRolePermissionsCommand_Command,
+		&internal.RemoveRolePermissionsCommand{
+			Name:        proto.String(name),
+			Permissions: marshalPermissions(perms),
+		},
+	)
+}
+
+// AddUserPermissions grants permissions directly to a user.
+func (c *Client) AddUserPermissions(name string, perms Permissions) error {
+	return c.retryUntilExec(internal.Command_AddUserPermissionsCommand, internal.E_AddUserPermissionsCommand_Command,
+		&internal.AddUserPermissionsCommand{
+			Name:        proto.String(name),
+			Permissions: marshalPermissions(perms),
+		},
+	)
+}
+
+// RemoveUserPermissions revokes permissions granted directly to a user.
+func (c *Client) RemoveUserPermissions(name string, perms Permissions) error {
+	return c.retryUntilExec(internal.Command_RemoveUserPermissionsCommand, internal.E_RemoveUserPermissionsCommand_Command,
+		&internal.RemoveUserPermissionsCommand{
+			Name:        proto.String(name),
+			Permissions: marshalPermissions(perms),
+		},
+	)
+}
+
+// Permissions returns the permissions of a user, both the ones granted
+// directly and through its roles.
+func (c *Client) Permissions(username string) Permissions {
+	return c.data().Permissions(username)
+}
+
 // Data returns a reference of data.
 func (c *Client) Data() *Data {
 	return c.data().Clone()
@@ -1048,8 +1511,15 @@ func (c *Client) index() uint64 {
 // retryUntilExec will attempt the command on each of the metaservers until it either succeeds or
 // hits the max number of tries
 func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) error {
+	_, err := c.retryUntilExecResponse(typ, desc, value)
+	return err
+}
+
+// retryUntilExecResponse is like retryUntilExec but returns the response of
+// the meta service. The response is nil if the client is closed.
+func (c *Client) retryUntilExecResponse(typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) (*internal.Response, error) {
 	var err error
-	var index uint64
+	var resp *internal.Response
 	tries := 0
 	currentServer := 0
 	var redirectServer string
@@ -1060,7 +1530,7 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 		select {
 		case <-c.closing:
 			c.mu.RUnlock()
-			return nil
+			return nil, nil
 		default:
 			// we're still open, continue on
 		}
@@ -1073,6 +1543,10 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 			redirectServer = ""
 		} else {
 			c.mu.RLock()
+			if len(c.metaServers) == 0 {
+				c.mu.RUnlock()
+				return nil, ErrServiceUnavailable
+			}
 			if currentServer >= len(c.metaServers) {
 				currentServer = 0
 			}
@@ -1087,17 +1561,17 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 			}
 		}
 
-		index, err = c.exec(url, typ, desc, value)
+		resp, err = c.exec(url, typ, desc, value)
 		tries++
 		currentServer++
 
 		if err == nil {
-			c.waitForIndex(index)
-			return nil
+			c.waitForIndex(resp.GetIndex())
+			return resp, nil
 		}
 
 		if tries > maxRetries {
-			return err
+			return nil, err
 		}
 
 		if e, ok := err.(errRedirect); ok {
@@ -1106,14 +1580,14 @@ func (c *Client) retryUntilExec(typ internal.Command_Type, desc *proto.Extension
 		}
 
 		if _, ok := err.(errCommand); ok {
-			return err
+			return nil, err
 		}
 
 		time.Sleep(errSleep)
 	}
 }
 
-func (c *Client) exec(url string, typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) (index uint64, err error) {
+func (c *Client) exec(url string, typ internal.Command_Type, desc *proto.ExtensionDesc, value interface{}) (*internal.Response, error) {
 	// Create command.
 	cmd := &internal.Command{Type: &typ}
 	if err := proto.SetExtension(cmd, desc, value); err != nil {
@@ -1122,37 +1596,37 @@ func (c *Client) exec(url string, typ internal.Command_Type, desc *proto.Extensi
 
 	b, err := proto.Marshal(cmd)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	resp, err := http.Post(url, "application/octet-stream", bytes.NewBuffer(b))
+	resp, err := c.post(url, "application/octet-stream", bytes.NewBuffer(b))
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// read the response
 	if resp.StatusCode == http.StatusTemporaryRedirect {
-		return 0, errRedirect{host: resp.Header.Get("Location")}
+		return nil, errRedirect{host: resp.Header.Get("Location")}
 	} else if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("meta service returned %s", resp.Status)
+		return nil, fmt.Errorf("meta service returned %s", resp.Status)
 	}
 
 	res := &internal.Response{}
 
 	b, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if err := proto.Unmarshal(b, res); err != nil {
-		return 0, err
+		return nil, err
 	}
 	es := res.GetError()
 	if es != "" {
-		return 0, errCommand{msg: es}
+		return nil, errCommand{msg: es}
 	}
 
-	return res.GetIndex(), nil
+	return res, nil
 }
 
 func (c *Client) waitForIndex(idx uint64) {
@@ -1170,7 +1644,7 @@ func (c *Client) waitForIndex(idx uint64) {
 
 func (c *Client) pollForUpdates() {
 	for {
-		data := c.retryUntilSnapshot(c.index())
+		data := c.retryUntilSnapshot(c.data())
 		if data == nil {
 			// this will only be nil if the client has been closed,
 			// so we can exit out
@@ -1189,12 +1663,60 @@ func (c *Client) pollForUpdates() {
 	}
 }
 
+// getSnapshotDelta returns data updated with the commands applied by server
+// since the index of data. The server sends a full snapshot instead when the
+// commands are no longer in its raft log.
+func (c *Client) getSnapshotDelta(server string, data *Data) (*Data, error) {
+	if server == "" {
+		return nil, errors.New("server host empty")
+	}
+	// A client that never loaded a snapshot has no data yet.
+	var index uint64
+	if data.Data != nil {
+		index = data.Data.Index
+	}
+	resp, err := c.get(c.url(server) + fmt.Sprintf("?index=%d&delta=true", index))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("meta server returned non-200: %s", resp.Status)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var pb internal.SnapshotDelta
+	if err := proto.Unmarshal(b, &pb); err != nil {
+		return nil, err
+	}
+
+	if pb.Snapshot != nil {
+		other := &Data{}
+		if err := other.UnmarshalBinary(pb.GetSnapshot()); err != nil {
+			return nil, err
+		}
+		return other, nil
+	}
+
+	other, err := applyLogs(data, pb.GetLogs(), pb.GetRetentionAutoCreate())
+	if err != nil {
+		// The commands are unknown to this client, fall back to a snapshot.
+		c.Logger().Printf("failure applying snapshot delta from %s: %s", server, err)
+		return c.getSnapshot(server, index)
+	}
+	return other, nil
+}
+
 func (c *Client) getSnapshot(server string, index uint64) (*Data, error) {
 	if server == "" {
 		return nil, errors.New("server host empty")
 	}
 	// resp, err := c.get(server + fmt.Sprintf("?index=%d", index))
-	resp, err := http.Get(c.url(server) + fmt.Sprintf("?index=%d", index))
+	resp, err := c.get(c.url(server) + fmt.Sprintf("?index=%d", index))
 
 	if err != nil {
 		return nil, err
@@ -1216,14 +1738,33 @@ func (c *Client) getSnapshot(server string, index uint64) (*Data, error) {
 	return data, nil
 }
 
-// peers returns the TCPHost addresses of all the metaservers
-func (c *Client) peers() []string {
+// ping returns the raft leader seen by the meta node at server, waiting at
+// most timeout for its answer.
+func (c *Client) ping(server string, timeout time.Duration) (string, error) {
+	resp, err := c.doHTTPTimeout(c.url(server)+"/ping", "GET", "", nil, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	} else if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ping %s: %s", server, resp.Status)
+	}
+	return string(b), nil
+}
+
+// peers returns the TCPHost addresses of all the metaservers, waiting at most
+// timeout for each of them unless it is zero.
+func (c *Client) peers(timeout time.Duration) []string {
 
 	var peers Peers
 	// query each server and keep track of who their peers are
 	for _, server := range c.metaServers {
 		url := c.url(server) + "/peers"
-		resp, err := http.Get(url)
+		resp, err := c.doHTTPTimeout(url, "GET", "", nil, timeout)
 		if err != nil {
 			continue
 		}
@@ -1257,19 +1798,24 @@ func (c *Client) url(server string) string {
 	return url
 }
 
-func (c *Client) retryUntilSnapshot(idx uint64) *Data {
+func (c *Client) retryUntilSnapshot(current *Data) *Data {
 	currentServer := 0
 	for {
 		if c.closed() {
 			return nil
 		}
 		metaServers := c.MetaServers()
+		if len(metaServers) == 0 {
+			// Wait for the meta servers of the cluster to be set.
+			time.Sleep(errSleep)
+			continue
+		}
 		if currentServer >= len(metaServers) {
 			currentServer = 0
 		}
 		server := metaServers[currentServer]
 
-		data, err := c.getSnapshot(server, idx)
+		data, err := c.getSnapshotDelta(server, current)
 
 		if err == nil {
 			return data
@@ -1310,7 +1856,8 @@ func (c *Client) saveMetaServers() error {
 		return err
 	}
 
-	if err := json.NewEncoder(f).Encode(c.metaServers); err != nil {
+	if err := json.NewEncoder(f).Encode(c.MetaServers()); err != nil {
+		_ = f.Close()
 		return err
 	}
 
@@ -1326,7 +1873,7 @@ func (c *Client) saveMetaServers() error {
 }
 
 func (c *Client) loadMetaServers(path string) error {
-	file := filepath.Join(c.path, metaFile)
+	file := filepath.Join(path, metaFile)
 
 	f, err := os.Open(file)
 	if err != nil {
@@ -1336,9 +1883,16 @@ func (c *Client) loadMetaServers(path string) error {
 		return err
 	}
 
-	if err := json.NewDecoder(f).Decode(c.metaServers); err != nil {
+	defer f.Close()
+
+	var metaServers []string
+	if err := json.NewDecoder(f).Decode(&metaServers); err != nil {
 		return err
 	}
+
+	c.mu.Lock()
+	c.metaServers = metaServers
+	c.mu.Unlock()
 	return nil
 }
 
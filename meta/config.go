@@ -78,6 +78,19 @@ type Config struct {
 	PprofEnabled         bool          `toml:"pprof-enabled"`
 
 	LeaseDuration toml.Duration `toml:"lease-duration"`
+
+	// AuthEnabled requires requests to the HTTP API to be signed with the
+	// InternalSharedSecret, which must be the same on all meta and data nodes.
+	AuthEnabled          bool   `toml:"auth-enabled"`
+	InternalSharedSecret string `toml:"internal-shared-secret"`
+
+	// NonVoting runs the meta node as a non-voting follower of the JoinPeers.
+	// It serves the meta data to clients but never takes part in raft.
+	NonVoting bool `toml:"non-voting"`
+
+	// RecoverPeers if specified forces the raft peers of a cluster that lost
+	// quorum. It is set on a surviving node for a single restart.
+	RecoverPeers []string `toml:"-"`
 }
 
 // NewConfig builds a new configuration with default values.
@@ -124,6 +137,9 @@ func (c *Config) Validate() error {
 	if c.Dir == "" {
 		return errors.New("Meta.Dir must be specified")
 	}
+	if c.AuthEnabled && c.InternalSharedSecret == "" {
+		return errors.New("Meta.InternalSharedSecret must be specified when auth is enabled")
+	}
 	return nil
 }
 
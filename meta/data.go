@@ -27,6 +27,16 @@ const (
 	MinRetentionPolicyDuration = time.Hour
 )
 
+const (
+	// ShardPlacementRoundRobin assigns shard owners to data nodes in turn,
+	// starting at an offset derived from the raft index. It is the default.
+	ShardPlacementRoundRobin = "round-robin"
+
+	// ShardPlacementBalanced assigns shard owners to the data nodes owning
+	// the fewest shards, breaking ties by the lowest reported disk usage.
+	ShardPlacementBalanced = "balanced"
+)
+
 // Data represents the top level collection of all metadata.
 type Data struct {
 	// This is coupled with influxdb's implementation, but the structure is pretty
@@ -36,6 +46,22 @@ type Data struct {
 	DataNodes NodeInfos
 	MaxNodeID uint64
 	ClusterID uint64
+
+	// ShardPlacements holds the retention policies not using the default
+	// round-robin shard placement.
+	ShardPlacements []ShardPlacement
+
+	// Roles grant their permissions to their users. UserPermissions holds
+	// the permissions granted directly to users, by user name.
+	Roles           []RoleInfo
+	UserPermissions map[string]Permissions
+}
+
+// ShardPlacement is the shard placement strategy of a retention policy.
+type ShardPlacement struct {
+	Database string
+	Policy   string
+	Strategy string
 }
 
 // Clone returns a copy of data with a new version.
@@ -61,6 +87,25 @@ func (data *Data) Clone() *Data {
 		}
 	}
 
+	if data.ShardPlacements != nil {
+		other.ShardPlacements = make([]ShardPlacement, len(data.ShardPlacements))
+		copy(other.ShardPlacements, data.ShardPlacements)
+	}
+
+	if data.Roles != nil {
+		other.Roles = make([]RoleInfo, len(data.Roles))
+		for i := range data.Roles {
+			other.Roles[i] = data.Roles[i].clone()
+		}
+	}
+
+	if data.UserPermissions != nil {
+		other.UserPermissions = make(map[string]Permissions, len(data.UserPermissions))
+		for name, ps := range data.UserPermissions {
+			other.UserPermissions[name] = ps.clone()
+		}
+	}
+
 	return &other
 }
 
@@ -77,10 +122,75 @@ type NodeInfo struct {
 	Host               string
 	TCPHost            string
 	PendingShardOwners uint64arr
+
+	// DiskUsage is the number of bytes used by shards on the node, as last
+	// reported by the node. It is zero if the node never reported it.
+	DiskUsage uint64
+
+	// Labels describe the failure domain of the node, e.g. its zone and rack.
+	// Owners of a shard are spread across nodes with distinct label values.
+	Labels map[string]string
 }
 
 // clone returns a deep copy of ni.
-func (ni NodeInfo) clone() NodeInfo { return ni }
+func (ni NodeInfo) clone() NodeInfo {
+	other := ni
+	if ni.PendingShardOwners != nil {
+		other.PendingShardOwners = make(uint64arr, len(ni.PendingShardOwners))
+		copy(other.PendingShardOwners, ni.PendingShardOwners)
+	}
+	other.Labels = cloneLabels(ni.Labels)
+	return other
+}
+
+// cloneLabels returns a copy of labels.
+func cloneLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	other := make(map[string]string, len(labels))
+	for k, v := range labels {
+		other[k] = v
+	}
+	return other
+}
+
+// marshalLabels serializes labels to a protobuf representation sorted by key.
+func marshalLabels(labels map[string]string) []*internal.NodeLabel {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pb := make([]*internal.NodeLabel, len(keys))
+	for i, k := range keys {
+		pb[i] = &internal.NodeLabel{Key: proto.String(k), Value: proto.String(labels[k])}
+	}
+	return pb
+}
+
+// unmarshalLabels deserializes labels from a protobuf representation.
+func unmarshalLabels(pb []*internal.NodeLabel) map[string]string {
+	if len(pb) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(pb))
+	for _, l := range pb {
+		labels[l.GetKey()] = l.GetValue()
+	}
+	return labels
+}
+
+// pending returns true if shardID is being added to the node.
+func (ni *NodeInfo) pending(shardID uint64) bool {
+	for _, id := range ni.PendingShardOwners {
+		if id == shardID {
+			return true
+		}
+	}
+	return false
+}
 
 // marshal serializes to a protobuf representation.
 func (ni NodeInfo) marshal() *internal.NodeInfo {
@@ -89,9 +199,9 @@ func (ni NodeInfo) marshal() *internal.NodeInfo {
 	pb.Host = proto.String(ni.Host)
 	pb.TCPHost = proto.String(ni.TCPHost)
 	pb.PendingShardOwners = make(uint64arr, len(ni.PendingShardOwners))
-	for _, pso := range ni.PendingShardOwners {
-		pb.PendingShardOwners = append(pb.PendingShardOwners, *proto.Uint64(pso))
-	}
+	copy(pb.PendingShardOwners, ni.PendingShardOwners)
+	pb.DiskUsage = proto.Uint64(ni.DiskUsage)
+	pb.Labels = marshalLabels(ni.Labels)
 	return pb
 }
 
@@ -101,6 +211,8 @@ func (ni *NodeInfo) unmarshal(pb *internal.NodeInfo) {
 	ni.Host = pb.GetHost()
 	ni.TCPHost = pb.GetTCPHost()
 	ni.PendingShardOwners = pb.GetPendingShardOwners()
+	ni.DiskUsage = pb.GetDiskUsage()
+	ni.Labels = unmarshalLabels(pb.GetLabels())
 }
 
 // MetaNode return meta node info according to nodeID
@@ -271,8 +383,8 @@ func (data *Data) DataNode(id uint64) *NodeInfo {
 	return nil
 }
 
-// CreateDataNode adds a node to the metadata.
-func (data *Data) CreateDataNode(host, tcpHost string) error {
+// CreateDataNode adds a node with the given labels to the metadata.
+func (data *Data) CreateDataNode(host, tcpHost string, labels map[string]string) error {
 	// Ensure a node with the same host doesn't already exist.
 	for _, n := range data.DataNodes {
 		if n.TCPHost == tcpHost {
@@ -301,21 +413,23 @@ func (data *Data) CreateDataNode(host, tcpHost string) error {
 		ID:      existingID,
 		Host:    host,
 		TCPHost: tcpHost,
+		Labels:  cloneLabels(labels),
 	})
 	sort.Sort(NodeInfos(data.DataNodes))
 
 	return nil
 }
 
-// UpdateDataNode updates a data node info according to is nodeID.
-func (data *Data) UpdateDataNode(nodeID uint64, host, tcpHost string) error {
-	for _, n := range data.DataNodes {
-		if n.ID == nodeID {
-			n.Host = host
-			n.TCPHost = tcpHost
-			break
-		}
+// UpdateDataNode updates the addresses and replaces the labels of a data node.
+func (data *Data) UpdateDataNode(nodeID uint64, host, tcpHost string, labels map[string]string) error {
+	n := data.DataNode(nodeID)
+	if n == nil {
+		return ErrNodeNotFound
 	}
+
+	n.Host = host
+	n.TCPHost = tcpHost
+	n.Labels = cloneLabels(labels)
 	return nil
 }
 
@@ -433,6 +547,33 @@ func (data *Data) marshal() *internal.ClusterData {
 		pb.DataNodes[i] = data.DataNodes[i].marshal()
 	}
 
+	pb.ShardPlacements = make([]*internal.ShardPlacement, len(data.ShardPlacements))
+	for i, sp := range data.ShardPlacements {
+		pb.ShardPlacements[i] = &internal.ShardPlacement{
+			Database: proto.String(sp.Database),
+			Policy:   proto.String(sp.Policy),
+			Strategy: proto.String(sp.Strategy),
+		}
+	}
+
+	pb.Roles = make([]*internal.RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		pb.Roles[i] = data.Roles[i].marshal()
+	}
+
+	names := make([]string, 0, len(data.UserPermissions))
+	for name := range data.UserPermissions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pb.Users = make([]*internal.UserInfo, len(names))
+	for i, name := range names {
+		pb.Users[i] = &internal.UserInfo{
+			Name:        proto.String(name),
+			Permissions: marshalPermissions(data.UserPermissions[name]),
+		}
+	}
+
 	return pb
 }
 
@@ -450,6 +591,7 @@ func (data *Data) UnmarshalBinary(buf []byte) error {
 func (data *Data) unmarshal(pb *internal.ClusterData) {
 	data.Data = &meta.Data{}
 	data.Data.UnmarshalBinary(pb.GetData())
+	data.MaxNodeID = pb.GetMaxNodeID()
 
 	data.MetaNodes = make([]NodeInfo, len(pb.GetMetaNodes()))
 	for i, meta := range pb.GetMetaNodes() {
@@ -461,10 +603,45 @@ func (data *Data) unmarshal(pb *internal.ClusterData) {
 		data.DataNodes[i].unmarshal(d)
 	}
 
+	data.ShardPlacements = nil
+	for _, sp := range pb.GetShardPlacements() {
+		data.ShardPlacements = append(data.ShardPlacements, ShardPlacement{
+			Database: sp.GetDatabase(),
+			Policy:   sp.GetPolicy(),
+			Strategy: sp.GetStrategy(),
+		})
+	}
+
+	data.Roles = nil
+	for _, r := range pb.GetRoles() {
+		var ri RoleInfo
+		ri.unmarshal(r)
+		data.Roles = append(data.Roles, ri)
+	}
+
+	data.UserPermissions = nil
+	for _, u := range pb.GetUsers() {
+		if data.UserPermissions == nil {
+			data.UserPermissions = make(map[string]Permissions)
+		}
+		data.UserPermissions[u.GetName()] = unmarshalPermissions(u.GetPermissions())
+	}
 }
 
-// CreateShardGroup creates a shard group on a database and policy for a given timestamp.
+// CreateShardGroup creates a shard group on a database and policy for a given
+// timestamp. Owners are placed using the shard placement of the policy.
 func (data *Data) CreateShardGroup(database, policy string, timestamp time.Time) error {
+	return data.createShardGroup(database, policy, timestamp, data.ShardPlacement(database, policy))
+}
+
+// CreateBalancedShardGroup creates a shard group on a database and policy for a
+// given timestamp, placing owners on the least loaded data nodes regardless of
+// the shard placement of the policy.
+func (data *Data) CreateBalancedShardGroup(database, policy string, timestamp time.Time) error {
+	return data.createShardGroup(database, policy, timestamp, ShardPlacementBalanced)
+}
+
+func (data *Data) createShardGroup(database, policy string, timestamp time.Time, strategy string) error {
 	// Ensure there are nodes in the metadata.
 	if len(data.DataNodes) == 0 {
 		return nil
@@ -504,7 +681,11 @@ func (data *Data) CreateShardGroup(database, policy string, timestamp time.Time)
 	sgi.EndTime = sgi.StartTime.Add(rpi.ShardGroupDuration).UTC()
 
 	// Create shards on the group.
-	data.generatedShards(&sgi, shardN, replicaN)
+	if strategy == ShardPlacementBalanced {
+		data.generatedBalancedShards(&sgi, shardN, replicaN)
+	} else {
+		data.generatedShards(&sgi, shardN, replicaN)
+	}
 
 	// Retention policy has a new shard group, so update the policy. Shard
 	// Groups must be stored in sorted order, as other parts of the system
@@ -515,6 +696,145 @@ func (data *Data) CreateShardGroup(database, policy string, timestamp time.Time)
 	return nil
 }
 
+// SplitShardGroup ends the shard group id at timestamp and creates a shard
+// group with shardN shards for the rest of its time range. Writes at or after
+// timestamp are spread across the new shards while queries cover the shards of
+// both groups. A shardN of zero sizes the new group for the current data nodes.
+func (data *Data) SplitShardGroup(database, policy string, id uint64, timestamp time.Time, shardN int) error {
+	if len(data.DataNodes) == 0 {
+		return ErrNodesRequired
+	}
+
+	rpi, err := data.Data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	var sg *meta.ShardGroupInfo
+	for i := range rpi.ShardGroups {
+		if rpi.ShardGroups[i].ID == id && !rpi.ShardGroups[i].Deleted() {
+			sg = &rpi.ShardGroups[i]
+		}
+	}
+	if sg == nil {
+		return ErrShardGroupNotFound
+	}
+
+	end := sg.EndTime
+	if sg.Truncated() {
+		end = sg.TruncatedAt
+	}
+	if !timestamp.After(sg.StartTime) || !timestamp.Before(end) {
+		return ErrShardGroupSplitTime
+	}
+
+	replicaN := rpi.ReplicaN
+	if replicaN == 0 {
+		replicaN = 1
+	} else if replicaN > len(data.DataNodes) {
+		replicaN = len(data.DataNodes)
+	}
+	if shardN <= 0 {
+		shardN = len(data.DataNodes) / replicaN
+	}
+
+	// Stop writes to the shard group before adding its successor.
+	sg.TruncatedAt = timestamp.UTC()
+
+	data.Data.MaxShardGroupID++
+	sgi := meta.ShardGroupInfo{}
+	sgi.ID = data.Data.MaxShardGroupID
+	sgi.StartTime = timestamp.UTC()
+	sgi.EndTime = end.UTC()
+
+	if data.ShardPlacement(database, policy) == ShardPlacementBalanced {
+		data.generatedBalancedShards(&sgi, shardN, replicaN)
+	} else {
+		data.generatedShards(&sgi, shardN, replicaN)
+	}
+
+	rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+	sort.Sort(meta.ShardGroupInfos(rpi.ShardGroups))
+
+	return nil
+}
+
+// RestoreShardGroup creates a shard group covering start to end with shardN
+// shards placed on the current data nodes. It is used to restore a backed up
+// shard group, whose shard count need not match the current data nodes. A
+// shardN of zero sizes the group for the current data nodes.
+func (data *Data) RestoreShardGroup(database, policy string, start, end time.Time, shardN int) error {
+	return data.restoreShardGroup(database, policy, start, end, time.Time{}, shardN, nil)
+}
+
+// restoreShardGroup restores a shard group truncated at truncatedAt, if set.
+// Groups overlap when the time ranges they accept writes for overlap, so the
+// successor of a truncated or split group doesn't conflict with it. The groups
+// in ignore are never counted as conflicts.
+func (data *Data) restoreShardGroup(database, policy string, start, end, truncatedAt time.Time, shardN int, ignore map[uint64]bool) error {
+	if len(data.DataNodes) == 0 {
+		return ErrNodesRequired
+	}
+
+	rpi, err := data.Data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	// Shards of a restored group must not overlap existing data.
+	writeEnd := end
+	if !truncatedAt.IsZero() {
+		writeEnd = truncatedAt
+	}
+	for i := range rpi.ShardGroups {
+		sg := &rpi.ShardGroups[i]
+		if sg.Deleted() || ignore[sg.ID] {
+			continue
+		}
+		sgEnd := sg.EndTime
+		if sg.Truncated() {
+			sgEnd = sg.TruncatedAt
+		}
+		if sg.StartTime.Before(writeEnd) && start.Before(sgEnd) {
+			return ErrShardGroupExists
+		}
+	}
+
+	replicaN := rpi.ReplicaN
+	if replicaN == 0 {
+		replicaN = 1
+	} else if replicaN > len(data.DataNodes) {
+		replicaN = len(data.DataNodes)
+	}
+	if shardN <= 0 {
+		shardN = len(data.DataNodes) / replicaN
+	}
+
+	data.Data.MaxShardGroupID++
+	sgi := meta.ShardGroupInfo{}
+	sgi.ID = data.Data.MaxShardGroupID
+	sgi.StartTime = start.UTC()
+	sgi.EndTime = end.UTC()
+	if !truncatedAt.IsZero() {
+		sgi.TruncatedAt = truncatedAt.UTC()
+	}
+
+	if data.ShardPlacement(database, policy) == ShardPlacementBalanced {
+		data.generatedBalancedShards(&sgi, shardN, replicaN)
+	} else {
+		data.generatedShards(&sgi, shardN, replicaN)
+	}
+
+	rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+	sort.Sort(meta.ShardGroupInfos(rpi.ShardGroups))
+
+	return nil
+}
+
 func (data *Data) gcd() {
 
 }
@@ -530,38 +850,261 @@ func (data *Data) generatedShards(sgi *meta.ShardGroupInfo, shardN, replicaN int
 	for i := range sgi.Shards {
 		si := &sgi.Shards[i]
 		for j := 0; j < replicaN; j++ {
-			nodeID := data.DataNodes[nodeIndex%len(data.DataNodes)].ID
-			si.Owners = append(si.Owners, meta.ShardOwner{NodeID: nodeID})
+			// Take the next node in turn unless a later one shares fewer
+			// labels with the owners already assigned.
+			var owner *NodeInfo
+			var conflicts int
+			for k := range data.DataNodes {
+				n := &data.DataNodes[(nodeIndex+k)%len(data.DataNodes)]
+				if si.OwnedBy(n.ID) {
+					continue
+				} else if c := data.labelConflicts(n, si); owner == nil || c < conflicts {
+					owner, conflicts = n, c
+				}
+			}
+
+			si.Owners = append(si.Owners, meta.ShardOwner{NodeID: owner.ID})
 			nodeIndex++
 		}
 	}
 }
 
-// TruncateShardsGrops truncates shardsGroup according to shardsGroupID
-func (data *Data) TruncateShardsGrops(sg *meta.ShardGroupInfo) error {
+// labelConflicts returns the number of labels of n whose value is shared by an
+// owner of si. Nodes with fewer conflicts are in a different failure domain.
+func (data *Data) labelConflicts(n *NodeInfo, si *meta.ShardInfo) int {
+	var conflicts int
+	for k, v := range n.Labels {
+		for _, o := range si.Owners {
+			if owner := data.DataNode(o.NodeID); owner != nil && owner.Labels[k] == v {
+				conflicts++
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// generatedBalancedShards creates shardN shards on sgi and assigns each of
+// them replicaN distinct owners. Owners are spread across failure domains
+// first; within them every owner is the data node owning the fewest shards at
+// that point, so groups fill up the emptiest nodes first. Ties are broken by
+// the lowest disk usage, then by the lowest node id.
+func (data *Data) generatedBalancedShards(sgi *meta.ShardGroupInfo, shardN, replicaN int) {
+	load := data.shardCounts()
+
+	sgi.Shards = make([]meta.ShardInfo, shardN)
+	for i := range sgi.Shards {
+		data.MaxShardID++
+		si := &sgi.Shards[i]
+		si.ID = data.MaxShardID
+
+		for j := 0; j < replicaN; j++ {
+			var owner *NodeInfo
+			var conflicts int
+			for k := range data.DataNodes {
+				n := &data.DataNodes[k]
+				if si.OwnedBy(n.ID) {
+					continue
+				}
+
+				c := data.labelConflicts(n, si)
+				if owner == nil || c < conflicts {
+					owner, conflicts = n, c
+				} else if c == conflicts && (load[n.ID] < load[owner.ID] ||
+					(load[n.ID] == load[owner.ID] && n.DiskUsage < owner.DiskUsage)) {
+					owner = n
+				}
+			}
+
+			si.Owners = append(si.Owners, meta.ShardOwner{NodeID: owner.ID})
+			load[owner.ID]++
+		}
+	}
+}
+
+// shardCounts returns the number of shards owned or being copied by each data
+// node. Shards of deleted shard groups are not counted.
+func (data *Data) shardCounts() map[uint64]int {
+	counts := make(map[uint64]int, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		counts[n.ID] = len(n.PendingShardOwners)
+	}
+
+	for _, dbi := range data.Data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					for _, o := range si.Owners {
+						if _, ok := counts[o.NodeID]; ok {
+							counts[o.NodeID]++
+						}
+					}
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// ShardPlacement returns the shard placement strategy of a retention policy.
+func (data *Data) ShardPlacement(database, policy string) string {
+	for _, sp := range data.ShardPlacements {
+		if sp.Database == database && sp.Policy == policy {
+			return sp.Strategy
+		}
+	}
+	return ShardPlacementRoundRobin
+}
+
+// DropDatabase removes a database and the shard placements of its retention
+// policies.
+func (data *Data) DropDatabase(name string) error {
+	if err := data.Data.DropDatabase(name); err != nil {
+		return err
+	}
+	data.removeShardPlacements(name, "")
+	return nil
+}
+
+// DropRetentionPolicy removes a retention policy and its shard placement.
+func (data *Data) DropRetentionPolicy(database, name string) error {
+	if err := data.Data.DropRetentionPolicy(database, name); err != nil {
+		return err
+	}
+	data.removeShardPlacements(database, name)
 	return nil
 }
 
-// AddPendingShardOwner adds a pending shardOwner according to nodeID.
-func (data *Data) AddPendingShardOwner(id uint64) {
-	for _, node := range data.MetaNodes {
-		node.PendingShardOwners = append(node.PendingShardOwners, id)
+// removeShardPlacements removes the shard placements of the retention
+// policies of a database, or only of policy if it is set.
+func (data *Data) removeShardPlacements(database, policy string) {
+	var placements []ShardPlacement
+	for _, sp := range data.ShardPlacements {
+		if sp.Database != database || (policy != "" && sp.Policy != policy) {
+			placements = append(placements, sp)
+		}
 	}
+	data.ShardPlacements = placements
 }
 
-// RemovePendingShardOwner removes a pending shardOwner according to nodeID.
-func (data *Data) RemovePendingShardOwner(id uint64) {
-	for _, node := range data.MetaNodes {
-		newPso := uint64arr{}
-		for _, pso := range node.PendingShardOwners {
-			if id != pso {
-				newPso = append(newPso, pso)
+// SetShardPlacement sets the shard placement strategy used by new shard groups
+// of a retention policy.
+func (data *Data) SetShardPlacement(database, policy, strategy string) error {
+	if strategy != ShardPlacementRoundRobin && strategy != ShardPlacementBalanced {
+		return ErrInvalidShardPlacement
+	}
+
+	rpi, err := data.Data.RetentionPolicy(database, policy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	placements := make([]ShardPlacement, 0, len(data.ShardPlacements)+1)
+	for _, sp := range data.ShardPlacements {
+		if sp.Database != database || sp.Policy != policy {
+			placements = append(placements, sp)
+		}
+	}
+	if strategy != ShardPlacementRoundRobin {
+		placements = append(placements, ShardPlacement{Database: database, Policy: policy, Strategy: strategy})
+	}
+	data.ShardPlacements = placements
+	return nil
+}
+
+// SetDataNodeDiskUsage records the disk usage reported by a data node.
+func (data *Data) SetDataNodeDiskUsage(id, size uint64) error {
+	n := data.DataNode(id)
+	if n == nil {
+		return ErrNodeNotFound
+	}
+	n.DiskUsage = size
+	return nil
+}
+
+// TruncateShardGroups ends every shard group still accepting writes at t.
+// Writes at or after t land in new shard groups, which are spread across the
+// data nodes of the cluster at the time they are created. Shard groups
+// starting after t are truncated at their start so they receive no writes.
+func (data *Data) TruncateShardGroups(t time.Time) {
+	for i := range data.Data.Databases {
+		dbi := &data.Data.Databases[i]
+		for j := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[j]
+			for k := range rpi.ShardGroups {
+				sgi := &rpi.ShardGroups[k]
+				if sgi.Deleted() || !t.Before(sgi.EndTime) {
+					continue
+				} else if sgi.Truncated() && !t.Before(sgi.TruncatedAt) {
+					continue
+				}
+
+				if !t.After(sgi.StartTime) {
+					sgi.TruncatedAt = sgi.StartTime
+				} else {
+					sgi.TruncatedAt = t.UTC()
+				}
 			}
 		}
-		node.PendingShardOwners = newPso
 	}
 }
 
+// AddPendingShardOwner marks nodeID as a pending owner of shardID. A pending
+// owner receives a copy of the shard but is not used by queries until it is
+// committed.
+func (data *Data) AddPendingShardOwner(shardID, nodeID uint64) error {
+	si, err := data.ShardLocation(shardID)
+	if err != nil {
+		return err
+	}
+
+	node := data.DataNode(nodeID)
+	if node == nil {
+		return ErrNodeNotFound
+	} else if si.OwnedBy(nodeID) {
+		return ErrShardOwnerExists
+	} else if node.pending(shardID) {
+		return nil
+	}
+
+	node.PendingShardOwners = append(node.PendingShardOwners, shardID)
+	sort.Sort(node.PendingShardOwners)
+	return nil
+}
+
+// RemovePendingShardOwner removes nodeID as a pending owner of shardID.
+func (data *Data) RemovePendingShardOwner(shardID, nodeID uint64) error {
+	node := data.DataNode(nodeID)
+	if node == nil {
+		return ErrNodeNotFound
+	} else if !node.pending(shardID) {
+		return ErrPendingShardOwnerNotFound
+	}
+
+	other := make(uint64arr, 0, len(node.PendingShardOwners)-1)
+	for _, id := range node.PendingShardOwners {
+		if id != shardID {
+			other = append(other, id)
+		}
+	}
+	node.PendingShardOwners = other
+	return nil
+}
+
+// CommitPendingShardOwner turns the pending owner nodeID of shardID into an
+// owner of the shard.
+func (data *Data) CommitPendingShardOwner(shardID, nodeID uint64) error {
+	if err := data.RemovePendingShardOwner(shardID, nodeID); err != nil {
+		return err
+	}
+	return data.AddShardOwner(shardID, nodeID)
+}
+
 // ShardOwners is an array ot ShardOwner.
 type ShardOwners []meta.ShardOwner
 
@@ -577,7 +1120,7 @@ func (so ShardOwners) Swap(i, j int) {
 	so[i], so[j] = so[j], so[i]
 }
 
-//ShardLocation return NodeInfos which is the o of the Shard
+// ShardLocation returns the shard with the given shardID.
 func (data *Data) ShardLocation(shardID uint64) (*meta.ShardInfo, error) {
 	for _, dbi := range data.Data.Databases {
 		for _, rpi := range dbi.RetentionPolicies {
@@ -597,82 +1140,171 @@ func (data *Data) ShardLocation(shardID uint64) (*meta.ShardInfo, error) {
 
 // UpdateShard will update ShardOwner of a Shard according to ShardID
 func (data *Data) UpdateShard(shardID uint64, newOwners []meta.ShardOwner) error {
+	for di, dbi := range data.Data.Databases {
+		for ri, rpi := range dbi.RetentionPolicies {
+			for gi, sg := range rpi.ShardGroups {
+				for si, s := range sg.Shards {
+					if s.ID == shardID {
+						owners := make([]meta.ShardOwner, len(newOwners))
+						copy(owners, newOwners)
+						data.Data.Databases[di].RetentionPolicies[ri].ShardGroups[gi].Shards[si].Owners = owners
+						return nil
+					}
+				}
+			}
+		}
+	}
 	return fmt.Errorf("Failed to find Shard assoicated with shard ID %d", shardID)
 }
 
-// AddShardOwner will update a shards labelled by shardID in this node if such shards ownby this newly adding node
+// AddShardOwner adds nodeID as an owner of shardID. A pending ownership of
+// the shard by nodeID is dropped.
 func (data *Data) AddShardOwner(shardID, nodeID uint64) error {
 	si, err := data.ShardLocation(shardID)
-	if err == nil {
-		if !si.OwnedBy(nodeID) {
-			if nodeID > data.MaxNodeID {
-				return nil
-			}
-			o := ShardOwners{}
-			o = append(o, meta.ShardOwner{NodeID: nodeID})
-			sort.Sort(o)
-			return data.UpdateShard(shardID, o)
+	if err != nil {
+		return err
+	}
+
+	node := data.DataNode(nodeID)
+	if node == nil {
+		return ErrNodeNotFound
+	}
+	if node.pending(shardID) {
+		if err := data.RemovePendingShardOwner(shardID, nodeID); err != nil {
+			return err
 		}
 	}
-	return err
+	if si.OwnedBy(nodeID) {
+		return nil
+	}
+
+	o := append(ShardOwners{}, si.Owners...)
+	o = append(o, meta.ShardOwner{NodeID: nodeID})
+	sort.Sort(o)
+	return data.UpdateShard(shardID, o)
 }
 
-// RemoveShardOwner will remove all shards in this node if such shard owned by this node
+// RemoveShardOwner removes nodeID as an owner of shardID. The last owner of a
+// shard can't be removed.
 func (data *Data) RemoveShardOwner(shardID, nodeID uint64) error {
 	si, err := data.ShardLocation(shardID)
 	if err != nil {
-		if si.OwnedBy(nodeID) {
-			o, _ := data.PruneShard(si, nodeID)
-			data.UpdateShard(shardID, o)
-		}
+		return err
+	}
+
+	o, err := data.PruneShard(si, nodeID)
+	if err != nil {
+		return err
+	} else if len(o) == 0 {
+		return ErrShardNotReplicated
 	}
-	return err
+	return data.UpdateShard(shardID, o)
 }
 
-// PruneShard prunes shard according to nodeID.
+// PruneShard returns the owners of si without nodeID.
 func (data *Data) PruneShard(si *meta.ShardInfo, nodeID uint64) ([]meta.ShardOwner, error) {
-	found := -1
-	for i, o := range si.Owners {
-		if o.NodeID == nodeID {
-			found = i
-			break
+	if !si.OwnedBy(nodeID) {
+		return nil, fmt.Errorf("failed to find shard owner %d", nodeID)
+	}
+
+	owners := make([]meta.ShardOwner, 0, len(si.Owners)-1)
+	for _, o := range si.Owners {
+		if o.NodeID != nodeID {
+			owners = append(owners, o)
 		}
 	}
+	return owners, nil
+}
 
-	if found != -1 {
-		copy(si.Owners[found:], si.Owners[found+1:])
-		// si.Owners[len(si.Owners)-1] = nil
-		// si.Owners = si.Owners[:len(si.Owners)-1]
-		return si.Owners, nil
-	}
-	return nil, fmt.Errorf("failed to find shard owner %d", nodeID)
-}
-
-// ImportData imports a binary form data as metastore data. Return error if
-// such binary form is invalid.
-func (data *Data) ImportData(buf []byte) error {
-	// other := Data{}
-	// if err := other.UnmarshalBinary(buf); err != nil {
-	// 	return err
-	// }
-
-	// // Restrict(other)
-	// for dbidx, db := range data.Data.Databases {
-	// 	dbn := other.Database(db.Name)
-	// 	if dbn == nil {
-	// 		if err = other.CreateDatabase(db.Name); err != nil {
-	// 			return err
-	// 		}
-	// 	}
-	// 	for _, rpi := range db.RetentionPolicies {
-	// 		other.CreateRetentionPolicy(dbn.Name, dbn.RetentionPolicy(rpi.Name))
-	// 		data.generatedShards(rpi.ShardGroups)
-	// 	}
-
-	// }
-	//sort
-	//call gcd
-	return nil
+// ImportData imports the retention policy database.policy of the binary
+// form data in buf as newDatabase.newPolicy, which default to the original
+// names. The retention policy is created with replicaN copies, or its original
+// replication factor when replicaN is zero, and each of its shard groups is
+// recreated with new IDs and the same number of shards placed on the current
+// data nodes, keeping the time its writes were truncated at. It returns the
+// IDs of the new shards by the IDs of the imported shards.
+//
+// It returns ErrRetentionPolicyExists if the retention policy exists unless
+// force is set, in which case the shard groups are imported into it. Shard
+// groups overlapping an existing shard group are skipped and their shards
+// are left out of the returned IDs. Groups imported by the same call never
+// conflict with each other.
+func (data *Data) ImportData(buf []byte, database, policy, newDatabase, newPolicy string, replicaN int, force bool) (map[uint64]uint64, error) {
+	other := &Data{}
+	if err := other.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+
+	dbi := other.Data.Database(database)
+	if dbi == nil {
+		return nil, influxdb.ErrDatabaseNotFound(database)
+	}
+	rpi := dbi.RetentionPolicy(policy)
+	if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	if newDatabase == "" {
+		newDatabase = database
+	}
+	if newPolicy == "" {
+		newPolicy = policy
+	}
+	if replicaN <= 0 {
+		replicaN = rpi.ReplicaN
+	}
+
+	if data.Data.Database(newDatabase) == nil {
+		if err := data.Data.CreateDatabase(newDatabase); err != nil {
+			return nil, err
+		}
+	}
+	if existing, err := data.Data.RetentionPolicy(newDatabase, newPolicy); err != nil {
+		return nil, err
+	} else if existing != nil && !force {
+		return nil, meta.ErrRetentionPolicyExists
+	} else if existing == nil {
+		if err := data.Data.CreateRetentionPolicy(newDatabase, &meta.RetentionPolicyInfo{
+			Name:               newPolicy,
+			ReplicaN:           replicaN,
+			Duration:           rpi.Duration,
+			ShardGroupDuration: rpi.ShardGroupDuration,
+		}, dbi.DefaultRetentionPolicy == policy && data.Data.Database(newDatabase).DefaultRetentionPolicy == ""); err != nil {
+			return nil, err
+		}
+		if strategy := other.ShardPlacement(database, policy); strategy != ShardPlacementRoundRobin {
+			if err := data.SetShardPlacement(newDatabase, newPolicy, strategy); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	shardIDs := make(map[uint64]uint64)
+	imported := make(map[uint64]bool)
+	for i := range rpi.ShardGroups {
+		sg := &rpi.ShardGroups[i]
+		if sg.Deleted() {
+			continue
+		}
+		if err := data.restoreShardGroup(newDatabase, newPolicy, sg.StartTime, sg.EndTime, sg.TruncatedAt, len(sg.Shards), imported); err == ErrShardGroupExists && force {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// The restored shard group has the highest shard group ID.
+		imported[data.Data.MaxShardGroupID] = true
+		newRPI, _ := data.Data.RetentionPolicy(newDatabase, newPolicy)
+		for j := range newRPI.ShardGroups {
+			if newSG := &newRPI.ShardGroups[j]; newSG.ID == data.Data.MaxShardGroupID {
+				for k := range sg.Shards {
+					shardIDs[sg.Shards[k].ID] = newSG.Shards[k].ID
+				}
+				break
+			}
+		}
+	}
+	return shardIDs, nil
 }
 
 type uint64arr []uint64
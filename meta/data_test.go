@@ -3,6 +3,7 @@ package meta
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb/services/meta"
 )
@@ -30,3 +31,119 @@ func testnewShardOwner(t *testing.T) {
 		t.Errorf("got owner frequencies %v, expected %v", got, exp)
 	}
 }
+
+// Ensure node IDs aren't reused after the data is unmarshaled.
+func TestData_UnmarshalBinary_MaxNodeID(t *testing.T) {
+	data := &Data{Data: &meta.Data{}}
+	if err := data.CreateMetaNode("host0:8091", "host0:8089"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateDataNode("host1:8086", "host1:8088", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other Data
+	if err := other.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if other.MaxNodeID != data.MaxNodeID {
+		t.Fatalf("unexpected max node id: got %d, exp %d", other.MaxNodeID, data.MaxNodeID)
+	}
+
+	if err := other.CreateDataNode("host2:8086", "host2:8088", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := other.DataNodes[1].ID, uint64(3); got != exp {
+		t.Fatalf("unexpected node id: got %d, exp %d", got, exp)
+	}
+}
+
+// Ensure a split shard group and its successor are both imported and the
+// split group keeps its truncation.
+func TestData_ImportData_SplitShardGroup(t *testing.T) {
+	data := &Data{Data: &meta.Data{}}
+	if err := data.CreateDataNode("host1:8086", "host1:8088", nil); err != nil {
+		t.Fatal(err)
+	} else if err := data.Data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.Data.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, ShardGroupDuration: 24 * time.Hour}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	splitAt := start.Add(12 * time.Hour)
+	if err := data.CreateShardGroup("db0", "rp0", start); err != nil {
+		t.Fatal(err)
+	} else if err := data.SplitShardGroup("db0", "rp0", 1, splitAt, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shardIDs, err := data.ImportData(b, "db0", "rp0", "db2", "", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := map[uint64]uint64{1: 3, 2: 4}; !reflect.DeepEqual(shardIDs, exp) {
+		t.Fatalf("unexpected shard ids: %v", shardIDs)
+	}
+
+	rpi, err := data.Data.RetentionPolicy("db2", "rp0")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rpi.ShardGroups) != 2 {
+		t.Fatalf("unexpected shard groups: %+v", rpi.ShardGroups)
+	} else if sg := rpi.ShardGroups[0]; !sg.StartTime.Equal(start) || !sg.TruncatedAt.Equal(splitAt) {
+		t.Fatalf("unexpected split shard group: %+v", sg)
+	} else if sg := rpi.ShardGroups[1]; !sg.StartTime.Equal(splitAt) || sg.Truncated() {
+		t.Fatalf("unexpected successor shard group: %+v", sg)
+	}
+
+	// Importing again with force skips both groups.
+	if shardIDs, err := data.ImportData(b, "db0", "rp0", "db2", "", 0, true); err != nil {
+		t.Fatal(err)
+	} else if len(shardIDs) != 0 {
+		t.Fatalf("unexpected shard ids: %v", shardIDs)
+	}
+}
+
+// Ensure the last owner of a shard can't be removed.
+func TestData_RemoveShardOwner_LastOwner(t *testing.T) {
+	data := &Data{Data: &meta.Data{}}
+	if err := data.CreateDataNode("host1:8086", "host1:8088", nil); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateDataNode("host2:8086", "host2:8088", nil); err != nil {
+		t.Fatal(err)
+	} else if err := data.Data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.Data.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 2, ShardGroupDuration: 24 * time.Hour}, true); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateShardGroup("db0", "rp0", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	si, err := data.ShardLocation(1)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(si.Owners) != 2 {
+		t.Fatalf("unexpected owners: %v", si.Owners)
+	}
+
+	if err := data.RemoveShardOwner(1, si.Owners[0].NodeID); err != nil {
+		t.Fatal(err)
+	}
+	si, err = data.ShardLocation(1)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := data.RemoveShardOwner(1, si.Owners[0].NodeID); err != ErrShardNotReplicated {
+		t.Fatalf("unexpected error: %v", err)
+	} else if si, _ := data.ShardLocation(1); len(si.Owners) != 1 {
+		t.Fatalf("unexpected owners: %v", si.Owners)
+	}
+}
@@ -11,6 +11,10 @@ var (
 
 	// ErrStoreClosed is returned when closing an already closed store.
 	ErrStoreClosed = errors.New("raft store already closed")
+
+	// ErrJoinPeersRequired is returned when opening a non-voting store
+	// without voters to follow.
+	ErrJoinPeersRequired = errors.New("non-voting meta node requires join peers")
 )
 
 var (
@@ -27,6 +31,10 @@ var (
 	// ErrNodeIDRequired is returned when using a zero node id.
 	ErrNodeIDRequired = errors.New("node id must be greater than 0")
 
+	// ErrNodeRemoved is returned when a meta node that was removed from the
+	// cluster is restarted.
+	ErrNodeRemoved = errors.New("meta node was removed from the cluster, remove its meta directory to add it again")
+
 	// ErrNodeUnableToDropFinalNode is returned if the node being dropped is the last
 	// node in the cluster
 	ErrNodeUnableToDropFinalNode = errors.New("unable to drop the final node in a cluster")
@@ -82,6 +90,22 @@ var (
 	// ErrShardNotReplicated is returned if the node requested to be dropped has
 	// the last copy of a shard present and the force keyword was not used
 	ErrShardNotReplicated = errors.New("shard not replicated")
+
+	// ErrShardOwnerExists is returned when adding a pending owner to a shard
+	// that the node already owns.
+	ErrShardOwnerExists = errors.New("shard owner already exists")
+
+	// ErrPendingShardOwnerNotFound is returned when committing or removing a
+	// pending shard owner that doesn't exist.
+	ErrPendingShardOwnerNotFound = errors.New("pending shard owner not found")
+
+	// ErrInvalidShardPlacement is returned when setting an unknown shard
+	// placement strategy on a retention policy.
+	ErrInvalidShardPlacement = errors.New("invalid shard placement strategy")
+
+	// ErrShardGroupSplitTime is returned when splitting a shard group at a
+	// time outside of the range it accepts writes for.
+	ErrShardGroupSplitTime = errors.New("split time must be within the shard group")
 )
 
 var (
@@ -113,3 +137,17 @@ var (
 	// ErrAuthenticate is returned when authentication fails.
 	ErrAuthenticate = errors.New("authentication failed")
 )
+
+var (
+	// ErrRoleExists is returned when creating an already existing role.
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrRoleNotFound is returned when mutating a role that doesn't exist.
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrRoleNameRequired is returned when creating a role without a name.
+	ErrRoleNameRequired = errors.New("role name required")
+
+	// ErrInvalidPermission is returned when granting an unknown permission.
+	ErrInvalidPermission = errors.New("invalid permission")
+)
@@ -34,7 +34,8 @@ type handler struct {
 		leader() string
 		leaderHTTP() string
 		snapshot() (*Data, error)
-		apply(b []byte) error
+		snapshotDelta(index uint64) (*internal.SnapshotDelta, error)
+		execute(b []byte) (interface{}, error)
 		join(n *NodeInfo) (*NodeInfo, error)
 		otherMetaServersHTTP() []string
 		peers() []string
@@ -64,6 +65,10 @@ func newHandler(c *Config, s *Service) *handler {
 func (h *handler) WrapHandler(name string, hf http.HandlerFunc) http.Handler {
 	var handler http.Handler
 	handler = http.HandlerFunc(hf)
+	// Pings stay open for health checks.
+	if h.config.AuthEnabled && name != "ping" {
+		handler = authenticate(handler, h)
+	}
 	handler = gzipFilter(handler)
 	handler = versionHeader(handler, h)
 	handler = requestID(handler)
@@ -186,7 +191,7 @@ func (h *handler) serveExec(w http.ResponseWriter, r *http.Request) {
 
 	// Apply the command to the store.
 	var resp *internal.Response
-	if err := h.store.apply(body); err != nil {
+	if v, err := h.store.execute(body); err != nil {
 		// If we aren't the leader, redirect client to the leader.
 		if err == raft.ErrNotLeader {
 			l := h.store.leaderHTTP()
@@ -216,6 +221,16 @@ func (h *handler) serveExec(w http.ResponseWriter, r *http.Request) {
 			OK:    proto.Bool(false),
 			Index: proto.Uint64(h.store.index()),
 		}
+
+		// Return the shard IDs created for imported shards.
+		if shardIDs, ok := v.(map[uint64]uint64); ok {
+			for oldID, newID := range shardIDs {
+				resp.ShardIDs = append(resp.ShardIDs, &internal.ShardIDMapping{
+					OldID: proto.Uint64(oldID),
+					NewID: proto.Uint64(newID),
+				})
+			}
+		}
 	}
 
 	// Marshal the response.
@@ -254,16 +269,31 @@ func (h *handler) serveSnapshot(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-h.store.afterIndex(index):
-		// Send updated snapshot to client.
-		ss, err := h.store.snapshot()
-		if err != nil {
-			h.httpError(err, w, http.StatusInternalServerError)
-			return
-		}
-		b, err := ss.MarshalBinary()
-		if err != nil {
-			h.httpError(err, w, http.StatusInternalServerError)
-			return
+		// Send the commands applied since index to clients asking for a
+		// delta, otherwise the updated snapshot.
+		var b []byte
+		if r.URL.Query().Get("delta") == "true" {
+			d, err := h.store.snapshotDelta(index)
+			if err != nil {
+				h.httpError(err, w, http.StatusInternalServerError)
+				return
+			}
+			b, err = proto.Marshal(d)
+			if err != nil {
+				h.httpError(err, w, http.StatusInternalServerError)
+				return
+			}
+		} else {
+			ss, err := h.store.snapshot()
+			if err != nil {
+				h.httpError(err, w, http.StatusInternalServerError)
+				return
+			}
+			b, err = ss.MarshalBinary()
+			if err != nil {
+				h.httpError(err, w, http.StatusInternalServerError)
+				return
+			}
 		}
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Write(b)
@@ -415,6 +445,19 @@ func (w gzipResponseWriter) CloseNotify() <-chan bool {
 	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
 }
 
+// authenticate rejects requests not signed with the shared secret.
+func authenticate(inner http.Handler, h *handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authenticateRequest(r, h.config.InternalSharedSecret); err != nil {
+			h.logger.Info("unauthorized request", zap.String("path", r.URL.Path), zap.Error(err))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="influxdb-meta"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		inner.ServeHTTP(w, r)
+	})
+}
+
 // determines if the client can accept compressed responses, and encodes accordingly
 func gzipFilter(inner http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -11,11 +11,14 @@ It is generated from these files:
 It has these top-level messages:
 	ClusterData
 	NodeInfo
+	NodeLabel
+	ShardPlacement
 	RoleInfo
 	UserInfo
 	UserPrivilege
 	ScopedPermission
 	Response
+	ShardIDMapping
 	Command
 	CreateDatabaseCommand
 	DropDatabaseCommand
@@ -61,6 +64,12 @@ It has these top-level messages:
 	ChangeRoleNameCommand
 	ImportDataCommand
 	CreateBalancedShardGroupCommand
+	SetShardPlacementCommand
+	SetDataNodeDiskUsageCommand
+	SplitShardGroupCommand
+	RestoreShardGroupCommand
+	SnapshotDelta
+	LogEntry
 */
 package internal
 
@@ -126,6 +135,10 @@ const (
 	Command_TruncateShardGroupsCommand       Command_Type = 42
 	Command_ChangeRoleNameCommand            Command_Type = 43
 	Command_CreateBalancedShardGroupCommand  Command_Type = 44
+	Command_SetShardPlacementCommand         Command_Type = 45
+	Command_SetDataNodeDiskUsageCommand      Command_Type = 46
+	Command_SplitShardGroupCommand           Command_Type = 47
+	Command_RestoreShardGroupCommand         Command_Type = 48
 )
 
 var Command_Type_name = map[int32]string{
@@ -173,6 +186,10 @@ var Command_Type_name = map[int32]string{
 	42: "TruncateShardGroupsCommand",
 	43: "ChangeRoleNameCommand",
 	44: "CreateBalancedShardGroupCommand",
+	45: "SetShardPlacementCommand",
+	46: "SetDataNodeDiskUsageCommand",
+	47: "SplitShardGroupCommand",
+	48: "RestoreShardGroupCommand",
 }
 var Command_Type_value = map[string]int32{
 	"CreateDatabaseCommand":            1,
@@ -219,6 +236,10 @@ var Command_Type_value = map[string]int32{
 	"TruncateShardGroupsCommand":       42,
 	"ChangeRoleNameCommand":            43,
 	"CreateBalancedShardGroupCommand":  44,
+	"SetShardPlacementCommand":         45,
+	"SetDataNodeDiskUsageCommand":      46,
+	"SplitShardGroupCommand":           47,
+	"RestoreShardGroupCommand":         48,
 }
 
 func (x Command_Type) Enum() *Command_Type {
@@ -237,16 +258,17 @@ func (x *Command_Type) UnmarshalJSON(data []byte) error {
 	*x = Command_Type(value)
 	return nil
 }
-func (Command_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorMeta, []int{7, 0} }
+func (Command_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorMeta, []int{10, 0} }
 
 type ClusterData struct {
-	Data             []byte      `protobuf:"bytes,1,req,name=Data" json:"Data,omitempty"`
-	MaxNodeID        *uint64     `protobuf:"varint,2,req,name=MaxNodeID" json:"MaxNodeID,omitempty"`
-	DataNodes        []*NodeInfo `protobuf:"bytes,3,rep,name=DataNodes" json:"DataNodes,omitempty"`
-	MetaNodes        []*NodeInfo `protobuf:"bytes,4,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
-	Roles            []*RoleInfo `protobuf:"bytes,5,rep,name=Roles" json:"Roles,omitempty"`
-	Users            []*UserInfo `protobuf:"bytes,6,rep,name=Users" json:"Users,omitempty"`
-	XXX_unrecognized []byte      `json:"-"`
+	Data             []byte            `protobuf:"bytes,1,req,name=Data" json:"Data,omitempty"`
+	MaxNodeID        *uint64           `protobuf:"varint,2,req,name=MaxNodeID" json:"MaxNodeID,omitempty"`
+	DataNodes        []*NodeInfo       `protobuf:"bytes,3,rep,name=DataNodes" json:"DataNodes,omitempty"`
+	MetaNodes        []*NodeInfo       `protobuf:"bytes,4,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	Roles            []*RoleInfo       `protobuf:"bytes,5,rep,name=Roles" json:"Roles,omitempty"`
+	Users            []*UserInfo       `protobuf:"bytes,6,rep,name=Users" json:"Users,omitempty"`
+	ShardPlacements  []*ShardPlacement `protobuf:"bytes,7,rep,name=ShardPlacements" json:"ShardPlacements,omitempty"`
+	XXX_unrecognized []byte            `json:"-"`
 }
 
 func (m *ClusterData) Reset()                    { *m = ClusterData{} }
@@ -296,12 +318,21 @@ func (m *ClusterData) GetUsers() []*UserInfo {
 	return nil
 }
 
+func (m *ClusterData) GetShardPlacements() []*ShardPlacement {
+	if m != nil {
+		return m.ShardPlacements
+	}
+	return nil
+}
+
 type NodeInfo struct {
-	ID                 *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
-	Host               *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
-	TCPHost            *string  `protobuf:"bytes,3,opt,name=TCPHost" json:"TCPHost,omitempty"`
-	PendingShardOwners []uint64 `protobuf:"varint,4,rep,name=PendingShardOwners" json:"PendingShardOwners,omitempty"`
-	XXX_unrecognized   []byte   `json:"-"`
+	ID                 *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Host               *string      `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
+	TCPHost            *string      `protobuf:"bytes,3,opt,name=TCPHost" json:"TCPHost,omitempty"`
+	PendingShardOwners []uint64     `protobuf:"varint,4,rep,name=PendingShardOwners" json:"PendingShardOwners,omitempty"`
+	DiskUsage          *uint64      `protobuf:"varint,5,opt,name=DiskUsage" json:"DiskUsage,omitempty"`
+	Labels             []*NodeLabel `protobuf:"bytes,6,rep,name=Labels" json:"Labels,omitempty"`
+	XXX_unrecognized   []byte       `json:"-"`
 }
 
 func (m *NodeInfo) Reset()                    { *m = NodeInfo{} }
@@ -337,17 +368,89 @@ func (m *NodeInfo) GetPendingShardOwners() []uint64 {
 	return nil
 }
 
+func (m *NodeInfo) GetDiskUsage() uint64 {
+	if m != nil && m.DiskUsage != nil {
+		return *m.DiskUsage
+	}
+	return 0
+}
+
+func (m *NodeInfo) GetLabels() []*NodeLabel {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type NodeLabel struct {
+	Key              *string `protobuf:"bytes,1,req,name=Key" json:"Key,omitempty"`
+	Value            *string `protobuf:"bytes,2,req,name=Value" json:"Value,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *NodeLabel) Reset()                    { *m = NodeLabel{} }
+func (m *NodeLabel) String() string            { return proto.CompactTextString(m) }
+func (*NodeLabel) ProtoMessage()               {}
+func (*NodeLabel) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{2} }
+
+func (m *NodeLabel) GetKey() string {
+	if m != nil && m.Key != nil {
+		return *m.Key
+	}
+	return ""
+}
+
+func (m *NodeLabel) GetValue() string {
+	if m != nil && m.Value != nil {
+		return *m.Value
+	}
+	return ""
+}
+
+type ShardPlacement struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy           *string `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Strategy         *string `protobuf:"bytes,3,req,name=Strategy" json:"Strategy,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *ShardPlacement) Reset()                    { *m = ShardPlacement{} }
+func (m *ShardPlacement) String() string            { return proto.CompactTextString(m) }
+func (*ShardPlacement) ProtoMessage()               {}
+func (*ShardPlacement) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{3} }
+
+func (m *ShardPlacement) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *ShardPlacement) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *ShardPlacement) GetStrategy() string {
+	if m != nil && m.Strategy != nil {
+		return *m.Strategy
+	}
+	return ""
+}
+
 type RoleInfo struct {
-	Name             *string        `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Permissions      *UserPrivilege `protobuf:"bytes,2,req,name=Permissions" json:"Permissions,omitempty"`
-	Users            *UserInfo      `protobuf:"bytes,3,req,name=Users" json:"Users,omitempty"`
-	XXX_unrecognized []byte         `json:"-"`
+	Name             *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Permissions      []*ScopedPermission `protobuf:"bytes,2,rep,name=Permissions" json:"Permissions,omitempty"`
+	Users            []string            `protobuf:"bytes,3,rep,name=Users" json:"Users,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
 }
 
 func (m *RoleInfo) Reset()                    { *m = RoleInfo{} }
 func (m *RoleInfo) String() string            { return proto.CompactTextString(m) }
 func (*RoleInfo) ProtoMessage()               {}
-func (*RoleInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{2} }
+func (*RoleInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{4} }
 
 func (m *RoleInfo) GetName() string {
 	if m != nil && m.Name != nil {
@@ -356,14 +459,14 @@ func (m *RoleInfo) GetName() string {
 	return ""
 }
 
-func (m *RoleInfo) GetPermissions() *UserPrivilege {
+func (m *RoleInfo) GetPermissions() []*ScopedPermission {
 	if m != nil {
 		return m.Permissions
 	}
 	return nil
 }
 
-func (m *RoleInfo) GetUsers() *UserInfo {
+func (m *RoleInfo) GetUsers() []string {
 	if m != nil {
 		return m.Users
 	}
@@ -371,16 +474,15 @@ func (m *RoleInfo) GetUsers() *UserInfo {
 }
 
 type UserInfo struct {
-	Name             *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Hash             *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
-	Permissions      []*UserPrivilege `protobuf:"bytes,3,rep,name=Permissions" json:"Permissions,omitempty"`
-	XXX_unrecognized []byte           `json:"-"`
+	Name             *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Permissions      []*ScopedPermission `protobuf:"bytes,3,rep,name=Permissions" json:"Permissions,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
 }
 
 func (m *UserInfo) Reset()                    { *m = UserInfo{} }
 func (m *UserInfo) String() string            { return proto.CompactTextString(m) }
 func (*UserInfo) ProtoMessage()               {}
-func (*UserInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{3} }
+func (*UserInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{5} }
 
 func (m *UserInfo) GetName() string {
 	if m != nil && m.Name != nil {
@@ -389,14 +491,7 @@ func (m *UserInfo) GetName() string {
 	return ""
 }
 
-func (m *UserInfo) GetHash() string {
-	if m != nil && m.Hash != nil {
-		return *m.Hash
-	}
-	return ""
-}
-
-func (m *UserInfo) GetPermissions() []*UserPrivilege {
+func (m *UserInfo) GetPermissions() []*ScopedPermission {
 	if m != nil {
 		return m.Permissions
 	}
@@ -412,7 +507,7 @@ type UserPrivilege struct {
 func (m *UserPrivilege) Reset()                    { *m = UserPrivilege{} }
 func (m *UserPrivilege) String() string            { return proto.CompactTextString(m) }
 func (*UserPrivilege) ProtoMessage()               {}
-func (*UserPrivilege) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{4} }
+func (*UserPrivilege) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{6} }
 
 func (m *UserPrivilege) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -429,24 +524,24 @@ func (m *UserPrivilege) GetPrivilege() int32 {
 }
 
 type ScopedPermission struct {
-	Resources        []byte         `protobuf:"bytes,1,req,name=Resources" json:"Resources,omitempty"`
-	Permissions      *UserPrivilege `protobuf:"bytes,2,req,name=Permissions" json:"Permissions,omitempty"`
-	XXX_unrecognized []byte         `json:"-"`
+	Resource         *string  `protobuf:"bytes,1,req,name=Resource" json:"Resource,omitempty"`
+	Permissions      []string `protobuf:"bytes,2,rep,name=Permissions" json:"Permissions,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
 }
 
 func (m *ScopedPermission) Reset()                    { *m = ScopedPermission{} }
 func (m *ScopedPermission) String() string            { return proto.CompactTextString(m) }
 func (*ScopedPermission) ProtoMessage()               {}
-func (*ScopedPermission) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{5} }
+func (*ScopedPermission) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{7} }
 
-func (m *ScopedPermission) GetResources() []byte {
-	if m != nil {
-		return m.Resources
+func (m *ScopedPermission) GetResource() string {
+	if m != nil && m.Resource != nil {
+		return *m.Resource
 	}
-	return nil
+	return ""
 }
 
-func (m *ScopedPermission) GetPermissions() *UserPrivilege {
+func (m *ScopedPermission) GetPermissions() []string {
 	if m != nil {
 		return m.Permissions
 	}
@@ -454,16 +549,17 @@ func (m *ScopedPermission) GetPermissions() *UserPrivilege {
 }
 
 type Response struct {
-	OK               *bool   `protobuf:"varint,1,req,name=OK" json:"OK,omitempty"`
-	Error            *string `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
-	Index            *uint64 `protobuf:"varint,3,opt,name=Index" json:"Index,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	OK               *bool             `protobuf:"varint,1,req,name=OK" json:"OK,omitempty"`
+	Error            *string           `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
+	Index            *uint64           `protobuf:"varint,3,opt,name=Index" json:"Index,omitempty"`
+	ShardIDs         []*ShardIDMapping `protobuf:"bytes,4,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	XXX_unrecognized []byte            `json:"-"`
 }
 
 func (m *Response) Reset()                    { *m = Response{} }
 func (m *Response) String() string            { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()               {}
-func (*Response) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{6} }
+func (*Response) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{8} }
 
 func (m *Response) GetOK() bool {
 	if m != nil && m.OK != nil {
@@ -486,6 +582,38 @@ func (m *Response) GetIndex() uint64 {
 	return 0
 }
 
+func (m *Response) GetShardIDs() []*ShardIDMapping {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+type ShardIDMapping struct {
+	OldID            *uint64 `protobuf:"varint,1,req,name=OldID" json:"OldID,omitempty"`
+	NewID            *uint64 `protobuf:"varint,2,req,name=NewID" json:"NewID,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *ShardIDMapping) Reset()                    { *m = ShardIDMapping{} }
+func (m *ShardIDMapping) String() string            { return proto.CompactTextString(m) }
+func (*ShardIDMapping) ProtoMessage()               {}
+func (*ShardIDMapping) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{9} }
+
+func (m *ShardIDMapping) GetOldID() uint64 {
+	if m != nil && m.OldID != nil {
+		return *m.OldID
+	}
+	return 0
+}
+
+func (m *ShardIDMapping) GetNewID() uint64 {
+	if m != nil && m.NewID != nil {
+		return *m.NewID
+	}
+	return 0
+}
+
 type Command struct {
 	Type                         *Command_Type `protobuf:"varint,1,req,name=type,enum=internal.Command_Type" json:"type,omitempty"`
 	proto.XXX_InternalExtensions `json:"-"`
@@ -495,7 +623,7 @@ type Command struct {
 func (m *Command) Reset()                    { *m = Command{} }
 func (m *Command) String() string            { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()               {}
-func (*Command) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{7} }
+func (*Command) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{10} }
 
 var extRange_Command = []proto.ExtensionRange{
 	{Start: 100, End: 536870911},
@@ -521,7 +649,7 @@ type CreateDatabaseCommand struct {
 func (m *CreateDatabaseCommand) Reset()                    { *m = CreateDatabaseCommand{} }
 func (m *CreateDatabaseCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateDatabaseCommand) ProtoMessage()               {}
-func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{8} }
+func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{11} }
 
 func (m *CreateDatabaseCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -553,7 +681,7 @@ type DropDatabaseCommand struct {
 func (m *DropDatabaseCommand) Reset()                    { *m = DropDatabaseCommand{} }
 func (m *DropDatabaseCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropDatabaseCommand) ProtoMessage()               {}
-func (*DropDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{9} }
+func (*DropDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{12} }
 
 func (m *DropDatabaseCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -580,7 +708,7 @@ func (m *CreateRetentionPolicyCommand) Reset()         { *m = CreateRetentionPol
 func (m *CreateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateRetentionPolicyCommand) ProtoMessage()    {}
 func (*CreateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{10}
+	return fileDescriptorMeta, []int{13}
 }
 
 func (m *CreateRetentionPolicyCommand) GetDatabase() string {
@@ -614,7 +742,7 @@ type DropRetentionPolicyCommand struct {
 func (m *DropRetentionPolicyCommand) Reset()                    { *m = DropRetentionPolicyCommand{} }
 func (m *DropRetentionPolicyCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropRetentionPolicyCommand) ProtoMessage()               {}
-func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{11} }
+func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{14} }
 
 func (m *DropRetentionPolicyCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -648,7 +776,7 @@ func (m *SetDefaultRetentionPolicyCommand) Reset()         { *m = SetDefaultRete
 func (m *SetDefaultRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDefaultRetentionPolicyCommand) ProtoMessage()    {}
 func (*SetDefaultRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{12}
+	return fileDescriptorMeta, []int{15}
 }
 
 func (m *SetDefaultRetentionPolicyCommand) GetDatabase() string {
@@ -686,7 +814,7 @@ func (m *UpdateRetentionPolicyCommand) Reset()         { *m = UpdateRetentionPol
 func (m *UpdateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateRetentionPolicyCommand) ProtoMessage()    {}
 func (*UpdateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{13}
+	return fileDescriptorMeta, []int{16}
 }
 
 func (m *UpdateRetentionPolicyCommand) GetDatabase() string {
@@ -742,7 +870,7 @@ type CreateShardGroupCommand struct {
 func (m *CreateShardGroupCommand) Reset()                    { *m = CreateShardGroupCommand{} }
 func (m *CreateShardGroupCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateShardGroupCommand) ProtoMessage()               {}
-func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{14} }
+func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{17} }
 
 func (m *CreateShardGroupCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -783,7 +911,7 @@ type DeleteShardGroupCommand struct {
 func (m *DeleteShardGroupCommand) Reset()                    { *m = DeleteShardGroupCommand{} }
 func (m *DeleteShardGroupCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteShardGroupCommand) ProtoMessage()               {}
-func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{15} }
+func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{18} }
 
 func (m *DeleteShardGroupCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -825,7 +953,7 @@ func (m *CreateContinuousQueryCommand) Reset()         { *m = CreateContinuousQu
 func (m *CreateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateContinuousQueryCommand) ProtoMessage()    {}
 func (*CreateContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{16}
+	return fileDescriptorMeta, []int{19}
 }
 
 func (m *CreateContinuousQueryCommand) GetDatabase() string {
@@ -866,7 +994,7 @@ type DropContinuousQueryCommand struct {
 func (m *DropContinuousQueryCommand) Reset()                    { *m = DropContinuousQueryCommand{} }
 func (m *DropContinuousQueryCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropContinuousQueryCommand) ProtoMessage()               {}
-func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{17} }
+func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{20} }
 
 func (m *DropContinuousQueryCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -900,7 +1028,7 @@ type CreateUserCommand struct {
 func (m *CreateUserCommand) Reset()                    { *m = CreateUserCommand{} }
 func (m *CreateUserCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateUserCommand) ProtoMessage()               {}
-func (*CreateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{18} }
+func (*CreateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{21} }
 
 func (m *CreateUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -939,7 +1067,7 @@ type DropUserCommand struct {
 func (m *DropUserCommand) Reset()                    { *m = DropUserCommand{} }
 func (m *DropUserCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropUserCommand) ProtoMessage()               {}
-func (*DropUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{19} }
+func (*DropUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{22} }
 
 func (m *DropUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -965,7 +1093,7 @@ type UpdateUserCommand struct {
 func (m *UpdateUserCommand) Reset()                    { *m = UpdateUserCommand{} }
 func (m *UpdateUserCommand) String() string            { return proto.CompactTextString(m) }
 func (*UpdateUserCommand) ProtoMessage()               {}
-func (*UpdateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{20} }
+func (*UpdateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{23} }
 
 func (m *UpdateUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -999,7 +1127,7 @@ type SetPrivilegeCommand struct {
 func (m *SetPrivilegeCommand) Reset()                    { *m = SetPrivilegeCommand{} }
 func (m *SetPrivilegeCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetPrivilegeCommand) ProtoMessage()               {}
-func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{21} }
+func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{24} }
 
 func (m *SetPrivilegeCommand) GetUsername() string {
 	if m != nil && m.Username != nil {
@@ -1031,13 +1159,21 @@ var E_SetPrivilegeCommand_Command = &proto.ExtensionDesc{
 }
 
 type CreateRoleCommand struct {
-	XXX_unrecognized []byte `json:"-"`
+	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *CreateRoleCommand) Reset()                    { *m = CreateRoleCommand{} }
 func (m *CreateRoleCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateRoleCommand) ProtoMessage()               {}
-func (*CreateRoleCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{22} }
+func (*CreateRoleCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{25} }
+
+func (m *CreateRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
 
 var E_CreateRoleCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
@@ -1048,13 +1184,21 @@ var E_CreateRoleCommand_Command = &proto.ExtensionDesc{
 }
 
 type DropRoleCommand struct {
-	XXX_unrecognized []byte `json:"-"`
+	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *DropRoleCommand) Reset()                    { *m = DropRoleCommand{} }
 func (m *DropRoleCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropRoleCommand) ProtoMessage()               {}
-func (*DropRoleCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{23} }
+func (*DropRoleCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{26} }
+
+func (m *DropRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
 
 var E_DropRoleCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
@@ -1065,13 +1209,29 @@ var E_DropRoleCommand_Command = &proto.ExtensionDesc{
 }
 
 type AddRoleUsersCommand struct {
-	XXX_unrecognized []byte `json:"-"`
+	Name             *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Users            []string `protobuf:"bytes,2,rep,name=Users" json:"Users,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
 }
 
 func (m *AddRoleUsersCommand) Reset()                    { *m = AddRoleUsersCommand{} }
 func (m *AddRoleUsersCommand) String() string            { return proto.CompactTextString(m) }
 func (*AddRoleUsersCommand) ProtoMessage()               {}
-func (*AddRoleUsersCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{24} }
+func (*AddRoleUsersCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{27} }
+
+func (m *AddRoleUsersCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *AddRoleUsersCommand) GetUsers() []string {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
 
 var E_AddRoleUsersCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
@@ -1082,13 +1242,29 @@ var E_AddRoleUsersCommand_Command = &proto.ExtensionDesc{
 }
 
 type RemoveRoleUsersCommand struct {
-	XXX_unrecognized []byte `json:"-"`
+	Name             *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Users            []string `protobuf:"bytes,2,rep,name=Users" json:"Users,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
 }
 
 func (m *RemoveRoleUsersCommand) Reset()                    { *m = RemoveRoleUsersCommand{} }
 func (m *RemoveRoleUsersCommand) String() string            { return proto.CompactTextString(m) }
 func (*RemoveRoleUsersCommand) ProtoMessage()               {}
-func (*RemoveRoleUsersCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{25} }
+func (*RemoveRoleUsersCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{28} }
+
+func (m *RemoveRoleUsersCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *RemoveRoleUsersCommand) GetUsers() []string {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
 
 var E_RemoveRoleUsersCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
@@ -1099,13 +1275,29 @@ var E_RemoveRoleUsersCommand_Command = &proto.ExtensionDesc{
 }
 
 type AddRolePermissionsCommand struct {
-	XXX_unrecognized []byte `json:"-"`
+	Name             *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Permissions      []*ScopedPermission `protobuf:"bytes,2,rep,name=Permissions" json:"Permissions,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
 }
 
 func (m *AddRolePermissionsCommand) Reset()                    { *m = AddRolePermissionsCommand{} }
 func (m *AddRolePermissionsCommand) String() string            { return proto.CompactTextString(m) }
 func (*AddRolePermissionsCommand) ProtoMessage()               {}
-func (*AddRolePermissionsCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{26} }
+func (*AddRolePermissionsCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{29} }
+
+func (m *AddRolePermissionsCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *AddRolePermissionsCommand) GetPermissions() []*ScopedPermission {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
 
 var E_AddRolePermissionsCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
@@ -1116,14 +1308,30 @@ var E_AddRolePermissionsCommand_Command = &proto.ExtensionDesc{
 }
 
 type RemoveRolePermissionsCommand struct {
-	XXX_unrecognized []byte `json:"-"`
+	Name             *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Permissions      []*ScopedPermission `protobuf:"bytes,2,rep,name=Permissions" json:"Permissions,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
 }
 
 func (m *RemoveRolePermissionsCommand) Reset()         { *m = RemoveRolePermissionsCommand{} }
 func (m *RemoveRolePermissionsCommand) String() string { return proto.CompactTextString(m) }
 func (*RemoveRolePermissionsCommand) ProtoMessage()    {}
 func (*RemoveRolePermissionsCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{27}
+	return fileDescriptorMeta, []int{30}
+}
+
+func (m *RemoveRolePermissionsCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *RemoveRolePermissionsCommand) GetPermissions() []*ScopedPermission {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
 }
 
 var E_RemoveRolePermissionsCommand_Command = &proto.ExtensionDesc{
@@ -1142,7 +1350,7 @@ type SetDataCommand struct {
 func (m *SetDataCommand) Reset()                    { *m = SetDataCommand{} }
 func (m *SetDataCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetDataCommand) ProtoMessage()               {}
-func (*SetDataCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{28} }
+func (*SetDataCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{31} }
 
 func (m *SetDataCommand) GetData() []byte {
 	if m != nil {
@@ -1168,7 +1376,7 @@ type SetAdminPrivilegeCommand struct {
 func (m *SetAdminPrivilegeCommand) Reset()                    { *m = SetAdminPrivilegeCommand{} }
 func (m *SetAdminPrivilegeCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetAdminPrivilegeCommand) ProtoMessage()               {}
-func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{29} }
+func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{32} }
 
 func (m *SetAdminPrivilegeCommand) GetUsername() string {
 	if m != nil && m.Username != nil {
@@ -1204,7 +1412,7 @@ type CreateSubscriptionCommand struct {
 func (m *CreateSubscriptionCommand) Reset()                    { *m = CreateSubscriptionCommand{} }
 func (m *CreateSubscriptionCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateSubscriptionCommand) ProtoMessage()               {}
-func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{30} }
+func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{33} }
 
 func (m *CreateSubscriptionCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1259,7 +1467,7 @@ type DropSubscriptionCommand struct {
 func (m *DropSubscriptionCommand) Reset()                    { *m = DropSubscriptionCommand{} }
 func (m *DropSubscriptionCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropSubscriptionCommand) ProtoMessage()               {}
-func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{31} }
+func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{34} }
 
 func (m *DropSubscriptionCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1299,7 +1507,7 @@ type RemovePeerCommand struct {
 func (m *RemovePeerCommand) Reset()                    { *m = RemovePeerCommand{} }
 func (m *RemovePeerCommand) String() string            { return proto.CompactTextString(m) }
 func (*RemovePeerCommand) ProtoMessage()               {}
-func (*RemovePeerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{32} }
+func (*RemovePeerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{35} }
 
 func (m *RemovePeerCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1333,7 +1541,7 @@ type CreateMetaNodeCommand struct {
 func (m *CreateMetaNodeCommand) Reset()                    { *m = CreateMetaNodeCommand{} }
 func (m *CreateMetaNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateMetaNodeCommand) ProtoMessage()               {}
-func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{33} }
+func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{36} }
 
 func (m *CreateMetaNodeCommand) GetHTTPAddr() string {
 	if m != nil && m.HTTPAddr != nil {
@@ -1365,15 +1573,16 @@ var E_CreateMetaNodeCommand_Command = &proto.ExtensionDesc{
 }
 
 type CreateDataNodeCommand struct {
-	HTTPAddr         *string `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
-	TCPAddr          *string `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	HTTPAddr         *string      `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
+	TCPAddr          *string      `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
+	Labels           []*NodeLabel `protobuf:"bytes,3,rep,name=Labels" json:"Labels,omitempty"`
+	XXX_unrecognized []byte       `json:"-"`
 }
 
 func (m *CreateDataNodeCommand) Reset()                    { *m = CreateDataNodeCommand{} }
 func (m *CreateDataNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateDataNodeCommand) ProtoMessage()               {}
-func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{34} }
+func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{37} }
 
 func (m *CreateDataNodeCommand) GetHTTPAddr() string {
 	if m != nil && m.HTTPAddr != nil {
@@ -1389,6 +1598,13 @@ func (m *CreateDataNodeCommand) GetTCPAddr() string {
 	return ""
 }
 
+func (m *CreateDataNodeCommand) GetLabels() []*NodeLabel {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 var E_CreateDataNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*CreateDataNodeCommand)(nil),
@@ -1398,16 +1614,17 @@ var E_CreateDataNodeCommand_Command = &proto.ExtensionDesc{
 }
 
 type UpdateDataNodeCommand struct {
-	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
-	Host             *string `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
-	TCPHost          *string `protobuf:"bytes,3,req,name=TCPHost" json:"TCPHost,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	ID               *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Host             *string      `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
+	TCPHost          *string      `protobuf:"bytes,3,req,name=TCPHost" json:"TCPHost,omitempty"`
+	Labels           []*NodeLabel `protobuf:"bytes,4,rep,name=Labels" json:"Labels,omitempty"`
+	XXX_unrecognized []byte       `json:"-"`
 }
 
 func (m *UpdateDataNodeCommand) Reset()                    { *m = UpdateDataNodeCommand{} }
 func (m *UpdateDataNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*UpdateDataNodeCommand) ProtoMessage()               {}
-func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{35} }
+func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{38} }
 
 func (m *UpdateDataNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1430,6 +1647,13 @@ func (m *UpdateDataNodeCommand) GetTCPHost() string {
 	return ""
 }
 
+func (m *UpdateDataNodeCommand) GetLabels() []*NodeLabel {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 var E_UpdateDataNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*UpdateDataNodeCommand)(nil),
@@ -1446,7 +1670,7 @@ type DeleteMetaNodeCommand struct {
 func (m *DeleteMetaNodeCommand) Reset()                    { *m = DeleteMetaNodeCommand{} }
 func (m *DeleteMetaNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteMetaNodeCommand) ProtoMessage()               {}
-func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{36} }
+func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{39} }
 
 func (m *DeleteMetaNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1471,7 +1695,7 @@ type DeleteDataNodeCommand struct {
 func (m *DeleteDataNodeCommand) Reset()                    { *m = DeleteDataNodeCommand{} }
 func (m *DeleteDataNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteDataNodeCommand) ProtoMessage()               {}
-func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{37} }
+func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{40} }
 
 func (m *DeleteDataNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1500,7 +1724,7 @@ type SetMetaNodeCommand struct {
 func (m *SetMetaNodeCommand) Reset()                    { *m = SetMetaNodeCommand{} }
 func (m *SetMetaNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetMetaNodeCommand) ProtoMessage()               {}
-func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{38} }
+func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{41} }
 
 func (m *SetMetaNodeCommand) GetHTTPAddr() string {
 	if m != nil && m.HTTPAddr != nil {
@@ -1539,7 +1763,7 @@ type DropShardCommand struct {
 func (m *DropShardCommand) Reset()                    { *m = DropShardCommand{} }
 func (m *DropShardCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropShardCommand) ProtoMessage()               {}
-func (*DropShardCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{39} }
+func (*DropShardCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{42} }
 
 func (m *DropShardCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1565,7 +1789,7 @@ type SetUserPasswordCommand struct {
 func (m *SetUserPasswordCommand) Reset()                    { *m = SetUserPasswordCommand{} }
 func (m *SetUserPasswordCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetUserPasswordCommand) ProtoMessage()               {}
-func (*SetUserPasswordCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{40} }
+func (*SetUserPasswordCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{43} }
 
 func (m *SetUserPasswordCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1590,15 +1814,15 @@ var E_SetUserPasswordCommand_Command = &proto.ExtensionDesc{
 }
 
 type AddUserPermissionsCommand struct {
-	Name             *string        `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Permissions      *UserPrivilege `protobuf:"bytes,2,req,name=Permissions" json:"Permissions,omitempty"`
-	XXX_unrecognized []byte         `json:"-"`
+	Name             *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Permissions      []*ScopedPermission `protobuf:"bytes,2,rep,name=Permissions" json:"Permissions,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
 }
 
 func (m *AddUserPermissionsCommand) Reset()                    { *m = AddUserPermissionsCommand{} }
 func (m *AddUserPermissionsCommand) String() string            { return proto.CompactTextString(m) }
 func (*AddUserPermissionsCommand) ProtoMessage()               {}
-func (*AddUserPermissionsCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{41} }
+func (*AddUserPermissionsCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{44} }
 
 func (m *AddUserPermissionsCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1607,7 +1831,7 @@ func (m *AddUserPermissionsCommand) GetName() string {
 	return ""
 }
 
-func (m *AddUserPermissionsCommand) GetPermissions() *UserPrivilege {
+func (m *AddUserPermissionsCommand) GetPermissions() []*ScopedPermission {
 	if m != nil {
 		return m.Permissions
 	}
@@ -1616,23 +1840,23 @@ func (m *AddUserPermissionsCommand) GetPermissions() *UserPrivilege {
 
 var E_AddUserPermissionsCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*AddUserPermissionsCommand)(nil),
 	Field:         134,
 	Name:          "internal.AddUserPermissionsCommand.command",
 	Tag:           "bytes,134,opt,name=command",
 }
 
 type RemoveUserPermissionsCommand struct {
-	Name             *string        `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Permissions      *UserPrivilege `protobuf:"bytes,2,req,name=Permissions" json:"Permissions,omitempty"`
-	XXX_unrecognized []byte         `json:"-"`
+	Name             *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Permissions      []*ScopedPermission `protobuf:"bytes,2,rep,name=Permissions" json:"Permissions,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
 }
 
 func (m *RemoveUserPermissionsCommand) Reset()         { *m = RemoveUserPermissionsCommand{} }
 func (m *RemoveUserPermissionsCommand) String() string { return proto.CompactTextString(m) }
 func (*RemoveUserPermissionsCommand) ProtoMessage()    {}
 func (*RemoveUserPermissionsCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{42}
+	return fileDescriptorMeta, []int{45}
 }
 
 func (m *RemoveUserPermissionsCommand) GetName() string {
@@ -1642,7 +1866,7 @@ func (m *RemoveUserPermissionsCommand) GetName() string {
 	return ""
 }
 
-func (m *RemoveUserPermissionsCommand) GetPermissions() *UserPrivilege {
+func (m *RemoveUserPermissionsCommand) GetPermissions() []*ScopedPermission {
 	if m != nil {
 		return m.Permissions
 	}
@@ -1651,7 +1875,7 @@ func (m *RemoveUserPermissionsCommand) GetPermissions() *UserPrivilege {
 
 var E_RemoveUserPermissionsCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*RemoveUserPermissionsCommand)(nil),
 	Field:         135,
 	Name:          "internal.RemoveUserPermissionsCommand.command",
 	Tag:           "bytes,135,opt,name=command",
@@ -1666,7 +1890,7 @@ type AddShardOwnerCommand struct {
 func (m *AddShardOwnerCommand) Reset()                    { *m = AddShardOwnerCommand{} }
 func (m *AddShardOwnerCommand) String() string            { return proto.CompactTextString(m) }
 func (*AddShardOwnerCommand) ProtoMessage()               {}
-func (*AddShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{43} }
+func (*AddShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{46} }
 
 func (m *AddShardOwnerCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1684,7 +1908,7 @@ func (m *AddShardOwnerCommand) GetNodeID() uint64 {
 
 var E_AddShardOwnerCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*AddShardOwnerCommand)(nil),
 	Field:         136,
 	Name:          "internal.AddShardOwnerCommand.command",
 	Tag:           "bytes,136,opt,name=command",
@@ -1699,7 +1923,7 @@ type RemoveShardOwnerCommand struct {
 func (m *RemoveShardOwnerCommand) Reset()                    { *m = RemoveShardOwnerCommand{} }
 func (m *RemoveShardOwnerCommand) String() string            { return proto.CompactTextString(m) }
 func (*RemoveShardOwnerCommand) ProtoMessage()               {}
-func (*RemoveShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{44} }
+func (*RemoveShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{47} }
 
 func (m *RemoveShardOwnerCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1717,7 +1941,7 @@ func (m *RemoveShardOwnerCommand) GetNodeID() uint64 {
 
 var E_RemoveShardOwnerCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*RemoveShardOwnerCommand)(nil),
 	Field:         137,
 	Name:          "internal.RemoveShardOwnerCommand.command",
 	Tag:           "bytes,137,opt,name=command",
@@ -1732,7 +1956,7 @@ type AddPendingShardOwnerCommand struct {
 func (m *AddPendingShardOwnerCommand) Reset()                    { *m = AddPendingShardOwnerCommand{} }
 func (m *AddPendingShardOwnerCommand) String() string            { return proto.CompactTextString(m) }
 func (*AddPendingShardOwnerCommand) ProtoMessage()               {}
-func (*AddPendingShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{45} }
+func (*AddPendingShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{48} }
 
 func (m *AddPendingShardOwnerCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1750,7 +1974,7 @@ func (m *AddPendingShardOwnerCommand) GetNodeID() uint64 {
 
 var E_AddPendingShardOwnerCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*AddPendingShardOwnerCommand)(nil),
 	Field:         138,
 	Name:          "internal.AddPendingShardOwnerCommand.command",
 	Tag:           "bytes,138,opt,name=command",
@@ -1766,7 +1990,7 @@ func (m *RemovePendingShardOwnerCommand) Reset()         { *m = RemovePendingSha
 func (m *RemovePendingShardOwnerCommand) String() string { return proto.CompactTextString(m) }
 func (*RemovePendingShardOwnerCommand) ProtoMessage()    {}
 func (*RemovePendingShardOwnerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{46}
+	return fileDescriptorMeta, []int{49}
 }
 
 func (m *RemovePendingShardOwnerCommand) GetID() uint64 {
@@ -1785,7 +2009,7 @@ func (m *RemovePendingShardOwnerCommand) GetNodeID() uint64 {
 
 var E_RemovePendingShardOwnerCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*RemovePendingShardOwnerCommand)(nil),
 	Field:         139,
 	Name:          "internal.RemovePendingShardOwnerCommand.command",
 	Tag:           "bytes,139,opt,name=command",
@@ -1801,7 +2025,7 @@ func (m *CommitPendingShardOwnerCommand) Reset()         { *m = CommitPendingSha
 func (m *CommitPendingShardOwnerCommand) String() string { return proto.CompactTextString(m) }
 func (*CommitPendingShardOwnerCommand) ProtoMessage()    {}
 func (*CommitPendingShardOwnerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{47}
+	return fileDescriptorMeta, []int{50}
 }
 
 func (m *CommitPendingShardOwnerCommand) GetID() uint64 {
@@ -1820,7 +2044,7 @@ func (m *CommitPendingShardOwnerCommand) GetNodeID() uint64 {
 
 var E_CommitPendingShardOwnerCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*CommitPendingShardOwnerCommand)(nil),
 	Field:         140,
 	Name:          "internal.CommitPendingShardOwnerCommand.command",
 	Tag:           "bytes,140,opt,name=command",
@@ -1834,7 +2058,7 @@ type TruncateShardGroupCommand struct {
 func (m *TruncateShardGroupCommand) Reset()                    { *m = TruncateShardGroupCommand{} }
 func (m *TruncateShardGroupCommand) String() string            { return proto.CompactTextString(m) }
 func (*TruncateShardGroupCommand) ProtoMessage()               {}
-func (*TruncateShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{48} }
+func (*TruncateShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{51} }
 
 func (m *TruncateShardGroupCommand) GetTruncateAt() uint64 {
 	if m != nil && m.TruncateAt != nil {
@@ -1845,7 +2069,7 @@ func (m *TruncateShardGroupCommand) GetTruncateAt() uint64 {
 
 var E_TruncateShardGroupCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*TruncateShardGroupCommand)(nil),
 	Field:         141,
 	Name:          "internal.TruncateShardGroupCommand.command",
 	Tag:           "bytes,141,opt,name=command",
@@ -1860,7 +2084,7 @@ type ChangeRoleNameCommand struct {
 func (m *ChangeRoleNameCommand) Reset()                    { *m = ChangeRoleNameCommand{} }
 func (m *ChangeRoleNameCommand) String() string            { return proto.CompactTextString(m) }
 func (*ChangeRoleNameCommand) ProtoMessage()               {}
-func (*ChangeRoleNameCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{49} }
+func (*ChangeRoleNameCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{52} }
 
 func (m *ChangeRoleNameCommand) GetOldName() string {
 	if m != nil && m.OldName != nil {
@@ -1878,7 +2102,7 @@ func (m *ChangeRoleNameCommand) GetNewName() string {
 
 var E_ChangeRoleNameCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*ChangeRoleNameCommand)(nil),
 	Field:         142,
 	Name:          "internal.ChangeRoleNameCommand.command",
 	Tag:           "bytes,142,opt,name=command",
@@ -1889,17 +2113,17 @@ type ImportDataCommand struct {
 	Force                *bool   `protobuf:"varint,2,req,name=Force" json:"Force,omitempty"`
 	Database             *string `protobuf:"bytes,3,req,name=Database" json:"Database,omitempty"`
 	RetentionPolicy      *string `protobuf:"bytes,4,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
-	ShardId              *uint64 `protobuf:"varint,5,req,name=ShardId" json:"ShardId,omitempty"`
-	NewDatabase          *string `protobuf:"bytes,6,req,name=NewDatabase" json:"NewDatabase,omitempty"`
-	NewRetentionPolicy   []byte  `protobuf:"bytes,7,req,name=NewRetentionPolicy" json:"NewRetentionPolicy,omitempty"`
-	NewReplicationFactor *uint64 `protobuf:"varint,8,req,name=NewReplicationFactor" json:"NewReplicationFactor,omitempty"`
+	ShardId              *uint64 `protobuf:"varint,5,opt,name=ShardId" json:"ShardId,omitempty"`
+	NewDatabase          *string `protobuf:"bytes,6,opt,name=NewDatabase" json:"NewDatabase,omitempty"`
+	NewRetentionPolicy   *string `protobuf:"bytes,7,opt,name=NewRetentionPolicy" json:"NewRetentionPolicy,omitempty"`
+	NewReplicationFactor *uint64 `protobuf:"varint,8,opt,name=NewReplicationFactor" json:"NewReplicationFactor,omitempty"`
 	XXX_unrecognized     []byte  `json:"-"`
 }
 
 func (m *ImportDataCommand) Reset()                    { *m = ImportDataCommand{} }
 func (m *ImportDataCommand) String() string            { return proto.CompactTextString(m) }
 func (*ImportDataCommand) ProtoMessage()               {}
-func (*ImportDataCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{50} }
+func (*ImportDataCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{53} }
 
 func (m *ImportDataCommand) GetData() []byte {
 	if m != nil {
@@ -1943,11 +2167,11 @@ func (m *ImportDataCommand) GetNewDatabase() string {
 	return ""
 }
 
-func (m *ImportDataCommand) GetNewRetentionPolicy() []byte {
-	if m != nil {
-		return m.NewRetentionPolicy
+func (m *ImportDataCommand) GetNewRetentionPolicy() string {
+	if m != nil && m.NewRetentionPolicy != nil {
+		return *m.NewRetentionPolicy
 	}
-	return nil
+	return ""
 }
 
 func (m *ImportDataCommand) GetNewReplicationFactor() uint64 {
@@ -1959,7 +2183,7 @@ func (m *ImportDataCommand) GetNewReplicationFactor() uint64 {
 
 var E_ImportDataCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*ImportDataCommand)(nil),
 	Field:         143,
 	Name:          "internal.ImportDataCommand.command",
 	Tag:           "bytes,143,opt,name=command",
@@ -1967,7 +2191,7 @@ var E_ImportDataCommand_Command = &proto.ExtensionDesc{
 
 type CreateBalancedShardGroupCommand struct {
 	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Policy           []byte  `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Policy           *string `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
 	Timestamp        *int64  `protobuf:"varint,3,req,name=Timestamp" json:"Timestamp,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
@@ -1976,7 +2200,7 @@ func (m *CreateBalancedShardGroupCommand) Reset()         { *m = CreateBalancedS
 func (m *CreateBalancedShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateBalancedShardGroupCommand) ProtoMessage()    {}
 func (*CreateBalancedShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{51}
+	return fileDescriptorMeta, []int{54}
 }
 
 func (m *CreateBalancedShardGroupCommand) GetDatabase() string {
@@ -1986,11 +2210,11 @@ func (m *CreateBalancedShardGroupCommand) GetDatabase() string {
 	return ""
 }
 
-func (m *CreateBalancedShardGroupCommand) GetPolicy() []byte {
-	if m != nil {
-		return m.Policy
+func (m *CreateBalancedShardGroupCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
 	}
-	return nil
+	return ""
 }
 
 func (m *CreateBalancedShardGroupCommand) GetTimestamp() int64 {
@@ -2002,20 +2226,279 @@ func (m *CreateBalancedShardGroupCommand) GetTimestamp() int64 {
 
 var E_CreateBalancedShardGroupCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
+	ExtensionType: (*CreateBalancedShardGroupCommand)(nil),
 	Field:         144,
 	Name:          "internal.CreateBalancedShardGroupCommand.command",
 	Tag:           "bytes,144,opt,name=command",
 }
 
+type SetShardPlacementCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy           *string `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	Strategy         *string `protobuf:"bytes,3,req,name=Strategy" json:"Strategy,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetShardPlacementCommand) Reset()                    { *m = SetShardPlacementCommand{} }
+func (m *SetShardPlacementCommand) String() string            { return proto.CompactTextString(m) }
+func (*SetShardPlacementCommand) ProtoMessage()               {}
+func (*SetShardPlacementCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{55} }
+
+func (m *SetShardPlacementCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetShardPlacementCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *SetShardPlacementCommand) GetStrategy() string {
+	if m != nil && m.Strategy != nil {
+		return *m.Strategy
+	}
+	return ""
+}
+
+var E_SetShardPlacementCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetShardPlacementCommand)(nil),
+	Field:         145,
+	Name:          "internal.SetShardPlacementCommand.command",
+	Tag:           "bytes,145,opt,name=command",
+}
+
+type SetDataNodeDiskUsageCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	DiskUsage        *uint64 `protobuf:"varint,2,req,name=DiskUsage" json:"DiskUsage,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetDataNodeDiskUsageCommand) Reset()         { *m = SetDataNodeDiskUsageCommand{} }
+func (m *SetDataNodeDiskUsageCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDataNodeDiskUsageCommand) ProtoMessage()    {}
+func (*SetDataNodeDiskUsageCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptorMeta, []int{56}
+}
+
+func (m *SetDataNodeDiskUsageCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *SetDataNodeDiskUsageCommand) GetDiskUsage() uint64 {
+	if m != nil && m.DiskUsage != nil {
+		return *m.DiskUsage
+	}
+	return 0
+}
+
+var E_SetDataNodeDiskUsageCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDataNodeDiskUsageCommand)(nil),
+	Field:         146,
+	Name:          "internal.SetDataNodeDiskUsageCommand.command",
+	Tag:           "bytes,146,opt,name=command",
+}
+
+type SplitShardGroupCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy           *string `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	ShardGroupID     *uint64 `protobuf:"varint,3,req,name=ShardGroupID" json:"ShardGroupID,omitempty"`
+	Timestamp        *int64  `protobuf:"varint,4,req,name=Timestamp" json:"Timestamp,omitempty"`
+	ShardN           *uint64 `protobuf:"varint,5,opt,name=ShardN" json:"ShardN,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SplitShardGroupCommand) Reset()                    { *m = SplitShardGroupCommand{} }
+func (m *SplitShardGroupCommand) String() string            { return proto.CompactTextString(m) }
+func (*SplitShardGroupCommand) ProtoMessage()               {}
+func (*SplitShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{57} }
+
+func (m *SplitShardGroupCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SplitShardGroupCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *SplitShardGroupCommand) GetShardGroupID() uint64 {
+	if m != nil && m.ShardGroupID != nil {
+		return *m.ShardGroupID
+	}
+	return 0
+}
+
+func (m *SplitShardGroupCommand) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *SplitShardGroupCommand) GetShardN() uint64 {
+	if m != nil && m.ShardN != nil {
+		return *m.ShardN
+	}
+	return 0
+}
+
+var E_SplitShardGroupCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SplitShardGroupCommand)(nil),
+	Field:         147,
+	Name:          "internal.SplitShardGroupCommand.command",
+	Tag:           "bytes,147,opt,name=command",
+}
+
+type RestoreShardGroupCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Policy           *string `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
+	StartTime        *int64  `protobuf:"varint,3,req,name=StartTime" json:"StartTime,omitempty"`
+	EndTime          *int64  `protobuf:"varint,4,req,name=EndTime" json:"EndTime,omitempty"`
+	ShardN           *uint64 `protobuf:"varint,5,req,name=ShardN" json:"ShardN,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *RestoreShardGroupCommand) Reset()                    { *m = RestoreShardGroupCommand{} }
+func (m *RestoreShardGroupCommand) String() string            { return proto.CompactTextString(m) }
+func (*RestoreShardGroupCommand) ProtoMessage()               {}
+func (*RestoreShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{58} }
+
+func (m *RestoreShardGroupCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *RestoreShardGroupCommand) GetPolicy() string {
+	if m != nil && m.Policy != nil {
+		return *m.Policy
+	}
+	return ""
+}
+
+func (m *RestoreShardGroupCommand) GetStartTime() int64 {
+	if m != nil && m.StartTime != nil {
+		return *m.StartTime
+	}
+	return 0
+}
+
+func (m *RestoreShardGroupCommand) GetEndTime() int64 {
+	if m != nil && m.EndTime != nil {
+		return *m.EndTime
+	}
+	return 0
+}
+
+func (m *RestoreShardGroupCommand) GetShardN() uint64 {
+	if m != nil && m.ShardN != nil {
+		return *m.ShardN
+	}
+	return 0
+}
+
+var E_RestoreShardGroupCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*RestoreShardGroupCommand)(nil),
+	Field:         148,
+	Name:          "internal.RestoreShardGroupCommand.command",
+	Tag:           "bytes,148,opt,name=command",
+}
+
+type SnapshotDelta struct {
+	Snapshot            []byte      `protobuf:"bytes,1,opt,name=Snapshot" json:"Snapshot,omitempty"`
+	Logs                []*LogEntry `protobuf:"bytes,2,rep,name=Logs" json:"Logs,omitempty"`
+	RetentionAutoCreate *bool       `protobuf:"varint,3,opt,name=RetentionAutoCreate" json:"RetentionAutoCreate,omitempty"`
+	XXX_unrecognized    []byte      `json:"-"`
+}
+
+func (m *SnapshotDelta) Reset()                    { *m = SnapshotDelta{} }
+func (m *SnapshotDelta) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotDelta) ProtoMessage()               {}
+func (*SnapshotDelta) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{59} }
+
+func (m *SnapshotDelta) GetSnapshot() []byte {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func (m *SnapshotDelta) GetLogs() []*LogEntry {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+func (m *SnapshotDelta) GetRetentionAutoCreate() bool {
+	if m != nil && m.RetentionAutoCreate != nil {
+		return *m.RetentionAutoCreate
+	}
+	return false
+}
+
+type LogEntry struct {
+	Index            *uint64 `protobuf:"varint,1,req,name=Index" json:"Index,omitempty"`
+	Term             *uint64 `protobuf:"varint,2,req,name=Term" json:"Term,omitempty"`
+	Data             []byte  `protobuf:"bytes,3,req,name=Data" json:"Data,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *LogEntry) Reset()                    { *m = LogEntry{} }
+func (m *LogEntry) String() string            { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()               {}
+func (*LogEntry) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{60} }
+
+func (m *LogEntry) GetIndex() uint64 {
+	if m != nil && m.Index != nil {
+		return *m.Index
+	}
+	return 0
+}
+
+func (m *LogEntry) GetTerm() uint64 {
+	if m != nil && m.Term != nil {
+		return *m.Term
+	}
+	return 0
+}
+
+func (m *LogEntry) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ClusterData)(nil), "internal.ClusterData")
 	proto.RegisterType((*NodeInfo)(nil), "internal.NodeInfo")
+	proto.RegisterType((*NodeLabel)(nil), "internal.NodeLabel")
+	proto.RegisterType((*ShardPlacement)(nil), "internal.ShardPlacement")
 	proto.RegisterType((*RoleInfo)(nil), "internal.RoleInfo")
 	proto.RegisterType((*UserInfo)(nil), "internal.UserInfo")
 	proto.RegisterType((*UserPrivilege)(nil), "internal.UserPrivilege")
 	proto.RegisterType((*ScopedPermission)(nil), "internal.ScopedPermission")
 	proto.RegisterType((*Response)(nil), "internal.Response")
+	proto.RegisterType((*ShardIDMapping)(nil), "internal.ShardIDMapping")
 	proto.RegisterType((*Command)(nil), "internal.Command")
 	proto.RegisterType((*CreateDatabaseCommand)(nil), "internal.CreateDatabaseCommand")
 	proto.RegisterType((*DropDatabaseCommand)(nil), "internal.DropDatabaseCommand")
@@ -2061,6 +2544,12 @@ func init() {
 	proto.RegisterType((*ChangeRoleNameCommand)(nil), "internal.ChangeRoleNameCommand")
 	proto.RegisterType((*ImportDataCommand)(nil), "internal.ImportDataCommand")
 	proto.RegisterType((*CreateBalancedShardGroupCommand)(nil), "internal.CreateBalancedShardGroupCommand")
+	proto.RegisterType((*SetShardPlacementCommand)(nil), "internal.SetShardPlacementCommand")
+	proto.RegisterType((*SetDataNodeDiskUsageCommand)(nil), "internal.SetDataNodeDiskUsageCommand")
+	proto.RegisterType((*SplitShardGroupCommand)(nil), "internal.SplitShardGroupCommand")
+	proto.RegisterType((*RestoreShardGroupCommand)(nil), "internal.RestoreShardGroupCommand")
+	proto.RegisterType((*SnapshotDelta)(nil), "internal.SnapshotDelta")
+	proto.RegisterType((*LogEntry)(nil), "internal.LogEntry")
 	proto.RegisterEnum("internal.Command_Type", Command_Type_name, Command_Type_value)
 	proto.RegisterExtension(E_CreateDatabaseCommand_Command)
 	proto.RegisterExtension(E_DropDatabaseCommand_Command)
@@ -2106,133 +2595,169 @@ func init() {
 	proto.RegisterExtension(E_ChangeRoleNameCommand_Command)
 	proto.RegisterExtension(E_ImportDataCommand_Command)
 	proto.RegisterExtension(E_CreateBalancedShardGroupCommand_Command)
+	proto.RegisterExtension(E_SetShardPlacementCommand_Command)
+	proto.RegisterExtension(E_SetDataNodeDiskUsageCommand_Command)
+	proto.RegisterExtension(E_SplitShardGroupCommand_Command)
+	proto.RegisterExtension(E_RestoreShardGroupCommand_Command)
 }
 
 func init() { proto.RegisterFile("internal/meta.proto", fileDescriptorMeta) }
 
 var fileDescriptorMeta = []byte{
-	// 1964 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0xcd, 0x6f, 0x1b, 0xc7,
-	0x15, 0xc7, 0xf0, 0x43, 0x22, 0x47, 0xb2, 0x2c, 0x8f, 0x68, 0x69, 0xf5, 0x61, 0x89, 0xa6, 0x5d,
-	0x97, 0x55, 0x0b, 0xb5, 0xe0, 0xad, 0x47, 0x55, 0xb4, 0x6a, 0xd6, 0x95, 0x44, 0x2f, 0x65, 0x14,
-	0x3d, 0xf4, 0xb0, 0xe6, 0x8e, 0xa5, 0xad, 0xc9, 0x5d, 0x76, 0x77, 0xa9, 0x8f, 0xb6, 0x49, 0xe4,
-	0x38, 0x8e, 0x13, 0xc7, 0xce, 0x07, 0x10, 0x20, 0x87, 0x20, 0xc8, 0x21, 0x39, 0x25, 0x40, 0x72,
-	0x4b, 0x10, 0xe4, 0x10, 0x04, 0xf9, 0x77, 0x02, 0xe4, 0x14, 0xe4, 0x98, 0x60, 0x66, 0x77, 0x38,
-	0xfb, 0x31, 0x3b, 0xab, 0x75, 0x94, 0x9c, 0xc4, 0x79, 0xef, 0xcd, 0x7b, 0xbf, 0xf7, 0xe6, 0xed,
-	0xdb, 0x37, 0x6f, 0x05, 0x67, 0x0c, 0xd3, 0xc5, 0xb6, 0xa9, 0xf5, 0xfe, 0xd8, 0xc7, 0xae, 0xb6,
-	0x36, 0xb0, 0x2d, 0xd7, 0x42, 0x25, 0x46, 0xac, 0xfd, 0x00, 0xe0, 0xc4, 0x46, 0x6f, 0xe8, 0xb8,
-	0xd8, 0x6e, 0x6a, 0xae, 0x86, 0x10, 0x2c, 0x90, 0xbf, 0x0a, 0xa8, 0xe6, 0xea, 0x93, 0x2a, 0xfd,
-	0x8d, 0x96, 0x60, 0x79, 0x4b, 0x3b, 0xda, 0xb6, 0x74, 0xdc, 0x6a, 0x2a, 0xb9, 0x6a, 0xae, 0x5e,
-	0x50, 0x39, 0x01, 0xfd, 0x09, 0x96, 0x89, 0x14, 0x59, 0x39, 0x4a, 0xbe, 0x9a, 0xaf, 0x4f, 0x34,
-	0xd0, 0x1a, 0xd3, 0xbf, 0x46, 0x85, 0xcc, 0xbb, 0x96, 0xca, 0x85, 0xc8, 0x8e, 0x2d, 0xcc, 0x76,
-	0x14, 0x92, 0x77, 0x8c, 0x84, 0x50, 0x1d, 0x16, 0x55, 0xab, 0x87, 0x1d, 0xa5, 0x18, 0x95, 0x26,
-	0x64, 0x2a, 0xed, 0x09, 0x10, 0xc9, 0xdb, 0x0e, 0xb6, 0x1d, 0x65, 0x2c, 0x2a, 0x49, 0xc8, 0x9e,
-	0x24, 0x15, 0xa8, 0x1d, 0xc1, 0x12, 0x33, 0x85, 0xa6, 0x60, 0xae, 0xd5, 0xa4, 0x3e, 0x17, 0xd4,
-	0x5c, 0xab, 0x49, 0xa2, 0x70, 0xc3, 0x72, 0x5c, 0xea, 0x6c, 0x59, 0xa5, 0xbf, 0x91, 0x02, 0xc7,
-	0x77, 0x37, 0xda, 0x94, 0x9c, 0xaf, 0x82, 0x7a, 0x59, 0x65, 0x4b, 0xb4, 0x06, 0x51, 0x1b, 0x9b,
-	0xba, 0x61, 0xee, 0x75, 0xf6, 0x35, 0x5b, 0xdf, 0x39, 0x34, 0x09, 0x00, 0xe2, 0x58, 0x41, 0x15,
-	0x70, 0x6a, 0x0f, 0x00, 0x2c, 0x31, 0xdc, 0xc4, 0xd4, 0xb6, 0xd6, 0xc7, 0xd4, 0x78, 0x59, 0xa5,
-	0xbf, 0xd1, 0x9f, 0xe1, 0x44, 0x1b, 0xdb, 0x7d, 0xc3, 0x71, 0x0c, 0xcb, 0x74, 0x28, 0x8a, 0x89,
-	0xc6, 0x5c, 0xd8, 0x95, 0xb6, 0x6d, 0x1c, 0x18, 0x3d, 0xbc, 0x87, 0xd5, 0xa0, 0x2c, 0xf7, 0x3f,
-	0x4f, 0x37, 0x49, 0xfc, 0xef, 0xc3, 0x12, 0x23, 0x09, 0x41, 0x90, 0x18, 0x68, 0xce, 0xfe, 0x28,
-	0x06, 0x9a, 0xb3, 0x1f, 0x05, 0xe6, 0x9d, 0xf6, 0xa9, 0x80, 0xd5, 0x5a, 0xf0, 0x5c, 0x88, 0x8b,
-	0x16, 0x60, 0x89, 0xa4, 0xc4, 0x1d, 0xcd, 0x61, 0x76, 0x47, 0x6b, 0x92, 0x71, 0x23, 0x41, 0x0a,
-	0xa0, 0xa8, 0x72, 0x42, 0xed, 0x1e, 0x9c, 0xee, 0x74, 0xad, 0x01, 0xd6, 0xb9, 0x7e, 0xb2, 0x43,
-	0xc5, 0x8e, 0x35, 0xb4, 0xbb, 0xd8, 0xf1, 0x93, 0x97, 0x13, 0x7e, 0x46, 0x40, 0x6b, 0x9b, 0xb0,
-	0xa4, 0x62, 0x67, 0x60, 0x99, 0x0e, 0x26, 0x69, 0xb2, 0x73, 0x93, 0x6a, 0x2f, 0xa9, 0xb9, 0x9d,
-	0x9b, 0xa8, 0x02, 0x8b, 0xd7, 0x6d, 0xdb, 0xb2, 0x95, 0x1c, 0x4d, 0x08, 0x6f, 0x41, 0xa8, 0x2d,
-	0x53, 0xc7, 0x47, 0x34, 0x4d, 0x0a, 0xaa, 0xb7, 0xa8, 0x7d, 0x02, 0xe1, 0xf8, 0x86, 0xd5, 0xef,
-	0x6b, 0xa6, 0x8e, 0x56, 0x61, 0xc1, 0x3d, 0x1e, 0x78, 0x6e, 0x4f, 0x35, 0x66, 0x39, 0x0e, 0x5f,
-	0x60, 0x6d, 0xf7, 0x78, 0x80, 0x55, 0x2a, 0x53, 0xfb, 0xb6, 0x0c, 0x0b, 0x64, 0x89, 0xe6, 0xe1,
-	0xc5, 0x0d, 0x1b, 0x6b, 0x2e, 0x66, 0x51, 0xf2, 0x85, 0xa7, 0x01, 0x9a, 0x83, 0x33, 0x4d, 0xdb,
-	0x1a, 0x44, 0x19, 0x39, 0x54, 0x85, 0x4b, 0xde, 0x1e, 0x15, 0xbb, 0xd8, 0x74, 0x0d, 0xcb, 0x6c,
-	0x5b, 0x3d, 0xa3, 0x7b, 0xcc, 0x24, 0xf2, 0x68, 0x19, 0x2e, 0x90, 0xad, 0x09, 0xfc, 0x02, 0xba,
-	0x0a, 0xab, 0x1d, 0xec, 0x36, 0xf1, 0x5d, 0x6d, 0xd8, 0x73, 0x13, 0xa4, 0x8a, 0xc4, 0xce, 0xed,
-	0x81, 0x9e, 0x6c, 0x67, 0x0c, 0x2d, 0xc2, 0x39, 0x0f, 0x09, 0x7d, 0x10, 0xfe, 0x6a, 0x5b, 0xc3,
-	0x01, 0x63, 0x8e, 0x13, 0x66, 0x13, 0xf7, 0xb0, 0x88, 0x59, 0xe2, 0x3e, 0x6c, 0x58, 0xa6, 0x6b,
-	0x98, 0x43, 0x6b, 0xe8, 0xdc, 0x1a, 0x62, 0x7b, 0xa4, 0xbb, 0xcc, 0x7c, 0x48, 0xe0, 0x43, 0x74,
-	0x11, 0x5e, 0xf0, 0x34, 0x90, 0x63, 0x66, 0xe4, 0x09, 0x34, 0x03, 0xcf, 0x93, 0x6d, 0x41, 0xe2,
-	0x24, 0x91, 0xf5, 0x3c, 0x09, 0x92, 0xcf, 0x91, 0x08, 0x77, 0xb0, 0x3b, 0x4a, 0x11, 0xc6, 0x98,
-	0xe2, 0xba, 0xc9, 0x03, 0xcd, 0xc8, 0xe7, 0x99, 0xee, 0x20, 0x71, 0x9a, 0x28, 0x59, 0xd7, 0x75,
-	0x42, 0xa3, 0x4f, 0x20, 0x63, 0x5c, 0x40, 0x0b, 0x70, 0x56, 0xc5, 0x7d, 0xeb, 0x00, 0xc7, 0x78,
-	0x08, 0x5d, 0x82, 0xf3, 0xfe, 0xa6, 0x40, 0x56, 0x32, 0xf6, 0x0c, 0x89, 0x0e, 0xdf, 0x2a, 0x90,
-	0xa8, 0x20, 0x04, 0xa7, 0xc8, 0x09, 0x6a, 0xae, 0xc6, 0x68, 0x17, 0xd1, 0x12, 0x54, 0x3a, 0xd8,
-	0x5d, 0xd7, 0xfb, 0x86, 0x19, 0xf3, 0x69, 0x96, 0x98, 0xf4, 0xcf, 0x6a, 0x78, 0xc7, 0xe9, 0xda,
-	0xc6, 0x80, 0x1c, 0x28, 0x63, 0xcf, 0xd1, 0xd3, 0xb2, 0xad, 0x81, 0x88, 0xa9, 0x90, 0x78, 0x78,
-	0x78, 0xda, 0x98, 0xc7, 0x6f, 0x9e, 0x27, 0x2f, 0xab, 0xe9, 0x8c, 0xb5, 0x10, 0xce, 0xeb, 0x20,
-	0x6b, 0x91, 0xb0, 0xbc, 0xc3, 0x88, 0xb2, 0x96, 0x08, 0xcb, 0x4b, 0x99, 0xa8, 0xc2, 0x4b, 0x9c,
-	0x15, 0xdd, 0xb5, 0x8c, 0x66, 0x21, 0xea, 0x60, 0x37, 0xba, 0x65, 0x05, 0x55, 0xe0, 0x34, 0x75,
-	0x89, 0xa4, 0x1f, 0xa3, 0x56, 0x89, 0x2f, 0xad, 0xfe, 0xc0, 0xb2, 0x43, 0xc1, 0xbb, 0x4c, 0x4e,
-	0xab, 0x83, 0x5d, 0x5a, 0x32, 0x34, 0xc7, 0x39, 0xb4, 0xf8, 0x96, 0x9a, 0x7f, 0x5a, 0x94, 0x17,
-	0x3f, 0x8b, 0x2b, 0xfc, 0xb4, 0x12, 0x24, 0xae, 0x22, 0x05, 0x56, 0xd6, 0x75, 0x9d, 0xbf, 0x2d,
-	0x18, 0xe7, 0x37, 0x24, 0xec, 0xde, 0xde, 0x38, 0xf3, 0x1a, 0x5a, 0x81, 0x8b, 0xeb, 0xba, 0x1e,
-	0x7b, 0xd7, 0x30, 0x81, 0xdf, 0xa2, 0x1a, 0x5c, 0x26, 0x0b, 0xc3, 0x4d, 0x94, 0xa9, 0x13, 0x19,
-	0x76, 0x76, 0x09, 0x32, 0xbf, 0x23, 0xcf, 0xda, 0xae, 0x3d, 0x34, 0xbb, 0xa1, 0x27, 0x79, 0x84,
-	0x7f, 0x95, 0x9e, 0xe6, 0xbe, 0x66, 0xee, 0xd1, 0x7c, 0x24, 0xef, 0x11, 0xc6, 0xfa, 0x3d, 0xba,
-	0x02, 0x57, 0xbc, 0x83, 0xfe, 0x8b, 0xd6, 0xd3, 0xcc, 0x2e, 0xd6, 0xe3, 0x4f, 0xfb, 0x1f, 0x56,
-	0x4b, 0x25, 0x7d, 0xfa, 0xe4, 0xe4, 0xe4, 0x24, 0x57, 0xfb, 0x00, 0x24, 0x14, 0x3c, 0xe1, 0xdb,
-	0xaa, 0x0e, 0xcf, 0x47, 0x6a, 0x0f, 0x2d, 0xca, 0x93, 0x6a, 0x94, 0xdc, 0xf8, 0x3b, 0x1c, 0xef,
-	0xfa, 0x8a, 0x2e, 0xc4, 0x2a, 0xaf, 0x82, 0xab, 0xa0, 0x3e, 0xd1, 0x58, 0x09, 0x30, 0x44, 0x10,
-	0x54, 0xa6, 0xa2, 0x36, 0x14, 0x96, 0x5e, 0x11, 0xc4, 0xc6, 0xdf, 0xa4, 0x86, 0xef, 0x52, 0xc3,
-	0x97, 0x38, 0x43, 0xa0, 0x96, 0x9b, 0xfd, 0x02, 0xc8, 0x2b, 0xbb, 0xf4, 0xed, 0x2a, 0x8c, 0x55,
-	0x4e, 0x14, 0xab, 0x8e, 0x14, 0xf2, 0x1e, 0x85, 0x7c, 0x2d, 0x1a, 0x2b, 0x31, 0x22, 0x8e, 0xfd,
-	0x7d, 0x20, 0x7b, 0xe7, 0x48, 0x91, 0xb3, 0xb0, 0xe6, 0x02, 0x61, 0xbd, 0x25, 0xc5, 0xb8, 0x4f,
-	0x31, 0x5e, 0x0d, 0x87, 0x35, 0x0d, 0xe1, 0xc7, 0x20, 0xfd, 0xad, 0x97, 0x19, 0xe7, 0x3f, 0xa4,
-	0x38, 0x0d, 0x8a, 0x73, 0x95, 0x33, 0xd2, 0xec, 0x73, 0xb4, 0xdf, 0x03, 0xf9, 0xdb, 0x37, 0x2b,
-	0x52, 0xd2, 0xe9, 0x6e, 0xe3, 0x43, 0x4a, 0xf6, 0x3b, 0x5d, 0x7f, 0x49, 0x35, 0x0d, 0x6d, 0x8d,
-	0x98, 0x50, 0x0a, 0x55, 0x50, 0xcf, 0xab, 0xa3, 0x35, 0xe1, 0xa9, 0x78, 0xd0, 0x33, 0xba, 0xda,
-	0xb6, 0x52, 0xac, 0x82, 0xfa, 0x39, 0x75, 0xb4, 0x4e, 0xc9, 0xa3, 0x7f, 0x47, 0xf3, 0x48, 0xe6,
-	0x0d, 0xf7, 0xfb, 0x4b, 0x90, 0xd8, 0x53, 0x48, 0x5d, 0x9e, 0x85, 0x63, 0x81, 0xac, 0x2f, 0xab,
-	0xfe, 0x8a, 0xb4, 0x90, 0xbb, 0x46, 0x1f, 0x3b, 0xae, 0xd6, 0x1f, 0xd0, 0xf6, 0x39, 0xaf, 0x72,
-	0x42, 0x63, 0x5b, 0xea, 0xc2, 0x3d, 0xea, 0xc2, 0xe5, 0xe8, 0xa3, 0x10, 0x03, 0xc6, 0xd1, 0x7f,
-	0x0d, 0x12, 0x9b, 0x9e, 0x67, 0x42, 0x5f, 0x83, 0x93, 0x5c, 0x51, 0xab, 0x49, 0x1d, 0x28, 0xa8,
-	0x21, 0x5a, 0x8a, 0x0f, 0xbd, 0xa8, 0x0f, 0x09, 0xf0, 0x44, 0x55, 0x48, 0xdc, 0x7b, 0x65, 0xce,
-	0xbc, 0x0a, 0x2c, 0xd2, 0xfd, 0x14, 0x7d, 0x59, 0xf5, 0x16, 0x29, 0xd9, 0xd3, 0x17, 0x57, 0x21,
-	0x31, 0xa2, 0x78, 0x15, 0x3a, 0x1b, 0xe4, 0x29, 0x55, 0xc8, 0x14, 0x55, 0xa1, 0x34, 0x84, 0xef,
-	0x02, 0x41, 0xdf, 0x7a, 0xea, 0xab, 0x5a, 0x05, 0x16, 0x69, 0x7f, 0x47, 0x43, 0x59, 0x52, 0xbd,
-	0x45, 0xe3, 0x86, 0x14, 0xa6, 0x45, 0x61, 0x2e, 0x46, 0x43, 0x19, 0x30, 0xcf, 0xd1, 0xf5, 0x63,
-	0xdd, 0xb3, 0xf0, 0xa5, 0xb7, 0x29, 0x35, 0x38, 0xa0, 0x06, 0xe7, 0xc3, 0x71, 0x11, 0x9a, 0x7b,
-	0x08, 0x04, 0x8d, 0xf9, 0x69, 0x83, 0x91, 0xe2, 0xf6, 0x7f, 0xa2, 0x6e, 0xc7, 0x0c, 0x71, 0x1c,
-	0x9f, 0x03, 0xe1, 0x4d, 0x80, 0xe4, 0x0b, 0x91, 0x37, 0x39, 0x9a, 0xd1, 0x3a, 0x94, 0x4b, 0x39,
-	0xd9, 0x4d, 0x37, 0x1f, 0xb9, 0xe9, 0xa6, 0xb4, 0x0c, 0x76, 0xb4, 0x65, 0x10, 0x00, 0xe3, 0xc8,
-	0xff, 0x25, 0xb8, 0xa9, 0xa4, 0x04, 0xc6, 0x11, 0xe7, 0x43, 0x40, 0x01, 0x57, 0xff, 0xcf, 0xd8,
-	0x8d, 0x27, 0xe5, 0xec, 0x5d, 0xd1, 0xd9, 0x0b, 0x55, 0x6b, 0xc2, 0x7b, 0x53, 0x4a, 0x70, 0x86,
-	0xd1, 0xe0, 0x08, 0x54, 0x70, 0x13, 0x7b, 0x49, 0x37, 0xb0, 0xc6, 0x96, 0xd4, 0xca, 0x01, 0xb5,
-	0x52, 0x0d, 0xcc, 0xa3, 0x84, 0x5a, 0x82, 0x8f, 0x4d, 0xf2, 0x75, 0xae, 0xd1, 0x96, 0xda, 0x3a,
-	0xa4, 0xb6, 0xae, 0xc4, 0x3c, 0x8a, 0x2b, 0xe2, 0xe6, 0x1c, 0xf9, 0xf5, 0x30, 0xa5, 0xb4, 0x1e,
-	0x45, 0x4b, 0xab, 0x4c, 0x17, 0x37, 0x7a, 0x2f, 0x7a, 0xe3, 0x14, 0x4d, 0x15, 0x1b, 0xd7, 0xa5,
-	0xa6, 0x8f, 0xa9, 0x69, 0x25, 0xdc, 0x0f, 0x71, 0x8d, 0xdc, 0xd8, 0x7b, 0x20, 0xf9, 0x2e, 0x2b,
-	0x7d, 0x2a, 0x47, 0x05, 0x32, 0x17, 0x2c, 0x90, 0x3b, 0x52, 0x54, 0xff, 0xa5, 0xa8, 0x6a, 0x21,
-	0x54, 0x42, 0xcb, 0x1c, 0xdf, 0x8f, 0x40, 0x72, 0x9b, 0x16, 0x16, 0x30, 0x59, 0xb9, 0x10, 0xb4,
-	0xee, 0xde, 0xab, 0x32, 0x4a, 0x26, 0x9a, 0xb7, 0x2c, 0x1d, 0x2b, 0x05, 0x4f, 0x33, 0xf9, 0x4d,
-	0x7a, 0x84, 0x26, 0x76, 0x5c, 0xc3, 0xa4, 0x1d, 0x9b, 0x37, 0x4d, 0x2d, 0xab, 0x21, 0x5a, 0x4a,
-	0x0e, 0xfe, 0x2f, 0x9a, 0x83, 0x89, 0xae, 0xf1, 0x08, 0x7c, 0x03, 0x12, 0x07, 0x06, 0xbf, 0x9c,
-	0xff, 0x29, 0xbd, 0xce, 0xff, 0x63, 0xbd, 0x8e, 0x18, 0x20, 0xf7, 0xe2, 0x3e, 0x10, 0x4c, 0x36,
-	0x46, 0x83, 0x63, 0xc0, 0x07, 0xc7, 0xeb, 0xba, 0x6e, 0xb3, 0x97, 0x0f, 0xf9, 0x9d, 0x52, 0x63,
-	0x9f, 0x8b, 0xd6, 0xd8, 0x98, 0x11, 0x8e, 0xe1, 0x53, 0x90, 0x30, 0x46, 0x21, 0x31, 0xbb, 0xb1,
-	0xbb, 0xdb, 0xa6, 0xb6, 0xfd, 0x44, 0x67, 0x6b, 0x7f, 0x70, 0x1d, 0x80, 0xc5, 0x96, 0x04, 0xad,
-	0x4a, 0x30, 0x78, 0xbd, 0x22, 0xfd, 0x9d, 0x72, 0x3d, 0x7e, 0x5e, 0x7c, 0x3d, 0x8e, 0xc0, 0x09,
-	0xf5, 0x30, 0xe2, 0xe9, 0xce, 0xb3, 0x21, 0x4e, 0x41, 0xf7, 0x42, 0xf2, 0xe5, 0x5d, 0x88, 0xee,
-	0x43, 0x90, 0x30, 0x60, 0xca, 0xfe, 0x41, 0x20, 0x17, 0xf8, 0x20, 0x90, 0xf2, 0xce, 0x38, 0x01,
-	0x51, 0x98, 0x42, 0x0c, 0x1c, 0xe6, 0x41, 0xc2, 0xac, 0x2b, 0x8a, 0x32, 0xc5, 0xee, 0xfd, 0x98,
-	0x5d, 0xa1, 0x56, 0x81, 0xdd, 0x94, 0xe8, 0xa4, 0xd8, 0x7d, 0x31, 0xc1, 0x6e, 0xa2, 0xbf, 0x1f,
-	0x01, 0xd1, 0x98, 0xee, 0x0c, 0x73, 0x5c, 0xde, 0x39, 0x3c, 0xf0, 0xf0, 0x2e, 0x85, 0xaa, 0x7c,
-	0x62, 0x90, 0xcc, 0xf8, 0xe8, 0x30, 0x16, 0x1f, 0xb9, 0xbd, 0x97, 0x32, 0xd9, 0x7b, 0x02, 0x92,
-	0xc6, 0x8f, 0xa7, 0xee, 0x86, 0xe5, 0x70, 0x1e, 0x66, 0x82, 0xf3, 0x19, 0x90, 0x4c, 0x3c, 0xcf,
-	0xf8, 0xe3, 0x56, 0x0a, 0xf0, 0x97, 0x33, 0x01, 0x27, 0x77, 0x57, 0xd9, 0x2c, 0xf6, 0xd7, 0xc5,
-	0xfe, 0x28, 0x13, 0xf6, 0xc7, 0x40, 0x3c, 0x25, 0x8e, 0x95, 0xad, 0x59, 0x38, 0x16, 0xfa, 0x6c,
-	0xeb, 0xaf, 0x52, 0xc0, 0xbc, 0x92, 0x09, 0xcc, 0x53, 0x90, 0x38, 0x98, 0x3e, 0x23, 0x3c, 0xaf,
-	0x66, 0xc2, 0xf3, 0x16, 0x90, 0xce, 0xc2, 0xcf, 0x08, 0xd3, 0xe3, 0x4c, 0x98, 0xde, 0x06, 0x69,
-	0xa3, 0xf5, 0x33, 0x82, 0xf5, 0x5a, 0x66, 0x58, 0xf2, 0xaf, 0x02, 0x67, 0x04, 0xeb, 0x49, 0x26,
-	0x58, 0x8f, 0x00, 0x9c, 0x8f, 0x7f, 0x64, 0x60, 0x88, 0x96, 0x21, 0x64, 0xcc, 0x75, 0xd7, 0x47,
-	0x16, 0xa0, 0xa4, 0x20, 0x79, 0x9a, 0x09, 0xc9, 0x3b, 0x20, 0xe1, 0x73, 0x06, 0x79, 0xe1, 0xec,
-	0xf4, 0xf4, 0x40, 0x81, 0x60, 0xcb, 0xe0, 0xf4, 0xd4, 0x7f, 0x15, 0xf9, 0xcb, 0x14, 0x64, 0xaf,
-	0x67, 0x42, 0xf6, 0x5d, 0x4e, 0xf0, 0x71, 0x4a, 0xf8, 0xdf, 0x1b, 0x15, 0x58, 0xdc, 0xb4, 0xec,
-	0x2e, 0x66, 0xf7, 0x1c, 0xba, 0x08, 0x35, 0xd9, 0xf9, 0xf4, 0x26, 0xbb, 0x20, 0xbe, 0x64, 0x28,
-	0x70, 0x9c, 0x1e, 0x50, 0x4b, 0x57, 0x8a, 0xf4, 0x20, 0xd8, 0x12, 0x55, 0xe1, 0xc4, 0x36, 0x3e,
-	0x1c, 0x99, 0x18, 0xa3, 0xfb, 0x83, 0x24, 0xb4, 0x06, 0xd1, 0x36, 0x3e, 0x8c, 0x1a, 0x1a, 0xa7,
-	0xc8, 0x05, 0x1c, 0xd4, 0x80, 0x15, 0x4a, 0xa5, 0x23, 0x65, 0x42, 0xdf, 0xd4, 0xba, 0xae, 0x65,
-	0x2b, 0x25, 0x6a, 0x58, 0xc8, 0x4b, 0x89, 0xf8, 0x1b, 0x99, 0x22, 0xfe, 0x15, 0x48, 0xfd, 0x7e,
-	0x95, 0x61, 0x70, 0x3b, 0x79, 0xca, 0xb1, 0xb3, 0xdc, 0x83, 0x37, 0xb3, 0x78, 0xf0, 0x53, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x6b, 0xb4, 0x5d, 0x43, 0x0b, 0x24, 0x00, 0x00,
+	// 2465 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0xcd, 0x73, 0x1c, 0x47,
+	0x15, 0xaf, 0x9e, 0x5d, 0x49, 0xbb, 0x2d, 0x59, 0x96, 0x5b, 0xb2, 0x3c, 0x92, 0x65, 0x79, 0xb3,
+	0x76, 0xcc, 0xc6, 0x01, 0xc5, 0xb5, 0x70, 0xcc, 0x45, 0xd1, 0xda, 0x78, 0xb1, 0x25, 0x6d, 0x66,
+	0xe5, 0x70, 0xe1, 0xc0, 0x78, 0xa7, 0xbd, 0x1a, 0xbc, 0x3b, 0x33, 0x99, 0x99, 0xb5, 0x2c, 0x08,
+	0xa0, 0x00, 0xe1, 0x2b, 0x26, 0x90, 0xc0, 0x09, 0x8a, 0x82, 0x2a, 0x0e, 0x54, 0xc1, 0x81, 0x23,
+	0x45, 0x85, 0x2a, 0xa0, 0x52, 0x50, 0x1c, 0xb8, 0xf0, 0x47, 0x70, 0xa5, 0xb8, 0xc0, 0x15, 0xaa,
+	0xbb, 0xa7, 0xa7, 0xe7, 0xa3, 0xa7, 0x47, 0x32, 0xd2, 0x69, 0xf7, 0x7d, 0x74, 0xbf, 0xdf, 0x7b,
+	0xfd, 0xba, 0x5f, 0x7f, 0x0c, 0x5c, 0xb4, 0x9d, 0x10, 0xfb, 0x8e, 0x39, 0x7a, 0x65, 0x8c, 0x43,
+	0x73, 0xc3, 0xf3, 0xdd, 0xd0, 0x45, 0x35, 0xce, 0x6c, 0x7e, 0xa8, 0xc1, 0xd9, 0xad, 0xd1, 0x24,
+	0x08, 0xb1, 0xdf, 0x31, 0x43, 0x13, 0x21, 0x58, 0x25, 0xbf, 0x3a, 0x68, 0x68, 0xad, 0x39, 0x83,
+	0xfe, 0x47, 0x6b, 0xb0, 0xbe, 0x6d, 0x3e, 0xdd, 0x71, 0x2d, 0xdc, 0xed, 0xe8, 0x5a, 0x43, 0x6b,
+	0x55, 0x0d, 0xc1, 0x40, 0xb7, 0x60, 0x9d, 0x68, 0x11, 0x2a, 0xd0, 0x2b, 0x8d, 0x4a, 0x6b, 0xb6,
+	0x8d, 0x36, 0x78, 0xff, 0x1b, 0x54, 0xc9, 0x79, 0xe4, 0x1a, 0x42, 0x89, 0xb4, 0xd8, 0xc6, 0xbc,
+	0x45, 0xb5, 0xb8, 0x45, 0xac, 0x84, 0x5a, 0x70, 0xca, 0x70, 0x47, 0x38, 0xd0, 0xa7, 0xb2, 0xda,
+	0x84, 0x4d, 0xb5, 0x99, 0x02, 0xd1, 0x7c, 0x10, 0x60, 0x3f, 0xd0, 0xa7, 0xb3, 0x9a, 0x84, 0xcd,
+	0x34, 0xa9, 0x02, 0x7a, 0x0d, 0x9e, 0xef, 0xef, 0x9b, 0xbe, 0xd5, 0x1b, 0x99, 0x03, 0x3c, 0xc6,
+	0x4e, 0x18, 0xe8, 0x33, 0xb4, 0x8d, 0x2e, 0xda, 0xa4, 0x15, 0x8c, 0x6c, 0x83, 0xe6, 0x47, 0x00,
+	0xd6, 0x38, 0x5e, 0x34, 0x0f, 0xb5, 0x6e, 0x87, 0x06, 0xae, 0x6a, 0x68, 0xdd, 0x0e, 0x09, 0xe5,
+	0x5d, 0x37, 0x08, 0x69, 0xc4, 0xea, 0x06, 0xfd, 0x8f, 0x74, 0x38, 0xb3, 0xb7, 0xd5, 0xa3, 0xec,
+	0x4a, 0x03, 0xb4, 0xea, 0x06, 0x27, 0xd1, 0x06, 0x44, 0x3d, 0xec, 0x58, 0xb6, 0x33, 0xa4, 0x46,
+	0x76, 0x0f, 0x1c, 0xec, 0xb3, 0xe8, 0x54, 0x0d, 0x89, 0x84, 0x0c, 0x4a, 0xc7, 0x0e, 0x1e, 0x3f,
+	0x08, 0xcc, 0x21, 0xd6, 0xa7, 0x1a, 0x80, 0x0c, 0x4a, 0xcc, 0x40, 0x2f, 0xc3, 0xe9, 0xfb, 0xe6,
+	0x43, 0x3c, 0xe2, 0x71, 0x58, 0x4c, 0xc7, 0x97, 0xca, 0x8c, 0x48, 0xa5, 0xf9, 0x49, 0x58, 0x8f,
+	0x99, 0x68, 0x01, 0x56, 0xee, 0xe1, 0x43, 0xea, 0x46, 0xdd, 0x20, 0x7f, 0xd1, 0x12, 0x9c, 0x7a,
+	0xc3, 0x1c, 0x4d, 0x70, 0xe4, 0x08, 0x23, 0x9a, 0x9f, 0x87, 0xf3, 0xe9, 0x68, 0xa0, 0x55, 0x58,
+	0x23, 0x63, 0xfc, 0xd0, 0x0c, 0x70, 0xd4, 0x3c, 0xa6, 0xd1, 0x32, 0x9c, 0xee, 0xb9, 0x23, 0x7b,
+	0x70, 0x18, 0x75, 0x12, 0x51, 0xa4, 0x4d, 0x3f, 0xf4, 0xcd, 0x10, 0x0f, 0x0f, 0xf5, 0x0a, 0x6b,
+	0xc3, 0xe9, 0xa6, 0x0f, 0x6b, 0x7c, 0x74, 0x49, 0x2c, 0x77, 0xcc, 0x31, 0xef, 0x97, 0xfe, 0x47,
+	0xaf, 0xc2, 0xd9, 0x1e, 0xf6, 0xc7, 0x76, 0x10, 0xd8, 0xae, 0x13, 0xe8, 0x1a, 0x75, 0x74, 0x35,
+	0x31, 0x78, 0x03, 0xd7, 0xc3, 0x96, 0x50, 0x31, 0x92, 0xea, 0xc4, 0x2b, 0x96, 0x28, 0x24, 0x65,
+	0xeb, 0x51, 0x52, 0x34, 0x3f, 0x07, 0x6b, 0x3c, 0x4f, 0x8e, 0x63, 0xb3, 0x72, 0x22, 0x9b, 0xcd,
+	0x2e, 0x3c, 0x47, 0x7a, 0xef, 0xf9, 0xf6, 0x13, 0x7b, 0x84, 0x87, 0x58, 0x19, 0xb2, 0x35, 0x58,
+	0x8f, 0x15, 0x69, 0xd4, 0xa6, 0x0c, 0xc1, 0x68, 0xf6, 0xe0, 0x42, 0xd6, 0x16, 0xe9, 0xcd, 0xc0,
+	0x81, 0x3b, 0xf1, 0x07, 0x71, 0x6f, 0x9c, 0x46, 0x8d, 0x7c, 0xb0, 0xea, 0x69, 0x70, 0x6f, 0xd1,
+	0xd6, 0x9e, 0xeb, 0x04, 0x98, 0xa4, 0xf2, 0xee, 0x3d, 0xda, 0x47, 0xcd, 0xd0, 0x76, 0xef, 0x91,
+	0x60, 0xdd, 0xf6, 0x7d, 0xd7, 0xd7, 0x35, 0x9a, 0xb4, 0x8c, 0x20, 0xdc, 0xae, 0x63, 0xe1, 0xa7,
+	0x34, 0x95, 0xab, 0x06, 0x23, 0xd0, 0xa7, 0x60, 0x8d, 0x26, 0x46, 0xb7, 0xc3, 0x27, 0x77, 0x76,
+	0x42, 0x75, 0x3b, 0xdb, 0xa6, 0xe7, 0xd9, 0xce, 0xd0, 0x88, 0x35, 0x9b, 0xaf, 0xc2, 0xf9, 0xb4,
+	0x8c, 0xf4, 0xbe, 0x3b, 0xb2, 0xe2, 0x19, 0xc5, 0x08, 0xc2, 0xdd, 0xc1, 0x07, 0xf1, 0x3a, 0xc4,
+	0x88, 0xe6, 0x2f, 0x67, 0xe1, 0xcc, 0x96, 0x3b, 0x1e, 0x9b, 0x8e, 0x85, 0x6e, 0xc2, 0x6a, 0x78,
+	0xe8, 0xb1, 0x08, 0xcc, 0xb7, 0x97, 0x85, 0xed, 0x48, 0x61, 0x63, 0xef, 0xd0, 0xc3, 0x06, 0xd5,
+	0x69, 0xfe, 0x03, 0xc2, 0x2a, 0x21, 0xd1, 0x0a, 0xbc, 0xb8, 0xe5, 0x63, 0x33, 0xc4, 0x3c, 0xfc,
+	0x91, 0xf2, 0x02, 0x40, 0x97, 0xe0, 0x62, 0xc7, 0x77, 0xbd, 0xac, 0x40, 0x43, 0x0d, 0xb8, 0xc6,
+	0xda, 0x18, 0x38, 0xc4, 0x4e, 0x68, 0xbb, 0x0e, 0x4b, 0x6a, 0xae, 0x51, 0x41, 0xeb, 0x70, 0x95,
+	0x34, 0x2d, 0x90, 0x57, 0xd1, 0x75, 0xd8, 0xe8, 0xe3, 0xb0, 0x83, 0x1f, 0x99, 0x93, 0x51, 0x58,
+	0xa0, 0x35, 0x45, 0xec, 0x3c, 0xf0, 0xac, 0x62, 0x3b, 0xd3, 0xe8, 0x32, 0xbc, 0xc4, 0x90, 0xd0,
+	0x10, 0x7e, 0xda, 0x77, 0x27, 0x1e, 0x17, 0xce, 0x10, 0x61, 0x07, 0x8f, 0xb0, 0x4c, 0x58, 0x13,
+	0x3e, 0x6c, 0xb9, 0x4e, 0x68, 0x3b, 0x13, 0x77, 0x12, 0xbc, 0x3e, 0xc1, 0x7e, 0xdc, 0x77, 0x9d,
+	0xfb, 0x50, 0x20, 0x87, 0xe8, 0x22, 0xbc, 0xc0, 0x7a, 0x20, 0x99, 0xcd, 0xd9, 0xb3, 0x68, 0x11,
+	0x9e, 0x27, 0xcd, 0x92, 0xcc, 0x39, 0xa2, 0xcb, 0x3c, 0x49, 0xb2, 0xcf, 0x91, 0x08, 0xf7, 0x71,
+	0x18, 0xe7, 0x36, 0x17, 0xcc, 0x8b, 0xbe, 0xc9, 0x3a, 0xc0, 0xd9, 0xe7, 0x79, 0xdf, 0x49, 0xe6,
+	0x02, 0xe9, 0x64, 0xd3, 0xb2, 0x08, 0x8f, 0xce, 0x64, 0x2e, 0xb8, 0x80, 0x56, 0xe1, 0xb2, 0x81,
+	0xc7, 0xee, 0x13, 0x9c, 0x93, 0x21, 0x74, 0x05, 0xae, 0x44, 0x8d, 0x12, 0x33, 0x81, 0x8b, 0x17,
+	0x49, 0x74, 0x44, 0x53, 0x89, 0xc6, 0x12, 0x42, 0x70, 0x9e, 0x8c, 0xa0, 0x19, 0x9a, 0x9c, 0x77,
+	0x11, 0xad, 0x41, 0xbd, 0x8f, 0xc3, 0x4d, 0x6b, 0x6c, 0x3b, 0x39, 0x9f, 0x96, 0x89, 0xc9, 0x68,
+	0xac, 0x26, 0x0f, 0x83, 0x81, 0x6f, 0x7b, 0x64, 0x40, 0xb9, 0xf8, 0x12, 0x1d, 0x2d, 0xdf, 0xf5,
+	0x64, 0x42, 0x9d, 0xc4, 0x83, 0xe1, 0xe9, 0x61, 0x11, 0xbf, 0x15, 0x91, 0xbc, 0xbc, 0x60, 0x72,
+	0xd1, 0x6a, 0x3a, 0xaf, 0x93, 0xa2, 0xcb, 0x44, 0xc4, 0x06, 0x23, 0x2b, 0x5a, 0x23, 0x22, 0x96,
+	0x32, 0xd9, 0x0e, 0xaf, 0x08, 0x51, 0xb6, 0xd5, 0x3a, 0x5a, 0x86, 0xa8, 0x8f, 0xc3, 0x6c, 0x93,
+	0xab, 0x68, 0x09, 0x2e, 0x50, 0x97, 0x48, 0xfa, 0x71, 0x6e, 0x83, 0xf8, 0xd2, 0x1d, 0x7b, 0xae,
+	0x9f, 0x0a, 0xde, 0x0b, 0x64, 0xb4, 0xfa, 0x38, 0xa4, 0xab, 0xa4, 0x19, 0x04, 0x07, 0xae, 0x68,
+	0xd2, 0x8c, 0x46, 0x8b, 0xca, 0xf2, 0x63, 0x71, 0x4d, 0x8c, 0x56, 0x81, 0xc6, 0x75, 0xa4, 0xc3,
+	0xa5, 0x4d, 0xcb, 0x12, 0x55, 0x94, 0x4b, 0x5e, 0x24, 0x61, 0x67, 0x6d, 0xf3, 0xc2, 0x1b, 0xe8,
+	0x2a, 0xbc, 0xbc, 0x69, 0x59, 0xb9, 0x1a, 0xcc, 0x15, 0x3e, 0x86, 0x9a, 0x70, 0x9d, 0x10, 0x76,
+	0x58, 0xa8, 0xd3, 0x22, 0x3a, 0x7c, 0xec, 0x0a, 0x74, 0x5e, 0x22, 0x73, 0x6d, 0xcf, 0x9f, 0x38,
+	0x83, 0xd4, 0x4c, 0x8e, 0xf1, 0xdf, 0xa4, 0xa3, 0xb9, 0x6f, 0x3a, 0x43, 0x9a, 0x8f, 0xa4, 0x1e,
+	0x71, 0xd1, 0xcb, 0xe8, 0x1a, 0xbc, 0xca, 0x06, 0xfa, 0x35, 0x73, 0x64, 0x3a, 0x03, 0x6c, 0xe5,
+	0x67, 0xfb, 0xc7, 0xa3, 0xcc, 0x4c, 0x97, 0x6d, 0x2e, 0xfd, 0x04, 0x71, 0x33, 0xca, 0x65, 0x32,
+	0x7e, 0xf1, 0x5e, 0x82, 0x2b, 0x6c, 0xd0, 0xb1, 0xf1, 0x46, 0x76, 0x98, 0xef, 0xfa, 0x15, 0xd2,
+	0xb5, 0x81, 0x83, 0xd0, 0xf5, 0x25, 0xcb, 0xcc, 0xad, 0x9b, 0xb5, 0x9a, 0xb5, 0x70, 0x74, 0x74,
+	0x74, 0xa4, 0x35, 0x7f, 0x01, 0x0a, 0x56, 0x5a, 0x69, 0xb9, 0x6d, 0xc1, 0xf3, 0x99, 0x45, 0x8f,
+	0x56, 0xa0, 0x39, 0x23, 0xcb, 0x6e, 0xdf, 0x87, 0x33, 0x83, 0xa8, 0xa3, 0x0b, 0xb9, 0x25, 0x5f,
+	0xc7, 0x0d, 0xd0, 0x9a, 0x6d, 0x5f, 0x4d, 0x08, 0x64, 0x10, 0x0c, 0xde, 0x45, 0x73, 0x22, 0x5d,
+	0xf3, 0x65, 0x10, 0xdb, 0x9f, 0x51, 0x1a, 0x7e, 0x44, 0x0d, 0x5f, 0x11, 0x02, 0x49, 0xb7, 0xc2,
+	0xec, 0xef, 0x80, 0xba, 0xa4, 0x28, 0xf7, 0x0b, 0xd2, 0x58, 0x69, 0xb2, 0x58, 0xf5, 0x95, 0x90,
+	0x87, 0x14, 0xf2, 0x8d, 0x6c, 0xac, 0xe4, 0x88, 0x04, 0xf6, 0x9f, 0x01, 0x55, 0xb1, 0x53, 0x22,
+	0xe7, 0x61, 0xd5, 0x12, 0x61, 0x7d, 0x5d, 0x89, 0x71, 0x9f, 0x62, 0xbc, 0x9e, 0x0e, 0x6b, 0x19,
+	0xc2, 0x5f, 0x83, 0xf2, 0x72, 0x7b, 0x62, 0x9c, 0x9f, 0x55, 0xe2, 0xb4, 0x29, 0xce, 0x9b, 0x42,
+	0x50, 0x66, 0x5f, 0xa0, 0xfd, 0x37, 0x50, 0x97, 0xfd, 0x93, 0x22, 0x25, 0x47, 0x8f, 0x1d, 0x7c,
+	0x40, 0xd9, 0xd1, 0xd1, 0x23, 0x22, 0x69, 0x4f, 0x13, 0xdf, 0x24, 0x26, 0xf4, 0x6a, 0x03, 0xb4,
+	0x2a, 0x46, 0x4c, 0xb3, 0x3d, 0xa5, 0x37, 0xb2, 0x07, 0xe6, 0x0e, 0x3d, 0x65, 0x9c, 0x33, 0x62,
+	0xba, 0x24, 0x8f, 0xbe, 0x90, 0xcd, 0x23, 0x95, 0x37, 0xc2, 0xef, 0x0f, 0x41, 0xe1, 0x66, 0xe6,
+	0xb9, 0x4e, 0x18, 0x6b, 0xb0, 0xbe, 0x67, 0x8f, 0x71, 0x10, 0x9a, 0x63, 0x8f, 0x1e, 0x31, 0x2a,
+	0x86, 0x60, 0xb4, 0x77, 0x94, 0x2e, 0x3c, 0xa6, 0x2e, 0xbc, 0x90, 0x9d, 0x0a, 0x39, 0x60, 0x02,
+	0xfd, 0x9f, 0x40, 0xe1, 0x6e, 0xeb, 0xb9, 0xd0, 0x37, 0xe1, 0x9c, 0xe8, 0xa8, 0xdb, 0xa1, 0x0e,
+	0x54, 0x8d, 0x14, 0xaf, 0xc4, 0x87, 0x51, 0xd6, 0x87, 0x02, 0x78, 0xb2, 0x55, 0x48, 0xbe, 0xe9,
+	0x3b, 0x71, 0xe6, 0x2d, 0xc1, 0x29, 0xda, 0x3e, 0x3a, 0xe1, 0x31, 0xa2, 0x24, 0x7b, 0xc6, 0xf2,
+	0x55, 0x48, 0x8e, 0x28, 0xbf, 0x0a, 0x9d, 0x0e, 0xf2, 0x92, 0x55, 0xc8, 0x91, 0xad, 0x42, 0x65,
+	0x08, 0x7f, 0x02, 0x24, 0x1b, 0x66, 0x69, 0xf1, 0x23, 0xf7, 0x07, 0x66, 0xb0, 0x1f, 0xdf, 0x1f,
+	0x98, 0xc1, 0x3e, 0x09, 0x25, 0xdd, 0x58, 0xd2, 0x50, 0xd6, 0x0c, 0x46, 0xb4, 0xef, 0x2a, 0x61,
+	0xba, 0x14, 0xe6, 0xe5, 0x6c, 0x28, 0x13, 0xe6, 0x05, 0xba, 0x71, 0x6e, 0xdb, 0x2e, 0x2d, 0x7a,
+	0x77, 0x94, 0x06, 0x3d, 0x6a, 0x70, 0x25, 0x1d, 0x17, 0xa9, 0xb9, 0x77, 0x80, 0xe4, 0x44, 0x70,
+	0xdc, 0x60, 0x94, 0xb8, 0xfd, 0x66, 0xd6, 0xed, 0x9c, 0x21, 0x81, 0xe3, 0xb7, 0x40, 0x7a, 0x04,
+	0x21, 0xf9, 0x42, 0xf4, 0x1d, 0x81, 0x26, 0xa6, 0x53, 0xb9, 0xa4, 0xa9, 0xce, 0xee, 0x95, 0xcc,
+	0xd9, 0xbd, 0x64, 0xcb, 0xe0, 0x67, 0xb7, 0x0c, 0x12, 0x60, 0x02, 0xf9, 0x9b, 0x92, 0x23, 0x92,
+	0x74, 0xc8, 0xd4, 0xc1, 0x0a, 0xe4, 0x39, 0x92, 0xe8, 0x34, 0x97, 0x23, 0x65, 0x06, 0xd5, 0x39,
+	0x12, 0xca, 0x72, 0x44, 0x6a, 0xee, 0x5d, 0x20, 0x3d, 0xd9, 0x49, 0xb3, 0x24, 0xbe, 0xd4, 0xd1,
+	0x12, 0x97, 0x3a, 0x25, 0xf1, 0x9e, 0x64, 0xe3, 0x2d, 0x31, 0x26, 0xd0, 0xbc, 0x0f, 0x8a, 0x8e,
+	0x93, 0x27, 0x00, 0xb4, 0xad, 0x04, 0xf4, 0x84, 0x02, 0x6a, 0x08, 0x81, 0xdc, 0x9e, 0xc0, 0xf4,
+	0x07, 0xa0, 0x38, 0xc6, 0x9e, 0xfe, 0xd5, 0x59, 0xbb, 0xa7, 0x84, 0x7f, 0x40, 0xe1, 0x5f, 0xcb,
+	0xc5, 0x33, 0x0f, 0x4d, 0x78, 0xf0, 0x67, 0xa0, 0x3e, 0x69, 0x9f, 0x81, 0x13, 0xea, 0xf2, 0xf3,
+	0x34, 0x5b, 0x7e, 0x54, 0xe8, 0x84, 0x1f, 0x8f, 0xb3, 0xd7, 0x01, 0xb2, 0xfb, 0xf4, 0xf6, 0x6d,
+	0xa5, 0xe9, 0xc3, 0x06, 0xc8, 0xdc, 0x99, 0xa5, 0x7a, 0x14, 0xc6, 0x7e, 0x0a, 0x8a, 0x2f, 0x1a,
+	0x94, 0x2b, 0x57, 0x5c, 0x44, 0xb4, 0x64, 0x11, 0xd9, 0x55, 0xa2, 0xfa, 0x22, 0x45, 0xd5, 0x4c,
+	0xa1, 0x92, 0x5a, 0x16, 0xf8, 0xfe, 0x0b, 0x14, 0x57, 0x1d, 0xd2, 0x11, 0x55, 0x2d, 0xa9, 0x92,
+	0xe3, 0x0d, 0xdb, 0x4e, 0x64, 0xd9, 0xa4, 0xe7, 0x6d, 0xd7, 0xc2, 0x7a, 0x95, 0xf5, 0x4c, 0xfe,
+	0x93, 0x7d, 0x54, 0x07, 0x07, 0xa1, 0xed, 0xd0, 0x5d, 0x2d, 0x7b, 0x47, 0xa8, 0x1b, 0x29, 0x5e,
+	0x49, 0x5a, 0x7f, 0x29, 0x9b, 0xd6, 0x85, 0xae, 0x89, 0x08, 0x7c, 0x04, 0x0a, 0x6f, 0x73, 0xce,
+	0xce, 0xff, 0x92, 0xfd, 0xe0, 0x5b, 0xb9, 0xfd, 0xa0, 0x1c, 0xa0, 0xf0, 0xe2, 0x6d, 0x20, 0xb9,
+	0x76, 0x8a, 0x5f, 0x3b, 0x80, 0x78, 0xed, 0xd8, 0xb4, 0x2c, 0x9f, 0x17, 0x68, 0xf2, 0xbf, 0xa4,
+	0xe6, 0x7c, 0x39, 0x5b, 0x73, 0x72, 0x46, 0x04, 0x86, 0xdf, 0x80, 0x82, 0x3b, 0x2e, 0x12, 0xb3,
+	0xbb, 0x7b, 0x7b, 0x3d, 0x6a, 0x3b, 0x4a, 0x74, 0x4e, 0x47, 0xaf, 0x2d, 0x09, 0x58, 0x9c, 0x24,
+	0x68, 0x0d, 0x82, 0x81, 0xed, 0xa7, 0xe9, 0xff, 0x92, 0x2b, 0x84, 0xaf, 0xc8, 0xaf, 0x10, 0x32,
+	0x70, 0x04, 0xe2, 0xbf, 0x82, 0x82, 0xab, 0xb7, 0xe7, 0x44, 0x2c, 0x5e, 0x74, 0x2a, 0xa5, 0x2f,
+	0x3a, 0x25, 0xae, 0x7c, 0xb5, 0xf8, 0x36, 0x44, 0xea, 0xca, 0xdf, 0x41, 0xc1, 0x55, 0xe1, 0xc9,
+	0x9f, 0xbc, 0xb4, 0xe4, 0x93, 0x97, 0x70, 0xa9, 0x5a, 0xee, 0x92, 0xba, 0x66, 0x1e, 0x81, 0xac,
+	0x4f, 0x52, 0xc0, 0xc2, 0xa7, 0x27, 0x05, 0x57, 0x9c, 0x59, 0x97, 0x4a, 0xec, 0xbe, 0x9d, 0xb3,
+	0x2b, 0xed, 0x55, 0x62, 0xb7, 0x63, 0xfe, 0x3f, 0x76, 0xbf, 0x56, 0x60, 0xb7, 0xd0, 0xdf, 0x5f,
+	0x01, 0xd9, 0xed, 0xec, 0x29, 0xce, 0x1e, 0xf5, 0x26, 0xeb, 0xeb, 0x0c, 0xef, 0x5a, 0xaa, 0x7e,
+	0x14, 0x06, 0xc9, 0xc9, 0xdf, 0x18, 0xe7, 0xe2, 0xa3, 0xb6, 0xf7, 0x8d, 0x13, 0xd9, 0x7b, 0x06,
+	0x8a, 0x6e, 0x9d, 0x8f, 0x7d, 0x16, 0x51, 0xc3, 0x79, 0xe7, 0x44, 0x70, 0xfe, 0x08, 0x14, 0x17,
+	0xdd, 0x67, 0xb0, 0x15, 0x52, 0x9f, 0x72, 0xbf, 0x09, 0x24, 0x1b, 0x3a, 0x39, 0x36, 0xe1, 0xc2,
+	0x5f, 0x80, 0xfa, 0x32, 0xfe, 0x0c, 0xbc, 0xd8, 0x53, 0x7a, 0xf1, 0x2d, 0x20, 0xdf, 0xd1, 0x95,
+	0x39, 0xf2, 0x0c, 0xc8, 0xdf, 0x0c, 0x72, 0x4b, 0xdf, 0x32, 0x9c, 0x4e, 0x7d, 0x21, 0x11, 0x51,
+	0x25, 0x4b, 0xf1, 0xb7, 0x19, 0xac, 0xf5, 0x54, 0x70, 0x73, 0xc6, 0x04, 0x9c, 0x0f, 0x40, 0xe1,
+	0x43, 0xc5, 0xb1, 0x11, 0xa9, 0x37, 0x7a, 0xdf, 0x01, 0xd9, 0x0d, 0x42, 0x81, 0x3d, 0x01, 0xea,
+	0xc7, 0x40, 0xf9, 0x40, 0x72, 0x6c, 0x60, 0xea, 0x2d, 0xf9, 0x77, 0x19, 0xb0, 0x17, 0x53, 0xa1,
+	0x2a, 0xb2, 0x29, 0xc0, 0xfd, 0x1c, 0x94, 0x3d, 0xbc, 0x1c, 0x1b, 0xdf, 0x1b, 0x4a, 0x7c, 0xef,
+	0x32, 0x7c, 0xad, 0xfc, 0x7e, 0xe6, 0x38, 0x10, 0xd5, 0xef, 0x47, 0xa7, 0x04, 0xf1, 0x59, 0x0e,
+	0xa2, 0xda, 0xac, 0x80, 0xf8, 0x1e, 0x80, 0x2b, 0xf9, 0xa7, 0x29, 0x8e, 0x6e, 0x1d, 0x42, 0x2e,
+	0xdc, 0x0c, 0x23, 0x94, 0x09, 0x4e, 0xc9, 0x02, 0xf3, 0xbd, 0xdc, 0x02, 0x53, 0x68, 0x29, 0x95,
+	0x73, 0xf2, 0xb7, 0x30, 0x52, 0xb6, 0x76, 0x47, 0x56, 0x62, 0x71, 0xe1, 0x64, 0xf2, 0x06, 0x3c,
+	0x2a, 0x68, 0x11, 0x59, 0x52, 0x6c, 0xdf, 0xcb, 0x15, 0x5b, 0xa9, 0x65, 0x01, 0xee, 0x9f, 0x9a,
+	0xe4, 0x71, 0x53, 0xfa, 0x69, 0xd5, 0x12, 0x9c, 0xba, 0xe3, 0xfa, 0x03, 0x06, 0xa8, 0x66, 0x30,
+	0x22, 0x75, 0x0e, 0xa8, 0x94, 0x9f, 0x03, 0xaa, 0xf2, 0x73, 0x90, 0x0e, 0x67, 0xd8, 0x27, 0x15,
+	0x56, 0xf4, 0x7d, 0x10, 0x27, 0xc9, 0xc7, 0x20, 0x3b, 0xf8, 0x20, 0x36, 0x31, 0x4d, 0x9f, 0x03,
+	0x92, 0x2c, 0xf2, 0x35, 0xd2, 0x0e, 0x3e, 0xc8, 0x1a, 0x9a, 0xa1, 0x8a, 0x12, 0x09, 0x6a, 0xc3,
+	0x25, 0xca, 0xa5, 0x2f, 0x03, 0x84, 0x7f, 0xc7, 0x1c, 0x84, 0xae, 0xaf, 0xd7, 0xa8, 0x61, 0xa9,
+	0xac, 0xdd, 0x55, 0x06, 0xfd, 0xfb, 0x20, 0x7b, 0x3c, 0xc8, 0x45, 0x54, 0x04, 0xfc, 0x6f, 0xa0,
+	0xf4, 0xf9, 0xf3, 0x0c, 0x1e, 0x0f, 0xd4, 0x6f, 0x3f, 0x3f, 0x60, 0x0e, 0xbc, 0x94, 0xdd, 0x66,
+	0x17, 0x22, 0x14, 0xee, 0xfc, 0x1e, 0x14, 0x3f, 0xd4, 0x9e, 0xf6, 0x67, 0x56, 0x25, 0xc7, 0xde,
+	0xf7, 0x81, 0xe4, 0xe0, 0x2f, 0x05, 0x96, 0x5a, 0xcf, 0x54, 0x2f, 0xc9, 0xb9, 0xc5, 0x2c, 0xf5,
+	0x29, 0x5b, 0xf4, 0x7d, 0x61, 0xcc, 0x28, 0xa9, 0x0a, 0x1f, 0xe4, 0xaa, 0x82, 0xc2, 0xb2, 0x80,
+	0xf8, 0x1f, 0x50, 0xf4, 0x96, 0x7d, 0x56, 0xcf, 0x34, 0xe9, 0x5c, 0xaa, 0x66, 0x72, 0x89, 0xf4,
+	0x4c, 0xb5, 0x77, 0xa2, 0xb9, 0x1a, 0x51, 0x25, 0x87, 0xf9, 0x1f, 0x82, 0xec, 0x5d, 0xa1, 0xdc,
+	0x29, 0xe1, 0xf8, 0xbf, 0x40, 0xf1, 0x43, 0xfd, 0xf3, 0x4e, 0x91, 0x7e, 0x68, 0xfa, 0x21, 0x71,
+	0x85, 0x4f, 0x91, 0x98, 0x41, 0xd6, 0xa0, 0xdb, 0x8e, 0x45, 0x65, 0xcc, 0x65, 0x4e, 0xa6, 0x1c,
+	0xd6, 0x12, 0x0e, 0xab, 0xd3, 0xf1, 0x47, 0xb9, 0x74, 0x2c, 0x72, 0x26, 0xf5, 0xc8, 0x70, 0xae,
+	0xef, 0x98, 0x5e, 0xb0, 0xef, 0x86, 0x1d, 0x3c, 0x0a, 0x4d, 0x3a, 0x1d, 0x22, 0x06, 0xbd, 0xc1,
+	0x98, 0x33, 0x62, 0x1a, 0xdd, 0x80, 0xd5, 0xfb, 0xee, 0x90, 0xef, 0x3e, 0x13, 0xdf, 0x8f, 0xde,
+	0x77, 0x87, 0xb7, 0x9d, 0xd0, 0x3f, 0x34, 0xa8, 0x1c, 0xdd, 0x82, 0x8b, 0xf1, 0x22, 0xb8, 0x39,
+	0x09, 0x5d, 0x36, 0xb9, 0xe9, 0xd3, 0x6a, 0xcd, 0x90, 0x89, 0x9a, 0x77, 0x61, 0x8d, 0xf7, 0x21,
+	0x3e, 0x9d, 0x8b, 0x3e, 0x6e, 0xa3, 0x04, 0xa9, 0x10, 0x7b, 0xd8, 0x1f, 0x47, 0x73, 0x80, 0xfe,
+	0x8f, 0xab, 0x46, 0x45, 0x54, 0x8d, 0xff, 0x0d, 0x00, 0x31, 0xac, 0x6c, 0xa3, 0xd4, 0x2b, 0x00,
+	0x00,
 }
@@ -0,0 +1,146 @@
+package meta
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// observer follows the meta data of the raft voters without voting. A
+// non-voting meta node uses it to serve snapshots to clients, adding read
+// capacity without growing the quorum. It keeps no raft log of the commands it
+// receives, so the snapshots it serves are always full snapshots.
+type observer struct {
+	mu      sync.RWMutex
+	closing chan struct{}
+	client  *Client
+	leader  string
+	peers   []string
+	logger  *log.Logger
+
+	// refreshInterval is how often the leader and peers are refreshed.
+	refreshInterval time.Duration
+}
+
+func newObserver(c *Config, voters []string) *observer {
+	client := NewClient(c)
+	client.SetMetaServers(voters)
+	client.SetTLS(c.HTTPSEnabled)
+
+	return &observer{
+		closing:         make(chan struct{}),
+		client:          client,
+		refreshInterval: time.Duration(c.HeartbeatTimeout),
+	}
+}
+
+// open starts following the voters and applying their meta data to s.
+func (o *observer) open(s *store) {
+	go o.follow(s)
+	go o.watchLeader()
+}
+
+// close stops following the voters. Closing the client aborts the snapshot
+// long-poll in flight.
+func (o *observer) close() error {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	select {
+	case <-o.closing:
+		return nil
+	default:
+		close(o.closing)
+	}
+	return o.client.Close()
+}
+
+func (o *observer) isClosed() bool {
+	select {
+	case <-o.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// follow long-polls the voters for snapshots newer than the data of s.
+func (o *observer) follow(s *store) {
+	var i int
+	for {
+		voters := o.client.MetaServers()
+		voter := voters[i%len(voters)]
+		data, err := o.client.getSnapshotDelta(voter, s.currentData())
+		if o.isClosed() {
+			return
+		}
+
+		if err != nil {
+			o.logger.Printf("failure getting snapshot from %s: %s", voter, err)
+			i++
+			select {
+			case <-o.closing:
+				return
+			case <-time.After(errSleep):
+			}
+			continue
+		}
+		s.setData(data)
+	}
+}
+
+// watchLeader periodically refreshes the raft leader and peers seen by the
+// voters.
+func (o *observer) watchLeader() {
+	ticker := time.NewTicker(o.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		o.refresh()
+
+		select {
+		case <-o.closing:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *observer) refresh() {
+	var leader string
+	for _, voter := range o.client.MetaServers() {
+		l, err := o.client.ping(voter, o.refreshInterval)
+		if err != nil {
+			continue
+		}
+
+		if leader = l; leader != "" {
+			break
+		}
+	}
+	peers := o.client.peers(o.refreshInterval)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.leader = leader
+	if len(peers) > 0 {
+		o.peers = peers
+	}
+}
+
+// leaderAddr returns the raft address of the leader seen by the voters.
+func (o *observer) leaderAddr() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.leader
+}
+
+// peerAddrs returns the raft addresses of the voters.
+func (o *observer) peerAddrs() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.peers
+}
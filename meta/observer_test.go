@@ -0,0 +1,38 @@
+package meta
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+)
+
+// Ensure the observer stops waiting for a voter which doesn't answer its ping.
+func TestObserver_Refresh_Timeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	c := NewConfig()
+	c.HeartbeatTimeout = toml.Duration(50 * time.Millisecond)
+	o := newObserver(c, []string{ln.Addr().String()})
+	defer o.close()
+
+	done := make(chan struct{})
+	go func() {
+		o.refresh()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the refresh")
+	}
+	if addr := o.leaderAddr(); addr != "" {
+		t.Fatalf("unexpected leader: %s", addr)
+	}
+}
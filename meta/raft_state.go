@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/raft-boltdb"
 )
@@ -61,8 +62,9 @@ func (r *raftState) open(s *store, ln net.Listener, initializePeers []string) er
 	config.ElectionTimeout = time.Duration(r.config.ElectionTimeout)
 	config.LeaderLeaseTimeout = time.Duration(r.config.LeaderLeaseTimeout)
 	config.CommitTimeout = time.Duration(r.config.CommitTimeout)
-	// Since we actually never call `removePeer` this is safe.
-	// If in the future we decide to call remove peer we have to re-evaluate how to handle this
+	// A node joining later replays the removal of other peers from the log
+	// and must not shut down on it. A removed node is left without peers so
+	// it doesn't start elections against the remaining ones.
 	config.ShutdownOnRemove = false
 
 	// Build raft layer to multiplex listener.
@@ -71,11 +73,11 @@ func (r *raftState) open(s *store, ln net.Listener, initializePeers []string) er
 	// Create a transport layer
 	r.transport = raft.NewNetworkTransport(r.raftLayer, 3, 10*time.Second, config.LogOutput)
 
-	// Create peer storage.TODO: zhexuany use JSON peers instead of in-memory peers store
-	// r.peerStore = raft.NewJSONPeers(filepath.Join(r.path, "peers.json"), r.transport)
-	r.peerStore = &peerStore{}
+	// Create peer storage so the peers survive a restart.
+	r.peerStore = raft.NewJSONPeers(r.path, r.transport)
 
-	// This server is joining the raft cluster for the first time if initializePeers are passed in
+	// This server is joining the raft cluster for the first time, or is
+	// recovering a cluster that lost quorum, if initializePeers are passed in
 	if len(initializePeers) > 0 {
 		if err := r.peerStore.SetPeers(initializePeers); err != nil {
 			return err
@@ -87,8 +89,28 @@ func (r *raftState) open(s *store, ln net.Listener, initializePeers []string) er
 		return err
 	}
 
-	// If no peers are set in the config or there is one and we are it, then start as a single server.
-	if len(initializePeers) <= 1 {
+	// Create the log store and stable store.
+	store, err := raftboltdb.NewBoltStore(filepath.Join(r.path, "raft.db"))
+	if err != nil {
+		return fmt.Errorf("new bolt store: %s", err)
+	}
+	r.raftStore = store
+
+	// A removed node is left with itself as only peer. It must not start as
+	// a single server next to the cluster it was removed from.
+	if len(peers) <= 1 && len(initializePeers) == 0 {
+		removed, err := removedPeer(store, r.addr)
+		if err != nil {
+			store.Close()
+			return err
+		} else if removed {
+			store.Close()
+			return ErrNodeRemoved
+		}
+	}
+
+	// If no peers are known or there is one and we are it, then start as a single server.
+	if len(peers) <= 1 {
 		config.EnableSingleNode = true
 
 		// Ensure we can always become the leader
@@ -105,13 +127,6 @@ func (r *raftState) open(s *store, ln net.Listener, initializePeers []string) er
 		peers = []string{r.addr}
 	}
 
-	// Create the log store and stable store.
-	store, err := raftboltdb.NewBoltStore(filepath.Join(r.path, "raft.db"))
-	if err != nil {
-		return fmt.Errorf("new bolt store: %s", err)
-	}
-	r.raftStore = store
-
 	// Create the snapshot store.
 	snapshots, err := raft.NewFileSnapshotStore(r.path, raftSnapshotsRetained, os.Stderr)
 	if err != nil {
@@ -131,6 +146,46 @@ func (r *raftState) open(s *store, ln net.Listener, initializePeers []string) er
 	return nil
 }
 
+// removedPeer returns true if the last change of the raft peers found in the
+// log removed addr.
+func removedPeer(logs raft.LogStore, addr string) (bool, error) {
+	first, err := logs.FirstIndex()
+	if err != nil {
+		return false, err
+	}
+	last, err := logs.LastIndex()
+	if err != nil {
+		return false, err
+	}
+
+	for i := last; i > 0 && i >= first; i-- {
+		var l raft.Log
+		if err := logs.GetLog(i, &l); err == raft.ErrLogNotFound {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+
+		switch l.Type {
+		case raft.LogAddPeer:
+			return false, nil
+		case raft.LogRemovePeer:
+			// The peers are encoded by raft as a msgpack array of addresses.
+			var peers [][]byte
+			if err := codec.NewDecoderBytes(l.Data, &codec.MsgpackHandle{}).Decode(&peers); err != nil {
+				return false, fmt.Errorf("decode peers: %s", err)
+			}
+			for _, p := range peers {
+				if string(p) == addr {
+					return false, nil
+				}
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (r *raftState) logLeaderChanges() {
 	defer r.wg.Done()
 	// Logs our current state (Node at 1.2.3.4:8088 [Follower])
@@ -185,32 +240,62 @@ func (r *raftState) isClosed() bool {
 
 // apply applies a serialized command to the raft log.
 func (r *raftState) apply(b []byte) error {
+	resp, err := r.execute(b)
+	if err != nil {
+		return err
+	}
+
+	// No other non-nil objects should be returned.
+	if resp != nil {
+		panic(fmt.Sprintf("unexpected response: %#v", resp))
+	}
+
+	return nil
+}
+
+// execute applies a serialized command to the raft log and returns the
+// response of the FSM, which some commands use to return their results.
+func (r *raftState) execute(b []byte) (interface{}, error) {
 	if r.isClosed() {
-		return raft.ErrRaftShutdown
+		return nil, raft.ErrRaftShutdown
 	}
 	// Apply to raft log.
 	f := r.raft.Apply(b, 0)
 	if err := f.Error(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Return response if it's an error.
-	// No other non-nil objects should be returned.
 	resp := f.Response()
 	if err, ok := resp.(error); ok {
-		return err
-	}
-	if resp != nil {
-		panic(fmt.Sprintf("unexpected response: %#v", resp))
+		return nil, err
 	}
-
-	return nil
+	return resp, nil
 }
 
 func (r *raftState) lastIndex() uint64 {
 	return r.raft.LastIndex()
 }
 
+// logs returns the commands in the raft log from index first to last.
+func (r *raftState) logs(first, last uint64) ([]*raft.Log, error) {
+	if r.raftStore == nil {
+		return nil, raft.ErrRaftShutdown
+	}
+
+	var logs []*raft.Log
+	for i := first; i <= last; i++ {
+		l := &raft.Log{}
+		if err := r.raftStore.GetLog(i, l); err != nil {
+			return nil, err
+		}
+		if l.Type == raft.LogCommand {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
 func (r *raftState) snapshot() error {
 	future := r.raft.Snapshot()
 	return future.Error()
@@ -340,22 +425,3 @@ func (l *raftLayer) Accept() (net.Conn, error) { return l.ln.Accept() }
 
 // Close closes the layer.
 func (l *raftLayer) Close() error { return l.ln.Close() }
-
-// peerStore is an in-memory implementation of raft.PeerStore
-type peerStore struct {
-	mu    sync.RWMutex
-	peers []string
-}
-
-func (m *peerStore) Peers() ([]string, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return m.peers, nil
-}
-
-func (m *peerStore) SetPeers(peers []string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.peers = peers
-	return nil
-}
@@ -0,0 +1,403 @@
+package meta
+
+import (
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/zhexuany/influxcloud/meta/internal"
+)
+
+// Permission is a named right granted to users and roles.
+type Permission string
+
+const (
+	// ReadDataPermission allows querying the data of a database.
+	ReadDataPermission Permission = "ReadData"
+
+	// WriteDataPermission allows writing points to a database.
+	WriteDataPermission Permission = "WriteData"
+
+	// ManageDatabasePermission allows creating and dropping databases,
+	// retention policies, measurements, continuous queries and
+	// subscriptions.
+	ManageDatabasePermission Permission = "ManageDatabase"
+
+	// ManageShardPermission allows listing and dropping shards.
+	ManageShardPermission Permission = "ManageShard"
+
+	// ManageQueryPermission allows listing and killing running queries.
+	ManageQueryPermission Permission = "ManageQuery"
+
+	// ManageUserPermission allows managing users, their privileges and
+	// their roles.
+	ManageUserPermission Permission = "ManageUser"
+
+	// MonitorPermission allows reading the statistics and diagnostics of a
+	// node.
+	MonitorPermission Permission = "Monitor"
+)
+
+// validPermissions holds the permissions that can be granted.
+var validPermissions = map[Permission]struct{}{
+	ReadDataPermission:       {},
+	WriteDataPermission:      {},
+	ManageDatabasePermission: {},
+	ManageShardPermission:    {},
+	ManageQueryPermission:    {},
+	ManageUserPermission:     {},
+	MonitorPermission:        {},
+}
+
+// Permissions maps a database to the permissions granted on it. Permissions
+// granted on the empty database apply to every database.
+type Permissions map[string][]Permission
+
+// Has returns true if p is granted on database or on every database.
+func (ps Permissions) Has(p Permission, database string) bool {
+	for _, resource := range []string{"", database} {
+		for _, other := range ps[resource] {
+			if other == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validate returns an error if ps holds an unknown permission.
+func (ps Permissions) validate() error {
+	for _, perms := range ps {
+		for _, p := range perms {
+			if _, ok := validPermissions[p]; !ok {
+				return ErrInvalidPermission
+			}
+		}
+	}
+	return nil
+}
+
+// add returns the union of ps and other.
+func (ps Permissions) add(other Permissions) Permissions {
+	result := ps.clone()
+	if result == nil {
+		result = make(Permissions)
+	}
+	for resource, perms := range other {
+		for _, p := range perms {
+			if !result.granted(p, resource) {
+				result[resource] = append(result[resource], p)
+			}
+		}
+		sort.Sort(permissionSlice(result[resource]))
+	}
+	return result
+}
+
+// remove returns ps without the permissions in other.
+func (ps Permissions) remove(other Permissions) Permissions {
+	result := make(Permissions, len(ps))
+	for resource, perms := range ps {
+		for _, p := range perms {
+			if !other.granted(p, resource) {
+				result[resource] = append(result[resource], p)
+			}
+		}
+	}
+	return result
+}
+
+// granted returns true if p is granted on exactly resource.
+func (ps Permissions) granted(p Permission, resource string) bool {
+	for _, other := range ps[resource] {
+		if other == p {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a deep copy of ps.
+func (ps Permissions) clone() Permissions {
+	if ps == nil {
+		return nil
+	}
+	other := make(Permissions, len(ps))
+	for resource, perms := range ps {
+		other[resource] = append([]Permission(nil), perms...)
+	}
+	return other
+}
+
+// marshalPermissions serializes ps to a protobuf representation sorted by
+// resource.
+func marshalPermissions(ps Permissions) []*internal.ScopedPermission {
+	resources := make([]string, 0, len(ps))
+	for resource, perms := range ps {
+		if len(perms) > 0 {
+			resources = append(resources, resource)
+		}
+	}
+	sort.Strings(resources)
+
+	pb := make([]*internal.ScopedPermission, len(resources))
+	for i, resource := range resources {
+		names := make([]string, len(ps[resource]))
+		for j, p := range ps[resource] {
+			names[j] = string(p)
+		}
+		pb[i] = &internal.ScopedPermission{
+			Resource:    proto.String(resource),
+			Permissions: names,
+		}
+	}
+	return pb
+}
+
+// unmarshalPermissions deserializes permissions from a protobuf
+// representation.
+func unmarshalPermissions(pb []*internal.ScopedPermission) Permissions {
+	if len(pb) == 0 {
+		return nil
+	}
+	ps := make(Permissions, len(pb))
+	for _, sp := range pb {
+		for _, name := range sp.GetPermissions() {
+			ps[sp.GetResource()] = append(ps[sp.GetResource()], Permission(name))
+		}
+	}
+	return ps
+}
+
+// permissionSlice sorts permissions by name.
+type permissionSlice []Permission
+
+func (a permissionSlice) Len() int           { return len(a) }
+func (a permissionSlice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a permissionSlice) Less(i, j int) bool { return a[i] < a[j] }
+
+// RoleInfo represents a named set of permissions granted to its users.
+type RoleInfo struct {
+	Name        string
+	Users       []string
+	Permissions Permissions
+}
+
+// clone returns a deep copy of ri.
+func (ri RoleInfo) clone() RoleInfo {
+	other := ri
+	if ri.Users != nil {
+		other.Users = make([]string, len(ri.Users))
+		copy(other.Users, ri.Users)
+	}
+	other.Permissions = ri.Permissions.clone()
+	return other
+}
+
+// hasUser returns true if name is a member of the role.
+func (ri *RoleInfo) hasUser(name string) bool {
+	for _, u := range ri.Users {
+		if u == name {
+			return true
+		}
+	}
+	return false
+}
+
+// marshal serializes to a protobuf representation.
+func (ri RoleInfo) marshal() *internal.RoleInfo {
+	return &internal.RoleInfo{
+		Name:        proto.String(ri.Name),
+		Users:       ri.Users,
+		Permissions: marshalPermissions(ri.Permissions),
+	}
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ri *RoleInfo) unmarshal(pb *internal.RoleInfo) {
+	ri.Name = pb.GetName()
+	ri.Users = pb.GetUsers()
+	ri.Permissions = unmarshalPermissions(pb.GetPermissions())
+}
+
+// Role returns a role by name.
+func (data *Data) Role(name string) *RoleInfo {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			return &data.Roles[i]
+		}
+	}
+	return nil
+}
+
+// CreateRole creates a new role without users or permissions.
+func (data *Data) CreateRole(name string) error {
+	if name == "" {
+		return ErrRoleNameRequired
+	} else if data.Role(name) != nil {
+		return ErrRoleExists
+	}
+
+	data.Roles = append(data.Roles, RoleInfo{Name: name})
+	sort.Sort(RoleInfos(data.Roles))
+	return nil
+}
+
+// DropRole removes a role by name.
+func (data *Data) DropRole(name string) error {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+			return nil
+		}
+	}
+	return ErrRoleNotFound
+}
+
+// ChangeRoleName renames a role.
+func (data *Data) ChangeRoleName(oldName, newName string) error {
+	if newName == "" {
+		return ErrRoleNameRequired
+	} else if data.Role(newName) != nil {
+		return ErrRoleExists
+	}
+
+	ri := data.Role(oldName)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+	ri.Name = newName
+	sort.Sort(RoleInfos(data.Roles))
+	return nil
+}
+
+// AddRoleUsers grants a role to existing users.
+func (data *Data) AddRoleUsers(name string, users []string) error {
+	ri := data.Role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	for _, u := range users {
+		if data.Data.User(u) == nil {
+			return ErrUserNotFound
+		}
+	}
+	for _, u := range users {
+		if !ri.hasUser(u) {
+			ri.Users = append(ri.Users, u)
+		}
+	}
+	sort.Strings(ri.Users)
+	return nil
+}
+
+// RemoveRoleUsers revokes a role from users.
+func (data *Data) RemoveRoleUsers(name string, users []string) error {
+	ri := data.Role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	remaining := make([]string, 0, len(ri.Users))
+	for _, u := range ri.Users {
+		removed := false
+		for _, other := range users {
+			if u == other {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			remaining = append(remaining, u)
+		}
+	}
+	ri.Users = remaining
+	return nil
+}
+
+// AddRolePermissions grants permissions to a role.
+func (data *Data) AddRolePermissions(name string, perms Permissions) error {
+	if err := perms.validate(); err != nil {
+		return err
+	}
+
+	ri := data.Role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+	ri.Permissions = ri.Permissions.add(perms)
+	return nil
+}
+
+// RemoveRolePermissions revokes permissions from a role.
+func (data *Data) RemoveRolePermissions(name string, perms Permissions) error {
+	ri := data.Role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+	ri.Permissions = ri.Permissions.remove(perms)
+	return nil
+}
+
+// AddUserPermissions grants permissions to an existing user.
+func (data *Data) AddUserPermissions(name string, perms Permissions) error {
+	if err := perms.validate(); err != nil {
+		return err
+	} else if data.Data.User(name) == nil {
+		return ErrUserNotFound
+	}
+
+	if data.UserPermissions == nil {
+		data.UserPermissions = make(map[string]Permissions)
+	}
+	data.UserPermissions[name] = data.UserPermissions[name].add(perms)
+	return nil
+}
+
+// RemoveUserPermissions revokes permissions granted directly to a user.
+func (data *Data) RemoveUserPermissions(name string, perms Permissions) error {
+	if data.Data.User(name) == nil {
+		return ErrUserNotFound
+	}
+
+	if remaining := data.UserPermissions[name].remove(perms); len(remaining) > 0 {
+		data.UserPermissions[name] = remaining
+	} else {
+		delete(data.UserPermissions, name)
+	}
+	return nil
+}
+
+// Permissions returns the permissions of a user, both the ones granted
+// directly and through its roles.
+func (data *Data) Permissions(username string) Permissions {
+	ps := data.UserPermissions[username].clone()
+	for i := range data.Roles {
+		if data.Roles[i].hasUser(username) {
+			ps = ps.add(data.Roles[i].Permissions)
+		}
+	}
+	return ps
+}
+
+// DropUser removes a user along with its permissions and role memberships.
+func (data *Data) DropUser(name string) error {
+	if err := data.Data.DropUser(name); err != nil {
+		return err
+	}
+
+	delete(data.UserPermissions, name)
+	for i := range data.Roles {
+		if data.Roles[i].hasUser(name) {
+			data.RemoveRoleUsers(data.Roles[i].Name, []string{name})
+		}
+	}
+	return nil
+}
+
+// RoleInfos is a slice of RoleInfo sorted by name.
+type RoleInfos []RoleInfo
+
+func (a RoleInfos) Len() int           { return len(a) }
+func (a RoleInfos) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a RoleInfos) Less(i, j int) bool { return a[i].Name < a[j].Name }
@@ -5,20 +5,27 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/httpd"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tcp"
 	"github.com/influxdata/influxdb/toml"
 	"github.com/zhexuany/influxcloud"
 	cloudMeta "github.com/zhexuany/influxcloud/meta"
+	"github.com/zhexuany/influxcloud/meta/internal"
 )
 
 func TestMetaService_CreateDatabase(t *testing.T) {
@@ -1006,6 +1013,1161 @@ func TestMetaService_DropDataNode_Reassign(t *testing.T) {
 	}
 }
 
+func TestMetaService_PendingShardOwner(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	n1, err := c.CreateDataNode("foo:8180", "bar:8181")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n2, err := c.CreateDataNode("foo:8280", "bar:8281")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 1
+	if _, err := c.CreateDatabaseWithRetentionPolicy("foo", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+
+	sg, err := c.CreateShardGroup("foo", "rp0", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Find a shard owned by the first node only.
+	var shardID uint64
+	for _, si := range sg.Shards {
+		if si.OwnedBy(n1.ID) {
+			shardID = si.ID
+		}
+	}
+	if shardID == 0 {
+		t.Fatalf("no shard owned by node %d: %v", n1.ID, sg.Shards)
+	}
+
+	owners := func() []meta.ShardOwner {
+		_, _, si := c.ShardOwner(shardID)
+		return si.Owners
+	}
+	pending := func() []uint64 {
+		n, err := c.DataNode(n2.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n.PendingShardOwners
+	}
+
+	// A pending owner is not an owner of the shard.
+	if err := c.AddPendingShardOwner(shardID, n2.ID); err != nil {
+		t.Fatal(err)
+	} else if got, exp := pending(), []uint64{shardID}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected pending shards: got %v, exp %v", got, exp)
+	} else if got, exp := owners(), []meta.ShardOwner{{NodeID: n1.ID}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected owners: got %v, exp %v", got, exp)
+	}
+
+	// Committing the pending owner makes it an owner of the shard.
+	if err := c.CommitPendingShardOwner(shardID, n2.ID); err != nil {
+		t.Fatal(err)
+	} else if got := pending(); len(got) != 0 {
+		t.Fatalf("unexpected pending shards: %v", got)
+	} else if got, exp := owners(), []meta.ShardOwner{{NodeID: n1.ID}, {NodeID: n2.ID}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected owners: got %v, exp %v", got, exp)
+	}
+
+	// An owner can't be added as a pending owner and a missing pending owner
+	// can't be committed.
+	if err := c.AddPendingShardOwner(shardID, n2.ID); err == nil || err.Error() != cloudMeta.ErrShardOwnerExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := c.CommitPendingShardOwner(shardID, n1.ID); err == nil || err.Error() != cloudMeta.ErrPendingShardOwnerNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Removing the original owner leaves the new owner only.
+	if err := c.RemoveShardOwner(shardID, n1.ID); err != nil {
+		t.Fatal(err)
+	} else if got, exp := owners(), []meta.ShardOwner{{NodeID: n2.ID}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected owners: got %v, exp %v", got, exp)
+	}
+
+	// An aborted copy removes the pending owner without changing the owners.
+	if err := c.AddPendingShardOwner(shardID, n1.ID); err != nil {
+		t.Fatal(err)
+	} else if err := c.RemovePendingShardOwner(shardID, n1.ID); err != nil {
+		t.Fatal(err)
+	} else if got, exp := owners(), []meta.ShardOwner{{NodeID: n2.ID}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected owners: got %v, exp %v", got, exp)
+	}
+}
+
+func TestMetaService_TruncateShardGroups(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8180", "bar:8181"); err != nil {
+		t.Fatal(err)
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 1
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	sg0, err := c.CreateShardGroup("db0", "rp0", now)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sg0.Shards) != 1 {
+		t.Fatalf("unexpected shards: %v", sg0.Shards)
+	}
+
+	// Precreate the next shard group.
+	sg1, err := c.CreateShardGroup("db0", "rp0", sg0.EndTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a node and truncate so writes pick it up.
+	if _, err := c.CreateDataNode("foo:8280", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+	truncateAt := now.Add(time.Second)
+	if err := c.TruncateShardGroups(truncateAt); err != nil {
+		t.Fatal(err)
+	}
+
+	rpi, err := c.RetentionPolicy("db0", "rp0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sgi := range rpi.ShardGroups {
+		switch sgi.ID {
+		case sg0.ID:
+			if !sgi.TruncatedAt.Equal(truncateAt) {
+				t.Fatalf("unexpected truncation of hot shard group: %s", sgi.TruncatedAt)
+			}
+		case sg1.ID:
+			if !sgi.TruncatedAt.Equal(sg1.StartTime) {
+				t.Fatalf("unexpected truncation of future shard group: %s", sgi.TruncatedAt)
+			}
+		}
+	}
+
+	// Writes before the truncation still go to the hot shard group.
+	if sg, err := c.CreateShardGroup("db0", "rp0", now); err != nil {
+		t.Fatal(err)
+	} else if sg.ID != sg0.ID {
+		t.Fatalf("unexpected shard group: %d", sg.ID)
+	}
+
+	// Writes after the truncation go to a new shard group on both nodes.
+	sg, err := c.CreateShardGroup("db0", "rp0", truncateAt)
+	if err != nil {
+		t.Fatal(err)
+	} else if sg.ID == sg0.ID || sg.ID == sg1.ID {
+		t.Fatalf("expected new shard group, got %d", sg.ID)
+	} else if len(sg.Shards) != 2 {
+		t.Fatalf("unexpected shards: %v", sg.Shards)
+	}
+}
+
+func TestMetaService_BalancedShardGroups(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	n1, err := c.CreateDataNode("foo:8180", "bar:8181")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 1
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first node owns a shard before the other nodes join.
+	sg0, err := c.CreateShardGroup("db0", "rp0", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n2, err := c.CreateDataNode("foo:8280", "bar:8281")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n3, err := c.CreateDataNode("foo:8380", "bar:8381")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDataNodeDiskUsage(n2.ID, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetShardPlacement("db0", "rp0", "bad"); err == nil || err.Error() != cloudMeta.ErrInvalidShardPlacement.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := c.SetShardPlacement("db0", "rp0", cloudMeta.ShardPlacementBalanced); err != nil {
+		t.Fatal(err)
+	} else if got := c.ShardPlacement("db0", "rp0"); got != cloudMeta.ShardPlacementBalanced {
+		t.Fatalf("unexpected shard placement: %s", got)
+	}
+
+	// Empty nodes are filled first, the one using less disk before the other.
+	sg, err := c.CreateShardGroup("db0", "rp0", sg0.EndTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var owners []uint64
+	for _, si := range sg.Shards {
+		for _, o := range si.Owners {
+			owners = append(owners, o.NodeID)
+		}
+	}
+	if exp := []uint64{n3.ID, n2.ID, n1.ID}; !reflect.DeepEqual(owners, exp) {
+		t.Fatalf("unexpected owners: got %v, exp %v", owners, exp)
+	}
+
+	// Replicas of a shard are placed on distinct nodes.
+	rp = meta.NewRetentionPolicyInfo("rp1")
+	rp.ReplicaN = 3
+	if _, err := c.CreateRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+	sg, err = c.CreateBalancedShardGroup("db0", "rp1", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sg.Shards) != 1 {
+		t.Fatalf("unexpected shards: %v", sg.Shards)
+	}
+	for _, id := range []uint64{n1.ID, n2.ID, n3.ID} {
+		if !sg.Shards[0].OwnedBy(id) {
+			t.Fatalf("shard not owned by node %d: %v", id, sg.Shards[0].Owners)
+		}
+	}
+}
+
+// Ensure the shard placement of a retention policy is removed with the
+// policy or its database.
+func TestMetaService_DropShardPlacement(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8180", "bar:8181"); err != nil {
+		t.Fatal(err)
+	}
+	for _, db := range []string{"db0", "db1"} {
+		if _, err := c.CreateDatabaseWithRetentionPolicy(db, rpi2rps(meta.NewRetentionPolicyInfo("rp0"))); err != nil {
+			t.Fatal(err)
+		} else if err := c.SetShardPlacement(db, "rp0", cloudMeta.ShardPlacementBalanced); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A policy created again with the same name uses the default placement.
+	if err := c.DropRetentionPolicy("db0", "rp0"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateRetentionPolicy("db0", rpi2rps(meta.NewRetentionPolicyInfo("rp0"))); err != nil {
+		t.Fatal(err)
+	} else if got := c.ShardPlacement("db0", "rp0"); got != cloudMeta.ShardPlacementRoundRobin {
+		t.Fatalf("unexpected shard placement: %s", got)
+	} else if got := c.ShardPlacement("db1", "rp0"); got != cloudMeta.ShardPlacementBalanced {
+		t.Fatalf("unexpected shard placement: %s", got)
+	}
+
+	if err := c.DropDatabase("db1"); err != nil {
+		t.Fatal(err)
+	} else if placements := c.Data().ShardPlacements; len(placements) != 0 {
+		t.Fatalf("unexpected shard placements: %v", placements)
+	}
+}
+
+func TestMetaService_LabelAwareShardGroups(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	// Two racks with two nodes each. Round-robin placement alone would put
+	// both replicas of a shard in the same rack.
+	racks := make(map[uint64]string)
+	for i, rack := range []string{"r1", "r1", "r2", "r2"} {
+		n, err := c.CreateDataNodeWithLabels(fmt.Sprintf("foo:%d", 8180+i), fmt.Sprintf("bar:%d", 8180+i), map[string]string{"rack": rack})
+		if err != nil {
+			t.Fatal(err)
+		} else if exp := map[string]string{"rack": rack}; !reflect.DeepEqual(n.Labels, exp) {
+			t.Fatalf("unexpected labels: %v", n.Labels)
+		}
+		racks[n.ID] = rack
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 2
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSpread := func(sg *meta.ShardGroupInfo) {
+		if len(sg.Shards) != 2 {
+			t.Fatalf("unexpected shards: %v", sg.Shards)
+		}
+		for _, si := range sg.Shards {
+			if r0, r1 := racks[si.Owners[0].NodeID], racks[si.Owners[1].NodeID]; r0 == r1 {
+				t.Fatalf("replicas of shard %d in the same rack: %v", si.ID, si.Owners)
+			}
+		}
+	}
+
+	now := time.Now()
+	sg, err := c.CreateShardGroup("db0", "rp0", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSpread(sg)
+
+	if err := c.SetShardPlacement("db0", "rp0", cloudMeta.ShardPlacementBalanced); err != nil {
+		t.Fatal(err)
+	}
+	sg, err = c.CreateShardGroup("db0", "rp0", sg.EndTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSpread(sg)
+
+	// Labels are replaced when a node is updated.
+	n, err := c.DataNodeByTCPHost("bar:8183")
+	if err != nil {
+		t.Fatal(err)
+	} else if err := c.UpdateDataNode(n.ID, n.Host, n.TCPHost, map[string]string{"rack": "r3"}); err != nil {
+		t.Fatal(err)
+	} else if n, err = c.DataNode(n.ID); err != nil {
+		t.Fatal(err)
+	} else if exp := map[string]string{"rack": "r3"}; !reflect.DeepEqual(n.Labels, exp) {
+		t.Fatalf("unexpected labels after update: %v", n.Labels)
+	}
+}
+
+func TestMetaService_SplitShardGroup(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8180", "bar:8181"); err != nil {
+		t.Fatal(err)
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 1
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	sg0, err := c.CreateShardGroup("db0", "rp0", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A split outside of the shard group is rejected.
+	if _, err := c.SplitShardGroup("db0", "rp0", sg0.ID, sg0.EndTime, 0); err == nil || err.Error() != cloudMeta.ErrShardGroupSplitTime.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := c.SplitShardGroup("db0", "rp0", 100, now, 0); err == nil || err.Error() != cloudMeta.ErrShardGroupNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Split the shard group once a second node has joined.
+	if _, err := c.CreateDataNode("foo:8280", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+	splitAt := now.Add(time.Second)
+	sg1, err := c.SplitShardGroup("db0", "rp0", sg0.ID, splitAt, 4)
+	if err != nil {
+		t.Fatal(err)
+	} else if sg1.ID == sg0.ID {
+		t.Fatalf("expected new shard group, got %d", sg1.ID)
+	} else if !sg1.StartTime.Equal(splitAt) || !sg1.EndTime.Equal(sg0.EndTime) {
+		t.Fatalf("unexpected time range: %s - %s", sg1.StartTime, sg1.EndTime)
+	} else if len(sg1.Shards) != 4 {
+		t.Fatalf("unexpected shards: %v", sg1.Shards)
+	}
+
+	// Writes before the split go to the old shards, later ones to the new.
+	if sg, err := c.CreateShardGroup("db0", "rp0", now); err != nil {
+		t.Fatal(err)
+	} else if sg.ID != sg0.ID {
+		t.Fatalf("unexpected shard group before split: %d", sg.ID)
+	}
+	if sg, err := c.CreateShardGroup("db0", "rp0", splitAt); err != nil {
+		t.Fatal(err)
+	} else if sg.ID != sg1.ID {
+		t.Fatalf("unexpected shard group after split: %d", sg.ID)
+	}
+
+	// Queries over the period cover the shards of both groups.
+	groups, err := c.ShardGroupsByTimeRange("db0", "rp0", now, splitAt.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(groups) != 2 {
+		t.Fatalf("unexpected shard groups: %v", groups)
+	}
+}
+
+func TestMetaService_RestoreShardGroup(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8180", "bar:8181"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateDataNode("foo:8280", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 2
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shard count of the backup is kept regardless of the node count.
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	sg, err := c.RestoreShardGroup("db0", "rp0", start, end, 3)
+	if err != nil {
+		t.Fatal(err)
+	} else if !sg.StartTime.Equal(start) || !sg.EndTime.Equal(end) {
+		t.Fatalf("unexpected time range: %s - %s", sg.StartTime, sg.EndTime)
+	} else if len(sg.Shards) != 3 {
+		t.Fatalf("unexpected shards: %v", sg.Shards)
+	}
+	for _, si := range sg.Shards {
+		if len(si.Owners) != 2 {
+			t.Fatalf("unexpected owners of shard %d: %v", si.ID, si.Owners)
+		}
+	}
+
+	// Restoring over existing shard groups is rejected.
+	if _, err := c.RestoreShardGroup("db0", "rp0", start.Add(time.Hour), end.Add(time.Hour), 1); err == nil || err.Error() != cloudMeta.ErrShardGroupExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetaService_ImportData(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDataNode("foo:8180", "bar:8181"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateDataNode("foo:8280", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+
+	rp := meta.NewRetentionPolicyInfo("rp0")
+	rp.ReplicaN = 2
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", rpi2rps(rp)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	sg, err := c.RestoreShardGroup("db0", "rp0", start, start.Add(24*time.Hour), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Import a copy of the retention policy next to the original with a
+	// single copy of each shard.
+	data := c.Data()
+	shardIDs, err := c.ImportData(data, "db0", "rp0", "db1", "rp1", 1, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(shardIDs) != len(sg.Shards) {
+		t.Fatalf("unexpected shard ids: %v", shardIDs)
+	}
+
+	rpi, err := c.RetentionPolicy("db1", "rp1")
+	if err != nil {
+		t.Fatal(err)
+	} else if rpi == nil || rpi.ReplicaN != 1 {
+		t.Fatalf("unexpected retention policy: %#v", rpi)
+	} else if len(rpi.ShardGroups) != 1 || !rpi.ShardGroups[0].StartTime.Equal(start) {
+		t.Fatalf("unexpected shard groups: %v", rpi.ShardGroups)
+	}
+	for i, si := range sg.Shards {
+		other := rpi.ShardGroups[0].Shards[i]
+		if shardIDs[si.ID] != other.ID || other.ID <= sg.Shards[len(sg.Shards)-1].ID {
+			t.Fatalf("unexpected shard id for shard %d: %d", si.ID, other.ID)
+		} else if len(other.Owners) != 1 {
+			t.Fatalf("unexpected owners of shard %d: %v", other.ID, other.Owners)
+		}
+	}
+
+	// The original retention policy is untouched.
+	if rpi, err := c.RetentionPolicy("db0", "rp0"); err != nil {
+		t.Fatal(err)
+	} else if len(rpi.ShardGroups) != 1 || rpi.ShardGroups[0].ID != sg.ID {
+		t.Fatalf("unexpected shard groups: %v", rpi.ShardGroups)
+	}
+
+	// Importing into an existing retention policy requires force.
+	if _, err := c.ImportData(data, "db0", "rp0", "db1", "rp1", 1, false); err == nil || err.Error() != meta.ErrRetentionPolicyExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A forced import skips the shard groups overlapping existing ones.
+	next, err := c.RestoreShardGroup("db0", "rp0", start.Add(24*time.Hour), start.Add(48*time.Hour), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shardIDs, err = c.ImportData(c.Data(), "db0", "rp0", "db1", "rp1", 1, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(shardIDs) != len(next.Shards) {
+		t.Fatalf("unexpected shard ids: %v", shardIDs)
+	}
+
+	rpi, err = c.RetentionPolicy("db1", "rp1")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rpi.ShardGroups) != 2 || !rpi.ShardGroups[1].StartTime.Equal(next.StartTime) {
+		t.Fatalf("unexpected shard groups: %v", rpi.ShardGroups)
+	}
+	for i, si := range next.Shards {
+		if id := rpi.ShardGroups[1].Shards[i].ID; shardIDs[si.ID] != id {
+			t.Fatalf("unexpected shard id for shard %d: %d", si.ID, id)
+		}
+	}
+}
+
+// Ensure a meta node keeps its raft peers after a restart without join peers.
+func TestMetaService_PersistPeersAfterRestart(t *testing.T) {
+	t.Parallel()
+	cfgs := make([]*cloudMeta.Config, 3)
+	srvs := make([]*testService, 3)
+	joinPeers := freePorts(len(cfgs))
+	raftPeers := freePorts(len(cfgs))
+
+	errc := make(chan error, len(cfgs))
+	for i := range cfgs {
+		c := newConfig()
+		c.HTTPBindAddress = joinPeers[i]
+		c.BindAddress = raftPeers[i]
+		c.JoinPeers = joinPeers
+		cfgs[i] = c
+		defer os.RemoveAll(c.Dir)
+
+		srvs[i] = newService(c)
+		go func(srv *testService) {
+			errc <- srv.Open()
+		}(srvs[i])
+	}
+	for range cfgs {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer srvs[0].Close()
+	defer srvs[1].Close()
+
+	if err := srvs[2].Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the node without join peers.
+	cfgs[2].JoinPeers = nil
+	s := newService(cfgs[2])
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if peers := mustGetPeers(t, s.HTTPAddr()); len(peers) != 3 {
+		t.Fatalf("wrong peers after restart: %v", peers)
+	}
+}
+
+// Ensure a meta cluster that lost quorum is recovered from a surviving node.
+func TestMetaService_RecoverPeers(t *testing.T) {
+	t.Parallel()
+	cfgs := make([]*cloudMeta.Config, 3)
+	srvs := make([]*testService, 3)
+	joinPeers := freePorts(len(cfgs))
+	raftPeers := freePorts(len(cfgs))
+
+	errc := make(chan error, len(cfgs))
+	for i := range cfgs {
+		c := newConfig()
+		c.HTTPBindAddress = joinPeers[i]
+		c.BindAddress = raftPeers[i]
+		c.JoinPeers = joinPeers
+		cfgs[i] = c
+		defer os.RemoveAll(c.Dir)
+
+		srvs[i] = newService(c)
+		go func(srv *testService) {
+			errc <- srv.Open()
+		}(srvs[i])
+	}
+	for range cfgs {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := cloudMeta.NewClient(cfgs[0])
+	c.SetMetaServers(joinPeers[:1])
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	// Lose every node and restart the first one with itself as only peer.
+	for _, srv := range srvs {
+		if err := srv.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfgs[0].JoinPeers = nil
+	cfgs[0].RecoverPeers = []string{raftPeers[0]}
+	s := newService(cfgs[0])
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if peers := mustGetPeers(t, s.HTTPAddr()); !reflect.DeepEqual(peers, raftPeers[:1]) {
+		t.Fatalf("wrong peers after recovery: %v", peers)
+	}
+
+	c = newClient(s)
+	defer c.Close()
+	if db, err := c.Database("db0"); err != nil {
+		t.Fatal(err)
+	} else if db == nil {
+		t.Fatal("database lost during recovery")
+	}
+
+	// Remove the lost meta nodes.
+	metaNodes, err := c.MetaNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range metaNodes {
+		if n.TCPHost == raftPeers[0] {
+			continue
+		}
+		if err := c.DeleteMetaNode(n.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if metaNodes, _ = c.MetaNodes(); len(metaNodes) != 1 {
+		t.Fatalf("wrong meta nodes: %v", metaNodes)
+	}
+	if err := c.DeleteMetaNode(metaNodes[0].ID); err == nil || err.Error() != cloudMeta.ErrNodeUnableToDropFinalNode.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a meta node removed from the cluster refuses to restart on its own.
+func TestMetaService_RestartRemovedNode(t *testing.T) {
+	t.Parallel()
+	cfgs := make([]*cloudMeta.Config, 3)
+	srvs := make([]*testService, 3)
+	joinPeers := freePorts(len(cfgs))
+	raftPeers := freePorts(len(cfgs))
+
+	errc := make(chan error, len(cfgs))
+	for i := range cfgs {
+		c := newConfig()
+		c.HTTPBindAddress = joinPeers[i]
+		c.BindAddress = raftPeers[i]
+		c.JoinPeers = joinPeers
+		cfgs[i] = c
+		defer os.RemoveAll(c.Dir)
+
+		srvs[i] = newService(c)
+		go func(srv *testService) {
+			errc <- srv.Open()
+		}(srvs[i])
+	}
+	for range cfgs {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer srvs[0].Close()
+	defer srvs[1].Close()
+
+	c := cloudMeta.NewClient(cfgs[0])
+	c.SetMetaServers(joinPeers[:1])
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	metaNodes, err := c.MetaNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range metaNodes {
+		if n.TCPHost != raftPeers[2] {
+			continue
+		}
+		if err := c.DeleteMetaNode(n.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Wait for the removed node to learn about its removal.
+	timeout := time.After(5 * time.Second)
+	for len(mustGetPeers(t, srvs[2].HTTPAddr())) > 1 {
+		select {
+		case <-timeout:
+			t.Fatal("removed node still has peers")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if err := srvs[2].Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfgs[2].JoinPeers = nil
+	s := newService(cfgs[2])
+	if err := s.Open(); err == nil || err.Error() != "raft: "+cloudMeta.ErrNodeRemoved.Error() {
+		s.Close()
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a non-voting meta node serves the meta data of the voters and
+// forwards commands to the leader without joining raft.
+func TestMetaService_NonVoting(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	cfg := newConfig()
+	cfg.NonVoting = true
+	cfg.JoinPeers = []string{s.HTTPAddr()}
+	defer os.RemoveAll(cfg.Dir)
+	o := newService(cfg)
+	if err := o.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer o.Close()
+
+	// Commands sent to the non-voting node are applied by the leader.
+	oc := newClient(o)
+	defer oc.Close()
+	if _, err := oc.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if db, err := oc.Database("db0"); err != nil {
+		t.Fatal(err)
+	} else if db == nil {
+		t.Fatal("database not replicated to non-voting node")
+	}
+
+	// The non-voting node is neither a raft peer nor a meta node.
+	peers := mustGetPeers(t, s.HTTPAddr())
+	if len(peers) != 1 {
+		t.Fatalf("wrong peers on voter: %v", peers)
+	} else if other := mustGetPeers(t, o.HTTPAddr()); !reflect.DeepEqual(other, peers) {
+		t.Fatalf("wrong peers on non-voting node: %v", other)
+	}
+	if metaNodes, err := c.MetaNodes(); err != nil {
+		t.Fatal(err)
+	} else if len(metaNodes) != 1 {
+		t.Fatalf("wrong meta nodes: %v", metaNodes)
+	}
+	if err := oc.Ping(true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure closing a client aborts its requests in flight.
+func TestClient_Close_AbortRequest(t *testing.T) {
+	t.Parallel()
+
+	requested := make(chan struct{}, 1)
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requested <- struct{}{}:
+		default:
+		}
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	cfg := newConfig()
+	defer os.RemoveAll(cfg.Dir)
+	c := cloudMeta.NewClient(cfg)
+	c.SetMetaServers([]string{strings.TrimPrefix(ts.URL, "http://")})
+
+	errc := make(chan error, 1)
+	go func() { errc <- c.Open() }()
+
+	<-requested
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-errc:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not aborted on close")
+	}
+}
+
+// Ensure a non-voting meta node can't be opened without voters.
+func TestMetaService_NonVoting_JoinPeersRequired(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfig()
+	cfg.NonVoting = true
+	defer os.RemoveAll(cfg.Dir)
+	o := newService(cfg)
+	defer o.Close()
+	if err := o.Open(); err != cloudMeta.ErrJoinPeersRequired {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure clients follow the meta data from the commands applied since their
+// index instead of full snapshots.
+func TestMetaService_SnapshotDelta(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	snap := mustGetSnapshotDelta(t, s.HTTPAddr(), 0)
+	if snap.Snapshot == nil || len(snap.Logs) != 0 {
+		t.Fatalf("expected full snapshot for index 0: %v", snap)
+	}
+	data := &cloudMeta.Data{}
+	if err := data.UnmarshalBinary(snap.Snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CreateDatabase("db1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DropDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	delta := mustGetSnapshotDelta(t, s.HTTPAddr(), data.Data.Index)
+	if delta.Snapshot != nil || len(delta.Logs) != 2 {
+		t.Fatalf("expected 2 logs: %v", delta)
+	}
+
+	// The client applied the same commands as the meta node.
+	if dbs, err := c.Databases(); err != nil {
+		t.Fatal(err)
+	} else if len(dbs) != 1 || dbs[0].Name != "db1" {
+		t.Fatalf("unexpected databases: %v", dbs)
+	}
+	full := mustGetSnapshotDelta(t, s.HTTPAddr(), 0)
+	if exp, err := c.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(full.Snapshot, exp) {
+		t.Fatal("client data differs from meta node")
+	}
+}
+
+// Ensure requests not signed with the shared secret are rejected when auth is
+// enabled.
+func TestMetaService_Auth(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfig()
+	cfg.AuthEnabled = true
+	cfg.InternalSharedSecret = "secret"
+	defer os.RemoveAll(cfg.Dir)
+	s := newService(cfg)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Pings stay open, the rest of the API requires a signed request.
+	for _, tt := range []struct {
+		path   string
+		secret string
+		code   int
+	}{
+		{path: "/ping", code: http.StatusOK},
+		{path: "/peers", code: http.StatusUnauthorized},
+		{path: "/peers", secret: "wrong", code: http.StatusUnauthorized},
+		{path: "/peers", secret: "secret", code: http.StatusOK},
+		{path: "/?index=0", code: http.StatusUnauthorized},
+	} {
+		req, err := http.NewRequest("GET", "http://"+s.HTTPAddr()+tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.secret != "" {
+			if err := cloudMeta.SignRequest(req, tt.secret); err != nil {
+				t.Fatal(err)
+			}
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.code {
+			t.Fatalf("%s signed with %q: exp %d, got %d", tt.path, tt.secret, tt.code, resp.StatusCode)
+		}
+	}
+
+	// A client with the secret signs its requests.
+	ccfg := newConfig()
+	ccfg.AuthEnabled = true
+	ccfg.InternalSharedSecret = "secret"
+	c := cloudMeta.NewClient(ccfg)
+	c.SetMetaServers([]string{s.HTTPAddr()})
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if db, err := c.Database("db0"); err != nil {
+		t.Fatal(err)
+	} else if db == nil {
+		t.Fatal("database not created")
+	}
+}
+
+// Ensure roles grant their permissions to their users.
+func TestMetaService_Roles(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := c.CreateUser(name, "pass", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := c.CreateRole("readers"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateRole("readers"); err == nil || err.Error() != cloudMeta.ErrRoleExists.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.AddRolePermissions("readers", cloudMeta.Permissions{"db0": {cloudMeta.ReadDataPermission}}); err != nil {
+		t.Fatal(err)
+	} else if err := c.AddRolePermissions("readers", cloudMeta.Permissions{"db0": {"Fly"}}); err == nil || err.Error() != cloudMeta.ErrInvalidPermission.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.AddRoleUsers("readers", []string{"alice", "bob"}); err != nil {
+		t.Fatal(err)
+	} else if err := c.AddRoleUsers("readers", []string{"carol"}); err == nil || err.Error() != cloudMeta.ErrUserNotFound.Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.AddUserPermissions("alice", cloudMeta.Permissions{"": {cloudMeta.ManageQueryPermission}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Users get the permissions of their roles and their own.
+	if exp, got := (cloudMeta.Permissions{"": {cloudMeta.ManageQueryPermission}, "db0": {cloudMeta.ReadDataPermission}}), c.Permissions("alice"); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected permissions: exp %v, got %v", exp, got)
+	} else if !c.Permissions("bob").Has(cloudMeta.ReadDataPermission, "db0") || c.Permissions("bob").Has(cloudMeta.ReadDataPermission, "db1") {
+		t.Fatalf("unexpected permissions: %v", c.Permissions("bob"))
+	}
+
+	if err := c.ChangeRoleName("readers", "analysts"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveRoleUsers("analysts", []string{"bob"}); err != nil {
+		t.Fatal(err)
+	} else if len(c.Permissions("bob")) != 0 {
+		t.Fatalf("unexpected permissions: %v", c.Permissions("bob"))
+	}
+
+	// Dropping a user removes it from its roles.
+	if err := c.DropUser("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if ri, err := c.Role("analysts"); err != nil {
+		t.Fatal(err)
+	} else if len(ri.Users) != 0 {
+		t.Fatalf("unexpected role users: %v", ri.Users)
+	}
+
+	if err := c.DropRole("analysts"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.Role("analysts"); err != cloudMeta.ErrRoleNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure queries and writes are authorized by the permissions of the user.
+func TestMetaService_RoleAuthorization(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	u, err := c.CreateUser("alice", "pass", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateRole("operators"); err != nil {
+		t.Fatal(err)
+	} else if err := c.AddRoleUsers("operators", []string{"alice"}); err != nil {
+		t.Fatal(err)
+	} else if err := c.AddRolePermissions("operators", cloudMeta.Permissions{
+		"":    {cloudMeta.ManageQueryPermission},
+		"db0": {cloudMeta.ReadDataPermission, cloudMeta.WriteDataPermission},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	qa := cloudMeta.NewQueryAuthorizer(c)
+	for _, tt := range []struct {
+		q   string
+		db  string
+		err bool
+	}{
+		{q: "SELECT * FROM cpu", db: "db0"},
+		{q: "SELECT * FROM cpu", db: "db1", err: true},
+		{q: "KILL QUERY 1"},
+		{q: "DROP SHARD 1", err: true},
+		{q: "CREATE USER bob WITH PASSWORD 'pass'", err: true},
+	} {
+		q, err := influxql.ParseQuery(tt.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := qa.AuthorizeQuery(u, q, tt.db); (err != nil) != tt.err {
+			t.Fatalf("%s on %q: unexpected error: %v", tt.q, tt.db, err)
+		}
+	}
+
+	wa := cloudMeta.NewWriteAuthorizer(c)
+	if err := wa.AuthorizeWrite("alice", "db0"); err != nil {
+		t.Fatal(err)
+	} else if err := wa.AuthorizeWrite("alice", "db1"); err == nil {
+		t.Fatal("expected write to db1 to be denied")
+	}
+}
+
+// Ensure the permissions of roles are enforced on queries and writes served
+// by the HTTP handler of a data node.
+func TestMetaService_RoleAuthorization_Handler(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	for _, db := range []string{"db0", "db1"} {
+		if _, err := c.CreateDatabase(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Users are only authenticated once an admin user exists.
+	if _, err := c.CreateUser("admin", "pass", true); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("alice", "pass", false); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateRole("writers"); err != nil {
+		t.Fatal(err)
+	} else if err := c.AddRoleUsers("writers", []string{"alice"}); err != nil {
+		t.Fatal(err)
+	} else if err := c.AddRolePermissions("writers", cloudMeta.Permissions{
+		"db0": {cloudMeta.ReadDataPermission, cloudMeta.WriteDataPermission},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := httpd.NewConfig()
+	config.AuthEnabled = true
+	config.LogEnabled = false
+	h := httpd.NewHandler(config)
+	h.MetaClient = &handlerMetaClient{c}
+	h.QueryAuthorizer = cloudMeta.NewQueryAuthorizer(c)
+	h.WriteAuthorizer = cloudMeta.NewWriteAuthorizer(c)
+	h.QueryExecutor = influxql.NewQueryExecutor()
+	h.QueryExecutor.StatementExecutor = &handlerStatementExecutor{}
+	h.PointsWriter = &handlerPointsWriter{}
+
+	for _, tt := range []struct {
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{method: "GET", path: "/query?db=db0&q=SELECT+*+FROM+cpu", code: http.StatusOK},
+		{method: "GET", path: "/query?db=db1&q=SELECT+*+FROM+cpu", code: http.StatusForbidden},
+		{method: "POST", path: "/write?db=db0", body: "cpu value=1", code: http.StatusNoContent},
+		{method: "POST", path: "/write?db=db1", body: "cpu value=1", code: http.StatusForbidden},
+	} {
+		r, err := http.NewRequest(tt.method, tt.path+"&u=alice&p=pass", strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.code {
+			t.Fatalf("%s %s: unexpected status: got %d, exp %d: %s", tt.method, tt.path, w.Code, tt.code, w.Body.String())
+		}
+	}
+}
+
 func TestMetaService_PersistClusterIDAfterRestart(t *testing.T) {
 	t.Skip("not enabled")
 	t.Parallel()
@@ -1208,6 +2370,30 @@ func newService(cfg *cloudMeta.Config) *testService {
 	return &testService{Service: s, ln: ln}
 }
 
+// handlerMetaClient adapts a meta client to the HTTP handler.
+type handlerMetaClient struct {
+	*cloudMeta.Client
+}
+
+func (c *handlerMetaClient) Database(name string) *meta.DatabaseInfo {
+	dbi, _ := c.Client.Database(name)
+	return dbi
+}
+
+// handlerStatementExecutor executes every statement without results.
+type handlerStatementExecutor struct{}
+
+func (e *handlerStatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+	return nil
+}
+
+// handlerPointsWriter discards the points written.
+type handlerPointsWriter struct{}
+
+func (w *handlerPointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+	return nil
+}
+
 func mustParseStatement(s string) influxql.Statement {
 	stmt, err := influxql.ParseStatement(s)
 	if err != nil {
@@ -1237,3 +2423,38 @@ func freePorts(i int) []string {
 	}
 	return ports
 }
+
+// mustGetPeers returns the raft peers of the meta node at addr.
+func mustGetPeers(t *testing.T, addr string) []string {
+	resp, err := http.Get("http://" + addr + "/peers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var peers []string
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		t.Fatal(err)
+	}
+	return peers
+}
+
+// mustGetSnapshotDelta returns the snapshot delta of the meta node at addr
+// since index.
+func mustGetSnapshotDelta(t *testing.T, addr string, index uint64) *internal.SnapshotDelta {
+	resp, err := http.Get(fmt.Sprintf("http://%s?index=%d&delta=true", addr, index))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pb internal.SnapshotDelta
+	if err := proto.Unmarshal(b, &pb); err != nil {
+		t.Fatal(err)
+	}
+	return &pb
+}
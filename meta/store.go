@@ -33,6 +33,10 @@ const (
 // Raft configuration.
 const (
 	raftListenerStartupTimeout = time.Second
+
+	// maxSnapshotDeltaLogs is the most commands sent to a polling client
+	// before a full snapshot is sent instead.
+	maxSnapshotDeltaLogs = 1000
 )
 
 type store struct {
@@ -42,6 +46,7 @@ type store struct {
 	config      *Config
 	data        *Data
 	raftState   *raftState
+	observer    *observer
 	dataChanged chan struct{}
 	path        string
 	opened      bool
@@ -93,14 +98,27 @@ func (s *store) open(raftln net.Listener) error {
 		return err
 	}
 
+	if s.config.NonVoting {
+		return s.openObserver(joinPeers)
+	}
+
 	joinPeers = s.config.JoinPeers
 	var initializePeers []string
-	if len(joinPeers) > 0 {
+	if len(s.config.RecoverPeers) > 0 {
+		// Force the peers of a cluster that lost quorum. The raft log and
+		// meta data of this node are kept, the lost peers are dropped.
+		initializePeers = s.config.RecoverPeers
+		if !Peers(initializePeers).Contains(s.raftAddr) {
+			initializePeers = append(initializePeers, s.raftAddr)
+		}
+		s.logger.Printf("recovering raft cluster with peers %v", initializePeers)
+		joinPeers = nil
+	} else if len(joinPeers) > 0 {
 		c := NewClient(s.config)
 		c.SetMetaServers(joinPeers)
 		c.SetTLS(s.config.HTTPSEnabled)
 		for {
-			peers := c.peers()
+			peers := c.peers(0)
 			if !Peers(peers).Contains(s.raftAddr) {
 				peers = append(peers, s.raftAddr)
 			}
@@ -181,6 +199,49 @@ func (s *store) open(raftln net.Listener) error {
 	return nil
 }
 
+// openObserver opens a non-voting store following the meta data of the
+// voters in joinPeers.
+func (s *store) openObserver(joinPeers []string) error {
+	if len(joinPeers) == 0 {
+		return ErrJoinPeersRequired
+	}
+	if err := s.setOpen(); err != nil {
+		return err
+	}
+
+	o := newObserver(s.config, joinPeers)
+	o.logger = s.logger
+	s.mu.Lock()
+	s.observer = o
+	s.mu.Unlock()
+	o.open(s)
+
+	// Wait for the leader and the meta data of the voters.
+	if err := s.waitForLeader(0); err != nil {
+		return err
+	}
+	select {
+	case <-s.closing:
+		return errors.New("closing")
+	case <-s.afterIndex(0):
+		return nil
+	}
+}
+
+// setData replaces the meta data of a non-voting store with a newer
+// snapshot of the voters.
+func (s *store) setData(data *Data) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if data.Data.Index <= s.data.Data.Index {
+		return
+	}
+
+	s.data = data
+	close(s.dataChanged)
+	s.dataChanged = make(chan struct{})
+}
+
 // setMetaNode is used when the raft group has only a single peer. It will
 // either create a metanode or update the information for the one metanode
 // that is there. It's used because hostnames can change
@@ -217,6 +278,9 @@ func (s *store) setOpen() error {
 
 // peers returns the raft peers known to this store
 func (s *store) peers() []string {
+	if o := s.observerState(); o != nil {
+		return o.peerAddrs()
+	}
 	if s.raftOpened() {
 		return []string{s.raftAddr}
 	}
@@ -278,6 +342,13 @@ func (s *store) openRaft(initializePeers []string, raftln net.Listener) error {
 	return nil
 }
 
+// observerState returns the observer of a non-voting store or nil.
+func (s *store) observerState() *observer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.observer
+}
+
 // raftOpened will return true is raftState is not nil
 // otherise will return false.
 func (s *store) raftOpened() bool {
@@ -326,17 +397,23 @@ func (s *store) reset(st *store) error {
 
 func (s *store) close() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	select {
 	case <-s.closing:
 		// already closed
+		s.mu.Unlock()
 		return nil
 	default:
 		//closing
 		close(s.closing)
-		return s.raftState.close()
 	}
+	s.mu.Unlock()
+
+	// Raft waits for the FSM to finish applying logs when it shuts down,
+	// which requires the lock.
+	if err := s.observer.close(); err != nil {
+		return err
+	}
+	return s.raftState.close()
 }
 
 func (s *store) snapshot() (*Data, error) {
@@ -345,6 +422,48 @@ func (s *store) snapshot() (*Data, error) {
 	return s.data.Clone(), nil
 }
 
+// currentData returns the meta data of the store without copying it. The
+// data must not be modified.
+func (s *store) currentData() *Data {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+// snapshotDelta returns the commands applied after index. A full snapshot is
+// returned instead if the commands are no longer in the raft log or there
+// are too many of them. A non-voting node has no raft log, so its clients
+// always receive full snapshots.
+func (s *store) snapshotDelta(index uint64) (*internal.SnapshotDelta, error) {
+	s.mu.RLock()
+	data, rs := s.data, s.raftState
+	s.mu.RUnlock()
+
+	last := data.Data.Index
+	if index > 0 && index <= last && last-index <= maxSnapshotDeltaLogs && rs != nil {
+		if logs, err := rs.logs(index+1, last); err == nil {
+			pb := &internal.SnapshotDelta{
+				Logs:                make([]*internal.LogEntry, len(logs)),
+				RetentionAutoCreate: proto.Bool(s.config.RetentionAutoCreate),
+			}
+			for i, l := range logs {
+				pb.Logs[i] = &internal.LogEntry{
+					Index: proto.Uint64(l.Index),
+					Term:  proto.Uint64(l.Term),
+					Data:  l.Data,
+				}
+			}
+			return pb, nil
+		}
+	}
+
+	b, err := data.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &internal.SnapshotDelta{Snapshot: b}, nil
+}
+
 func (s *store) setSnapshot(data *Data) error {
 	dataB, err := data.MarshalBinary()
 	if err != nil {
@@ -438,6 +557,9 @@ func (s *store) isLeader() bool {
 func (s *store) leader() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	if s.observer != nil {
+		return s.observer.leaderAddr()
+	}
 	if s.raftState == nil || s.raftState.raft == nil {
 		return ""
 	}
@@ -449,10 +571,12 @@ func (s *store) leader() string {
 func (s *store) leaderHTTP() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	if s.raftState == nil {
-		return ""
+	var l string
+	if s.observer != nil {
+		l = s.observer.leaderAddr()
+	} else if s.raftState != nil {
+		l = s.raftState.raft.Leader()
 	}
-	l := s.raftState.raft.Leader()
 
 	for _, n := range s.data.MetaNodes {
 		if n.TCPHost == l {
@@ -521,15 +645,72 @@ func (s *store) index() uint64 {
 
 // apply applies a command to raft.
 func (s *store) apply(b []byte) error {
+	_, err := s.execute(b)
+	return err
+}
+
+// execute applies a command to raft and returns the response of the FSM.
+func (s *store) execute(b []byte) (interface{}, error) {
+	// A non-voting store redirects commands to the leader.
+	if s.observerState() != nil {
+		return nil, raft.ErrNotLeader
+	}
 	if s.raftState == nil {
-		return fmt.Errorf("store not open")
+		return nil, fmt.Errorf("store not open")
 	}
 
+	// Deleting a meta node also changes the raft peers.
+	var cmd internal.Command
+	if err := proto.Unmarshal(b, &cmd); err != nil {
+		return nil, err
+	}
+	if cmd.GetType() == internal.Command_DeleteMetaNodeCommand {
+		ext, _ := proto.GetExtension(&cmd, internal.E_DeleteMetaNodeCommand_Command)
+		return nil, s.removeMetaNode(ext.(*internal.DeleteMetaNodeCommand).GetID(), b)
+	}
+
+	return s.raftState.execute(b)
+}
+
+// removeMetaNode removes the meta node id from the raft peers and applies the
+// command b deleting it from the meta data. The peer is removed first so a
+// failure never leaves a raft member unknown to the meta data. A leader
+// removing itself applies the command first as it can't apply commands once
+// it left the raft cluster.
+func (s *store) removeMetaNode(id uint64, b []byte) error {
+	if !s.raftState.isLeader() {
+		return raft.ErrNotLeader
+	}
+
+	s.mu.RLock()
+	n := s.data.MetaNode(id)
+	metaN := len(s.data.MetaNodes)
+	s.mu.RUnlock()
+	if n == nil {
+		return ErrNodeNotFound
+	} else if metaN == 1 {
+		return ErrNodeUnableToDropFinalNode
+	}
+
+	if n.TCPHost == s.raftAddr {
+		if err := s.raftState.apply(b); err != nil {
+			return err
+		}
+		return s.removePeer(n.TCPHost)
+	}
+
+	if err := s.removePeer(n.TCPHost); err != nil {
+		return err
+	}
 	return s.raftState.apply(b)
 }
 
 // join adds a new server to the metaservice and raft
 func (s *store) join(n *NodeInfo) (*NodeInfo, error) {
+	if s.observerState() != nil {
+		return nil, raft.ErrNotLeader
+	}
+
 	s.mu.RLock()
 	if s.raftState == nil {
 		s.mu.RUnlock()
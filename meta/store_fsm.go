@@ -68,19 +68,60 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 		case internal.Command_CreateMetaNodeCommand:
 			return fsm.applyCreateMetaNodeCommand(&cmd)
 		case internal.Command_DeleteMetaNodeCommand:
-			return fsm.applyDeleteMetaNodeCommand(&cmd, s)
+			return fsm.applyDeleteMetaNodeCommand(&cmd)
 		case internal.Command_SetMetaNodeCommand:
 			return fsm.applySetMetaNodeCommand(&cmd)
 		case internal.Command_CreateDataNodeCommand:
 			return fsm.applyCreateDataNodeCommand(&cmd)
+		case internal.Command_UpdateDataNodeCommand:
+			return fsm.applyUpdateDataNodeCommand(&cmd)
 		case internal.Command_DeleteDataNodeCommand:
 			return fsm.applyDeleteDataNodeCommand(&cmd)
 		case internal.Command_AddShardOwnerCommand:
-			// return fsm.applyAddShardOwnerCommand(&cmd)
+			return fsm.applyAddShardOwnerCommand(&cmd)
+		case internal.Command_RemoveShardOwnerCommand:
+			return fsm.applyRemoveShardOwnerCommand(&cmd)
+		case internal.Command_AddPendingShardOwnerCommand:
+			return fsm.applyAddPendingShardOwnerCommand(&cmd)
+		case internal.Command_RemovePendingShardOwnerCommand:
+			return fsm.applyRemovePendingShardOwnerCommand(&cmd)
+		case internal.Command_CommitPendingShardOwnerCommand:
+			return fsm.applyCommitPendingShardOwnerCommand(&cmd)
+		case internal.Command_TruncateShardGroupsCommand:
+			return fsm.applyTruncateShardGroupsCommand(&cmd)
+		case internal.Command_CreateBalancedShardGroupCommand:
+			return fsm.applyCreateBalancedShardGroupCommand(&cmd)
+		case internal.Command_SetShardPlacementCommand:
+			return fsm.applySetShardPlacementCommand(&cmd)
+		case internal.Command_SetDataNodeDiskUsageCommand:
+			return fsm.applySetDataNodeDiskUsageCommand(&cmd)
+		case internal.Command_SplitShardGroupCommand:
+			return fsm.applySplitShardGroupCommand(&cmd)
+		case internal.Command_RestoreShardGroupCommand:
+			return fsm.applyRestoreShardGroupCommand(&cmd)
+		case internal.Command_ImportDataCommand:
+			return fsm.applyImportDataCommand(&cmd)
+		case internal.Command_CreateRoleCommand:
+			return fsm.applyCreateRoleCommand(&cmd)
+		case internal.Command_DropRoleCommand:
+			return fsm.applyDropRoleCommand(&cmd)
+		case internal.Command_ChangeRoleNameCommand:
+			return fsm.applyChangeRoleNameCommand(&cmd)
+		case internal.Command_AddRoleUsersCommand:
+			return fsm.applyAddRoleUsersCommand(&cmd)
+		case internal.Command_RemoveRoleUsersCommand:
+			return fsm.applyRemoveRoleUsersCommand(&cmd)
+		case internal.Command_AddRolePermissionsCommand:
+			return fsm.applyAddRolePermissionsCommand(&cmd)
+		case internal.Command_RemoveRolePermissionsCommand:
+			return fsm.applyRemoveRolePermissionsCommand(&cmd)
+		case internal.Command_AddUserPermissionsCommand:
+			return fsm.applyAddUserPermissionsCommand(&cmd)
+		case internal.Command_RemoveUserPermissionsCommand:
+			return fsm.applyRemoveUserPermissionsCommand(&cmd)
 		default:
 			panic(fmt.Errorf("cannot apply command: %x", l.Data))
 		}
-		return nil
 	}()
 
 	// Copy term and index to new metadata.
@@ -95,22 +136,15 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 }
 
 func (fsm *storeFSM) applyUpdateDataNodeCommand(cmd *internal.Command) interface{} {
-	ext, err := proto.GetExtension(cmd, internal.E_CreateDataNodeCommand_Command)
-	if err != nil {
-		return nil
-	}
+	ext, _ := proto.GetExtension(cmd, internal.E_UpdateDataNodeCommand_Command)
 	v := ext.(*internal.UpdateDataNodeCommand)
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	node := other.DataNode(v.GetID())
-	if node == nil {
-		return nil
+	if err := other.UpdateDataNode(v.GetID(), v.GetHost(), v.GetTCPHost(), unmarshalLabels(v.GetLabels())); err != nil {
+		return err
 	}
 
-	node.Host = v.GetHost()
-	node.TCPHost = v.GetTCPHost()
-
 	fsm.data = other
 	return nil
 }
@@ -165,7 +199,7 @@ func (fsm *storeFSM) applyDropDatabaseCommand(cmd *internal.Command) interface{}
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.Data.DropDatabase(v.GetName()); err != nil {
+	if err := other.DropDatabase(v.GetName()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -196,7 +230,7 @@ func (fsm *storeFSM) applyDropRetentionPolicyCommand(cmd *internal.Command) inte
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.Data.DropRetentionPolicy(v.GetDatabase(), v.GetName()); err != nil {
+	if err := other.DropRetentionPolicy(v.GetDatabase(), v.GetName()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -205,14 +239,14 @@ func (fsm *storeFSM) applyDropRetentionPolicyCommand(cmd *internal.Command) inte
 }
 
 func (fsm *storeFSM) applySetDefaultRetentionPolicyCommand(cmd *internal.Command) interface{} {
-	// ext, _ := proto.GetExtension(cmd, internal.E_SetDefaultRetentionPolicyCommand_Command)
-	// v := ext.(*internal.SetDefaultRetentionPolicyCommand)
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDefaultRetentionPolicyCommand_Command)
+	v := ext.(*internal.SetDefaultRetentionPolicyCommand)
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	// if err := other.Data.SetDefaultRetentionPolicY(v.GetDatabase(), v.GetName()); err != nil {
-	// return err
-	// }
+	if err := other.Data.UpdateRetentionPolicy(v.GetDatabase(), v.GetName(), &meta.RetentionPolicyUpdate{}, true); err != nil {
+		return err
+	}
 	fsm.data = other
 
 	return nil
@@ -348,7 +382,7 @@ func (fsm *storeFSM) applyDropUserCommand(cmd *internal.Command) interface{} {
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.Data.DropUser(v.GetName()); err != nil {
+	if err := other.DropUser(v.GetName()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -437,20 +471,12 @@ func (fsm *storeFSM) applySetMetaNodeCommand(cmd *internal.Command) interface{}
 	return nil
 }
 
-func (fsm *storeFSM) applyDeleteMetaNodeCommand(cmd *internal.Command, s *store) interface{} {
+func (fsm *storeFSM) applyDeleteMetaNodeCommand(cmd *internal.Command) interface{} {
 	ext, _ := proto.GetExtension(cmd, internal.E_DeleteMetaNodeCommand_Command)
 	v := ext.(*internal.DeleteMetaNodeCommand)
 
+	// The raft peer was already removed by the leader applying the command.
 	other := fsm.data.Clone()
-	node := other.MetaNode(v.GetID())
-	if node == nil {
-		return ErrNodeNotFound
-	}
-
-	if err := s.leave(node); err != nil && err != raft.ErrNotLeader {
-		return err
-	}
-
 	if err := other.DeleteMetaNode(v.GetID()); err != nil {
 		return err
 	}
@@ -463,7 +489,7 @@ func (fsm *storeFSM) applyCreateDataNodeCommand(cmd *internal.Command) interface
 	v := ext.(*internal.CreateDataNodeCommand)
 
 	other := fsm.data.Clone()
-	_ = other.CreateDataNode(v.GetHTTPAddr(), v.GetTCPAddr())
+	_ = other.CreateDataNode(v.GetHTTPAddr(), v.GetTCPAddr(), unmarshalLabels(v.GetLabels()))
 
 	fsm.data = other
 	return nil
@@ -492,9 +518,7 @@ func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *internal.Command) interface
 // func (fsm *storeFSM) applyUpdateRetentionPolicy(cmd *internal.Command) (interface{})     {}
 // func (fsm *storeFSM) applyDropShard(cmd *internal.Command) (interface{})                 {}
 // func (fsm *storeFSM) applyCreateShardGroup(cmd *internal.Command) (interface{})          {}
-// func (fsm *storeFSM) applyCreateBalancedShardGroup(cmd *internal.Command) (interface{})  {}
 // func (fsm *storeFSM) applyDeleteShardGroup(cmd *internal.Command) (interface{})          {}
-// func (fsm *storeFSM) applyTruncateShardGroups(cmd *internal.Command) (interface{})       {}
 // func (fsm *storeFSM) applyAddShardOwner(cmd *internal.Command) (interface{})             {}
 // func (fsm *storeFSM) applyRemoveShardOwner(cmd *internal.Command) (interface{})          {}
 // func (fsm *storeFSM) applyCreateContinuousQuery(cmd *internal.Command) (interface{})     {}
@@ -524,18 +548,284 @@ func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *internal.Command) interface
 // func (fsm *storeFSM) applyDeleteMetaNode(cmd *internal.Command) (interface{})            {}
 // func (fsm *storeFSM) applyCreateDataNode(cmd *internal.Command) (interface{})            {}
 // func (fsm *storeFSM) applyDeleteDataNode(cmd *internal.Command) (interface{})            {}
-// func (fsm *storeFSM) applyRemovePendingShardOwner(cmd *internal.Command) (interface{})   {}
-func (fsm *storeFSM) applyAddPendingShardOwner(cmd *internal.Command) interface{} {
-	// ext, err := proto.GetExtension(cmd, internal.E_AddPendingShardOwnerCommand_Command)
-	// if err != nil {
-	// 	return nil
-	// }
-	// v := ext.(*internal.AddPendingShardOwnerCommand)
 
+func (fsm *storeFSM) applyAddShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddShardOwnerCommand_Command)
+	v := ext.(*internal.AddShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.AddShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyRemoveShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemoveShardOwnerCommand_Command)
+	v := ext.(*internal.RemoveShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RemoveShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyAddPendingShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddPendingShardOwnerCommand_Command)
+	v := ext.(*internal.AddPendingShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.AddPendingShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyRemovePendingShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemovePendingShardOwnerCommand_Command)
+	v := ext.(*internal.RemovePendingShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RemovePendingShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyCommitPendingShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CommitPendingShardOwnerCommand_Command)
+	v := ext.(*internal.CommitPendingShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.CommitPendingShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyTruncateShardGroupsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_TruncateShardGroupCommand_Command)
+	v := ext.(*internal.TruncateShardGroupCommand)
+
+	other := fsm.data.Clone()
+	other.TruncateShardGroups(time.Unix(0, int64(v.GetTruncateAt())))
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyCreateBalancedShardGroupCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CreateBalancedShardGroupCommand_Command)
+	v := ext.(*internal.CreateBalancedShardGroupCommand)
+
+	other := fsm.data.Clone()
+	if err := other.CreateBalancedShardGroup(v.GetDatabase(), v.GetPolicy(), time.Unix(0, v.GetTimestamp())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetShardPlacementCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetShardPlacementCommand_Command)
+	v := ext.(*internal.SetShardPlacementCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SetShardPlacement(v.GetDatabase(), v.GetPolicy(), v.GetStrategy()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySplitShardGroupCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SplitShardGroupCommand_Command)
+	v := ext.(*internal.SplitShardGroupCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SplitShardGroup(v.GetDatabase(), v.GetPolicy(), v.GetShardGroupID(), time.Unix(0, v.GetTimestamp()), int(v.GetShardN())); err != nil {
+		return err
+	}
+	fsm.data = other
 	return nil
 }
 
-// func (fsm *storeFSM) applyCommitPendingShardOwner(cmd *internal.Command) (interface{})   {}
+func (fsm *storeFSM) applyRestoreShardGroupCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RestoreShardGroupCommand_Command)
+	v := ext.(*internal.RestoreShardGroupCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RestoreShardGroup(v.GetDatabase(), v.GetPolicy(), time.Unix(0, v.GetStartTime()), time.Unix(0, v.GetEndTime()), int(v.GetShardN())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyImportDataCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_ImportDataCommand_Command)
+	v := ext.(*internal.ImportDataCommand)
+
+	other := fsm.data.Clone()
+	shardIDs, err := other.ImportData(v.GetData(), v.GetDatabase(), v.GetRetentionPolicy(), v.GetNewDatabase(), v.GetNewRetentionPolicy(), int(v.GetNewReplicationFactor()), v.GetForce())
+	if err != nil {
+		return err
+	}
+	fsm.data = other
+	return shardIDs
+}
+
+func (fsm *storeFSM) applySetDataNodeDiskUsageCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDataNodeDiskUsageCommand_Command)
+	v := ext.(*internal.SetDataNodeDiskUsageCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SetDataNodeDiskUsage(v.GetID(), v.GetDiskUsage()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyCreateRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CreateRoleCommand_Command)
+	v := ext.(*internal.CreateRoleCommand)
+
+	other := fsm.data.Clone()
+	if err := other.CreateRole(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyDropRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_DropRoleCommand_Command)
+	v := ext.(*internal.DropRoleCommand)
+
+	other := fsm.data.Clone()
+	if err := other.DropRole(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyChangeRoleNameCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_ChangeRoleNameCommand_Command)
+	v := ext.(*internal.ChangeRoleNameCommand)
+
+	other := fsm.data.Clone()
+	if err := other.ChangeRoleName(v.GetOldName(), v.GetNewName()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyAddRoleUsersCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddRoleUsersCommand_Command)
+	v := ext.(*internal.AddRoleUsersCommand)
+
+	other := fsm.data.Clone()
+	if err := other.AddRoleUsers(v.GetName(), v.GetUsers()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyRemoveRoleUsersCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemoveRoleUsersCommand_Command)
+	v := ext.(*internal.RemoveRoleUsersCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RemoveRoleUsers(v.GetName(), v.GetUsers()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyAddRolePermissionsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddRolePermissionsCommand_Command)
+	v := ext.(*internal.AddRolePermissionsCommand)
+
+	other := fsm.data.Clone()
+	if err := other.AddRolePermissions(v.GetName(), unmarshalPermissions(v.GetPermissions())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyRemoveRolePermissionsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemoveRolePermissionsCommand_Command)
+	v := ext.(*internal.RemoveRolePermissionsCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RemoveRolePermissions(v.GetName(), unmarshalPermissions(v.GetPermissions())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyAddUserPermissionsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddUserPermissionsCommand_Command)
+	v := ext.(*internal.AddUserPermissionsCommand)
+
+	other := fsm.data.Clone()
+	if err := other.AddUserPermissions(v.GetName(), unmarshalPermissions(v.GetPermissions())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyRemoveUserPermissionsCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemoveUserPermissionsCommand_Command)
+	v := ext.(*internal.RemoveUserPermissionsCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RemoveUserPermissions(v.GetName(), unmarshalPermissions(v.GetPermissions())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+// applyLogs returns a copy of data with the commands of logs applied the
+// same way the meta nodes applied them. Clients use it to follow the meta
+// data from snapshot deltas.
+func applyLogs(data *Data, logs []*internal.LogEntry, retentionAutoCreate bool) (other *Data, err error) {
+	// Unknown commands panic in Apply.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("apply log: %v", r)
+		}
+	}()
+
+	s := &store{
+		config:      &Config{RetentionAutoCreate: retentionAutoCreate},
+		data:        data.Clone(),
+		dataChanged: make(chan struct{}),
+	}
+	for _, l := range logs {
+		(*storeFSM)(s).Apply(&raft.Log{
+			Index: l.GetIndex(),
+			Term:  l.GetTerm(),
+			Type:  raft.LogCommand,
+			Data:  l.GetData(),
+		})
+	}
+	return s.data, nil
+}
 
 func (fsm *storeFSM) Snapshot() (raft.FSMSnapshot, error) {
 	s := (*store)(fsm)
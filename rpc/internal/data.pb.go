@@ -37,6 +37,7 @@ It has these top-level messages:
 	DownloadShardSnapshotRequest
 	DownloadShardSnapshotResponse
 	ShardStatusRequest
+	ShardStatus
 	ShardStatusResponse
 	CreateShardSnapshotRequest
 	CreateShardSnapshotResponse
@@ -55,6 +56,16 @@ It has these top-level messages:
 	TagValues
 	ShowTagValuesRequest
 	ShowTagValuesResponse
+	ShardDigestRequest
+	SeriesDigest
+	ShardDigestResponse
+	ShardPointsRequest
+	ShardPointsResponse
+	HintedHandoffStatusRequest
+	HintedHandoffQueue
+	HintedHandoffStatusResponse
+	HintedHandoffActionRequest
+	HintedHandoffActionResponse
 */
 package internal
 
@@ -77,7 +88,7 @@ type CopyShardRequest struct {
 	Source           *string `protobuf:"bytes,1,req,name=Source,json=source" json:"Source,omitempty"`
 	Dest             *string `protobuf:"bytes,2,req,name=Dest,json=dest" json:"Dest,omitempty"`
 	Database         *string `protobuf:"bytes,3,opt,name=Database,json=database" json:"Database,omitempty"`
-	Policy           *string `protobuf:"bytes,4,req,name=Policy,json=policy" json:"Policy,omitempty"`
+	Policy           *string `protobuf:"bytes,4,opt,name=Policy,json=policy" json:"Policy,omitempty"`
 	ShardID          *uint64 `protobuf:"varint,5,req,name=ShardID,json=shardID" json:"ShardID,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
@@ -123,7 +134,7 @@ func (m *CopyShardRequest) GetShardID() uint64 {
 }
 
 type CopyShardResponse struct {
-	Err              *string `protobuf:"bytes,1,req,name=Err,json=err" json:"Err,omitempty"`
+	Err              *string `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -149,9 +160,9 @@ func (*CopyShardStatusRequest) ProtoMessage()               {}
 func (*CopyShardStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{2} }
 
 type CopyShardStatusResponse struct {
-	Err              *string  `protobuf:"bytes,1,req,name=Err,json=err" json:"Err,omitempty"`
-	Tasks            []string `protobuf:"bytes,2,rep,name=Tasks,json=tasks" json:"Tasks,omitempty"`
-	XXX_unrecognized []byte   `json:"-"`
+	Err              *string            `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
+	Tasks            []*CopyShardStatus `protobuf:"bytes,2,rep,name=Tasks,json=tasks" json:"Tasks,omitempty"`
+	XXX_unrecognized []byte             `json:"-"`
 }
 
 func (m *CopyShardStatusResponse) Reset()                    { *m = CopyShardStatusResponse{} }
@@ -166,7 +177,7 @@ func (m *CopyShardStatusResponse) GetErr() string {
 	return ""
 }
 
-func (m *CopyShardStatusResponse) GetTasks() []string {
+func (m *CopyShardStatusResponse) GetTasks() []*CopyShardStatus {
 	if m != nil {
 		return m.Tasks
 	}
@@ -178,9 +189,10 @@ type CopyShardStatus struct {
 	Dest             *string `protobuf:"bytes,2,req,name=Dest,json=dest" json:"Dest,omitempty"`
 	Database         *string `protobuf:"bytes,3,req,name=Database,json=database" json:"Database,omitempty"`
 	Policy           *string `protobuf:"bytes,4,req,name=Policy,json=policy" json:"Policy,omitempty"`
-	TotalSize        *uint64 `protobuf:"varint,5,req,name=TotalSize,json=totalSize" json:"TotalSize,omitempty"`
+	TotalSize        *uint64 `protobuf:"varint,5,opt,name=TotalSize,json=totalSize" json:"TotalSize,omitempty"`
 	CurrentSize      *uint64 `protobuf:"varint,6,req,name=currentSize" json:"currentSize,omitempty"`
 	StartedAt        *uint64 `protobuf:"varint,7,req,name=StartedAt,json=startedAt" json:"StartedAt,omitempty"`
+	ShardID          *uint64 `protobuf:"varint,8,opt,name=ShardID,json=shardID" json:"ShardID,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -238,6 +250,13 @@ func (m *CopyShardStatus) GetStartedAt() uint64 {
 	return 0
 }
 
+func (m *CopyShardStatus) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
 type KillCopyShardRequest struct {
 	Source           *string `protobuf:"bytes,1,req,name=Source,json=source" json:"Source,omitempty"`
 	Dest             *string `protobuf:"bytes,2,req,name=Dest,json=dest" json:"Dest,omitempty"`
@@ -272,7 +291,7 @@ func (m *KillCopyShardRequest) GetShardID() uint64 {
 }
 
 type KillCopyShardResponse struct {
-	Err              *string `protobuf:"bytes,1,req,name=Err,json=err" json:"Err,omitempty"`
+	Err              *string `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -342,7 +361,7 @@ type JoinClusterRequest struct {
 	NodeID           *uint64  `protobuf:"varint,1,req,name=NodeID,json=nodeID" json:"NodeID,omitempty"`
 	NodeAddr         *string  `protobuf:"bytes,2,req,name=NodeAddr,json=nodeAddr" json:"NodeAddr,omitempty"`
 	MetaAddrs        []string `protobuf:"bytes,3,rep,name=MetaAddrs,json=metaAddrs" json:"MetaAddrs,omitempty"`
-	ImportMetaData   *string  `protobuf:"bytes,4,req,name=ImportMetaData,json=importMetaData" json:"ImportMetaData,omitempty"`
+	ImportMetaData   *bool    `protobuf:"varint,4,opt,name=ImportMetaData,json=importMetaData" json:"ImportMetaData,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -372,11 +391,11 @@ func (m *JoinClusterRequest) GetMetaAddrs() []string {
 	return nil
 }
 
-func (m *JoinClusterRequest) GetImportMetaData() string {
+func (m *JoinClusterRequest) GetImportMetaData() bool {
 	if m != nil && m.ImportMetaData != nil {
 		return *m.ImportMetaData
 	}
-	return ""
+	return false
 }
 
 type JoinClusterResponse struct {
@@ -565,6 +584,7 @@ func (m *ExecuteStatementResponse) GetMessage() string {
 type CreateIteratorRequest struct {
 	ShardIDs         []uint64 `protobuf:"varint,1,rep,name=ShardIDs,json=shardIDs" json:"ShardIDs,omitempty"`
 	Opt              []byte   `protobuf:"bytes,2,req,name=Opt,json=opt" json:"Opt,omitempty"`
+	Measurement      []byte   `protobuf:"bytes,3,opt,name=Measurement,json=measurement" json:"Measurement,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -587,8 +607,16 @@ func (m *CreateIteratorRequest) GetOpt() []byte {
 	return nil
 }
 
+func (m *CreateIteratorRequest) GetMeasurement() []byte {
+	if m != nil {
+		return m.Measurement
+	}
+	return nil
+}
+
 type CreateIteratorResponse struct {
 	Err              *string `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
+	Type             *int32  `protobuf:"varint,2,opt,name=Type,json=type" json:"Type,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -604,6 +632,13 @@ func (m *CreateIteratorResponse) GetErr() string {
 	return ""
 }
 
+func (m *CreateIteratorResponse) GetType() int32 {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return 0
+}
+
 type IteratorStats struct {
 	SeriesN          *uint64 `protobuf:"varint,1,req,name=SeriesN,json=seriesN" json:"SeriesN,omitempty"`
 	PointN           []byte  `protobuf:"bytes,2,req,name=PointN,json=pointN" json:"PointN,omitempty"`
@@ -680,7 +715,7 @@ func (m *Field) GetType() uint64 {
 }
 
 type FieldDimensionsResponse struct {
-	Fields           []string `protobuf:"bytes,1,rep,name=Fields,json=fields" json:"Fields,omitempty"`
+	Fields           []*Field `protobuf:"bytes,1,rep,name=Fields,json=fields" json:"Fields,omitempty"`
 	Dimensions       []string `protobuf:"bytes,2,rep,name=Dimensions,json=dimensions" json:"Dimensions,omitempty"`
 	Err              *string  `protobuf:"bytes,3,opt,name=Err,json=err" json:"Err,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
@@ -691,7 +726,7 @@ func (m *FieldDimensionsResponse) String() string            { return proto.Comp
 func (*FieldDimensionsResponse) ProtoMessage()               {}
 func (*FieldDimensionsResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{22} }
 
-func (m *FieldDimensionsResponse) GetFields() []string {
+func (m *FieldDimensionsResponse) GetFields() []*Field {
 	if m != nil {
 		return m.Fields
 	}
@@ -764,7 +799,8 @@ func (m *ExpandSourcesResponse) GetErr() string {
 
 type DownloadShardSnapshotRequest struct {
 	ShardID          *uint64 `protobuf:"varint,1,req,name=ShardID,json=shardID" json:"ShardID,omitempty"`
-	Path             *string `protobuf:"bytes,2,req,name=Path,json=path" json:"Path,omitempty"`
+	Path             *string `protobuf:"bytes,2,opt,name=Path,json=path" json:"Path,omitempty"`
+	Since            *int64  `protobuf:"varint,3,opt,name=Since,json=since" json:"Since,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -789,8 +825,16 @@ func (m *DownloadShardSnapshotRequest) GetPath() string {
 	return ""
 }
 
+func (m *DownloadShardSnapshotRequest) GetSince() int64 {
+	if m != nil && m.Since != nil {
+		return *m.Since
+	}
+	return 0
+}
+
 type DownloadShardSnapshotResponse struct {
 	Err              *string `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
+	SnapshotTime     *int64  `protobuf:"varint,2,opt,name=SnapshotTime,json=snapshotTime" json:"SnapshotTime,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -808,9 +852,16 @@ func (m *DownloadShardSnapshotResponse) GetErr() string {
 	return ""
 }
 
+func (m *DownloadShardSnapshotResponse) GetSnapshotTime() int64 {
+	if m != nil && m.SnapshotTime != nil {
+		return *m.SnapshotTime
+	}
+	return 0
+}
+
 type ShardStatusRequest struct {
-	ShardID          *uint64 `protobuf:"varint,1,req,name=ShardID,json=shardID" json:"ShardID,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	ShardIDs         []uint64 `protobuf:"varint,1,rep,name=ShardIDs,json=shardIDs" json:"ShardIDs,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
 }
 
 func (m *ShardStatusRequest) Reset()                    { *m = ShardStatusRequest{} }
@@ -818,23 +869,48 @@ func (m *ShardStatusRequest) String() string            { return proto.CompactTe
 func (*ShardStatusRequest) ProtoMessage()               {}
 func (*ShardStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{27} }
 
-func (m *ShardStatusRequest) GetShardID() uint64 {
+func (m *ShardStatusRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+type ShardStatus struct {
+	ShardID          *uint64 `protobuf:"varint,1,req,name=ShardID,json=shardID" json:"ShardID,omitempty"`
+	Size_            *uint64 `protobuf:"varint,2,req,name=Size,json=size" json:"Size,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *ShardStatus) Reset()                    { *m = ShardStatus{} }
+func (m *ShardStatus) String() string            { return proto.CompactTextString(m) }
+func (*ShardStatus) ProtoMessage()               {}
+func (*ShardStatus) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{28} }
+
+func (m *ShardStatus) GetShardID() uint64 {
 	if m != nil && m.ShardID != nil {
 		return *m.ShardID
 	}
 	return 0
 }
 
+func (m *ShardStatus) GetSize_() uint64 {
+	if m != nil && m.Size_ != nil {
+		return *m.Size_
+	}
+	return 0
+}
+
 type ShardStatusResponse struct {
-	Err              *string `protobuf:"bytes,1,req,name=Err,json=err" json:"Err,omitempty"`
-	Size_            *uint64 `protobuf:"varint,2,req,name=Size,json=size" json:"Size,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Err              *string        `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
+	Shards           []*ShardStatus `protobuf:"bytes,2,rep,name=Shards,json=shards" json:"Shards,omitempty"`
+	XXX_unrecognized []byte         `json:"-"`
 }
 
 func (m *ShardStatusResponse) Reset()                    { *m = ShardStatusResponse{} }
 func (m *ShardStatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*ShardStatusResponse) ProtoMessage()               {}
-func (*ShardStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{28} }
+func (*ShardStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{29} }
 
 func (m *ShardStatusResponse) GetErr() string {
 	if m != nil && m.Err != nil {
@@ -843,11 +919,11 @@ func (m *ShardStatusResponse) GetErr() string {
 	return ""
 }
 
-func (m *ShardStatusResponse) GetSize_() uint64 {
-	if m != nil && m.Size_ != nil {
-		return *m.Size_
+func (m *ShardStatusResponse) GetShards() []*ShardStatus {
+	if m != nil {
+		return m.Shards
 	}
-	return 0
+	return nil
 }
 
 type CreateShardSnapshotRequest struct {
@@ -858,7 +934,7 @@ type CreateShardSnapshotRequest struct {
 func (m *CreateShardSnapshotRequest) Reset()                    { *m = CreateShardSnapshotRequest{} }
 func (m *CreateShardSnapshotRequest) String() string            { return proto.CompactTextString(m) }
 func (*CreateShardSnapshotRequest) ProtoMessage()               {}
-func (*CreateShardSnapshotRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{29} }
+func (*CreateShardSnapshotRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{30} }
 
 func (m *CreateShardSnapshotRequest) GetShardID() uint64 {
 	if m != nil && m.ShardID != nil {
@@ -877,7 +953,7 @@ type CreateShardSnapshotResponse struct {
 func (m *CreateShardSnapshotResponse) Reset()                    { *m = CreateShardSnapshotResponse{} }
 func (m *CreateShardSnapshotResponse) String() string            { return proto.CompactTextString(m) }
 func (*CreateShardSnapshotResponse) ProtoMessage()               {}
-func (*CreateShardSnapshotResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{30} }
+func (*CreateShardSnapshotResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{31} }
 
 func (m *CreateShardSnapshotResponse) GetErr() string {
 	if m != nil && m.Err != nil {
@@ -909,7 +985,7 @@ type DeleteShardSnapshotRequest struct {
 func (m *DeleteShardSnapshotRequest) Reset()                    { *m = DeleteShardSnapshotRequest{} }
 func (m *DeleteShardSnapshotRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteShardSnapshotRequest) ProtoMessage()               {}
-func (*DeleteShardSnapshotRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{31} }
+func (*DeleteShardSnapshotRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{32} }
 
 func (m *DeleteShardSnapshotRequest) GetShardID() uint64 {
 	if m != nil && m.ShardID != nil {
@@ -933,7 +1009,7 @@ type DeleteShardSnapshotResponse struct {
 func (m *DeleteShardSnapshotResponse) Reset()                    { *m = DeleteShardSnapshotResponse{} }
 func (m *DeleteShardSnapshotResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeleteShardSnapshotResponse) ProtoMessage()               {}
-func (*DeleteShardSnapshotResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{32} }
+func (*DeleteShardSnapshotResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{33} }
 
 func (m *DeleteShardSnapshotResponse) GetErr() string {
 	if m != nil && m.Err != nil {
@@ -945,7 +1021,7 @@ func (m *DeleteShardSnapshotResponse) GetErr() string {
 type QueryInfo struct {
 	ID               *uint64 `protobuf:"varint,1,req,name=ID,json=iD" json:"ID,omitempty"`
 	Query            *string `protobuf:"bytes,2,req,name=Query,json=query" json:"Query,omitempty"`
-	User             *string `protobuf:"bytes,3,req,name=User,json=user" json:"User,omitempty"`
+	User             *string `protobuf:"bytes,3,opt,name=User,json=user" json:"User,omitempty"`
 	Database         *string `protobuf:"bytes,4,req,name=Database,json=database" json:"Database,omitempty"`
 	Duration         *int64  `protobuf:"varint,5,req,name=Duration,json=duration" json:"Duration,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
@@ -954,7 +1030,7 @@ type QueryInfo struct {
 func (m *QueryInfo) Reset()                    { *m = QueryInfo{} }
 func (m *QueryInfo) String() string            { return proto.CompactTextString(m) }
 func (*QueryInfo) ProtoMessage()               {}
-func (*QueryInfo) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{33} }
+func (*QueryInfo) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{34} }
 
 func (m *QueryInfo) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -998,24 +1074,24 @@ type ShowQueriesRequest struct {
 func (m *ShowQueriesRequest) Reset()                    { *m = ShowQueriesRequest{} }
 func (m *ShowQueriesRequest) String() string            { return proto.CompactTextString(m) }
 func (*ShowQueriesRequest) ProtoMessage()               {}
-func (*ShowQueriesRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{34} }
+func (*ShowQueriesRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{35} }
 
 type ShowQueriesResponse struct {
-	Queries          *string `protobuf:"bytes,1,req,name=Queries,json=queries" json:"Queries,omitempty"`
-	Err              *string `protobuf:"bytes,2,req,name=Err,json=err" json:"Err,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Queries          []*QueryInfo `protobuf:"bytes,1,rep,name=Queries,json=queries" json:"Queries,omitempty"`
+	Err              *string      `protobuf:"bytes,2,opt,name=Err,json=err" json:"Err,omitempty"`
+	XXX_unrecognized []byte       `json:"-"`
 }
 
 func (m *ShowQueriesResponse) Reset()                    { *m = ShowQueriesResponse{} }
 func (m *ShowQueriesResponse) String() string            { return proto.CompactTextString(m) }
 func (*ShowQueriesResponse) ProtoMessage()               {}
-func (*ShowQueriesResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{35} }
+func (*ShowQueriesResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{36} }
 
-func (m *ShowQueriesResponse) GetQueries() string {
-	if m != nil && m.Queries != nil {
-		return *m.Queries
+func (m *ShowQueriesResponse) GetQueries() []*QueryInfo {
+	if m != nil {
+		return m.Queries
 	}
-	return ""
+	return nil
 }
 
 func (m *ShowQueriesResponse) GetErr() string {
@@ -1033,7 +1109,7 @@ type KillQueryRequest struct {
 func (m *KillQueryRequest) Reset()                    { *m = KillQueryRequest{} }
 func (m *KillQueryRequest) String() string            { return proto.CompactTextString(m) }
 func (*KillQueryRequest) ProtoMessage()               {}
-func (*KillQueryRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{36} }
+func (*KillQueryRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{37} }
 
 func (m *KillQueryRequest) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1043,14 +1119,14 @@ func (m *KillQueryRequest) GetID() uint64 {
 }
 
 type KillQueryResponse struct {
-	Err              *string `protobuf:"bytes,1,req,name=Err,json=err" json:"Err,omitempty"`
+	Err              *string `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *KillQueryResponse) Reset()                    { *m = KillQueryResponse{} }
 func (m *KillQueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*KillQueryResponse) ProtoMessage()               {}
-func (*KillQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{37} }
+func (*KillQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{38} }
 
 func (m *KillQueryResponse) GetErr() string {
 	if m != nil && m.Err != nil {
@@ -1068,7 +1144,7 @@ type RestoreShardRequest struct {
 func (m *RestoreShardRequest) Reset()                    { *m = RestoreShardRequest{} }
 func (m *RestoreShardRequest) String() string            { return proto.CompactTextString(m) }
 func (*RestoreShardRequest) ProtoMessage()               {}
-func (*RestoreShardRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{38} }
+func (*RestoreShardRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{39} }
 
 func (m *RestoreShardRequest) GetShardID() uint64 {
 	if m != nil && m.ShardID != nil {
@@ -1092,7 +1168,7 @@ type RestoreShardResponse struct {
 func (m *RestoreShardResponse) Reset()                    { *m = RestoreShardResponse{} }
 func (m *RestoreShardResponse) String() string            { return proto.CompactTextString(m) }
 func (*RestoreShardResponse) ProtoMessage()               {}
-func (*RestoreShardResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{39} }
+func (*RestoreShardResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{40} }
 
 func (m *RestoreShardResponse) GetErr() string {
 	if m != nil && m.Err != nil {
@@ -1111,7 +1187,7 @@ type ShowMeasurementsRequest struct {
 func (m *ShowMeasurementsRequest) Reset()                    { *m = ShowMeasurementsRequest{} }
 func (m *ShowMeasurementsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ShowMeasurementsRequest) ProtoMessage()               {}
-func (*ShowMeasurementsRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{40} }
+func (*ShowMeasurementsRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{41} }
 
 func (m *ShowMeasurementsRequest) GetErr() string {
 	if m != nil && m.Err != nil {
@@ -1136,7 +1212,7 @@ type ShowMeasurementsResponse struct {
 func (m *ShowMeasurementsResponse) Reset()                    { *m = ShowMeasurementsResponse{} }
 func (m *ShowMeasurementsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ShowMeasurementsResponse) ProtoMessage()               {}
-func (*ShowMeasurementsResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{41} }
+func (*ShowMeasurementsResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{42} }
 
 func (m *ShowMeasurementsResponse) GetMeasurements() string {
 	if m != nil && m.Measurements != nil {
@@ -1161,7 +1237,7 @@ type KeyValue struct {
 func (m *KeyValue) Reset()                    { *m = KeyValue{} }
 func (m *KeyValue) String() string            { return proto.CompactTextString(m) }
 func (*KeyValue) ProtoMessage()               {}
-func (*KeyValue) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{42} }
+func (*KeyValue) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{43} }
 
 func (m *KeyValue) GetKey() string {
 	if m != nil && m.Key != nil {
@@ -1186,7 +1262,7 @@ type TagValues struct {
 func (m *TagValues) Reset()                    { *m = TagValues{} }
 func (m *TagValues) String() string            { return proto.CompactTextString(m) }
 func (*TagValues) ProtoMessage()               {}
-func (*TagValues) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{43} }
+func (*TagValues) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{44} }
 
 func (m *TagValues) GetMeasurement() string {
 	if m != nil && m.Measurement != nil {
@@ -1211,7 +1287,7 @@ type ShowTagValuesRequest struct {
 func (m *ShowTagValuesRequest) Reset()                    { *m = ShowTagValuesRequest{} }
 func (m *ShowTagValuesRequest) String() string            { return proto.CompactTextString(m) }
 func (*ShowTagValuesRequest) ProtoMessage()               {}
-func (*ShowTagValuesRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{44} }
+func (*ShowTagValuesRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{45} }
 
 func (m *ShowTagValuesRequest) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -1236,7 +1312,7 @@ type ShowTagValuesResponse struct {
 func (m *ShowTagValuesResponse) Reset()                    { *m = ShowTagValuesResponse{} }
 func (m *ShowTagValuesResponse) String() string            { return proto.CompactTextString(m) }
 func (*ShowTagValuesResponse) ProtoMessage()               {}
-func (*ShowTagValuesResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{45} }
+func (*ShowTagValuesResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{46} }
 
 func (m *ShowTagValuesResponse) GetValues() []byte {
 	if m != nil {
@@ -1252,6 +1328,308 @@ func (m *ShowTagValuesResponse) GetErr() string {
 	return ""
 }
 
+type ShardDigestRequest struct {
+	ShardID          *uint64 `protobuf:"varint,1,req,name=ShardID,json=shardID" json:"ShardID,omitempty"`
+	Interval         *int64  `protobuf:"varint,2,req,name=Interval,json=interval" json:"Interval,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *ShardDigestRequest) Reset()                    { *m = ShardDigestRequest{} }
+func (m *ShardDigestRequest) String() string            { return proto.CompactTextString(m) }
+func (*ShardDigestRequest) ProtoMessage()               {}
+func (*ShardDigestRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{47} }
+
+func (m *ShardDigestRequest) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+func (m *ShardDigestRequest) GetInterval() int64 {
+	if m != nil && m.Interval != nil {
+		return *m.Interval
+	}
+	return 0
+}
+
+type SeriesDigest struct {
+	Key              *string `protobuf:"bytes,1,req,name=Key,json=key" json:"Key,omitempty"`
+	Start            *int64  `protobuf:"varint,2,req,name=Start,json=start" json:"Start,omitempty"`
+	Count            *uint64 `protobuf:"varint,3,req,name=Count,json=count" json:"Count,omitempty"`
+	Sum              *uint64 `protobuf:"varint,4,req,name=Sum,json=sum" json:"Sum,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SeriesDigest) Reset()                    { *m = SeriesDigest{} }
+func (m *SeriesDigest) String() string            { return proto.CompactTextString(m) }
+func (*SeriesDigest) ProtoMessage()               {}
+func (*SeriesDigest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{48} }
+
+func (m *SeriesDigest) GetKey() string {
+	if m != nil && m.Key != nil {
+		return *m.Key
+	}
+	return ""
+}
+
+func (m *SeriesDigest) GetStart() int64 {
+	if m != nil && m.Start != nil {
+		return *m.Start
+	}
+	return 0
+}
+
+func (m *SeriesDigest) GetCount() uint64 {
+	if m != nil && m.Count != nil {
+		return *m.Count
+	}
+	return 0
+}
+
+func (m *SeriesDigest) GetSum() uint64 {
+	if m != nil && m.Sum != nil {
+		return *m.Sum
+	}
+	return 0
+}
+
+type ShardDigestResponse struct {
+	Digests          []*SeriesDigest `protobuf:"bytes,1,rep,name=Digests,json=digests" json:"Digests,omitempty"`
+	Err              *string         `protobuf:"bytes,2,opt,name=Err,json=err" json:"Err,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *ShardDigestResponse) Reset()                    { *m = ShardDigestResponse{} }
+func (m *ShardDigestResponse) String() string            { return proto.CompactTextString(m) }
+func (*ShardDigestResponse) ProtoMessage()               {}
+func (*ShardDigestResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{49} }
+
+func (m *ShardDigestResponse) GetDigests() []*SeriesDigest {
+	if m != nil {
+		return m.Digests
+	}
+	return nil
+}
+
+func (m *ShardDigestResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type ShardPointsRequest struct {
+	ShardID          *uint64  `protobuf:"varint,1,req,name=ShardID,json=shardID" json:"ShardID,omitempty"`
+	Keys             []string `protobuf:"bytes,2,rep,name=Keys,json=keys" json:"Keys,omitempty"`
+	Start            *int64   `protobuf:"varint,3,req,name=Start,json=start" json:"Start,omitempty"`
+	End              *int64   `protobuf:"varint,4,req,name=End,json=end" json:"End,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ShardPointsRequest) Reset()                    { *m = ShardPointsRequest{} }
+func (m *ShardPointsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ShardPointsRequest) ProtoMessage()               {}
+func (*ShardPointsRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{50} }
+
+func (m *ShardPointsRequest) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+func (m *ShardPointsRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *ShardPointsRequest) GetStart() int64 {
+	if m != nil && m.Start != nil {
+		return *m.Start
+	}
+	return 0
+}
+
+func (m *ShardPointsRequest) GetEnd() int64 {
+	if m != nil && m.End != nil {
+		return *m.End
+	}
+	return 0
+}
+
+type ShardPointsResponse struct {
+	Points           [][]byte `protobuf:"bytes,1,rep,name=Points,json=points" json:"Points,omitempty"`
+	Err              *string  `protobuf:"bytes,2,opt,name=Err,json=err" json:"Err,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ShardPointsResponse) Reset()                    { *m = ShardPointsResponse{} }
+func (m *ShardPointsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ShardPointsResponse) ProtoMessage()               {}
+func (*ShardPointsResponse) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{51} }
+
+func (m *ShardPointsResponse) GetPoints() [][]byte {
+	if m != nil {
+		return m.Points
+	}
+	return nil
+}
+
+func (m *ShardPointsResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type HintedHandoffStatusRequest struct {
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *HintedHandoffStatusRequest) Reset()                    { *m = HintedHandoffStatusRequest{} }
+func (m *HintedHandoffStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*HintedHandoffStatusRequest) ProtoMessage()               {}
+func (*HintedHandoffStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{52} }
+
+type HintedHandoffQueue struct {
+	NodeID           *uint64 `protobuf:"varint,1,req,name=NodeID,json=nodeID" json:"NodeID,omitempty"`
+	Bytes            *int64  `protobuf:"varint,2,req,name=Bytes,json=bytes" json:"Bytes,omitempty"`
+	Segments         *int64  `protobuf:"varint,3,req,name=Segments,json=segments" json:"Segments,omitempty"`
+	HeadModified     *int64  `protobuf:"varint,4,opt,name=HeadModified,json=headModified" json:"HeadModified,omitempty"`
+	LastErr          *string `protobuf:"bytes,5,opt,name=LastErr,json=lastErr" json:"LastErr,omitempty"`
+	LastErrAt        *int64  `protobuf:"varint,6,opt,name=LastErrAt,json=lastErrAt" json:"LastErrAt,omitempty"`
+	Paused           *bool   `protobuf:"varint,7,req,name=Paused,json=paused" json:"Paused,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *HintedHandoffQueue) Reset()                    { *m = HintedHandoffQueue{} }
+func (m *HintedHandoffQueue) String() string            { return proto.CompactTextString(m) }
+func (*HintedHandoffQueue) ProtoMessage()               {}
+func (*HintedHandoffQueue) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{53} }
+
+func (m *HintedHandoffQueue) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *HintedHandoffQueue) GetBytes() int64 {
+	if m != nil && m.Bytes != nil {
+		return *m.Bytes
+	}
+	return 0
+}
+
+func (m *HintedHandoffQueue) GetSegments() int64 {
+	if m != nil && m.Segments != nil {
+		return *m.Segments
+	}
+	return 0
+}
+
+func (m *HintedHandoffQueue) GetHeadModified() int64 {
+	if m != nil && m.HeadModified != nil {
+		return *m.HeadModified
+	}
+	return 0
+}
+
+func (m *HintedHandoffQueue) GetLastErr() string {
+	if m != nil && m.LastErr != nil {
+		return *m.LastErr
+	}
+	return ""
+}
+
+func (m *HintedHandoffQueue) GetLastErrAt() int64 {
+	if m != nil && m.LastErrAt != nil {
+		return *m.LastErrAt
+	}
+	return 0
+}
+
+func (m *HintedHandoffQueue) GetPaused() bool {
+	if m != nil && m.Paused != nil {
+		return *m.Paused
+	}
+	return false
+}
+
+type HintedHandoffStatusResponse struct {
+	Err              *string               `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
+	Queues           []*HintedHandoffQueue `protobuf:"bytes,2,rep,name=Queues,json=queues" json:"Queues,omitempty"`
+	XXX_unrecognized []byte                `json:"-"`
+}
+
+func (m *HintedHandoffStatusResponse) Reset()         { *m = HintedHandoffStatusResponse{} }
+func (m *HintedHandoffStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*HintedHandoffStatusResponse) ProtoMessage()    {}
+func (*HintedHandoffStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorData, []int{54}
+}
+
+func (m *HintedHandoffStatusResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+func (m *HintedHandoffStatusResponse) GetQueues() []*HintedHandoffQueue {
+	if m != nil {
+		return m.Queues
+	}
+	return nil
+}
+
+type HintedHandoffActionRequest struct {
+	NodeID           *uint64 `protobuf:"varint,1,req,name=NodeID,json=nodeID" json:"NodeID,omitempty"`
+	Action           *string `protobuf:"bytes,2,req,name=Action,json=action" json:"Action,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *HintedHandoffActionRequest) Reset()                    { *m = HintedHandoffActionRequest{} }
+func (m *HintedHandoffActionRequest) String() string            { return proto.CompactTextString(m) }
+func (*HintedHandoffActionRequest) ProtoMessage()               {}
+func (*HintedHandoffActionRequest) Descriptor() ([]byte, []int) { return fileDescriptorData, []int{55} }
+
+func (m *HintedHandoffActionRequest) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *HintedHandoffActionRequest) GetAction() string {
+	if m != nil && m.Action != nil {
+		return *m.Action
+	}
+	return ""
+}
+
+type HintedHandoffActionResponse struct {
+	Err              *string `protobuf:"bytes,1,opt,name=Err,json=err" json:"Err,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *HintedHandoffActionResponse) Reset()         { *m = HintedHandoffActionResponse{} }
+func (m *HintedHandoffActionResponse) String() string { return proto.CompactTextString(m) }
+func (*HintedHandoffActionResponse) ProtoMessage()    {}
+func (*HintedHandoffActionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorData, []int{56}
+}
+
+func (m *HintedHandoffActionResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*CopyShardRequest)(nil), "internal.CopyShardRequest")
 	proto.RegisterType((*CopyShardResponse)(nil), "internal.CopyShardResponse")
@@ -1281,6 +1659,7 @@ func init() {
 	proto.RegisterType((*DownloadShardSnapshotRequest)(nil), "internal.DownloadShardSnapshotRequest")
 	proto.RegisterType((*DownloadShardSnapshotResponse)(nil), "internal.DownloadShardSnapshotResponse")
 	proto.RegisterType((*ShardStatusRequest)(nil), "internal.ShardStatusRequest")
+	proto.RegisterType((*ShardStatus)(nil), "internal.ShardStatus")
 	proto.RegisterType((*ShardStatusResponse)(nil), "internal.ShardStatusResponse")
 	proto.RegisterType((*CreateShardSnapshotRequest)(nil), "internal.CreateShardSnapshotRequest")
 	proto.RegisterType((*CreateShardSnapshotResponse)(nil), "internal.CreateShardSnapshotResponse")
@@ -1299,80 +1678,116 @@ func init() {
 	proto.RegisterType((*TagValues)(nil), "internal.TagValues")
 	proto.RegisterType((*ShowTagValuesRequest)(nil), "internal.ShowTagValuesRequest")
 	proto.RegisterType((*ShowTagValuesResponse)(nil), "internal.ShowTagValuesResponse")
+	proto.RegisterType((*ShardDigestRequest)(nil), "internal.ShardDigestRequest")
+	proto.RegisterType((*SeriesDigest)(nil), "internal.SeriesDigest")
+	proto.RegisterType((*ShardDigestResponse)(nil), "internal.ShardDigestResponse")
+	proto.RegisterType((*ShardPointsRequest)(nil), "internal.ShardPointsRequest")
+	proto.RegisterType((*ShardPointsResponse)(nil), "internal.ShardPointsResponse")
+	proto.RegisterType((*HintedHandoffStatusRequest)(nil), "internal.HintedHandoffStatusRequest")
+	proto.RegisterType((*HintedHandoffQueue)(nil), "internal.HintedHandoffQueue")
+	proto.RegisterType((*HintedHandoffStatusResponse)(nil), "internal.HintedHandoffStatusResponse")
+	proto.RegisterType((*HintedHandoffActionRequest)(nil), "internal.HintedHandoffActionRequest")
+	proto.RegisterType((*HintedHandoffActionResponse)(nil), "internal.HintedHandoffActionResponse")
 }
 
 func init() { proto.RegisterFile("internal/data.proto", fileDescriptorData) }
 
 var fileDescriptorData = []byte{
-	// 1117 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x56, 0xcf, 0x6e, 0xdb, 0xc6,
-	0x13, 0x06, 0x25, 0xca, 0x92, 0x26, 0xfe, 0x25, 0x0e, 0x25, 0xdb, 0x84, 0xe3, 0x5f, 0x21, 0x2c,
-	0xd0, 0x56, 0xed, 0x21, 0x46, 0x7a, 0xe8, 0xa5, 0x27, 0x57, 0x72, 0x11, 0xc5, 0xb1, 0xea, 0x52,
-	0x6e, 0x83, 0x02, 0xbd, 0x6c, 0xc4, 0x49, 0x4c, 0x44, 0xe2, 0xd2, 0xbb, 0x4b, 0x27, 0x0a, 0xd0,
-	0x27, 0x68, 0xd1, 0x57, 0xeb, 0x2b, 0x15, 0xfb, 0x87, 0x14, 0x49, 0x99, 0xae, 0x53, 0xdf, 0x38,
-	0xb3, 0xcb, 0x99, 0x6f, 0xbe, 0x6f, 0x76, 0x67, 0xa1, 0x17, 0xc5, 0x12, 0x79, 0x4c, 0x17, 0x47,
-	0x21, 0x95, 0xf4, 0x69, 0xc2, 0x99, 0x64, 0x5e, 0x27, 0x73, 0x92, 0x3f, 0x1d, 0xd8, 0x19, 0xb1,
-	0x64, 0x35, 0xbb, 0xa4, 0x3c, 0x0c, 0xf0, 0x2a, 0x45, 0x21, 0xbd, 0x3d, 0xd8, 0x9a, 0xb1, 0x94,
-	0xcf, 0xd1, 0x77, 0x06, 0x8d, 0x61, 0x37, 0xd8, 0x12, 0xda, 0xf2, 0x3c, 0x70, 0xc7, 0x28, 0xa4,
-	0xdf, 0xd0, 0x5e, 0x37, 0x54, 0x7b, 0x0f, 0xa0, 0x33, 0xa6, 0x92, 0xbe, 0xa6, 0x02, 0xfd, 0xe6,
-	0xc0, 0x19, 0x76, 0x83, 0x4e, 0x68, 0x6d, 0x15, 0xe7, 0x9c, 0x2d, 0xa2, 0xf9, 0xca, 0x77, 0x4d,
-	0x9c, 0x44, 0x5b, 0x9e, 0x0f, 0x6d, 0x9d, 0x6f, 0x32, 0xf6, 0x5b, 0x83, 0xc6, 0xd0, 0x0d, 0xda,
-	0xc2, 0x98, 0xe4, 0x73, 0x78, 0x5c, 0x40, 0x23, 0x12, 0x16, 0x0b, 0xf4, 0x76, 0xa0, 0x79, 0xc2,
-	0xb9, 0xc5, 0xd2, 0x44, 0xce, 0x89, 0x0f, 0x7b, 0xf9, 0xb6, 0x99, 0xa4, 0x32, 0x15, 0x16, 0x3a,
-	0x39, 0x86, 0xfd, 0x8d, 0x95, 0xba, 0x30, 0x5e, 0x1f, 0x5a, 0x17, 0x54, 0xbc, 0x13, 0x7e, 0x63,
-	0xd0, 0x1c, 0x76, 0x83, 0x96, 0x54, 0x06, 0xf9, 0xdb, 0x81, 0x47, 0x95, 0x18, 0xf7, 0x60, 0xa4,
-	0x71, 0x27, 0x46, 0x0e, 0xa1, 0x7b, 0xc1, 0x24, 0x5d, 0xcc, 0xa2, 0x8f, 0x68, 0x39, 0xe9, 0xca,
-	0xcc, 0xe1, 0x0d, 0xe0, 0xc1, 0x3c, 0xe5, 0x1c, 0x63, 0xa9, 0xd7, 0xb7, 0xf4, 0x7a, 0xd1, 0xa5,
-	0xfe, 0x9f, 0x49, 0xca, 0x25, 0x86, 0xc7, 0xd2, 0x6f, 0x9b, 0xff, 0x45, 0xe6, 0x20, 0xbf, 0x41,
-	0xff, 0x34, 0x5a, 0x2c, 0xee, 0xa5, 0x73, 0x41, 0xb3, 0x66, 0x59, 0xb3, 0xaf, 0x60, 0xb7, 0x12,
-	0xbd, 0x56, 0xb7, 0xd7, 0xe0, 0x05, 0xb8, 0x64, 0xd7, 0x58, 0x82, 0x51, 0x24, 0xcc, 0xa9, 0x25,
-	0xac, 0x51, 0xd7, 0x42, 0x15, 0x38, 0x5f, 0x42, 0xaf, 0x94, 0xa3, 0x16, 0xcc, 0x5f, 0x0e, 0x78,
-	0x2f, 0x58, 0x14, 0x8f, 0x16, 0xa9, 0x90, 0xc8, 0x0b, 0xa4, 0x4c, 0x59, 0x88, 0x93, 0xb1, 0xde,
-	0xeb, 0x06, 0x5b, 0xb1, 0xb6, 0x14, 0x4a, 0xe5, 0x3f, 0x0e, 0x43, 0x6e, 0xb1, 0x74, 0x62, 0x6b,
-	0x2b, 0xfa, 0xcf, 0x50, 0x52, 0xf5, 0x2d, 0xfc, 0xa6, 0x6e, 0xa6, 0xee, 0x32, 0x73, 0x78, 0x5f,
-	0xc0, 0xc3, 0xc9, 0x32, 0x61, 0x5c, 0xaa, 0x3d, 0xaa, 0x52, 0x2b, 0xfe, 0xc3, 0xa8, 0xe4, 0x25,
-	0xbf, 0x42, 0xaf, 0x84, 0xc7, 0x22, 0xaf, 0x03, 0xe4, 0x43, 0xfb, 0x62, 0x74, 0xfe, 0x9c, 0xe5,
-	0x42, 0xb5, 0xa5, 0x31, 0xb3, 0x5a, 0x9b, 0xeb, 0x5a, 0x9f, 0x41, 0xef, 0x25, 0xd2, 0x6b, 0xac,
-	0xd4, 0x5a, 0xac, 0xc9, 0x29, 0xd7, 0x44, 0x86, 0xd0, 0x2f, 0xff, 0x52, 0x4b, 0xe4, 0x1f, 0x0e,
-	0x3c, 0x7e, 0xc5, 0x23, 0x59, 0x56, 0xb5, 0xa0, 0x90, 0x53, 0x52, 0xc8, 0x68, 0x1a, 0xc5, 0xd2,
-	0x9c, 0xbb, 0x6d, 0xa5, 0xa9, 0xb2, 0x6e, 0xbd, 0x4a, 0x86, 0xf0, 0x28, 0x40, 0x89, 0xb1, 0x8c,
-	0x58, 0x9c, 0x9f, 0x20, 0xb5, 0xe5, 0x11, 0x2f, 0xbb, 0xc9, 0xf7, 0xe0, 0x15, 0xc1, 0x58, 0xd4,
-	0x1e, 0xb8, 0x23, 0x16, 0x9a, 0xfe, 0x6a, 0x05, 0xee, 0x9c, 0x85, 0xa8, 0x10, 0x9e, 0xa1, 0x10,
-	0xf4, 0x2d, 0xfa, 0x0d, 0x1d, 0xab, 0xbd, 0x34, 0x26, 0x99, 0xc1, 0xfe, 0xc9, 0x07, 0x9c, 0xa7,
-	0x12, 0xd5, 0xf9, 0xc7, 0x25, 0xc6, 0x32, 0x2b, 0xcb, 0x9c, 0x34, 0xe3, 0xb3, 0x24, 0xa8, 0x93,
-	0x66, 0x1c, 0xa5, 0x12, 0x1a, 0xe5, 0x56, 0x26, 0xcf, 0xc1, 0xdf, 0x0c, 0xfa, 0x9f, 0xe0, 0x9d,
-	0xc0, 0xee, 0x88, 0x23, 0x95, 0x38, 0x91, 0xc8, 0xa9, 0x64, 0x45, 0x3d, 0x2d, 0xe7, 0xc2, 0x77,
-	0x06, 0xcd, 0xa1, 0x1b, 0x74, 0x2c, 0xe9, 0x42, 0xe9, 0xf6, 0x63, 0x62, 0x5a, 0x65, 0x3b, 0x68,
-	0xb2, 0x44, 0x92, 0xaf, 0x61, 0xaf, 0x1a, 0xa6, 0xaa, 0xb1, 0x93, 0x69, 0x7c, 0x0c, 0xff, 0xcb,
-	0x76, 0x29, 0xf4, 0x42, 0xcb, 0x8b, 0x3c, 0x42, 0x31, 0xcd, 0xe5, 0x35, 0x66, 0x2e, 0xef, 0xd4,
-	0xe6, 0x32, 0xf2, 0x4e, 0xc9, 0x14, 0xf6, 0x7e, 0x88, 0x70, 0x11, 0x8e, 0xa3, 0x25, 0xc6, 0x22,
-	0x62, 0xb1, 0xb8, 0x0b, 0x6c, 0x95, 0x47, 0xdf, 0x4a, 0xc2, 0x86, 0x6b, 0x9b, 0x4b, 0x4a, 0x90,
-	0x23, 0x68, 0xe9, 0x78, 0x8a, 0xbc, 0x29, 0x5d, 0x66, 0x77, 0x87, 0x1b, 0xd3, 0xa5, 0x26, 0xf4,
-	0x62, 0x95, 0x18, 0x11, 0xdc, 0xc0, 0x95, 0xab, 0x04, 0xc9, 0x1c, 0xf6, 0x37, 0x00, 0xac, 0xcf,
-	0x98, 0x5e, 0x32, 0xf9, 0xbb, 0xc1, 0xd6, 0x1b, 0x6d, 0x79, 0x9f, 0x01, 0xac, 0x77, 0xdb, 0x31,
-	0x01, 0x61, 0xee, 0x59, 0x9f, 0xb4, 0x9c, 0xa8, 0x97, 0xd0, 0x3f, 0xf9, 0x90, 0xd0, 0x38, 0xb4,
-	0xa8, 0xef, 0x57, 0xe3, 0x08, 0x76, 0x2b, 0xd1, 0x2c, 0xe0, 0xc2, 0x2f, 0x4a, 0xa5, 0xf5, 0x2f,
-	0x19, 0xa4, 0x46, 0x11, 0xd2, 0xe1, 0x98, 0xbd, 0x8f, 0x17, 0x8c, 0x86, 0x66, 0xa6, 0xc5, 0x34,
-	0x11, 0x97, 0x4c, 0xfe, 0xfb, 0x49, 0xf5, 0xc0, 0x3d, 0xa7, 0xf2, 0x32, 0x1b, 0x04, 0x09, 0x95,
-	0x97, 0xe4, 0x19, 0xfc, 0xbf, 0x26, 0x5a, 0x6d, 0xf3, 0x3c, 0x05, 0x6f, 0x73, 0x54, 0xd7, 0xa7,
-	0x25, 0xdf, 0x41, 0xef, 0x6e, 0x03, 0xdc, 0x03, 0x57, 0x4f, 0x44, 0xab, 0xb2, 0x88, 0x3e, 0x22,
-	0xf9, 0x16, 0x0e, 0x4c, 0x57, 0x7f, 0x5a, 0xad, 0xe4, 0x15, 0x3c, 0xb9, 0xf1, 0xbf, 0xdb, 0x92,
-	0x57, 0xc9, 0xc9, 0x01, 0x35, 0x0b, 0x80, 0x5e, 0xc0, 0xc1, 0x18, 0x17, 0xf8, 0xa9, 0x80, 0x6e,
-	0x24, 0xff, 0x08, 0x9e, 0xdc, 0x18, 0xab, 0xf6, 0x6e, 0xfe, 0x1d, 0xba, 0x3f, 0xa5, 0xc8, 0x57,
-	0x93, 0xf8, 0x0d, 0xf3, 0x1e, 0x42, 0x23, 0x4f, 0xd3, 0x88, 0xc6, 0xea, 0xfd, 0xa3, 0x17, 0x6d,
-	0x8a, 0xd6, 0x95, 0x32, 0x54, 0xde, 0x9f, 0x05, 0x66, 0xe3, 0xc3, 0x4d, 0x05, 0xf2, 0xd2, 0xbd,
-	0xe6, 0x56, 0x46, 0xb4, 0x5a, 0x4b, 0x39, 0x55, 0x57, 0xb0, 0x7e, 0xba, 0x34, 0x83, 0x4e, 0x68,
-	0x6d, 0xd2, 0x57, 0xca, 0xb3, 0xf7, 0x2a, 0x4b, 0x84, 0x85, 0x47, 0x5a, 0xaf, 0xe4, 0x5d, 0xf7,
-	0xb4, 0x75, 0xd9, 0x0a, 0xda, 0x57, 0xc6, 0x5c, 0xf7, 0x74, 0x5e, 0x17, 0x81, 0x1d, 0xf5, 0xe8,
-	0xd0, 0xf0, 0x33, 0x2a, 0x2b, 0xe5, 0xa9, 0xc7, 0x64, 0x61, 0x4f, 0x2d, 0x45, 0x23, 0xf5, 0x60,
-	0x10, 0x92, 0xf1, 0xbb, 0xce, 0xaf, 0x9b, 0xba, 0x6e, 0x08, 0xfd, 0x72, 0x90, 0xda, 0x74, 0x13,
-	0xd8, 0x57, 0xc5, 0x9f, 0x21, 0x15, 0x29, 0xd7, 0x53, 0x20, 0x3f, 0x11, 0x9b, 0x3d, 0x76, 0x08,
-	0xdd, 0x11, 0x8b, 0xc3, 0x48, 0x93, 0x6b, 0xca, 0xef, 0xce, 0x33, 0x07, 0x39, 0x07, 0x7f, 0x33,
-	0x94, 0x4d, 0x4c, 0x60, 0xbb, 0xe8, 0xb7, 0x41, 0xb7, 0x97, 0x05, 0xdf, 0x0d, 0xb4, 0x7e, 0x03,
-	0x9d, 0x53, 0x5c, 0xfd, 0x42, 0x17, 0xa9, 0x86, 0x7e, 0x8a, 0xab, 0x0c, 0xcd, 0x3b, 0x5c, 0xa9,
-	0x7e, 0xd1, 0x4b, 0x59, 0xbf, 0x5c, 0x2b, 0x83, 0x9c, 0x40, 0xf7, 0x82, 0xbe, 0xd5, 0x0b, 0x42,
-	0x3d, 0x55, 0x0b, 0x69, 0xed, 0xcf, 0x0f, 0x0a, 0x59, 0xd5, 0x55, 0x6b, 0xf6, 0x66, 0x2f, 0x3a,
-	0x1d, 0x45, 0x90, 0x73, 0xe8, 0xab, 0x62, 0xf2, 0x50, 0x77, 0x79, 0x1d, 0xde, 0x4e, 0xcf, 0x31,
-	0xec, 0x56, 0x22, 0xae, 0x6f, 0x7b, 0x0b, 0xc1, 0x31, 0x13, 0xca, 0x40, 0xd8, 0xe4, 0xe3, 0x9f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xd0, 0xb8, 0xbc, 0xbd, 0x3e, 0x0d, 0x00, 0x00,
+	// 1530 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xeb, 0x6e, 0xdb, 0xc6,
+	0x12, 0x06, 0x45, 0x52, 0x97, 0xb1, 0x4e, 0x9c, 0xd0, 0xb2, 0xcd, 0xe3, 0xf8, 0x1c, 0x08, 0x0b,
+	0xb4, 0x51, 0x7f, 0x24, 0x4e, 0x83, 0xa2, 0x7f, 0x0a, 0xb4, 0x70, 0x24, 0x17, 0x76, 0x7c, 0xa9,
+	0xb3, 0x72, 0x12, 0x14, 0x08, 0x0a, 0x6c, 0xc4, 0xb5, 0xcd, 0x46, 0xe2, 0xca, 0xdc, 0xa5, 0x13,
+	0x05, 0xe8, 0x13, 0xb4, 0xe8, 0xc3, 0xf5, 0x2d, 0xfa, 0x16, 0xc5, 0x5e, 0x28, 0x2d, 0x25, 0xd1,
+	0x71, 0x9a, 0x7f, 0x9c, 0xd9, 0xdd, 0xd9, 0xef, 0x9b, 0xcb, 0xce, 0x10, 0xd6, 0xe2, 0x44, 0xd0,
+	0x34, 0x21, 0xc3, 0x9d, 0x88, 0x08, 0xf2, 0x68, 0x9c, 0x32, 0xc1, 0x82, 0x7a, 0xae, 0x44, 0x7f,
+	0x38, 0x70, 0xb7, 0xcb, 0xc6, 0x93, 0xfe, 0x25, 0x49, 0x23, 0x4c, 0xaf, 0x32, 0xca, 0x45, 0xb0,
+	0x01, 0xd5, 0x3e, 0xcb, 0xd2, 0x01, 0x0d, 0x9d, 0x76, 0xa5, 0xd3, 0xc0, 0x55, 0xae, 0xa4, 0x20,
+	0x00, 0xaf, 0x47, 0xb9, 0x08, 0x2b, 0x4a, 0xeb, 0x45, 0x72, 0xef, 0x16, 0xd4, 0x7b, 0x44, 0x90,
+	0x37, 0x84, 0xd3, 0xd0, 0x6d, 0x3b, 0x9d, 0x06, 0xae, 0x47, 0x46, 0x96, 0x76, 0x4e, 0xd9, 0x30,
+	0x1e, 0x4c, 0x42, 0x4f, 0xad, 0x54, 0xc7, 0x4a, 0x0a, 0x42, 0xa8, 0xa9, 0xfb, 0x0e, 0x7a, 0xa1,
+	0xdf, 0xae, 0x74, 0x3c, 0x5c, 0xe3, 0x5a, 0x44, 0x5f, 0xc0, 0x3d, 0x0b, 0x0d, 0x1f, 0xb3, 0x84,
+	0xd3, 0xe0, 0x2e, 0xb8, 0x7b, 0x69, 0x1a, 0x3a, 0xca, 0x86, 0x4b, 0xd3, 0x14, 0x85, 0xb0, 0x31,
+	0xdd, 0xd6, 0x17, 0x44, 0x64, 0xdc, 0x40, 0x47, 0xaf, 0x61, 0x73, 0x61, 0xa5, 0xcc, 0x4c, 0xb0,
+	0x03, 0xfe, 0x19, 0xe1, 0x6f, 0x79, 0x58, 0x69, 0xbb, 0x9d, 0x95, 0x27, 0xff, 0x7d, 0x94, 0xbb,
+	0xe5, 0xd1, 0xbc, 0x0d, 0x5f, 0xc8, 0x7d, 0xe8, 0x6f, 0x07, 0x56, 0xe7, 0x96, 0x3e, 0xc3, 0x59,
+	0x95, 0x52, 0x67, 0x55, 0x2c, 0x67, 0x6d, 0x43, 0xe3, 0x8c, 0x09, 0x32, 0xec, 0xc7, 0x1f, 0x68,
+	0xe8, 0xb7, 0x9d, 0x8e, 0x87, 0x1b, 0x22, 0x57, 0x04, 0x6d, 0x58, 0x19, 0x64, 0x69, 0x4a, 0x13,
+	0xa1, 0xd6, 0xab, 0xca, 0x9d, 0xb6, 0x4a, 0x9e, 0xef, 0x0b, 0x92, 0x0a, 0x1a, 0xed, 0x8a, 0xb0,
+	0xa6, 0xd6, 0x1b, 0x3c, 0x57, 0xd8, 0xa1, 0xa8, 0xb7, 0x1d, 0x3b, 0x14, 0xaf, 0xa1, 0x75, 0x18,
+	0x0f, 0x87, 0x9f, 0x95, 0x1c, 0x96, 0x75, 0xb7, 0x18, 0xe8, 0xaf, 0x60, 0x7d, 0xce, 0x7a, 0x69,
+	0xb0, 0xdf, 0x40, 0x80, 0xe9, 0x88, 0x5d, 0xd3, 0x02, 0x0c, 0xdb, 0x95, 0x4e, 0xa9, 0x2b, 0x2b,
+	0x05, 0x57, 0x96, 0xc3, 0x79, 0x00, 0x6b, 0x85, 0x3b, 0xe6, 0xc1, 0x54, 0x72, 0x30, 0x7f, 0x3a,
+	0x10, 0x3c, 0x63, 0x71, 0xd2, 0x1d, 0x66, 0x5c, 0xd0, 0xd4, 0x72, 0xca, 0x09, 0x8b, 0xe8, 0x41,
+	0x4f, 0xed, 0xf5, 0x70, 0x35, 0x51, 0x92, 0x44, 0x29, 0xf5, 0xbb, 0x51, 0x94, 0x1a, 0x2c, 0xf5,
+	0xc4, 0xc8, 0x32, 0x30, 0xc7, 0x54, 0x10, 0xf9, 0xcd, 0x43, 0xb7, 0xed, 0x76, 0x1a, 0xb8, 0x31,
+	0xca, 0x15, 0xc1, 0x97, 0x70, 0xe7, 0x60, 0x34, 0x66, 0xa9, 0x90, 0x7b, 0x24, 0x53, 0x55, 0x43,
+	0x75, 0x7c, 0x27, 0x2e, 0x68, 0xd1, 0xcf, 0xb0, 0x56, 0xc0, 0x63, 0x90, 0x97, 0x01, 0x0a, 0xa1,
+	0x76, 0xd6, 0x3d, 0xdd, 0x67, 0xd3, 0x40, 0xd5, 0x84, 0x16, 0x73, 0xae, 0xee, 0x8c, 0xeb, 0xd7,
+	0xb0, 0x76, 0x44, 0xc9, 0x35, 0x9d, 0xe3, 0x6a, 0x73, 0x72, 0x8a, 0x9c, 0x50, 0x07, 0x5a, 0xc5,
+	0x23, 0xa5, 0x8e, 0xfc, 0xdd, 0x81, 0x7b, 0xaf, 0xd2, 0x58, 0x14, 0xa3, 0x6a, 0x45, 0xc8, 0x29,
+	0x44, 0x48, 0xc7, 0x34, 0x4e, 0x84, 0x2e, 0xd6, 0xa6, 0x8c, 0xa9, 0x94, 0x6e, 0x7c, 0x7f, 0x3a,
+	0xb0, 0x8a, 0xa9, 0xa0, 0x89, 0x88, 0x59, 0x52, 0x78, 0x88, 0x56, 0xd3, 0xa2, 0x1a, 0x3d, 0x85,
+	0xc0, 0x06, 0x63, 0x50, 0x07, 0xe0, 0x75, 0x59, 0xa4, 0xf3, 0xcb, 0xc7, 0xde, 0x80, 0x45, 0x54,
+	0x22, 0x3c, 0xa6, 0x9c, 0x93, 0x0b, 0x1a, 0x56, 0x94, 0xad, 0xda, 0x48, 0x8b, 0xa8, 0x0f, 0x9b,
+	0x7b, 0xef, 0xe9, 0x20, 0x13, 0x54, 0xbe, 0x0c, 0x74, 0x44, 0x13, 0x91, 0xd3, 0xd2, 0x35, 0xa8,
+	0x75, 0xc6, 0x09, 0x0d, 0x9e, 0x2b, 0x0a, 0x14, 0x2a, 0xc5, 0x54, 0x46, 0xfb, 0x10, 0x2e, 0x1a,
+	0xfd, 0x57, 0xf0, 0x2e, 0x60, 0xbd, 0x9b, 0x52, 0x22, 0xe8, 0x81, 0xa0, 0x29, 0x11, 0xcc, 0x8e,
+	0xa7, 0xf1, 0x39, 0x0f, 0x9d, 0xb6, 0xdb, 0xf1, 0x70, 0xdd, 0x38, 0x9d, 0xcb, 0xb8, 0xfd, 0x34,
+	0xd6, 0xa9, 0xd2, 0xc4, 0x2e, 0x1b, 0x0b, 0xf9, 0xe0, 0x1c, 0x53, 0xc2, 0xb3, 0x54, 0x93, 0x91,
+	0x2e, 0x6f, 0xe2, 0x95, 0xd1, 0x4c, 0x85, 0xbe, 0x87, 0x8d, 0xf9, 0x8b, 0x4a, 0x5f, 0xe0, 0x00,
+	0xbc, 0xb3, 0xc9, 0x58, 0x63, 0xf5, 0xb1, 0x27, 0x26, 0x63, 0x8a, 0x76, 0xe1, 0x3f, 0xf9, 0x49,
+	0xc9, 0x99, 0xab, 0xa4, 0xa0, 0x69, 0x4c, 0xf9, 0xc9, 0x34, 0x29, 0xb4, 0x38, 0x4d, 0x8a, 0x13,
+	0x83, 0x50, 0x27, 0xc5, 0x09, 0x3a, 0x81, 0x8d, 0x1f, 0x63, 0x3a, 0x8c, 0x7a, 0xf1, 0x88, 0x26,
+	0x3c, 0x66, 0x09, 0xbf, 0x0d, 0x59, 0x79, 0x8f, 0x7a, 0xcb, 0xb8, 0x31, 0x57, 0xd3, 0x4f, 0x1b,
+	0x47, 0x3b, 0xe0, 0x2b, 0x7b, 0x12, 0xef, 0x09, 0x19, 0xe5, 0x2f, 0x8e, 0x97, 0x90, 0x11, 0xb5,
+	0x38, 0x48, 0x6c, 0x9a, 0x83, 0x80, 0xcd, 0x05, 0x00, 0xc6, 0x09, 0x0f, 0xa0, 0xaa, 0x96, 0xf4,
+	0xfd, 0x2b, 0x4f, 0x56, 0x67, 0x5d, 0x47, 0xe9, 0x71, 0xf5, 0x5c, 0x2d, 0x07, 0xff, 0x07, 0x98,
+	0x1d, 0x57, 0x59, 0xdf, 0xc0, 0x10, 0x4d, 0x35, 0xb3, 0x82, 0x9d, 0xbe, 0x94, 0x47, 0xd0, 0xda,
+	0x7b, 0x3f, 0x26, 0x49, 0x64, 0x68, 0x7c, 0x1e, 0xe9, 0x2e, 0xac, 0xcf, 0x59, 0x33, 0x0c, 0xac,
+	0x23, 0x4e, 0xdb, 0xb1, 0x8e, 0xe4, 0x90, 0x2a, 0xf6, 0xe3, 0xbd, 0xdd, 0x63, 0xef, 0x92, 0x21,
+	0x23, 0x91, 0x6e, 0x9a, 0x09, 0x19, 0xf3, 0x4b, 0x26, 0x3e, 0x5e, 0xf0, 0x01, 0x78, 0xa7, 0x44,
+	0x5c, 0x1a, 0x63, 0xde, 0x98, 0x88, 0xcb, 0xa0, 0x05, 0x7e, 0x3f, 0x4e, 0x06, 0xba, 0xd2, 0x5d,
+	0xec, 0x73, 0x29, 0xa0, 0x17, 0xf0, 0xbf, 0x92, 0x3b, 0x4a, 0xf3, 0x0e, 0x41, 0x33, 0xdf, 0x75,
+	0x16, 0x8f, 0x74, 0xfe, 0xb9, 0xb8, 0xc9, 0x2d, 0x1d, 0x7a, 0x0c, 0xc1, 0xe2, 0x80, 0x71, 0x93,
+	0x2f, 0xd1, 0x77, 0xb0, 0x62, 0x9d, 0xb8, 0x99, 0x9b, 0x6a, 0xd7, 0x26, 0x65, 0x78, 0xfc, 0x81,
+	0xa2, 0x97, 0xb0, 0x76, 0xbb, 0xa9, 0xe5, 0x21, 0x54, 0xd5, 0xc6, 0x7c, 0x6c, 0x59, 0x9f, 0x25,
+	0x90, 0x6d, 0xa0, 0xaa, 0x2e, 0xe3, 0xe8, 0x5b, 0xd8, 0xd2, 0xe5, 0xf8, 0x69, 0xfe, 0x47, 0xaf,
+	0xe0, 0xfe, 0xd2, 0x73, 0x65, 0x2f, 0xba, 0x15, 0xb0, 0xca, 0x34, 0x60, 0x39, 0x51, 0xd7, 0x22,
+	0xfa, 0x0c, 0xb6, 0x7a, 0x74, 0x48, 0x3f, 0x15, 0xd0, 0x32, 0xfb, 0x68, 0x07, 0xee, 0x2f, 0xb5,
+	0x55, 0xda, 0x76, 0x7e, 0x83, 0xc6, 0xf3, 0x8c, 0xa6, 0x93, 0x83, 0xe4, 0x9c, 0x05, 0x77, 0xa0,
+	0x32, 0xbd, 0xa6, 0x12, 0xf7, 0x64, 0x7a, 0xa9, 0x45, 0x73, 0x85, 0x7f, 0x25, 0x05, 0x79, 0xef,
+	0x0b, 0x4e, 0xf3, 0x42, 0xf3, 0x32, 0x4e, 0xd3, 0xc2, 0x93, 0xed, 0xcd, 0x4d, 0x1f, 0x72, 0x2d,
+	0x4b, 0x89, 0xec, 0x2e, 0x6a, 0xbc, 0x75, 0x71, 0x3d, 0x32, 0x32, 0x6a, 0xc9, 0x9c, 0x62, 0xef,
+	0xe4, 0x2d, 0xf1, 0xb4, 0x3e, 0x75, 0xe8, 0x2d, 0xad, 0x41, 0xff, 0x10, 0x6a, 0x46, 0x65, 0x9e,
+	0x8a, 0xb5, 0x59, 0xa4, 0xa7, 0x24, 0x70, 0xed, 0x4a, 0xef, 0x59, 0x52, 0x7c, 0x08, 0xee, 0xca,
+	0x21, 0x4b, 0xed, 0xcd, 0xfd, 0x3b, 0xc7, 0x59, 0x4e, 0xdc, 0xd6, 0x9e, 0xd2, 0x21, 0xac, 0x2b,
+	0x07, 0x24, 0x2e, 0x58, 0x7a, 0xdb, 0x7e, 0xbd, 0x2c, 0xc5, 0x3b, 0xd0, 0x2a, 0x1a, 0x29, 0x0d,
+	0xd3, 0x01, 0x6c, 0x4a, 0x8f, 0x58, 0x9d, 0x66, 0x5a, 0x80, 0x8b, 0x89, 0xb7, 0x0d, 0x8d, 0x2e,
+	0x4b, 0xa2, 0x58, 0x79, 0x5c, 0x87, 0xae, 0x31, 0xc8, 0x15, 0xe8, 0x14, 0xc2, 0x45, 0x53, 0xe6,
+	0x62, 0x04, 0x4d, 0x5b, 0x6f, 0x8c, 0x36, 0xad, 0x6e, 0x66, 0xb9, 0x75, 0x0a, 0xee, 0x09, 0xd4,
+	0x0f, 0xe9, 0xe4, 0x25, 0x19, 0x66, 0x0a, 0xfa, 0x21, 0x9d, 0xe4, 0x68, 0xde, 0xd2, 0x89, 0x4c,
+	0x22, 0xb5, 0x94, 0x27, 0xd1, 0xb5, 0x14, 0xd0, 0x1e, 0x34, 0xce, 0xc8, 0x85, 0x5a, 0xe0, 0xf3,
+	0x3d, 0x54, 0x1f, 0xb6, 0x7b, 0xa8, 0x6c, 0x6c, 0x7a, 0x6f, 0x3e, 0xc1, 0x2a, 0x2b, 0x1c, 0x9d,
+	0x42, 0x4b, 0x92, 0x99, 0x9a, 0xba, 0xcd, 0x34, 0x7c, 0xb3, 0x7b, 0x76, 0x61, 0x7d, 0xce, 0xe2,
+	0x6c, 0x82, 0x34, 0x10, 0x1c, 0xdd, 0x5b, 0x35, 0x84, 0x25, 0xfe, 0x78, 0x66, 0x1e, 0xca, 0x5e,
+	0x7c, 0x41, 0xf9, 0x2d, 0x0a, 0x79, 0x0b, 0xea, 0x07, 0x32, 0x8f, 0xaf, 0xc9, 0x50, 0x99, 0x71,
+	0xb1, 0xfe, 0x1f, 0xbd, 0x26, 0x43, 0xf4, 0x0b, 0x34, 0x75, 0xaf, 0xd7, 0xc6, 0x96, 0xfb, 0x57,
+	0xfd, 0xcf, 0x98, 0xa3, 0xbe, 0xfa, 0x97, 0x91, 0xda, 0x2e, 0xcb, 0xd4, 0x40, 0x22, 0xef, 0xf2,
+	0x07, 0x52, 0x90, 0xa7, 0xfb, 0xd9, 0x48, 0x55, 0xa8, 0x87, 0x5d, 0x9e, 0x8d, 0xe4, 0xb8, 0x5c,
+	0xc0, 0x6a, 0xc8, 0x3e, 0x86, 0x9a, 0xd6, 0xe4, 0xa5, 0xb6, 0x61, 0x3d, 0xaa, 0x16, 0x1e, 0x5c,
+	0x8b, 0xf4, 0xb6, 0x25, 0xd5, 0xf6, 0xab, 0x71, 0x83, 0x1e, 0x53, 0x6f, 0x55, 0x21, 0x87, 0x74,
+	0x92, 0x77, 0x76, 0xef, 0x2d, 0x9d, 0xf0, 0x19, 0x39, 0xd7, 0x26, 0x27, 0xef, 0x4a, 0x22, 0x45,
+	0xc3, 0xc5, 0x2e, 0x4d, 0x22, 0xf4, 0x03, 0xac, 0x15, 0xee, 0x9a, 0xc5, 0x4c, 0x6b, 0x14, 0x8b,
+	0xd9, 0x90, 0xbc, 0x08, 0x76, 0x1b, 0xb6, 0xf6, 0x25, 0xc3, 0x68, 0x9f, 0x24, 0x11, 0x3b, 0x3f,
+	0x2f, 0xfe, 0x45, 0xff, 0xe5, 0x40, 0x50, 0x58, 0x7e, 0x9e, 0xd1, 0xac, 0xfc, 0xa7, 0xa2, 0x05,
+	0xfe, 0xd3, 0x89, 0x30, 0xc9, 0xea, 0x62, 0xff, 0x8d, 0x14, 0x54, 0xa7, 0xa4, 0x17, 0xba, 0xb0,
+	0x34, 0x9d, 0x3a, 0x37, 0xb2, 0x2c, 0xbc, 0x7d, 0x4a, 0xa2, 0x63, 0x16, 0xc5, 0xe7, 0x31, 0x8d,
+	0xd4, 0x58, 0xee, 0xe2, 0xe6, 0xa5, 0xa5, 0x93, 0x9e, 0x3b, 0x22, 0x5c, 0x48, 0xe0, 0xbe, 0x1e,
+	0x65, 0x87, 0x5a, 0x94, 0x19, 0x6d, 0x56, 0x76, 0x45, 0x58, 0x55, 0x47, 0x1b, 0xc3, 0x5c, 0xa1,
+	0x9c, 0x40, 0x32, 0x4e, 0x23, 0xf5, 0xb7, 0x5b, 0xc7, 0xd5, 0xb1, 0x92, 0x10, 0x85, 0xfb, 0x4b,
+	0x29, 0x97, 0x36, 0xda, 0x6f, 0xa0, 0xaa, 0x78, 0xe7, 0x8d, 0x76, 0x7b, 0x96, 0x13, 0x8b, 0xce,
+	0xc1, 0xd5, 0x2b, 0xb5, 0x17, 0x1d, 0xcd, 0x79, 0x76, 0x77, 0x20, 0xeb, 0xec, 0x63, 0x3f, 0x8a,
+	0x1b, 0x50, 0xd5, 0x1b, 0xf3, 0x82, 0x27, 0x4a, 0x92, 0x0d, 0x6e, 0xa9, 0xb5, 0x32, 0xd0, 0xff,
+	0x0c, 0x00, 0xb1, 0x6f, 0xf8, 0x9a, 0xf0, 0x11, 0x00, 0x00,
 }
@@ -187,8 +187,9 @@ func (w *ExecuteStatementResponse) UnmarshalBinary(buf []byte) error {
 
 // CreateIteratorRequest represents a request to create a remote iterator.
 type CreateIteratorRequest struct {
-	ShardIDs []uint64
-	Opt      influxql.IteratorOptions
+	ShardIDs    []uint64
+	Measurement influxql.Measurement
+	Opt         influxql.IteratorOptions
 }
 
 // MarshalBinary encodes r to a binary format.
@@ -197,9 +198,17 @@ func (r *CreateIteratorRequest) MarshalBinary() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	m := r.Measurement
+	mbuf, err := influxql.Sources{&m}.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
 	return proto.Marshal(&internal.CreateIteratorRequest{
-		ShardIDs: r.ShardIDs,
-		Opt:      buf,
+		ShardIDs:    r.ShardIDs,
+		Measurement: mbuf,
+		Opt:         buf,
 	})
 }
 
@@ -214,17 +223,31 @@ func (r *CreateIteratorRequest) UnmarshalBinary(data []byte) error {
 	if err := r.Opt.UnmarshalBinary(pb.GetOpt()); err != nil {
 		return err
 	}
+
+	if buf := pb.GetMeasurement(); len(buf) > 0 {
+		var sources influxql.Sources
+		if err := sources.UnmarshalBinary(buf); err != nil {
+			return err
+		}
+		if len(sources) > 0 {
+			if m, ok := sources[0].(*influxql.Measurement); ok {
+				r.Measurement = *m
+			}
+		}
+	}
 	return nil
 }
 
 // CreateIteratorResponse represents a response from remote iterator creation.
 type CreateIteratorResponse struct {
-	Err error
+	Type influxql.DataType
+	Err  error
 }
 
 // MarshalBinary encodes r to a binary format.
 func (r *CreateIteratorResponse) MarshalBinary() ([]byte, error) {
 	var pb internal.CreateIteratorResponse
+	pb.Type = proto.Int32(int32(r.Type))
 	if r.Err != nil {
 		pb.Err = proto.String(r.Err.Error())
 	}
@@ -237,6 +260,7 @@ func (r *CreateIteratorResponse) UnmarshalBinary(data []byte) error {
 	if err := proto.Unmarshal(data, &pb); err != nil {
 		return err
 	}
+	r.Type = influxql.DataType(pb.GetType())
 	if pb.Err != nil {
 		r.Err = errors.New(pb.GetErr())
 	}
@@ -275,9 +299,9 @@ func (r *FieldDimensionsRequest) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-// FieldDimensionsResponse represents a response from remote iterator creation.
+// FieldDimensionsResponse represents a response with the unique fields & dimensions.
 type FieldDimensionsResponse struct {
-	Fields     map[string]struct{}
+	Fields     map[string]influxql.DataType
 	Dimensions map[string]struct{}
 	Err        error
 }
@@ -286,9 +310,12 @@ type FieldDimensionsResponse struct {
 func (r *FieldDimensionsResponse) MarshalBinary() ([]byte, error) {
 	var pb internal.FieldDimensionsResponse
 
-	pb.Fields = make([]string, 0, len(r.Fields))
-	for k := range r.Fields {
-		pb.Fields = append(pb.Fields, k)
+	pb.Fields = make([]*internal.Field, 0, len(r.Fields))
+	for k, typ := range r.Fields {
+		pb.Fields = append(pb.Fields, &internal.Field{
+			Name: proto.String(k),
+			Type: proto.Uint64(uint64(typ)),
+		})
 	}
 
 	pb.Dimensions = make([]string, 0, len(r.Dimensions))
@@ -309,9 +336,9 @@ func (r *FieldDimensionsResponse) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
-	r.Fields = make(map[string]struct{}, len(pb.GetFields()))
-	for _, s := range pb.GetFields() {
-		r.Fields[s] = struct{}{}
+	r.Fields = make(map[string]influxql.DataType, len(pb.GetFields()))
+	for _, f := range pb.GetFields() {
+		r.Fields[f.GetName()] = influxql.DataType(f.GetType())
 	}
 
 	r.Dimensions = make(map[string]struct{}, len(pb.GetDimensions()))
@@ -394,15 +421,134 @@ func (r *ExpandSourcesResponse) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// ShowQueriesRequest represents a request for the queries running on a node.
+type ShowQueriesRequest struct{}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShowQueriesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.ShowQueriesRequest{})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShowQueriesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ShowQueriesRequest
+	return proto.Unmarshal(data, &pb)
+}
+
+// ShowQueriesResponse represents the queries running on a node.
+type ShowQueriesResponse struct {
+	Queries []influxql.QueryInfo
+	Err     error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShowQueriesResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.ShowQueriesResponse
+
+	pb.Queries = make([]*internal.QueryInfo, 0, len(r.Queries))
+	for _, q := range r.Queries {
+		pb.Queries = append(pb.Queries, &internal.QueryInfo{
+			ID:       proto.Uint64(q.ID),
+			Query:    proto.String(q.Query),
+			Database: proto.String(q.Database),
+			Duration: proto.Int64(int64(q.Duration)),
+		})
+	}
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShowQueriesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ShowQueriesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Queries = make([]influxql.QueryInfo, 0, len(pb.GetQueries()))
+	for _, q := range pb.GetQueries() {
+		r.Queries = append(r.Queries, influxql.QueryInfo{
+			ID:       q.GetID(),
+			Query:    q.GetQuery(),
+			Database: q.GetDatabase(),
+			Duration: time.Duration(q.GetDuration()),
+		})
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// KillQueryRequest represents a request to kill a query running on a node.
+type KillQueryRequest struct {
+	QueryID uint64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *KillQueryRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.KillQueryRequest{
+		ID: proto.Uint64(r.QueryID),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *KillQueryRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.KillQueryRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.QueryID = pb.GetID()
+	return nil
+}
+
+// KillQueryResponse represents a response from killing a query.
+type KillQueryResponse struct {
+	Err error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *KillQueryResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.KillQueryResponse
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *KillQueryResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.KillQueryResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
 type DownloadShardSnapshotRequest struct {
 	Path    string
 	ShardID uint64
+
+	// Since limits the snapshot to the files changed after it, if set.
+	Since time.Time
 }
 
 func (dsr *DownloadShardSnapshotRequest) MarshalBinary() ([]byte, error) {
 	var pb internal.DownloadShardSnapshotRequest
-	pb.Path = proto.String(dsr.Path)
+	if dsr.Path != "" {
+		pb.Path = proto.String(dsr.Path)
+	}
 	pb.ShardID = proto.Uint64(dsr.ShardID)
+	if !dsr.Since.IsZero() {
+		pb.Since = proto.Int64(dsr.Since.UnixNano())
+	}
 
 	return proto.Marshal(&pb)
 }
@@ -415,7 +561,44 @@ func (dsr *DownloadShardSnapshotRequest) UnmarshalBinary(data []byte) error {
 
 	dsr.ShardID = pb.GetShardID()
 	dsr.Path = pb.GetPath()
+	if pb.Since != nil {
+		dsr.Since = time.Unix(0, pb.GetSince()).UTC()
+	}
+
+	return nil
+}
+
+type DownloadShardSnapshotResponse struct {
+	Err string
+
+	// SnapshotTime is the time of the data node when the snapshot started.
+	// Passed as Since it limits a later snapshot to the files changed after
+	// this one.
+	SnapshotTime time.Time
+}
+
+func (dsr *DownloadShardSnapshotResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.DownloadShardSnapshotResponse
+	if dsr.Err != "" {
+		pb.Err = proto.String(dsr.Err)
+	}
+	if !dsr.SnapshotTime.IsZero() {
+		pb.SnapshotTime = proto.Int64(dsr.SnapshotTime.UnixNano())
+	}
+
+	return proto.Marshal(&pb)
+}
+
+func (dsr *DownloadShardSnapshotResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.DownloadShardSnapshotResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
 
+	dsr.Err = pb.GetErr()
+	if pb.SnapshotTime != nil {
+		dsr.SnapshotTime = time.Unix(0, pb.GetSnapshotTime()).UTC()
+	}
 	return nil
 }
 
@@ -518,26 +701,33 @@ func (m *RestoreShardResponse) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// JoinClusterRequest asks a data node to join the cluster of the meta
+// servers at MetaAddrs.
 type JoinClusterRequest struct {
-	NodeID    uint64
+	NodeID uint64
+
+	// NodeAddr is the TCP address of the data node.
 	NodeAddr  string
 	MetaAddrs []string
+
+	// ImportMetaData creates the databases stored on the data node in the
+	// cluster meta data.
+	ImportMetaData bool
 }
 
+// MarshalBinary encodes the object to a binary format.
 func (jc *JoinClusterRequest) MarshalBinary() ([]byte, error) {
 	var pb internal.JoinClusterRequest
 
 	pb.NodeID = proto.Uint64(jc.NodeID)
-
-	for i, metaAddr := range jc.MetaAddrs {
-		pb.MetaAddrs[i] = metaAddr
-	}
 	pb.NodeAddr = proto.String(jc.NodeAddr)
+	pb.MetaAddrs = jc.MetaAddrs
+	pb.ImportMetaData = proto.Bool(jc.ImportMetaData)
 
 	return proto.Marshal(&pb)
-
 }
 
+// UnmarshalBinary populates JoinClusterRequest from a binary format.
 func (jc *JoinClusterRequest) UnmarshalBinary(data []byte) error {
 	var pb internal.JoinClusterRequest
 	if err := proto.Unmarshal(data, &pb); err != nil {
@@ -547,24 +737,29 @@ func (jc *JoinClusterRequest) UnmarshalBinary(data []byte) error {
 	jc.MetaAddrs = pb.GetMetaAddrs()
 	jc.NodeID = pb.GetNodeID()
 	jc.NodeAddr = pb.GetNodeAddr()
+	jc.ImportMetaData = pb.GetImportMetaData()
 
 	return nil
-
 }
 
+// JoinClusterResponse returns the data node registered with the cluster.
 type JoinClusterResponse struct {
 	NodeID  uint64
 	TCPHost string
+	Err     string
 }
 
-func (jcr *JoinClusterResponse) MarshalBinery() ([]byte, error) {
+// MarshalBinary encodes the object to a binary format.
+func (jcr *JoinClusterResponse) MarshalBinary() ([]byte, error) {
 	var pb internal.JoinClusterResponse
 	pb.NodeID = proto.Uint64(jcr.NodeID)
 	pb.TCPHost = proto.String(jcr.TCPHost)
+	pb.Err = proto.String(jcr.Err)
 
 	return proto.Marshal(&pb)
 }
 
+// UnmarshalBinary populates JoinClusterResponse from a binary format.
 func (jcr *JoinClusterResponse) UnmarshalBinary(data []byte) error {
 	var pb internal.JoinClusterResponse
 	if err := proto.Unmarshal(data, &pb); err != nil {
@@ -573,14 +768,18 @@ func (jcr *JoinClusterResponse) UnmarshalBinary(data []byte) error {
 
 	jcr.NodeID = pb.GetNodeID()
 	jcr.TCPHost = pb.GetTCPHost()
+	jcr.Err = pb.GetErr()
 
 	return nil
 }
 
+// LeaveClusterRequest asks a data node to leave its cluster.
 type LeaveClusterRequest struct {
+	// NodeAddr is the TCP address of the data node.
 	NodeAddr string
 }
 
+// MarshalBinary encodes the object to a binary format.
 func (lcr *LeaveClusterRequest) MarshalBinary() ([]byte, error) {
 	var pb internal.LeaveClusterRequest
 	pb.NodeAddr = proto.String(lcr.NodeAddr)
@@ -588,6 +787,7 @@ func (lcr *LeaveClusterRequest) MarshalBinary() ([]byte, error) {
 	return proto.Marshal(&pb)
 }
 
+// UnmarshalBinary populates LeaveClusterRequest from a binary format.
 func (lcr *LeaveClusterRequest) UnmarshalBinary(data []byte) error {
 	var pb internal.LeaveClusterRequest
 	if err := proto.Unmarshal(data, &pb); err != nil {
@@ -599,21 +799,28 @@ func (lcr *LeaveClusterRequest) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// LeaveClusterReesponse returns the result of leaving the cluster.
 type LeaveClusterReesponse struct {
+	Err string
 }
 
+// MarshalBinary encodes the object to a binary format.
 func (lcr *LeaveClusterReesponse) MarshalBinary() ([]byte, error) {
 	var pb internal.LeaveClusterResponse
+	pb.Err = proto.String(lcr.Err)
 
 	return proto.Marshal(&pb)
 }
 
+// UnmarshalBinary populates LeaveClusterReesponse from a binary format.
 func (lcr *LeaveClusterReesponse) UnmarshalBinary(data []byte) error {
 	var pb internal.LeaveClusterResponse
 	if err := proto.Unmarshal(data, &pb); err != nil {
 		return err
 	}
 
+	lcr.Err = pb.GetErr()
+
 	return nil
 }
 
@@ -646,10 +853,12 @@ func (rsr *RemoveShardRequest) UnmarshalBinary(data []byte) error {
 }
 
 type RemoveShardResponse struct {
+	Err string
 }
 
 func (rsr *RemoveShardResponse) MarshalBinary() ([]byte, error) {
 	var pb internal.RemoveShardResponse
+	pb.Err = proto.String(rsr.Err)
 
 	return proto.Marshal(&pb)
 }
@@ -660,6 +869,8 @@ func (rsr *RemoveShardResponse) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	rsr.Err = pb.GetErr()
+
 	return nil
 }
 
@@ -681,16 +892,35 @@ func (csr *CopyShardStatusRequest) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// CopyShardStatus represents the progress of a shard copy.
+type CopyShardStatus struct {
+	ShardID     uint64
+	Source      string
+	Dest        string
+	Database    string
+	Policy      string
+	CurrentSize uint64
+	StartedAt   time.Time
+}
+
 type CopyShardStatusResponse struct {
 	Err   string
-	Tasks []string
+	Tasks []CopyShardStatus
 }
 
 func (csr *CopyShardStatusResponse) MarshalBinary() ([]byte, error) {
 	var pb internal.CopyShardStatusResponse
 	pb.Err = proto.String(csr.Err)
-	for i, task := range csr.Tasks {
-		pb.Tasks[i] = task
+	for _, task := range csr.Tasks {
+		pb.Tasks = append(pb.Tasks, &internal.CopyShardStatus{
+			ShardID:     proto.Uint64(task.ShardID),
+			Source:      proto.String(task.Source),
+			Dest:        proto.String(task.Dest),
+			Database:    proto.String(task.Database),
+			Policy:      proto.String(task.Policy),
+			CurrentSize: proto.Uint64(task.CurrentSize),
+			StartedAt:   proto.Uint64(uint64(task.StartedAt.UnixNano())),
+		})
 	}
 
 	return proto.Marshal(&pb)
@@ -703,8 +933,17 @@ func (csr *CopyShardStatusResponse) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
-	for i, task := range pb.GetTasks() {
-		csr.Tasks[i] = task
+	csr.Tasks = make([]CopyShardStatus, 0, len(pb.GetTasks()))
+	for _, task := range pb.GetTasks() {
+		csr.Tasks = append(csr.Tasks, CopyShardStatus{
+			ShardID:     task.GetShardID(),
+			Source:      task.GetSource(),
+			Dest:        task.GetDest(),
+			Database:    task.GetDatabase(),
+			Policy:      task.GetPolicy(),
+			CurrentSize: task.GetCurrentSize(),
+			StartedAt:   time.Unix(0, int64(task.GetStartedAt())),
+		})
 	}
 	csr.Err = pb.GetErr()
 
@@ -712,10 +951,11 @@ func (csr *CopyShardStatusResponse) UnmarshalBinary(data []byte) error {
 }
 
 type CopyShardRequest struct {
-	Source  string
-	Dest    string
-	ShardID uint64
-	Policy  string
+	Source   string
+	Dest     string
+	ShardID  uint64
+	Database string
+	Policy   string
 }
 
 func (m *CopyShardRequest) MarshalBinary() ([]byte, error) {
@@ -730,13 +970,15 @@ func (m *CopyShardRequest) MarshalBinary() ([]byte, error) {
 	if m.ShardID == 0 {
 		return nil, fmt.Errorf("ShardID must be larger than 0")
 	}
-	if m.Policy == "" {
-		return nil, fmt.Errorf("RetentionPolicy' name cannot be empty")
-	}
 	pb.Source = proto.String(m.Source)
 	pb.Dest = proto.String(m.Dest)
 	pb.ShardID = proto.Uint64(m.ShardID)
-	pb.Policy = proto.String(m.Policy)
+	if m.Database != "" {
+		pb.Database = proto.String(m.Database)
+	}
+	if m.Policy != "" {
+		pb.Policy = proto.String(m.Policy)
+	}
 
 	return proto.Marshal(&pb)
 }
@@ -750,6 +992,7 @@ func (m *CopyShardRequest) UnmarshalBinary(data []byte) error {
 	m.Source = pb.GetSource()
 	m.Dest = pb.GetDest()
 	m.ShardID = pb.GetShardID()
+	m.Database = pb.GetDatabase()
 	m.Policy = pb.GetPolicy()
 
 	return nil
@@ -826,3 +1069,402 @@ func (ksr *KillCopyShardResponse) UnmarshalBinary(data []byte) error {
 
 	return nil
 }
+
+// ShardDigestRequest represents a request for the digests of a shard.
+type ShardDigestRequest struct {
+	ShardID uint64
+
+	// Interval is the width of the time range covered by each digest.
+	Interval time.Duration
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShardDigestRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.ShardDigestRequest{
+		ShardID:  proto.Uint64(r.ShardID),
+		Interval: proto.Int64(int64(r.Interval)),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShardDigestRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ShardDigestRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.ShardID = pb.GetShardID()
+	r.Interval = time.Duration(pb.GetInterval())
+	return nil
+}
+
+// SeriesDigest summarizes the points of a series within a time range.
+type SeriesDigest struct {
+	// Key is the series key.
+	Key string
+
+	// Start is the start of the time range in nanoseconds.
+	Start int64
+
+	// Count is the number of points and Sum is the sum of the point hashes.
+	Count uint64
+	Sum   uint64
+}
+
+// ShardDigestResponse represents the digests of a shard.
+type ShardDigestResponse struct {
+	Digests []SeriesDigest
+	Err     error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShardDigestResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.ShardDigestResponse
+
+	pb.Digests = make([]*internal.SeriesDigest, 0, len(r.Digests))
+	for _, d := range r.Digests {
+		pb.Digests = append(pb.Digests, &internal.SeriesDigest{
+			Key:   proto.String(d.Key),
+			Start: proto.Int64(d.Start),
+			Count: proto.Uint64(d.Count),
+			Sum:   proto.Uint64(d.Sum),
+		})
+	}
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShardDigestResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ShardDigestResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Digests = make([]SeriesDigest, 0, len(pb.GetDigests()))
+	for _, d := range pb.GetDigests() {
+		r.Digests = append(r.Digests, SeriesDigest{
+			Key:   d.GetKey(),
+			Start: d.GetStart(),
+			Count: d.GetCount(),
+			Sum:   d.GetSum(),
+		})
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// ShardPointsRequest represents a request for the points of a set of series
+// within a time range of a shard.
+type ShardPointsRequest struct {
+	ShardID uint64
+	Keys    []string
+	Start   int64
+	End     int64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShardPointsRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.ShardPointsRequest{
+		ShardID: proto.Uint64(r.ShardID),
+		Keys:    r.Keys,
+		Start:   proto.Int64(r.Start),
+		End:     proto.Int64(r.End),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShardPointsRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ShardPointsRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.ShardID = pb.GetShardID()
+	r.Keys = pb.GetKeys()
+	r.Start = pb.GetStart()
+	r.End = pb.GetEnd()
+	return nil
+}
+
+// ShardPointsResponse represents the points read from a shard.
+type ShardPointsResponse struct {
+	Points []models.Point
+	Err    error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShardPointsResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.ShardPointsResponse
+
+	pb.Points = make([][]byte, 0, len(r.Points))
+	for _, p := range r.Points {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		pb.Points = append(pb.Points, b)
+	}
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShardPointsResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ShardPointsResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Points = make([]models.Point, 0, len(pb.GetPoints()))
+	for _, b := range pb.GetPoints() {
+		p, err := models.NewPointFromBytes(b)
+		if err != nil {
+			return err
+		}
+		r.Points = append(r.Points, p)
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// ShardStatusRequest represents a request for the status of local shards.
+type ShardStatusRequest struct {
+	ShardIDs []uint64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShardStatusRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.ShardStatusRequest{
+		ShardIDs: r.ShardIDs,
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShardStatusRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ShardStatusRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.ShardIDs = pb.GetShardIDs()
+	return nil
+}
+
+// ShardStatus represents the status of a shard stored on a node.
+type ShardStatus struct {
+	ShardID uint64
+
+	// Size is the size of the shard on disk in bytes.
+	Size uint64
+}
+
+// ShardStatusResponse represents the status of the requested shards found on
+// a node.
+type ShardStatusResponse struct {
+	Shards []ShardStatus
+	Err    string
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShardStatusResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.ShardStatusResponse
+
+	pb.Shards = make([]*internal.ShardStatus, 0, len(r.Shards))
+	for _, sh := range r.Shards {
+		pb.Shards = append(pb.Shards, &internal.ShardStatus{
+			ShardID: proto.Uint64(sh.ShardID),
+			Size_:   proto.Uint64(sh.Size),
+		})
+	}
+
+	if r.Err != "" {
+		pb.Err = proto.String(r.Err)
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShardStatusResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ShardStatusResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Shards = make([]ShardStatus, 0, len(pb.GetShards()))
+	for _, sh := range pb.GetShards() {
+		r.Shards = append(r.Shards, ShardStatus{
+			ShardID: sh.GetShardID(),
+			Size:    sh.GetSize_(),
+		})
+	}
+	r.Err = pb.GetErr()
+	return nil
+}
+
+// HintedHandoffStatusRequest represents a request for the hinted handoff
+// queues of a node.
+type HintedHandoffStatusRequest struct{}
+
+// MarshalBinary encodes r to a binary format.
+func (r *HintedHandoffStatusRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.HintedHandoffStatusRequest{})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *HintedHandoffStatusRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.HintedHandoffStatusRequest
+	return proto.Unmarshal(data, &pb)
+}
+
+// HintedHandoffQueue represents the queue of writes a node holds for another
+// node.
+type HintedHandoffQueue struct {
+	NodeID uint64
+
+	// Bytes is the size of the writes not yet replayed, Segments is the
+	// number of segment files holding them.
+	Bytes    int64
+	Segments int64
+
+	// HeadModified is the last time the head segment of the queue was
+	// written. The oldest write queued is at least as old. It is zero when
+	// the queue is empty.
+	HeadModified time.Time
+
+	// LastErr is the last error replaying the queue, at LastErrAt.
+	LastErr   string
+	LastErrAt time.Time
+
+	Paused bool
+}
+
+// HintedHandoffStatusResponse represents the hinted handoff queues of a node.
+type HintedHandoffStatusResponse struct {
+	Queues []HintedHandoffQueue
+	Err    string
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *HintedHandoffStatusResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.HintedHandoffStatusResponse
+
+	pb.Queues = make([]*internal.HintedHandoffQueue, 0, len(r.Queues))
+	for _, q := range r.Queues {
+		pq := &internal.HintedHandoffQueue{
+			NodeID:   proto.Uint64(q.NodeID),
+			Bytes:    proto.Int64(q.Bytes),
+			Segments: proto.Int64(q.Segments),
+			Paused:   proto.Bool(q.Paused),
+		}
+		if !q.HeadModified.IsZero() {
+			pq.HeadModified = proto.Int64(q.HeadModified.UnixNano())
+		}
+		if q.LastErr != "" {
+			pq.LastErr = proto.String(q.LastErr)
+			pq.LastErrAt = proto.Int64(q.LastErrAt.UnixNano())
+		}
+		pb.Queues = append(pb.Queues, pq)
+	}
+
+	if r.Err != "" {
+		pb.Err = proto.String(r.Err)
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *HintedHandoffStatusResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.HintedHandoffStatusResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Queues = make([]HintedHandoffQueue, 0, len(pb.GetQueues()))
+	for _, pq := range pb.GetQueues() {
+		q := HintedHandoffQueue{
+			NodeID:   pq.GetNodeID(),
+			Bytes:    pq.GetBytes(),
+			Segments: pq.GetSegments(),
+			LastErr:  pq.GetLastErr(),
+			Paused:   pq.GetPaused(),
+		}
+		if pq.HeadModified != nil {
+			q.HeadModified = time.Unix(0, pq.GetHeadModified()).UTC()
+		}
+		if pq.LastErrAt != nil {
+			q.LastErrAt = time.Unix(0, pq.GetLastErrAt()).UTC()
+		}
+		r.Queues = append(r.Queues, q)
+	}
+	r.Err = pb.GetErr()
+	return nil
+}
+
+// Actions of a HintedHandoffActionRequest.
+const (
+	HintedHandoffDrain  = "drain"
+	HintedHandoffPurge  = "purge"
+	HintedHandoffPause  = "pause"
+	HintedHandoffResume = "resume"
+)
+
+// HintedHandoffActionRequest represents a request to drain, purge, pause or
+// resume the hinted handoff queue a node holds for another node.
+type HintedHandoffActionRequest struct {
+	NodeID uint64
+	Action string
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *HintedHandoffActionRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.HintedHandoffActionRequest{
+		NodeID: proto.Uint64(r.NodeID),
+		Action: proto.String(r.Action),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *HintedHandoffActionRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.HintedHandoffActionRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.NodeID = pb.GetNodeID()
+	r.Action = pb.GetAction()
+	return nil
+}
+
+// HintedHandoffActionResponse represents the result of a
+// HintedHandoffActionRequest.
+type HintedHandoffActionResponse struct {
+	Err string
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *HintedHandoffActionResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.HintedHandoffActionResponse
+	if r.Err != "" {
+		pb.Err = proto.String(r.Err)
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *HintedHandoffActionResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.HintedHandoffActionResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.Err = pb.GetErr()
+	return nil
+}
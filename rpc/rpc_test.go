@@ -2,6 +2,7 @@ package rpc_test
 
 import (
 	"bytes"
+	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
 	"github.com/zhexuany/influxcloud/rpc"
 	"testing"
@@ -113,3 +114,177 @@ func TestWriteShardResponseBinary(t *testing.T) {
 	}
 
 }
+
+func TestCreateIteratorRequestBinary(t *testing.T) {
+	req := &rpc.CreateIteratorRequest{
+		ShardIDs:    []uint64{1, 2},
+		Measurement: influxql.Measurement{Database: "db0", RetentionPolicy: "rp0", Name: "cpu"},
+		Opt: influxql.IteratorOptions{
+			Expr:      &influxql.VarRef{Val: "value"},
+			StartTime: influxql.MinTime,
+			EndTime:   influxql.MaxTime,
+			Ascending: true,
+		},
+	}
+
+	b, err := req.MarshalBinary()
+	if err != nil {
+		t.Fatalf("CreateIteratorRequest.MarshalBinary() failed: %v", err)
+	}
+
+	got := &rpc.CreateIteratorRequest{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("CreateIteratorRequest.UnmarshalBinary() failed: %v", err)
+	}
+
+	if len(got.ShardIDs) != 2 || got.ShardIDs[0] != 1 || got.ShardIDs[1] != 2 {
+		t.Errorf("ShardIDs mismatch: got %v, exp %v", got.ShardIDs, req.ShardIDs)
+	}
+	if got.Measurement.String() != req.Measurement.String() {
+		t.Errorf("Measurement mismatch: got %v, exp %v", got.Measurement.String(), req.Measurement.String())
+	}
+	if got.Opt.Expr.String() != req.Opt.Expr.String() {
+		t.Errorf("Opt.Expr mismatch: got %v, exp %v", got.Opt.Expr, req.Opt.Expr)
+	}
+}
+
+func TestCreateIteratorResponseBinary(t *testing.T) {
+	resp := &rpc.CreateIteratorResponse{Type: influxql.Integer}
+
+	b, err := resp.MarshalBinary()
+	if err != nil {
+		t.Fatalf("CreateIteratorResponse.MarshalBinary() failed: %v", err)
+	}
+
+	got := &rpc.CreateIteratorResponse{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("CreateIteratorResponse.UnmarshalBinary() failed: %v", err)
+	}
+
+	if got.Type != influxql.Integer {
+		t.Errorf("Type mismatch: got %v, exp %v", got.Type, influxql.Integer)
+	}
+	if got.Err != nil {
+		t.Errorf("unexpected error: %v", got.Err)
+	}
+}
+
+func TestFieldDimensionsResponseBinary(t *testing.T) {
+	resp := &rpc.FieldDimensionsResponse{
+		Fields:     map[string]influxql.DataType{"value": influxql.Float, "count": influxql.Integer},
+		Dimensions: map[string]struct{}{"host": {}},
+	}
+
+	b, err := resp.MarshalBinary()
+	if err != nil {
+		t.Fatalf("FieldDimensionsResponse.MarshalBinary() failed: %v", err)
+	}
+
+	got := &rpc.FieldDimensionsResponse{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("FieldDimensionsResponse.UnmarshalBinary() failed: %v", err)
+	}
+
+	if len(got.Fields) != 2 || got.Fields["value"] != influxql.Float || got.Fields["count"] != influxql.Integer {
+		t.Errorf("Fields mismatch: got %v, exp %v", got.Fields, resp.Fields)
+	}
+	if _, ok := got.Dimensions["host"]; !ok || len(got.Dimensions) != 1 {
+		t.Errorf("Dimensions mismatch: got %v, exp %v", got.Dimensions, resp.Dimensions)
+	}
+}
+
+func TestCopyShardStatusResponseBinary(t *testing.T) {
+	resp := &rpc.CopyShardStatusResponse{
+		Tasks: []rpc.CopyShardStatus{{
+			ShardID:     1,
+			Source:      "host0:8088",
+			Dest:        "host1:8088",
+			Database:    "db0",
+			Policy:      "rp0",
+			CurrentSize: 100,
+			StartedAt:   time.Unix(0, 10),
+		}},
+	}
+
+	b, err := resp.MarshalBinary()
+	if err != nil {
+		t.Fatalf("CopyShardStatusResponse.MarshalBinary() failed: %v", err)
+	}
+
+	got := &rpc.CopyShardStatusResponse{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("CopyShardStatusResponse.UnmarshalBinary() failed: %v", err)
+	}
+
+	if len(got.Tasks) != 1 {
+		t.Fatalf("Tasks mismatch: got %v, exp %v", got.Tasks, resp.Tasks)
+	} else if task := got.Tasks[0]; task.ShardID != 1 || task.Source != "host0:8088" || task.Dest != "host1:8088" ||
+		task.Database != "db0" || task.Policy != "rp0" || task.CurrentSize != 100 || !task.StartedAt.Equal(time.Unix(0, 10)) {
+		t.Errorf("Task mismatch: got %+v, exp %+v", task, resp.Tasks[0])
+	}
+	if got.Err != "" {
+		t.Errorf("unexpected error: %v", got.Err)
+	}
+}
+
+func TestShardDigestResponseBinary(t *testing.T) {
+	resp := &rpc.ShardDigestResponse{
+		Digests: []rpc.SeriesDigest{
+			{Key: "cpu,host=serverA", Start: 0, Count: 2, Sum: 100},
+			{Key: "cpu,host=serverB", Start: 3600, Count: 1, Sum: 1 << 63},
+		},
+	}
+
+	b, err := resp.MarshalBinary()
+	if err != nil {
+		t.Fatalf("ShardDigestResponse.MarshalBinary() failed: %v", err)
+	}
+
+	got := &rpc.ShardDigestResponse{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("ShardDigestResponse.UnmarshalBinary() failed: %v", err)
+	}
+
+	if len(got.Digests) != len(resp.Digests) {
+		t.Fatalf("Digests mismatch: got %v, exp %v", got.Digests, resp.Digests)
+	}
+	for i := range resp.Digests {
+		if got.Digests[i] != resp.Digests[i] {
+			t.Errorf("Digest mismatch: got %+v, exp %+v", got.Digests[i], resp.Digests[i])
+		}
+	}
+	if got.Err != nil {
+		t.Errorf("unexpected error: %v", got.Err)
+	}
+}
+
+func TestHintedHandoffStatusResponseBinary(t *testing.T) {
+	resp := &rpc.HintedHandoffStatusResponse{
+		Queues: []rpc.HintedHandoffQueue{
+			{NodeID: 2, Bytes: 1024, Segments: 1, HeadModified: time.Unix(0, 10).UTC(), LastErr: "node unreachable", LastErrAt: time.Unix(0, 20).UTC(), Paused: true},
+			{NodeID: 3},
+		},
+	}
+
+	b, err := resp.MarshalBinary()
+	if err != nil {
+		t.Fatalf("HintedHandoffStatusResponse.MarshalBinary() failed: %v", err)
+	}
+
+	got := &rpc.HintedHandoffStatusResponse{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("HintedHandoffStatusResponse.UnmarshalBinary() failed: %v", err)
+	}
+
+	if len(got.Queues) != len(resp.Queues) {
+		t.Fatalf("Queues mismatch: got %v, exp %v", got.Queues, resp.Queues)
+	}
+	for i := range resp.Queues {
+		if got.Queues[i] != resp.Queues[i] {
+			t.Errorf("Queue mismatch: got %+v, exp %+v", got.Queues[i], resp.Queues[i])
+		}
+	}
+	if got.Err != "" {
+		t.Errorf("unexpected error: %v", got.Err)
+	}
+}
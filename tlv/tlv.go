@@ -41,6 +41,39 @@ const (
 
 	ShowQuriesStatementRequestMessage
 	ShowQuriesStatementResponseMessage
+
+	ExpandSourcesRequestMessage
+	ExpandSourcesResponseMessage
+
+	KillQueryRequestMessage
+	KillQueryResponseMessage
+
+	DownloadShardSnapshotRequestMessage
+	DownloadShardSnapshotResponseMessage
+
+	ShardDigestRequestMessage
+	ShardDigestResponseMessage
+
+	ShardPointsRequestMessage
+	ShardPointsResponseMessage
+
+	JoinClusterRequestMessage
+	JoinClusterResponseMessage
+
+	LeaveClusterRequestMessage
+	LeaveClusterResponseMessage
+
+	ShardStatusRequestMessage
+	ShardStatusResponseMessage
+
+	RestoreShardRequestMessage
+	RestoreShardResponseMessage
+
+	HintedHandoffStatusRequestMessage
+	HintedHandoffStatusResponseMessage
+
+	HintedHandoffActionRequestMessage
+	HintedHandoffActionResponseMessage
 )
 
 // ReadTLV reads a type-length-value record from r.